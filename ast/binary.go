@@ -0,0 +1,2720 @@
+// Code generated by gen_binary.go; DO NOT EDIT.
+package ast
+
+import "github.com/t14raptor/go-fast/token"
+
+// binarySchema identifies the node definitions the encoding was generated
+// from. Data written from other definitions is rejected.
+const binarySchema = 0x60f5d43be3c71a4c
+
+// arenas allocates the nodes of a decoder.
+type arenas struct {
+	ArrayLiteral          arena[ArrayLiteral]
+	ArrayPattern          arena[ArrayPattern]
+	ArrowFunctionLiteral  arena[ArrowFunctionLiteral]
+	AssignExpression      arena[AssignExpression]
+	AwaitExpression       arena[AwaitExpression]
+	BadStatement          arena[BadStatement]
+	BinaryExpression      arena[BinaryExpression]
+	BindingTarget         arena[BindingTarget]
+	BlockStatement        arena[BlockStatement]
+	BooleanLiteral        arena[BooleanLiteral]
+	BreakStatement        arena[BreakStatement]
+	CallExpression        arena[CallExpression]
+	CaseStatement         arena[CaseStatement]
+	CatchStatement        arena[CatchStatement]
+	ClassDeclaration      arena[ClassDeclaration]
+	ClassElement          arena[ClassElement]
+	ClassLiteral          arena[ClassLiteral]
+	ClassStaticBlock      arena[ClassStaticBlock]
+	ComputedProperty      arena[ComputedProperty]
+	ConciseBody           arena[ConciseBody]
+	ConditionalExpression arena[ConditionalExpression]
+	ContinueStatement     arena[ContinueStatement]
+	DebuggerStatement     arena[DebuggerStatement]
+	DoWhileStatement      arena[DoWhileStatement]
+	EmptyStatement        arena[EmptyStatement]
+	ExportDeclaration     arena[ExportDeclaration]
+	ExportSpecifier       arena[ExportSpecifier]
+	Expression            arena[Expression]
+	ExpressionStatement   arena[ExpressionStatement]
+	FieldDefinition       arena[FieldDefinition]
+	ForInStatement        arena[ForInStatement]
+	ForInto               arena[ForInto]
+	ForLoopInitializer    arena[ForLoopInitializer]
+	ForOfStatement        arena[ForOfStatement]
+	ForStatement          arena[ForStatement]
+	FunctionDeclaration   arena[FunctionDeclaration]
+	FunctionLiteral       arena[FunctionLiteral]
+	Identifier            arena[Identifier]
+	IfStatement           arena[IfStatement]
+	ImportDeclaration     arena[ImportDeclaration]
+	ImportSpecifier       arena[ImportSpecifier]
+	InvalidExpression     arena[InvalidExpression]
+	LabelledStatement     arena[LabelledStatement]
+	MemberExpression      arena[MemberExpression]
+	MemberProperty        arena[MemberProperty]
+	MetaProperty          arena[MetaProperty]
+	MethodDefinition      arena[MethodDefinition]
+	NewExpression         arena[NewExpression]
+	NullLiteral           arena[NullLiteral]
+	NumberLiteral         arena[NumberLiteral]
+	ObjectLiteral         arena[ObjectLiteral]
+	ObjectPattern         arena[ObjectPattern]
+	Optional              arena[Optional]
+	OptionalChain         arena[OptionalChain]
+	ParameterList         arena[ParameterList]
+	PrivateDotExpression  arena[PrivateDotExpression]
+	PrivateIdentifier     arena[PrivateIdentifier]
+	Program               arena[Program]
+	Property              arena[Property]
+	PropertyKeyed         arena[PropertyKeyed]
+	PropertyShort         arena[PropertyShort]
+	RegExpLiteral         arena[RegExpLiteral]
+	ReturnStatement       arena[ReturnStatement]
+	SequenceExpression    arena[SequenceExpression]
+	SpreadElement         arena[SpreadElement]
+	Statement             arena[Statement]
+	StringLiteral         arena[StringLiteral]
+	SuperExpression       arena[SuperExpression]
+	SwitchStatement       arena[SwitchStatement]
+	TemplateElement       arena[TemplateElement]
+	TemplateLiteral       arena[TemplateLiteral]
+	ThisExpression        arena[ThisExpression]
+	ThrowStatement        arena[ThrowStatement]
+	TryStatement          arena[TryStatement]
+	UnaryExpression       arena[UnaryExpression]
+	UpdateExpression      arena[UpdateExpression]
+	VariableDeclaration   arena[VariableDeclaration]
+	VariableDeclarator    arena[VariableDeclarator]
+	WhileStatement        arena[WhileStatement]
+	WithStatement         arena[WithStatement]
+	YieldExpression       arena[YieldExpression]
+}
+
+func (n *ArrayLiteral) encode(e *encoder) {
+	e.idx(n.LeftBracket)
+	e.idx(n.RightBracket)
+	n.Value.encode(e)
+}
+
+func (n *ArrayLiteral) decode(d *decoder) {
+	n.LeftBracket = d.idx()
+	n.RightBracket = d.idx()
+	n.Value.decode(d)
+}
+
+func (n *ArrayPattern) encode(e *encoder) {
+	e.idx(n.LeftBracket)
+	e.idx(n.RightBracket)
+	n.Elements.encode(e)
+	if n.Rest == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Rest.Expr)
+	}
+}
+
+func (n *ArrayPattern) decode(d *decoder) {
+	n.LeftBracket = d.idx()
+	n.RightBracket = d.idx()
+	n.Elements.decode(d)
+	if v := d.uint(); v != 0 {
+		n.Rest = d.arenas.Expression.new()
+		n.Rest.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *ArrowFunctionLiteral) encode(e *encoder) {
+	var flags uint64
+	if n.Async {
+		flags |= 1 << 0
+	}
+	if n.ScopeContext != 0 {
+		flags |= 1 << 1
+	}
+	e.uint(flags)
+	e.idx(n.Start)
+	n.ParameterList.encode(e)
+	if n.Body == nil {
+		e.uint(0)
+	} else {
+		encodeBody(e, n.Body.Body)
+	}
+	if n.ScopeContext != 0 {
+		e.int(int64(n.ScopeContext))
+	}
+}
+
+func (n *ArrowFunctionLiteral) decode(d *decoder) {
+	flags := d.uint()
+	n.Start = d.idx()
+	n.ParameterList.decode(d)
+	if v := d.uint(); v != 0 {
+		n.Body = d.arenas.ConciseBody.new()
+		n.Body.Body = decodeBody(d, v)
+	}
+	n.Async = flags&(1<<0) != 0
+	if flags&(1<<1) != 0 {
+		n.ScopeContext = ScopeContext(d.int())
+	}
+}
+
+func (n *AssignExpression) encode(e *encoder) {
+	e.uint(uint64(n.Operator))
+	if n.Left == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Left.Expr)
+	}
+	if n.Right == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Right.Expr)
+	}
+}
+
+func (n *AssignExpression) decode(d *decoder) {
+	n.Operator = token.Token(d.uint())
+	if v := d.uint(); v != 0 {
+		n.Left = d.arenas.Expression.new()
+		n.Left.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Right = d.arenas.Expression.new()
+		n.Right.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *AwaitExpression) encode(e *encoder) {
+	e.idx(n.Await)
+	if n.Argument == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Argument.Expr)
+	}
+}
+
+func (n *AwaitExpression) decode(d *decoder) {
+	n.Await = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Argument = d.arenas.Expression.new()
+		n.Argument.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *BadStatement) encode(e *encoder) {
+	e.idx(n.From)
+	e.idx(n.To)
+}
+
+func (n *BadStatement) decode(d *decoder) {
+	n.From = d.idx()
+	n.To = d.idx()
+}
+
+func (n *BinaryExpression) encode(e *encoder) {
+	e.uint(uint64(n.Operator))
+	if n.Left == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Left.Expr)
+	}
+	if n.Right == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Right.Expr)
+	}
+}
+
+func (n *BinaryExpression) decode(d *decoder) {
+	n.Operator = token.Token(d.uint())
+	if v := d.uint(); v != 0 {
+		n.Left = d.arenas.Expression.new()
+		n.Left.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Right = d.arenas.Expression.new()
+		n.Right.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *BindingTarget) encode(e *encoder) {
+	encodeTarget(e, n.Target)
+}
+
+func (n *BindingTarget) decode(d *decoder) {
+	n.Target = decodeTarget(d, d.uint())
+}
+
+func (n *BlockStatement) encode(e *encoder) {
+	var flags uint64
+	if n.ScopeContext != 0 {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.LeftBrace)
+	n.List.encode(e)
+	e.idx(n.RightBrace)
+	if n.ScopeContext != 0 {
+		e.int(int64(n.ScopeContext))
+	}
+}
+
+func (n *BlockStatement) decode(d *decoder) {
+	flags := d.uint()
+	n.LeftBrace = d.idx()
+	n.List.decode(d)
+	n.RightBrace = d.idx()
+	if flags&(1<<0) != 0 {
+		n.ScopeContext = ScopeContext(d.int())
+	}
+}
+
+func (n *BooleanLiteral) encode(e *encoder) {
+	var flags uint64
+	if n.Value {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.Idx)
+}
+
+func (n *BooleanLiteral) decode(d *decoder) {
+	flags := d.uint()
+	n.Idx = d.idx()
+	n.Value = flags&(1<<0) != 0
+}
+
+func (n *BreakStatement) encode(e *encoder) {
+	var flags uint64
+	if n.Label != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.Idx)
+	if n.Label != nil {
+		n.Label.encode(e)
+	}
+}
+
+func (n *BreakStatement) decode(d *decoder) {
+	flags := d.uint()
+	n.Idx = d.idx()
+	if flags&(1<<0) != 0 {
+		n.Label = d.arenas.Identifier.new()
+		n.Label.decode(d)
+	}
+}
+
+func (n *CallExpression) encode(e *encoder) {
+	if n.Callee == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Callee.Expr)
+	}
+	e.idx(n.LeftParenthesis)
+	n.ArgumentList.encode(e)
+	e.idx(n.RightParenthesis)
+}
+
+func (n *CallExpression) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Callee = d.arenas.Expression.new()
+		n.Callee.Expr = decodeExpr(d, v)
+	}
+	n.LeftParenthesis = d.idx()
+	n.ArgumentList.decode(d)
+	n.RightParenthesis = d.idx()
+}
+
+func (n *CaseStatement) encode(e *encoder) {
+	e.idx(n.Case)
+	if n.Test == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Test.Expr)
+	}
+	n.Consequent.encode(e)
+}
+
+func (n *CaseStatement) decode(d *decoder) {
+	n.Case = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Test = d.arenas.Expression.new()
+		n.Test.Expr = decodeExpr(d, v)
+	}
+	n.Consequent.decode(d)
+}
+
+func (n *CaseStatements) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *CaseStatements) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.CaseStatement.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *CatchStatement) encode(e *encoder) {
+	var flags uint64
+	if n.Body != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.Catch)
+	if n.Parameter == nil {
+		e.uint(0)
+	} else {
+		encodeTarget(e, n.Parameter.Target)
+	}
+	if n.Body != nil {
+		n.Body.encode(e)
+	}
+}
+
+func (n *CatchStatement) decode(d *decoder) {
+	flags := d.uint()
+	n.Catch = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Parameter = d.arenas.BindingTarget.new()
+		n.Parameter.Target = decodeTarget(d, v)
+	}
+	if flags&(1<<0) != 0 {
+		n.Body = d.arenas.BlockStatement.new()
+		n.Body.decode(d)
+	}
+}
+
+func (n *ClassDeclaration) encode(e *encoder) {
+	var flags uint64
+	if n.Class != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	if n.Class != nil {
+		n.Class.encode(e)
+	}
+}
+
+func (n *ClassDeclaration) decode(d *decoder) {
+	flags := d.uint()
+	if flags&(1<<0) != 0 {
+		n.Class = d.arenas.ClassLiteral.new()
+		n.Class.decode(d)
+	}
+}
+
+func (n *ClassElement) encode(e *encoder) {
+	encodeElement(e, n.Element)
+}
+
+func (n *ClassElement) decode(d *decoder) {
+	n.Element = decodeElement(d, d.uint())
+}
+
+func (n *ClassElements) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *ClassElements) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.ClassElement.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *ClassLiteral) encode(e *encoder) {
+	var flags uint64
+	if n.Name != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.Class)
+	e.idx(n.RightBrace)
+	if n.Name != nil {
+		n.Name.encode(e)
+	}
+	if n.SuperClass == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.SuperClass.Expr)
+	}
+	n.Body.encode(e)
+}
+
+func (n *ClassLiteral) decode(d *decoder) {
+	flags := d.uint()
+	n.Class = d.idx()
+	n.RightBrace = d.idx()
+	if flags&(1<<0) != 0 {
+		n.Name = d.arenas.Identifier.new()
+		n.Name.decode(d)
+	}
+	if v := d.uint(); v != 0 {
+		n.SuperClass = d.arenas.Expression.new()
+		n.SuperClass.Expr = decodeExpr(d, v)
+	}
+	n.Body.decode(d)
+}
+
+func (n *ClassStaticBlock) encode(e *encoder) {
+	var flags uint64
+	if n.Block != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.Static)
+	if n.Block != nil {
+		n.Block.encode(e)
+	}
+}
+
+func (n *ClassStaticBlock) decode(d *decoder) {
+	flags := d.uint()
+	n.Static = d.idx()
+	if flags&(1<<0) != 0 {
+		n.Block = d.arenas.BlockStatement.new()
+		n.Block.decode(d)
+	}
+}
+
+func (n *ComputedProperty) encode(e *encoder) {
+	if n.Expr == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Expr.Expr)
+	}
+}
+
+func (n *ComputedProperty) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Expr = d.arenas.Expression.new()
+		n.Expr.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *ConciseBody) encode(e *encoder) {
+	encodeBody(e, n.Body)
+}
+
+func (n *ConciseBody) decode(d *decoder) {
+	n.Body = decodeBody(d, d.uint())
+}
+
+func (n *ConditionalExpression) encode(e *encoder) {
+	if n.Test == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Test.Expr)
+	}
+	if n.Consequent == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Consequent.Expr)
+	}
+	if n.Alternate == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Alternate.Expr)
+	}
+}
+
+func (n *ConditionalExpression) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Test = d.arenas.Expression.new()
+		n.Test.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Consequent = d.arenas.Expression.new()
+		n.Consequent.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Alternate = d.arenas.Expression.new()
+		n.Alternate.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *ContinueStatement) encode(e *encoder) {
+	var flags uint64
+	if n.Label != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.Idx)
+	if n.Label != nil {
+		n.Label.encode(e)
+	}
+}
+
+func (n *ContinueStatement) decode(d *decoder) {
+	flags := d.uint()
+	n.Idx = d.idx()
+	if flags&(1<<0) != 0 {
+		n.Label = d.arenas.Identifier.new()
+		n.Label.decode(d)
+	}
+}
+
+func (n *DebuggerStatement) encode(e *encoder) {
+	e.idx(n.Debugger)
+}
+
+func (n *DebuggerStatement) decode(d *decoder) {
+	n.Debugger = d.idx()
+}
+
+func (n *DoWhileStatement) encode(e *encoder) {
+	e.idx(n.Do)
+	if n.Test == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Test.Expr)
+	}
+	if n.Body == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Body.Stmt)
+	}
+}
+
+func (n *DoWhileStatement) decode(d *decoder) {
+	n.Do = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Test = d.arenas.Expression.new()
+		n.Test.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Body = d.arenas.Statement.new()
+		n.Body.Stmt = decodeStmt(d, v)
+	}
+}
+
+func (n *EmptyStatement) encode(e *encoder) {
+	e.idx(n.Semicolon)
+}
+
+func (n *EmptyStatement) decode(d *decoder) {
+	n.Semicolon = d.idx()
+}
+
+func (n *ExportDeclaration) encode(e *encoder) {
+	var flags uint64
+	if n.Source != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.Export)
+	e.idx(n.From)
+	if n.Source != nil {
+		n.Source.encode(e)
+	}
+	if n.Default == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Default.Expr)
+	}
+	if n.Declaration == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Declaration.Stmt)
+	}
+	n.Specifiers.encode(e)
+}
+
+func (n *ExportDeclaration) decode(d *decoder) {
+	flags := d.uint()
+	n.Export = d.idx()
+	n.From = d.idx()
+	if flags&(1<<0) != 0 {
+		n.Source = d.arenas.StringLiteral.new()
+		n.Source.decode(d)
+	}
+	if v := d.uint(); v != 0 {
+		n.Default = d.arenas.Expression.new()
+		n.Default.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Declaration = d.arenas.Statement.new()
+		n.Declaration.Stmt = decodeStmt(d, v)
+	}
+	n.Specifiers.decode(d)
+}
+
+func (n *ExportSpecifier) encode(e *encoder) {
+	var flags uint64
+	if n.Local != nil {
+		flags |= 1 << 0
+	}
+	if n.Exported != nil {
+		flags |= 1 << 1
+	}
+	e.uint(flags)
+	if n.Local != nil {
+		n.Local.encode(e)
+	}
+	if n.Exported != nil {
+		n.Exported.encode(e)
+	}
+}
+
+func (n *ExportSpecifier) decode(d *decoder) {
+	flags := d.uint()
+	if flags&(1<<0) != 0 {
+		n.Local = d.arenas.Identifier.new()
+		n.Local.decode(d)
+	}
+	if flags&(1<<1) != 0 {
+		n.Exported = d.arenas.Identifier.new()
+		n.Exported.decode(d)
+	}
+}
+
+func (n *ExportSpecifiers) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *ExportSpecifiers) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.ExportSpecifier.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *Expression) encode(e *encoder) {
+	encodeExpr(e, n.Expr)
+}
+
+func (n *Expression) decode(d *decoder) {
+	n.Expr = decodeExpr(d, d.uint())
+}
+
+func (n *ExpressionStatement) encode(e *encoder) {
+	if n.Expression == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Expression.Expr)
+	}
+	e.string(string(n.Comment))
+}
+
+func (n *ExpressionStatement) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Expression = d.arenas.Expression.new()
+		n.Expression.Expr = decodeExpr(d, v)
+	}
+	n.Comment = d.string()
+}
+
+func (n *Expressions) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *Expressions) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.Expression.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *FieldDefinition) encode(e *encoder) {
+	var flags uint64
+	if n.Computed {
+		flags |= 1 << 0
+	}
+	if n.Static {
+		flags |= 1 << 1
+	}
+	e.uint(flags)
+	e.idx(n.Idx)
+	if n.Key == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Key.Expr)
+	}
+	if n.Initializer == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Initializer.Expr)
+	}
+}
+
+func (n *FieldDefinition) decode(d *decoder) {
+	flags := d.uint()
+	n.Idx = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Key = d.arenas.Expression.new()
+		n.Key.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Initializer = d.arenas.Expression.new()
+		n.Initializer.Expr = decodeExpr(d, v)
+	}
+	n.Computed = flags&(1<<0) != 0
+	n.Static = flags&(1<<1) != 0
+}
+
+func (n *ForInStatement) encode(e *encoder) {
+	e.idx(n.For)
+	if n.Into == nil {
+		e.uint(0)
+	} else {
+		encodeInto(e, n.Into.Into)
+	}
+	if n.Source == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Source.Expr)
+	}
+	if n.Body == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Body.Stmt)
+	}
+}
+
+func (n *ForInStatement) decode(d *decoder) {
+	n.For = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Into = d.arenas.ForInto.new()
+		n.Into.Into = decodeInto(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Source = d.arenas.Expression.new()
+		n.Source.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Body = d.arenas.Statement.new()
+		n.Body.Stmt = decodeStmt(d, v)
+	}
+}
+
+func (n *ForInto) encode(e *encoder) {
+	encodeInto(e, n.Into)
+}
+
+func (n *ForInto) decode(d *decoder) {
+	n.Into = decodeInto(d, d.uint())
+}
+
+func (n *ForLoopInitializer) encode(e *encoder) {
+	encodeForLoopInit(e, n.Initializer)
+}
+
+func (n *ForLoopInitializer) decode(d *decoder) {
+	n.Initializer = decodeForLoopInit(d, d.uint())
+}
+
+func (n *ForOfStatement) encode(e *encoder) {
+	e.idx(n.For)
+	if n.Into == nil {
+		e.uint(0)
+	} else {
+		encodeInto(e, n.Into.Into)
+	}
+	if n.Source == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Source.Expr)
+	}
+	if n.Body == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Body.Stmt)
+	}
+}
+
+func (n *ForOfStatement) decode(d *decoder) {
+	n.For = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Into = d.arenas.ForInto.new()
+		n.Into.Into = decodeInto(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Source = d.arenas.Expression.new()
+		n.Source.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Body = d.arenas.Statement.new()
+		n.Body.Stmt = decodeStmt(d, v)
+	}
+}
+
+func (n *ForStatement) encode(e *encoder) {
+	e.idx(n.For)
+	if n.Initializer == nil {
+		e.uint(0)
+	} else {
+		encodeForLoopInit(e, n.Initializer.Initializer)
+	}
+	if n.Update == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Update.Expr)
+	}
+	if n.Test == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Test.Expr)
+	}
+	if n.Body == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Body.Stmt)
+	}
+}
+
+func (n *ForStatement) decode(d *decoder) {
+	n.For = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Initializer = d.arenas.ForLoopInitializer.new()
+		n.Initializer.Initializer = decodeForLoopInit(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Update = d.arenas.Expression.new()
+		n.Update.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Test = d.arenas.Expression.new()
+		n.Test.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Body = d.arenas.Statement.new()
+		n.Body.Stmt = decodeStmt(d, v)
+	}
+}
+
+func (n *FunctionDeclaration) encode(e *encoder) {
+	var flags uint64
+	if n.Function != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	if n.Function != nil {
+		n.Function.encode(e)
+	}
+}
+
+func (n *FunctionDeclaration) decode(d *decoder) {
+	flags := d.uint()
+	if flags&(1<<0) != 0 {
+		n.Function = d.arenas.FunctionLiteral.new()
+		n.Function.decode(d)
+	}
+}
+
+func (n *FunctionLiteral) encode(e *encoder) {
+	var flags uint64
+	if n.Name != nil {
+		flags |= 1 << 0
+	}
+	if n.Body != nil {
+		flags |= 1 << 1
+	}
+	if n.Async {
+		flags |= 1 << 2
+	}
+	if n.Generator {
+		flags |= 1 << 3
+	}
+	if n.ScopeContext != 0 {
+		flags |= 1 << 4
+	}
+	e.uint(flags)
+	e.idx(n.Function)
+	if n.Name != nil {
+		n.Name.encode(e)
+	}
+	n.ParameterList.encode(e)
+	if n.Body != nil {
+		n.Body.encode(e)
+	}
+	if n.ScopeContext != 0 {
+		e.int(int64(n.ScopeContext))
+	}
+}
+
+func (n *FunctionLiteral) decode(d *decoder) {
+	flags := d.uint()
+	n.Function = d.idx()
+	if flags&(1<<0) != 0 {
+		n.Name = d.arenas.Identifier.new()
+		n.Name.decode(d)
+	}
+	n.ParameterList.decode(d)
+	if flags&(1<<1) != 0 {
+		n.Body = d.arenas.BlockStatement.new()
+		n.Body.decode(d)
+	}
+	n.Async = flags&(1<<2) != 0
+	n.Generator = flags&(1<<3) != 0
+	if flags&(1<<4) != 0 {
+		n.ScopeContext = ScopeContext(d.int())
+	}
+}
+
+func (n *Identifier) encode(e *encoder) {
+	var flags uint64
+	if n.ScopeContext != 0 {
+		flags |= 1 << 0
+	}
+	e.flaggedString(string(n.Name), flags, 1)
+	e.idx(n.Idx)
+	if n.ScopeContext != 0 {
+		e.int(int64(n.ScopeContext))
+	}
+}
+
+func (n *Identifier) decode(d *decoder) {
+	str, flags := d.flaggedString(1)
+	n.Name = str
+	n.Idx = d.idx()
+	if flags&(1<<0) != 0 {
+		n.ScopeContext = ScopeContext(d.int())
+	}
+}
+
+func (n *IfStatement) encode(e *encoder) {
+	e.idx(n.If)
+	if n.Test == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Test.Expr)
+	}
+	if n.Consequent == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Consequent.Stmt)
+	}
+	if n.Alternate == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Alternate.Stmt)
+	}
+}
+
+func (n *IfStatement) decode(d *decoder) {
+	n.If = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Test = d.arenas.Expression.new()
+		n.Test.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Consequent = d.arenas.Statement.new()
+		n.Consequent.Stmt = decodeStmt(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Alternate = d.arenas.Statement.new()
+		n.Alternate.Stmt = decodeStmt(d, v)
+	}
+}
+
+func (n *ImportDeclaration) encode(e *encoder) {
+	var flags uint64
+	if n.Source != nil {
+		flags |= 1 << 0
+	}
+	if n.Default != nil {
+		flags |= 1 << 1
+	}
+	if n.Namespace != nil {
+		flags |= 1 << 2
+	}
+	e.uint(flags)
+	e.idx(n.Import)
+	e.idx(n.From)
+	if n.Source != nil {
+		n.Source.encode(e)
+	}
+	if n.Default != nil {
+		n.Default.encode(e)
+	}
+	if n.Namespace != nil {
+		n.Namespace.encode(e)
+	}
+	n.Specifiers.encode(e)
+}
+
+func (n *ImportDeclaration) decode(d *decoder) {
+	flags := d.uint()
+	n.Import = d.idx()
+	n.From = d.idx()
+	if flags&(1<<0) != 0 {
+		n.Source = d.arenas.StringLiteral.new()
+		n.Source.decode(d)
+	}
+	if flags&(1<<1) != 0 {
+		n.Default = d.arenas.Identifier.new()
+		n.Default.decode(d)
+	}
+	if flags&(1<<2) != 0 {
+		n.Namespace = d.arenas.Identifier.new()
+		n.Namespace.decode(d)
+	}
+	n.Specifiers.decode(d)
+}
+
+func (n *ImportSpecifier) encode(e *encoder) {
+	var flags uint64
+	if n.Imported != nil {
+		flags |= 1 << 0
+	}
+	if n.Local != nil {
+		flags |= 1 << 1
+	}
+	e.uint(flags)
+	if n.Imported != nil {
+		n.Imported.encode(e)
+	}
+	if n.Local != nil {
+		n.Local.encode(e)
+	}
+}
+
+func (n *ImportSpecifier) decode(d *decoder) {
+	flags := d.uint()
+	if flags&(1<<0) != 0 {
+		n.Imported = d.arenas.Identifier.new()
+		n.Imported.decode(d)
+	}
+	if flags&(1<<1) != 0 {
+		n.Local = d.arenas.Identifier.new()
+		n.Local.decode(d)
+	}
+}
+
+func (n *ImportSpecifiers) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *ImportSpecifiers) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.ImportSpecifier.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *InvalidExpression) encode(e *encoder) {
+	e.idx(n.From)
+	e.idx(n.To)
+}
+
+func (n *InvalidExpression) decode(d *decoder) {
+	n.From = d.idx()
+	n.To = d.idx()
+}
+
+func (n *LabelledStatement) encode(e *encoder) {
+	var flags uint64
+	if n.Label != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	if n.Label != nil {
+		n.Label.encode(e)
+	}
+	e.idx(n.Colon)
+	if n.Statement == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Statement.Stmt)
+	}
+}
+
+func (n *LabelledStatement) decode(d *decoder) {
+	flags := d.uint()
+	if flags&(1<<0) != 0 {
+		n.Label = d.arenas.Identifier.new()
+		n.Label.decode(d)
+	}
+	n.Colon = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Statement = d.arenas.Statement.new()
+		n.Statement.Stmt = decodeStmt(d, v)
+	}
+}
+
+func (n *MemberExpression) encode(e *encoder) {
+	if n.Object == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Object.Expr)
+	}
+	if n.Property == nil {
+		e.uint(0)
+	} else {
+		encodeMemberProp(e, n.Property.Prop)
+	}
+}
+
+func (n *MemberExpression) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Object = d.arenas.Expression.new()
+		n.Object.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Property = d.arenas.MemberProperty.new()
+		n.Property.Prop = decodeMemberProp(d, v)
+	}
+}
+
+func (n *MemberProperty) encode(e *encoder) {
+	encodeMemberProp(e, n.Prop)
+}
+
+func (n *MemberProperty) decode(d *decoder) {
+	n.Prop = decodeMemberProp(d, d.uint())
+}
+
+func (n *MetaProperty) encode(e *encoder) {
+	var flags uint64
+	if n.Meta != nil {
+		flags |= 1 << 0
+	}
+	if n.Property != nil {
+		flags |= 1 << 1
+	}
+	e.uint(flags)
+	if n.Meta != nil {
+		n.Meta.encode(e)
+	}
+	if n.Property != nil {
+		n.Property.encode(e)
+	}
+	e.idx(n.Idx)
+}
+
+func (n *MetaProperty) decode(d *decoder) {
+	flags := d.uint()
+	if flags&(1<<0) != 0 {
+		n.Meta = d.arenas.Identifier.new()
+		n.Meta.decode(d)
+	}
+	if flags&(1<<1) != 0 {
+		n.Property = d.arenas.Identifier.new()
+		n.Property.decode(d)
+	}
+	n.Idx = d.idx()
+}
+
+func (n *MethodDefinition) encode(e *encoder) {
+	var flags uint64
+	if n.Body != nil {
+		flags |= 1 << 0
+	}
+	if n.Computed {
+		flags |= 1 << 1
+	}
+	if n.Static {
+		flags |= 1 << 2
+	}
+	e.flaggedString(string(n.Kind), flags, 3)
+	e.idx(n.Idx)
+	if n.Key == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Key.Expr)
+	}
+	if n.Body != nil {
+		n.Body.encode(e)
+	}
+}
+
+func (n *MethodDefinition) decode(d *decoder) {
+	str, flags := d.flaggedString(3)
+	n.Kind = PropertyKind(str)
+	n.Idx = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Key = d.arenas.Expression.new()
+		n.Key.Expr = decodeExpr(d, v)
+	}
+	if flags&(1<<0) != 0 {
+		n.Body = d.arenas.FunctionLiteral.new()
+		n.Body.decode(d)
+	}
+	n.Computed = flags&(1<<1) != 0
+	n.Static = flags&(1<<2) != 0
+}
+
+func (n *NewExpression) encode(e *encoder) {
+	e.idx(n.New)
+	if n.Callee == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Callee.Expr)
+	}
+	e.idx(n.LeftParenthesis)
+	n.ArgumentList.encode(e)
+	e.idx(n.RightParenthesis)
+}
+
+func (n *NewExpression) decode(d *decoder) {
+	n.New = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Callee = d.arenas.Expression.new()
+		n.Callee.Expr = decodeExpr(d, v)
+	}
+	n.LeftParenthesis = d.idx()
+	n.ArgumentList.decode(d)
+	n.RightParenthesis = d.idx()
+}
+
+func (n *NullLiteral) encode(e *encoder) {
+	e.idx(n.Idx)
+}
+
+func (n *NullLiteral) decode(d *decoder) {
+	n.Idx = d.idx()
+}
+
+func (n *NumberLiteral) encode(e *encoder) {
+	e.idx(n.Idx)
+	e.float(n.Value)
+	e.raw(n.Raw)
+}
+
+func (n *NumberLiteral) decode(d *decoder) {
+	n.Idx = d.idx()
+	n.Value = d.float()
+	n.Raw = d.raw()
+}
+
+func (n *ObjectLiteral) encode(e *encoder) {
+	e.idx(n.LeftBrace)
+	e.idx(n.RightBrace)
+	n.Value.encode(e)
+}
+
+func (n *ObjectLiteral) decode(d *decoder) {
+	n.LeftBrace = d.idx()
+	n.RightBrace = d.idx()
+	n.Value.decode(d)
+}
+
+func (n *ObjectPattern) encode(e *encoder) {
+	e.idx(n.LeftBrace)
+	e.idx(n.RightBrace)
+	n.Properties.encode(e)
+	encodeExpr(e, n.Rest)
+}
+
+func (n *ObjectPattern) decode(d *decoder) {
+	n.LeftBrace = d.idx()
+	n.RightBrace = d.idx()
+	n.Properties.decode(d)
+	n.Rest = decodeExpr(d, d.uint())
+}
+
+func (n *Optional) encode(e *encoder) {
+	if n.Expr == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Expr.Expr)
+	}
+}
+
+func (n *Optional) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Expr = d.arenas.Expression.new()
+		n.Expr.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *OptionalChain) encode(e *encoder) {
+	if n.Base == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Base.Expr)
+	}
+}
+
+func (n *OptionalChain) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Base = d.arenas.Expression.new()
+		n.Base.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *ParameterList) encode(e *encoder) {
+	e.idx(n.Opening)
+	n.List.encode(e)
+	encodeExpr(e, n.Rest)
+	e.idx(n.Closing)
+}
+
+func (n *ParameterList) decode(d *decoder) {
+	n.Opening = d.idx()
+	n.List.decode(d)
+	n.Rest = decodeExpr(d, d.uint())
+	n.Closing = d.idx()
+}
+
+func (n *PrivateDotExpression) encode(e *encoder) {
+	var flags uint64
+	if n.Identifier != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	if n.Left == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Left.Expr)
+	}
+	if n.Identifier != nil {
+		n.Identifier.encode(e)
+	}
+}
+
+func (n *PrivateDotExpression) decode(d *decoder) {
+	flags := d.uint()
+	if v := d.uint(); v != 0 {
+		n.Left = d.arenas.Expression.new()
+		n.Left.Expr = decodeExpr(d, v)
+	}
+	if flags&(1<<0) != 0 {
+		n.Identifier = d.arenas.PrivateIdentifier.new()
+		n.Identifier.decode(d)
+	}
+}
+
+func (n *PrivateIdentifier) encode(e *encoder) {
+	var flags uint64
+	if n.Identifier != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	if n.Identifier != nil {
+		n.Identifier.encode(e)
+	}
+}
+
+func (n *PrivateIdentifier) decode(d *decoder) {
+	flags := d.uint()
+	if flags&(1<<0) != 0 {
+		n.Identifier = d.arenas.Identifier.new()
+		n.Identifier.decode(d)
+	}
+}
+
+func (n *Program) encode(e *encoder) {
+	n.Body.encode(e)
+}
+
+func (n *Program) decode(d *decoder) {
+	n.Body.decode(d)
+}
+
+func (n *Properties) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *Properties) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.Property.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *Property) encode(e *encoder) {
+	encodeProp(e, n.Prop)
+}
+
+func (n *Property) decode(d *decoder) {
+	n.Prop = decodeProp(d, d.uint())
+}
+
+func (n *PropertyKeyed) encode(e *encoder) {
+	var flags uint64
+	if n.Computed {
+		flags |= 1 << 0
+	}
+	e.flaggedString(string(n.Kind), flags, 1)
+	if n.Key == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Key.Expr)
+	}
+	if n.Value == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Value.Expr)
+	}
+}
+
+func (n *PropertyKeyed) decode(d *decoder) {
+	str, flags := d.flaggedString(1)
+	n.Kind = PropertyKind(str)
+	if v := d.uint(); v != 0 {
+		n.Key = d.arenas.Expression.new()
+		n.Key.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Value = d.arenas.Expression.new()
+		n.Value.Expr = decodeExpr(d, v)
+	}
+	n.Computed = flags&(1<<0) != 0
+}
+
+func (n *PropertyShort) encode(e *encoder) {
+	var flags uint64
+	if n.Name != nil {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	if n.Name != nil {
+		n.Name.encode(e)
+	}
+	if n.Initializer == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Initializer.Expr)
+	}
+}
+
+func (n *PropertyShort) decode(d *decoder) {
+	flags := d.uint()
+	if flags&(1<<0) != 0 {
+		n.Name = d.arenas.Identifier.new()
+		n.Name.decode(d)
+	}
+	if v := d.uint(); v != 0 {
+		n.Initializer = d.arenas.Expression.new()
+		n.Initializer.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *RegExpLiteral) encode(e *encoder) {
+	e.idx(n.Idx)
+	e.string(string(n.Literal))
+	e.string(string(n.Pattern))
+	e.string(string(n.Flags))
+}
+
+func (n *RegExpLiteral) decode(d *decoder) {
+	n.Idx = d.idx()
+	n.Literal = d.string()
+	n.Pattern = d.string()
+	n.Flags = d.string()
+}
+
+func (n *ReturnStatement) encode(e *encoder) {
+	e.idx(n.Return)
+	if n.Argument == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Argument.Expr)
+	}
+}
+
+func (n *ReturnStatement) decode(d *decoder) {
+	n.Return = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Argument = d.arenas.Expression.new()
+		n.Argument.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *SequenceExpression) encode(e *encoder) {
+	n.Sequence.encode(e)
+}
+
+func (n *SequenceExpression) decode(d *decoder) {
+	n.Sequence.decode(d)
+}
+
+func (n *SpreadElement) encode(e *encoder) {
+	if n.Expression == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Expression.Expr)
+	}
+}
+
+func (n *SpreadElement) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Expression = d.arenas.Expression.new()
+		n.Expression.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *Statement) encode(e *encoder) {
+	encodeStmt(e, n.Stmt)
+}
+
+func (n *Statement) decode(d *decoder) {
+	n.Stmt = decodeStmt(d, d.uint())
+}
+
+func (n *Statements) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *Statements) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.Statement.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *StringLiteral) encode(e *encoder) {
+	e.idx(n.Idx)
+	e.string(string(n.Value))
+	e.raw(n.Raw)
+}
+
+func (n *StringLiteral) decode(d *decoder) {
+	n.Idx = d.idx()
+	n.Value = d.string()
+	n.Raw = d.raw()
+}
+
+func (n *SuperExpression) encode(e *encoder) {
+	e.idx(n.Idx)
+}
+
+func (n *SuperExpression) decode(d *decoder) {
+	n.Idx = d.idx()
+}
+
+func (n *SwitchStatement) encode(e *encoder) {
+	e.idx(n.Switch)
+	if n.Discriminant == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Discriminant.Expr)
+	}
+	e.int(int64(n.Default))
+	n.Body.encode(e)
+}
+
+func (n *SwitchStatement) decode(d *decoder) {
+	n.Switch = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Discriminant = d.arenas.Expression.new()
+		n.Discriminant.Expr = decodeExpr(d, v)
+	}
+	n.Default = int(d.int())
+	n.Body.decode(d)
+}
+
+func (n *TemplateElement) encode(e *encoder) {
+	var flags uint64
+	if n.Valid {
+		flags |= 1 << 0
+	}
+	e.flaggedString(string(n.Literal), flags, 1)
+	e.idx(n.Idx)
+	e.string(string(n.Parsed))
+}
+
+func (n *TemplateElement) decode(d *decoder) {
+	str, flags := d.flaggedString(1)
+	n.Literal = str
+	n.Idx = d.idx()
+	n.Parsed = d.string()
+	n.Valid = flags&(1<<0) != 0
+}
+
+func (n *TemplateElements) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *TemplateElements) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.TemplateElement.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *TemplateLiteral) encode(e *encoder) {
+	e.idx(n.OpenQuote)
+	e.idx(n.CloseQuote)
+	if n.Tag == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Tag.Expr)
+	}
+	n.Elements.encode(e)
+	n.Expressions.encode(e)
+}
+
+func (n *TemplateLiteral) decode(d *decoder) {
+	n.OpenQuote = d.idx()
+	n.CloseQuote = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Tag = d.arenas.Expression.new()
+		n.Tag.Expr = decodeExpr(d, v)
+	}
+	n.Elements.decode(d)
+	n.Expressions.decode(d)
+}
+
+func (n *ThisExpression) encode(e *encoder) {
+	e.idx(n.Idx)
+}
+
+func (n *ThisExpression) decode(d *decoder) {
+	n.Idx = d.idx()
+}
+
+func (n *ThrowStatement) encode(e *encoder) {
+	e.idx(n.Throw)
+	if n.Argument == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Argument.Expr)
+	}
+}
+
+func (n *ThrowStatement) decode(d *decoder) {
+	n.Throw = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Argument = d.arenas.Expression.new()
+		n.Argument.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *TryStatement) encode(e *encoder) {
+	var flags uint64
+	if n.Body != nil {
+		flags |= 1 << 0
+	}
+	if n.Catch != nil {
+		flags |= 1 << 1
+	}
+	if n.Finally != nil {
+		flags |= 1 << 2
+	}
+	e.uint(flags)
+	e.idx(n.Try)
+	if n.Body != nil {
+		n.Body.encode(e)
+	}
+	if n.Catch != nil {
+		n.Catch.encode(e)
+	}
+	if n.Finally != nil {
+		n.Finally.encode(e)
+	}
+}
+
+func (n *TryStatement) decode(d *decoder) {
+	flags := d.uint()
+	n.Try = d.idx()
+	if flags&(1<<0) != 0 {
+		n.Body = d.arenas.BlockStatement.new()
+		n.Body.decode(d)
+	}
+	if flags&(1<<1) != 0 {
+		n.Catch = d.arenas.CatchStatement.new()
+		n.Catch.decode(d)
+	}
+	if flags&(1<<2) != 0 {
+		n.Finally = d.arenas.BlockStatement.new()
+		n.Finally.decode(d)
+	}
+}
+
+func (n *UnaryExpression) encode(e *encoder) {
+	e.uint(uint64(n.Operator))
+	e.idx(n.Idx)
+	if n.Operand == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Operand.Expr)
+	}
+}
+
+func (n *UnaryExpression) decode(d *decoder) {
+	n.Operator = token.Token(d.uint())
+	n.Idx = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Operand = d.arenas.Expression.new()
+		n.Operand.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *UpdateExpression) encode(e *encoder) {
+	var flags uint64
+	if n.Postfix {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.uint(uint64(n.Operator))
+	e.idx(n.Idx)
+	if n.Operand == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Operand.Expr)
+	}
+}
+
+func (n *UpdateExpression) decode(d *decoder) {
+	flags := d.uint()
+	n.Operator = token.Token(d.uint())
+	n.Idx = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Operand = d.arenas.Expression.new()
+		n.Operand.Expr = decodeExpr(d, v)
+	}
+	n.Postfix = flags&(1<<0) != 0
+}
+
+func (n *VariableDeclaration) encode(e *encoder) {
+	e.idx(n.Idx)
+	e.uint(uint64(n.Token))
+	n.List.encode(e)
+	e.string(string(n.Comment))
+}
+
+func (n *VariableDeclaration) decode(d *decoder) {
+	n.Idx = d.idx()
+	n.Token = token.Token(d.uint())
+	n.List.decode(d)
+	n.Comment = d.string()
+}
+
+func (n *VariableDeclarator) encode(e *encoder) {
+	if n.Target == nil {
+		e.uint(0)
+	} else {
+		encodeTarget(e, n.Target.Target)
+	}
+	if n.Initializer == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Initializer.Expr)
+	}
+}
+
+func (n *VariableDeclarator) decode(d *decoder) {
+	if v := d.uint(); v != 0 {
+		n.Target = d.arenas.BindingTarget.new()
+		n.Target.Target = decodeTarget(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Initializer = d.arenas.Expression.new()
+		n.Initializer.Expr = decodeExpr(d, v)
+	}
+}
+
+func (n *VariableDeclarators) encode(e *encoder) {
+	if e.length(*n == nil, len(*n)) {
+		for i := range *n {
+			(*n)[i].encode(e)
+		}
+	}
+}
+
+func (n *VariableDeclarators) decode(d *decoder) {
+	if l, ok := d.length(); ok {
+		*n = d.arenas.VariableDeclarator.slice(l)
+		for i := range *n {
+			(*n)[i].decode(d)
+		}
+	}
+}
+
+func (n *WhileStatement) encode(e *encoder) {
+	e.idx(n.While)
+	if n.Test == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Test.Expr)
+	}
+	if n.Body == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Body.Stmt)
+	}
+}
+
+func (n *WhileStatement) decode(d *decoder) {
+	n.While = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Test = d.arenas.Expression.new()
+		n.Test.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Body = d.arenas.Statement.new()
+		n.Body.Stmt = decodeStmt(d, v)
+	}
+}
+
+func (n *WithStatement) encode(e *encoder) {
+	e.idx(n.With)
+	if n.Object == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Object.Expr)
+	}
+	if n.Body == nil {
+		e.uint(0)
+	} else {
+		encodeStmt(e, n.Body.Stmt)
+	}
+}
+
+func (n *WithStatement) decode(d *decoder) {
+	n.With = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Object = d.arenas.Expression.new()
+		n.Object.Expr = decodeExpr(d, v)
+	}
+	if v := d.uint(); v != 0 {
+		n.Body = d.arenas.Statement.new()
+		n.Body.Stmt = decodeStmt(d, v)
+	}
+}
+
+func (n *YieldExpression) encode(e *encoder) {
+	var flags uint64
+	if n.Delegate {
+		flags |= 1 << 0
+	}
+	e.uint(flags)
+	e.idx(n.Yield)
+	if n.Argument == nil {
+		e.uint(0)
+	} else {
+		encodeExpr(e, n.Argument.Expr)
+	}
+}
+
+func (n *YieldExpression) decode(d *decoder) {
+	flags := d.uint()
+	n.Yield = d.idx()
+	if v := d.uint(); v != 0 {
+		n.Argument = d.arenas.Expression.new()
+		n.Argument.Expr = decodeExpr(d, v)
+	}
+	n.Delegate = flags&(1<<0) != 0
+}
+
+func encodeBody(e *encoder, n Body) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *BlockStatement:
+		e.uint(2)
+		n.encode(e)
+	case *Expression:
+		e.uint(3)
+		n.encode(e)
+	default:
+		e.fail("unexpected Body variant %T", n)
+	}
+}
+
+func decodeBody(d *decoder, v uint64) Body {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.BlockStatement.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.Expression.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown Body variant %d", v)
+		return nil
+	}
+}
+
+func encodeElement(e *encoder, n Element) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *ClassStaticBlock:
+		e.uint(2)
+		n.encode(e)
+	case *FieldDefinition:
+		e.uint(3)
+		n.encode(e)
+	case *MethodDefinition:
+		e.uint(4)
+		n.encode(e)
+	default:
+		e.fail("unexpected Element variant %T", n)
+	}
+}
+
+func decodeElement(d *decoder, v uint64) Element {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.ClassStaticBlock.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.FieldDefinition.new()
+		n.decode(d)
+		return n
+	case 4:
+		n := d.arenas.MethodDefinition.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown Element variant %d", v)
+		return nil
+	}
+}
+
+func encodeExpr(e *encoder, n Expr) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *ArrayLiteral:
+		e.uint(2)
+		n.encode(e)
+	case *ArrayPattern:
+		e.uint(3)
+		n.encode(e)
+	case *ArrowFunctionLiteral:
+		e.uint(4)
+		n.encode(e)
+	case *AssignExpression:
+		e.uint(5)
+		n.encode(e)
+	case *AwaitExpression:
+		e.uint(6)
+		n.encode(e)
+	case *BinaryExpression:
+		e.uint(7)
+		n.encode(e)
+	case *BooleanLiteral:
+		e.uint(8)
+		n.encode(e)
+	case *CallExpression:
+		e.uint(9)
+		n.encode(e)
+	case *ClassLiteral:
+		e.uint(10)
+		n.encode(e)
+	case *ConditionalExpression:
+		e.uint(11)
+		n.encode(e)
+	case *FunctionLiteral:
+		e.uint(12)
+		n.encode(e)
+	case *Identifier:
+		e.uint(13)
+		n.encode(e)
+	case *InvalidExpression:
+		e.uint(14)
+		n.encode(e)
+	case *MemberExpression:
+		e.uint(15)
+		n.encode(e)
+	case *MetaProperty:
+		e.uint(16)
+		n.encode(e)
+	case *NewExpression:
+		e.uint(17)
+		n.encode(e)
+	case *NullLiteral:
+		e.uint(18)
+		n.encode(e)
+	case *NumberLiteral:
+		e.uint(19)
+		n.encode(e)
+	case *ObjectLiteral:
+		e.uint(20)
+		n.encode(e)
+	case *ObjectPattern:
+		e.uint(21)
+		n.encode(e)
+	case *Optional:
+		e.uint(22)
+		n.encode(e)
+	case *OptionalChain:
+		e.uint(23)
+		n.encode(e)
+	case *PrivateDotExpression:
+		e.uint(24)
+		n.encode(e)
+	case *PrivateIdentifier:
+		e.uint(25)
+		n.encode(e)
+	case *PropertyKeyed:
+		e.uint(26)
+		n.encode(e)
+	case *PropertyShort:
+		e.uint(27)
+		n.encode(e)
+	case *RegExpLiteral:
+		e.uint(28)
+		n.encode(e)
+	case *SequenceExpression:
+		e.uint(29)
+		n.encode(e)
+	case *SpreadElement:
+		e.uint(30)
+		n.encode(e)
+	case *StringLiteral:
+		e.uint(31)
+		n.encode(e)
+	case *SuperExpression:
+		e.uint(32)
+		n.encode(e)
+	case *TemplateLiteral:
+		e.uint(33)
+		n.encode(e)
+	case *ThisExpression:
+		e.uint(34)
+		n.encode(e)
+	case *UnaryExpression:
+		e.uint(35)
+		n.encode(e)
+	case *UpdateExpression:
+		e.uint(36)
+		n.encode(e)
+	case *VariableDeclarator:
+		e.uint(37)
+		n.encode(e)
+	case *YieldExpression:
+		e.uint(38)
+		n.encode(e)
+	default:
+		e.fail("unexpected Expr variant %T", n)
+	}
+}
+
+func decodeExpr(d *decoder, v uint64) Expr {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.ArrayLiteral.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.ArrayPattern.new()
+		n.decode(d)
+		return n
+	case 4:
+		n := d.arenas.ArrowFunctionLiteral.new()
+		n.decode(d)
+		return n
+	case 5:
+		n := d.arenas.AssignExpression.new()
+		n.decode(d)
+		return n
+	case 6:
+		n := d.arenas.AwaitExpression.new()
+		n.decode(d)
+		return n
+	case 7:
+		n := d.arenas.BinaryExpression.new()
+		n.decode(d)
+		return n
+	case 8:
+		n := d.arenas.BooleanLiteral.new()
+		n.decode(d)
+		return n
+	case 9:
+		n := d.arenas.CallExpression.new()
+		n.decode(d)
+		return n
+	case 10:
+		n := d.arenas.ClassLiteral.new()
+		n.decode(d)
+		return n
+	case 11:
+		n := d.arenas.ConditionalExpression.new()
+		n.decode(d)
+		return n
+	case 12:
+		n := d.arenas.FunctionLiteral.new()
+		n.decode(d)
+		return n
+	case 13:
+		n := d.arenas.Identifier.new()
+		n.decode(d)
+		return n
+	case 14:
+		n := d.arenas.InvalidExpression.new()
+		n.decode(d)
+		return n
+	case 15:
+		n := d.arenas.MemberExpression.new()
+		n.decode(d)
+		return n
+	case 16:
+		n := d.arenas.MetaProperty.new()
+		n.decode(d)
+		return n
+	case 17:
+		n := d.arenas.NewExpression.new()
+		n.decode(d)
+		return n
+	case 18:
+		n := d.arenas.NullLiteral.new()
+		n.decode(d)
+		return n
+	case 19:
+		n := d.arenas.NumberLiteral.new()
+		n.decode(d)
+		return n
+	case 20:
+		n := d.arenas.ObjectLiteral.new()
+		n.decode(d)
+		return n
+	case 21:
+		n := d.arenas.ObjectPattern.new()
+		n.decode(d)
+		return n
+	case 22:
+		n := d.arenas.Optional.new()
+		n.decode(d)
+		return n
+	case 23:
+		n := d.arenas.OptionalChain.new()
+		n.decode(d)
+		return n
+	case 24:
+		n := d.arenas.PrivateDotExpression.new()
+		n.decode(d)
+		return n
+	case 25:
+		n := d.arenas.PrivateIdentifier.new()
+		n.decode(d)
+		return n
+	case 26:
+		n := d.arenas.PropertyKeyed.new()
+		n.decode(d)
+		return n
+	case 27:
+		n := d.arenas.PropertyShort.new()
+		n.decode(d)
+		return n
+	case 28:
+		n := d.arenas.RegExpLiteral.new()
+		n.decode(d)
+		return n
+	case 29:
+		n := d.arenas.SequenceExpression.new()
+		n.decode(d)
+		return n
+	case 30:
+		n := d.arenas.SpreadElement.new()
+		n.decode(d)
+		return n
+	case 31:
+		n := d.arenas.StringLiteral.new()
+		n.decode(d)
+		return n
+	case 32:
+		n := d.arenas.SuperExpression.new()
+		n.decode(d)
+		return n
+	case 33:
+		n := d.arenas.TemplateLiteral.new()
+		n.decode(d)
+		return n
+	case 34:
+		n := d.arenas.ThisExpression.new()
+		n.decode(d)
+		return n
+	case 35:
+		n := d.arenas.UnaryExpression.new()
+		n.decode(d)
+		return n
+	case 36:
+		n := d.arenas.UpdateExpression.new()
+		n.decode(d)
+		return n
+	case 37:
+		n := d.arenas.VariableDeclarator.new()
+		n.decode(d)
+		return n
+	case 38:
+		n := d.arenas.YieldExpression.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown Expr variant %d", v)
+		return nil
+	}
+}
+
+func encodeForLoopInit(e *encoder, n ForLoopInit) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *Expression:
+		e.uint(2)
+		n.encode(e)
+	case *VariableDeclaration:
+		e.uint(3)
+		n.encode(e)
+	default:
+		e.fail("unexpected ForLoopInit variant %T", n)
+	}
+}
+
+func decodeForLoopInit(d *decoder, v uint64) ForLoopInit {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.Expression.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.VariableDeclaration.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown ForLoopInit variant %d", v)
+		return nil
+	}
+}
+
+func encodeInto(e *encoder, n Into) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *Expression:
+		e.uint(2)
+		n.encode(e)
+	case *VariableDeclaration:
+		e.uint(3)
+		n.encode(e)
+	default:
+		e.fail("unexpected Into variant %T", n)
+	}
+}
+
+func decodeInto(d *decoder, v uint64) Into {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.Expression.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.VariableDeclaration.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown Into variant %d", v)
+		return nil
+	}
+}
+
+func encodeMemberProp(e *encoder, n MemberProp) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *ComputedProperty:
+		e.uint(2)
+		n.encode(e)
+	case *Identifier:
+		e.uint(3)
+		n.encode(e)
+	default:
+		e.fail("unexpected MemberProp variant %T", n)
+	}
+}
+
+func decodeMemberProp(d *decoder, v uint64) MemberProp {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.ComputedProperty.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.Identifier.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown MemberProp variant %d", v)
+		return nil
+	}
+}
+
+func encodePattern(e *encoder, n Pattern) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *ArrayPattern:
+		e.uint(2)
+		n.encode(e)
+	case *ObjectPattern:
+		e.uint(3)
+		n.encode(e)
+	default:
+		e.fail("unexpected Pattern variant %T", n)
+	}
+}
+
+func decodePattern(d *decoder, v uint64) Pattern {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.ArrayPattern.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.ObjectPattern.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown Pattern variant %d", v)
+		return nil
+	}
+}
+
+func encodeProp(e *encoder, n Prop) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *PropertyKeyed:
+		e.uint(2)
+		n.encode(e)
+	case *PropertyShort:
+		e.uint(3)
+		n.encode(e)
+	case *SpreadElement:
+		e.uint(4)
+		n.encode(e)
+	default:
+		e.fail("unexpected Prop variant %T", n)
+	}
+}
+
+func decodeProp(d *decoder, v uint64) Prop {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.PropertyKeyed.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.PropertyShort.new()
+		n.decode(d)
+		return n
+	case 4:
+		n := d.arenas.SpreadElement.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown Prop variant %d", v)
+		return nil
+	}
+}
+
+func encodeStmt(e *encoder, n Stmt) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *BadStatement:
+		e.uint(2)
+		n.encode(e)
+	case *BlockStatement:
+		e.uint(3)
+		n.encode(e)
+	case *BreakStatement:
+		e.uint(4)
+		n.encode(e)
+	case *CaseStatement:
+		e.uint(5)
+		n.encode(e)
+	case *CatchStatement:
+		e.uint(6)
+		n.encode(e)
+	case *ClassDeclaration:
+		e.uint(7)
+		n.encode(e)
+	case *ContinueStatement:
+		e.uint(8)
+		n.encode(e)
+	case *DebuggerStatement:
+		e.uint(9)
+		n.encode(e)
+	case *DoWhileStatement:
+		e.uint(10)
+		n.encode(e)
+	case *EmptyStatement:
+		e.uint(11)
+		n.encode(e)
+	case *ExportDeclaration:
+		e.uint(12)
+		n.encode(e)
+	case *ExpressionStatement:
+		e.uint(13)
+		n.encode(e)
+	case *ForInStatement:
+		e.uint(14)
+		n.encode(e)
+	case *ForOfStatement:
+		e.uint(15)
+		n.encode(e)
+	case *ForStatement:
+		e.uint(16)
+		n.encode(e)
+	case *FunctionDeclaration:
+		e.uint(17)
+		n.encode(e)
+	case *IfStatement:
+		e.uint(18)
+		n.encode(e)
+	case *ImportDeclaration:
+		e.uint(19)
+		n.encode(e)
+	case *LabelledStatement:
+		e.uint(20)
+		n.encode(e)
+	case *ReturnStatement:
+		e.uint(21)
+		n.encode(e)
+	case *SwitchStatement:
+		e.uint(22)
+		n.encode(e)
+	case *ThrowStatement:
+		e.uint(23)
+		n.encode(e)
+	case *TryStatement:
+		e.uint(24)
+		n.encode(e)
+	case *VariableDeclaration:
+		e.uint(25)
+		n.encode(e)
+	case *WhileStatement:
+		e.uint(26)
+		n.encode(e)
+	case *WithStatement:
+		e.uint(27)
+		n.encode(e)
+	default:
+		e.fail("unexpected Stmt variant %T", n)
+	}
+}
+
+func decodeStmt(d *decoder, v uint64) Stmt {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.BadStatement.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.BlockStatement.new()
+		n.decode(d)
+		return n
+	case 4:
+		n := d.arenas.BreakStatement.new()
+		n.decode(d)
+		return n
+	case 5:
+		n := d.arenas.CaseStatement.new()
+		n.decode(d)
+		return n
+	case 6:
+		n := d.arenas.CatchStatement.new()
+		n.decode(d)
+		return n
+	case 7:
+		n := d.arenas.ClassDeclaration.new()
+		n.decode(d)
+		return n
+	case 8:
+		n := d.arenas.ContinueStatement.new()
+		n.decode(d)
+		return n
+	case 9:
+		n := d.arenas.DebuggerStatement.new()
+		n.decode(d)
+		return n
+	case 10:
+		n := d.arenas.DoWhileStatement.new()
+		n.decode(d)
+		return n
+	case 11:
+		n := d.arenas.EmptyStatement.new()
+		n.decode(d)
+		return n
+	case 12:
+		n := d.arenas.ExportDeclaration.new()
+		n.decode(d)
+		return n
+	case 13:
+		n := d.arenas.ExpressionStatement.new()
+		n.decode(d)
+		return n
+	case 14:
+		n := d.arenas.ForInStatement.new()
+		n.decode(d)
+		return n
+	case 15:
+		n := d.arenas.ForOfStatement.new()
+		n.decode(d)
+		return n
+	case 16:
+		n := d.arenas.ForStatement.new()
+		n.decode(d)
+		return n
+	case 17:
+		n := d.arenas.FunctionDeclaration.new()
+		n.decode(d)
+		return n
+	case 18:
+		n := d.arenas.IfStatement.new()
+		n.decode(d)
+		return n
+	case 19:
+		n := d.arenas.ImportDeclaration.new()
+		n.decode(d)
+		return n
+	case 20:
+		n := d.arenas.LabelledStatement.new()
+		n.decode(d)
+		return n
+	case 21:
+		n := d.arenas.ReturnStatement.new()
+		n.decode(d)
+		return n
+	case 22:
+		n := d.arenas.SwitchStatement.new()
+		n.decode(d)
+		return n
+	case 23:
+		n := d.arenas.ThrowStatement.new()
+		n.decode(d)
+		return n
+	case 24:
+		n := d.arenas.TryStatement.new()
+		n.decode(d)
+		return n
+	case 25:
+		n := d.arenas.VariableDeclaration.new()
+		n.decode(d)
+		return n
+	case 26:
+		n := d.arenas.WhileStatement.new()
+		n.decode(d)
+		return n
+	case 27:
+		n := d.arenas.WithStatement.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown Stmt variant %d", v)
+		return nil
+	}
+}
+
+func encodeTarget(e *encoder, n Target) {
+	switch n := n.(type) {
+	case nil:
+		e.uint(1)
+	case *ArrayPattern:
+		e.uint(2)
+		n.encode(e)
+	case *Identifier:
+		e.uint(3)
+		n.encode(e)
+	case *InvalidExpression:
+		e.uint(4)
+		n.encode(e)
+	case *MemberExpression:
+		e.uint(5)
+		n.encode(e)
+	case *ObjectPattern:
+		e.uint(6)
+		n.encode(e)
+	default:
+		e.fail("unexpected Target variant %T", n)
+	}
+}
+
+func decodeTarget(d *decoder, v uint64) Target {
+	switch v {
+	case 1:
+		return nil
+	case 2:
+		n := d.arenas.ArrayPattern.new()
+		n.decode(d)
+		return n
+	case 3:
+		n := d.arenas.Identifier.new()
+		n.decode(d)
+		return n
+	case 4:
+		n := d.arenas.InvalidExpression.new()
+		n.decode(d)
+		return n
+	case 5:
+		n := d.arenas.MemberExpression.new()
+		n.decode(d)
+		return n
+	case 6:
+		n := d.arenas.ObjectPattern.new()
+		n.decode(d)
+		return n
+	default:
+		d.fail("unknown Target variant %d", v)
+		return nil
+	}
+}
@@ -0,0 +1,141 @@
+// Package build provides concise constructors for AST nodes.
+//
+// The nodes it returns are well-formed: expressions are wrapped in
+// ast.Expression where the tree expects it, member properties use
+// ast.MemberProperty, and literals carry a Raw text the generator prints
+// verbatim.
+//
+//	// obj.method(arg1, "x")
+//	build.Call(build.Member(build.Ident("obj"), "method"), build.Ident("arg1"), build.Str("x"))
+package build
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Ident returns an unresolved identifier.
+func Ident(name string) *ast.Identifier {
+	return &ast.Identifier{Name: name}
+}
+
+// Str returns a string literal whose Raw is a double quoted JavaScript string.
+func Str(value string) *ast.StringLiteral {
+	raw := Quote(value)
+	return &ast.StringLiteral{Value: value, Raw: &raw}
+}
+
+// Num returns a number. Negative numbers, -0, NaN and Infinity have no literal
+// form, so they are built as `-x`, `NaN` and `Infinity` instead.
+func Num(value float64) ast.Expr {
+	switch {
+	case math.IsNaN(value):
+		return Ident("NaN")
+	case math.IsInf(value, 1):
+		return Ident("Infinity")
+	case math.IsInf(value, -1):
+		return Unary(token.Minus, Ident("Infinity"))
+	case value < 0 || value == 0 && math.Signbit(value):
+		return Unary(token.Minus, NumLit(-value))
+	}
+	return NumLit(value)
+}
+
+// NumLit returns a number literal. value must be finite and not negative.
+func NumLit(value float64) *ast.NumberLiteral {
+	raw := formatNumber(value)
+	return &ast.NumberLiteral{Value: value, Raw: &raw}
+}
+
+// Bool returns a boolean literal.
+func Bool(value bool) *ast.BooleanLiteral {
+	return &ast.BooleanLiteral{Value: value}
+}
+
+// Null returns the null literal.
+func Null() *ast.NullLiteral {
+	return &ast.NullLiteral{}
+}
+
+// Undefined returns `void 0`, which cannot be shadowed unlike `undefined`.
+func Undefined() *ast.UnaryExpression {
+	return Unary(token.Void, NumLit(0))
+}
+
+// Regex returns a regular expression literal.
+func Regex(pattern, flags string) *ast.RegExpLiteral {
+	return &ast.RegExpLiteral{
+		Literal: "/" + pattern + "/" + flags,
+		Pattern: pattern,
+		Flags:   flags,
+	}
+}
+
+// Quote returns value as a double quoted JavaScript string literal.
+func Quote(value string) string {
+	var b strings.Builder
+	b.Grow(len(value) + 2)
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\v':
+			b.WriteString(`\v`)
+		case '\u2028', '\u2029', utf8.RuneError:
+			b.WriteString(`\u`)
+			b.WriteString(strconv.FormatInt(int64(r)|0x10000, 16)[1:])
+		default:
+			if r < 0x20 || r == 0x7f {
+				b.WriteString(`\x`)
+				b.WriteString(strconv.FormatInt(int64(r)|0x100, 16)[1:])
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// IsIdentifierName reports whether name can be written as a plain identifier,
+// for example after a dot in a member expression.
+func IsIdentifierName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '$' || r == '_' || unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func formatNumber(value float64) string {
+	if value == math.Trunc(value) && value < 1e21 {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,124 @@
+package build_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/token"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		stmt ast.Stmt
+		want string
+	}{
+		{build.ExprStmt(build.Call(build.Member(build.Ident("obj"), "method"), build.Ident("arg1"), build.Str("x"))), `obj.method(arg1, "x");`},
+		{build.ExprStmt(build.Member(build.Ident("o"), "a-b")), `o["a-b"];`},
+		{build.ExprStmt(build.Index(build.Ident("a"), build.NumLit(0))), `a[0];`},
+		{build.ExprStmt(build.Path("a", "b", "c")), `a.b.c;`},
+		{build.ExprStmt(build.New(build.Ident("C"), build.Num(1))), `new C(1);`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Num(-1))), `x = -1;`},
+		{build.ExprStmt(build.AssignOp(token.Plus, build.Ident("x"), build.Num(0.1))), `x += 0.1;`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Num(1e21))), `x = 1e21;`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Num(math.NaN()))), `x = NaN;`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Num(math.Inf(-1)))), `x = -Infinity;`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Num(math.Copysign(0, -1)))), `x = -0;`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Str("a\"b\n\t"))), `x = "a\"b\n\t";`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Undefined())), `x = void 0;`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Regex("a+", "g"))), `x = /a+/g;`},
+		{build.ExprStmt(build.Logical(token.LogicalAnd, build.Ident("a"), build.Not(build.Ident("b")))), `a && !b;`},
+		{build.ExprStmt(build.Cond(build.Ident("a"), build.Bool(true), build.Null())), `a ? true : null;`},
+		{build.ExprStmt(build.Update(token.Increment, build.Ident("i"), true)), `i++;`},
+		{build.ExprStmt(build.Seq(build.Ident("a"), build.Ident("b"))), `a, b;`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Array(build.Num(1), build.Spread(build.Ident("a"))))), `x = [1, ...a];`},
+		{build.ExprStmt(build.Assign(build.Ident("x"), build.Object(build.Prop("a", build.Num(1)), build.Prop("b c", build.Num(2)), build.ComputedProp(build.Ident("k"), build.Num(3))))), `x = {a: 1, "b c": 2, [k]: 3};`},
+		{build.ExprStmt(build.Assign(build.Ident("f"), build.Arrow([]string{"a"}, build.Return(build.Ident("a"))))), `f = (a) => { return a; };`},
+		{build.ExprStmt(build.Assign(build.Ident("f"), build.ArrowExpr([]string{"a", "b"}, build.Binary(token.Plus, build.Ident("a"), build.Ident("b"))))), `f = (a, b) => a + b;`},
+		{build.Var("a", build.Num(1)), `var a = 1;`},
+		{build.Let("a", nil), `let a;`},
+		{build.Const("a", build.Str("s")), `const a = "s";`},
+		{build.FunctionDecl("f", []string{"a", "b"}, build.Return(build.Ident("b"))), `function f(a, b) { return b; }`},
+		{build.If(build.Ident("a"), build.Block(build.ExprStmt(build.Call(build.Ident("f")))), build.Throw(build.Ident("e"))), `if (a) { f(); } else throw e;`},
+		{build.While(build.Bool(true), build.Block(build.Break(""))), `while (true) { break; }`},
+		{build.For(build.Let("i", build.Num(0)), build.Binary(token.Less, build.Ident("i"), build.Num(3)), build.Update(token.Increment, build.Ident("i"), false), build.Continue("")), `for (let i = 0; i < 3; ++i) continue;`},
+		{build.Empty(), `;`},
+	}
+	for _, tt := range tests {
+		want, err := parser.ParseFile(tt.want)
+		if err != nil {
+			t.Fatalf("ParseFile(%q): %v", tt.want, err)
+		}
+		p := build.Program(tt.stmt)
+		if !ast.Equal(p, want) {
+			t.Errorf("built tree differs from that of %q", tt.want)
+		}
+		src := generator.Generate(p)
+		got, err := parser.ParseFile(src)
+		if err != nil {
+			t.Errorf("generated %q for %q, which does not parse: %v", src, tt.want, err)
+			continue
+		}
+		if !ast.Equal(got, want) {
+			t.Errorf("generated %q, want %q", src, tt.want)
+		}
+	}
+}
+
+func TestFunction(t *testing.T) {
+	// The parser names anonymous functions with an empty identifier, so
+	// only the generated code is compared.
+	p := build.Program(build.ExprStmt(build.IIFE(build.Return(nil))))
+	src := generator.Generate(p)
+	got, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("generated %q, which does not parse: %v", src, err)
+	}
+	want, _ := parser.ParseFile(`(function () { return; })();`)
+	if !ast.Equal(got, want) {
+		t.Errorf("generated %q, want an IIFE returning nothing", src)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", `""`},
+		{`a"b`, `"a\"b"`},
+		{`a\b`, `"a\\b"`},
+		{"\n\r\t\b\f\v", `"\n\r\t\b\f\v"`},
+		{"\x00\x1f\x7f", `"\x00\x1f\x7f"`},
+		{"  ", `"\u2028\u2029"`},
+		{"é€😀", `"é€😀"`},
+	}
+	for _, tt := range tests {
+		if got := build.Quote(tt.value); got != tt.want {
+			t.Errorf("Quote(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestIsIdentifierName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"a", true},
+		{"$_a1", true},
+		{"é", true},
+		{"", false},
+		{"1a", false},
+		{"a-b", false},
+		{"a b", false},
+	}
+	for _, tt := range tests {
+		if got := build.IsIdentifierName(tt.name); got != tt.want {
+			t.Errorf("IsIdentifierName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
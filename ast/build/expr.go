@@ -0,0 +1,227 @@
+package build
+
+import (
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Expr wraps e in an ast.Expression. A nil e gives an empty expression.
+func Expr(e ast.Expr) *ast.Expression {
+	return &ast.Expression{Expr: e}
+}
+
+// Exprs wraps every element of es.
+func Exprs(es ...ast.Expr) ast.Expressions {
+	list := make(ast.Expressions, len(es))
+	for i, e := range es {
+		list[i].Expr = e
+	}
+	return list
+}
+
+// This returns `this`.
+func This() *ast.ThisExpression {
+	return &ast.ThisExpression{}
+}
+
+// Call returns `callee(args...)`.
+func Call(callee ast.Expr, args ...ast.Expr) *ast.CallExpression {
+	return &ast.CallExpression{
+		Callee:       Expr(callee),
+		ArgumentList: Exprs(args...),
+	}
+}
+
+// New returns `new callee(args...)`.
+func New(callee ast.Expr, args ...ast.Expr) *ast.NewExpression {
+	return &ast.NewExpression{
+		Callee:       Expr(callee),
+		ArgumentList: Exprs(args...),
+	}
+}
+
+// Member returns `object.name`, or `object["name"]` when name is not a valid
+// identifier name.
+func Member(object ast.Expr, name string) *ast.MemberExpression {
+	if !IsIdentifierName(name) {
+		return Index(object, Str(name))
+	}
+	return &ast.MemberExpression{
+		Object:   Expr(object),
+		Property: &ast.MemberProperty{Prop: Ident(name)},
+	}
+}
+
+// Index returns `object[property]`.
+func Index(object, property ast.Expr) *ast.MemberExpression {
+	return &ast.MemberExpression{
+		Object:   Expr(object),
+		Property: &ast.MemberProperty{Prop: &ast.ComputedProperty{Expr: Expr(property)}},
+	}
+}
+
+// Path returns a chain of member expressions, so Path("a", "b", "c") is `a.b.c`.
+func Path(root string, names ...string) ast.Expr {
+	var e ast.Expr = Ident(root)
+	for _, name := range names {
+		e = Member(e, name)
+	}
+	return e
+}
+
+// Binary returns `left op right`.
+func Binary(op token.Token, left, right ast.Expr) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: op,
+		Left:     Expr(left),
+		Right:    Expr(right),
+	}
+}
+
+// Logical returns `left op right` for one of &&, || and ??.
+func Logical(op token.Token, left, right ast.Expr) *ast.BinaryExpression {
+	return Binary(op, left, right)
+}
+
+// Unary returns `op operand`.
+func Unary(op token.Token, operand ast.Expr) *ast.UnaryExpression {
+	return &ast.UnaryExpression{
+		Operator: op,
+		Operand:  Expr(operand),
+	}
+}
+
+// Not returns `!operand`.
+func Not(operand ast.Expr) *ast.UnaryExpression {
+	return Unary(token.Not, operand)
+}
+
+// Update returns `++operand` or `operand++` and their decrement forms.
+func Update(op token.Token, operand ast.Expr, postfix bool) *ast.UpdateExpression {
+	return &ast.UpdateExpression{
+		Operator: op,
+		Operand:  Expr(operand),
+		Postfix:  postfix,
+	}
+}
+
+// Assign returns `left = right`.
+func Assign(left, right ast.Expr) *ast.AssignExpression {
+	return AssignOp(token.Assign, left, right)
+}
+
+// AssignOp returns a compound assignment. op is the binary operator, so
+// token.Plus gives `left += right`.
+func AssignOp(op token.Token, left, right ast.Expr) *ast.AssignExpression {
+	return &ast.AssignExpression{
+		Operator: op,
+		Left:     Expr(left),
+		Right:    Expr(right),
+	}
+}
+
+// Cond returns `test ? consequent : alternate`.
+func Cond(test, consequent, alternate ast.Expr) *ast.ConditionalExpression {
+	return &ast.ConditionalExpression{
+		Test:       Expr(test),
+		Consequent: Expr(consequent),
+		Alternate:  Expr(alternate),
+	}
+}
+
+// Seq returns `a, b, ...`.
+func Seq(exprs ...ast.Expr) *ast.SequenceExpression {
+	return &ast.SequenceExpression{Sequence: Exprs(exprs...)}
+}
+
+// Spread returns `...e`, for use in array literals and argument lists.
+func Spread(e ast.Expr) *ast.SpreadElement {
+	return &ast.SpreadElement{Expression: Expr(e)}
+}
+
+// Array returns `[elements...]`. A nil element is a hole.
+func Array(elements ...ast.Expr) *ast.ArrayLiteral {
+	return &ast.ArrayLiteral{Value: Exprs(elements...)}
+}
+
+// Object returns `{props...}`.
+func Object(props ...ast.Prop) *ast.ObjectLiteral {
+	list := make(ast.Properties, len(props))
+	for i, p := range props {
+		list[i].Prop = p
+	}
+	return &ast.ObjectLiteral{Value: list}
+}
+
+// Prop returns the property `key: value`. Keys that are not valid identifier
+// names are quoted.
+func Prop(key string, value ast.Expr) *ast.PropertyKeyed {
+	var k *ast.StringLiteral
+	if IsIdentifierName(key) {
+		raw := key
+		k = &ast.StringLiteral{Value: key, Raw: &raw}
+	} else {
+		k = Str(key)
+	}
+	return &ast.PropertyKeyed{
+		Key:   Expr(k),
+		Kind:  ast.PropertyKindValue,
+		Value: Expr(value),
+	}
+}
+
+// ComputedProp returns the property `[key]: value`.
+func ComputedProp(key, value ast.Expr) *ast.PropertyKeyed {
+	return &ast.PropertyKeyed{
+		Key:      Expr(key),
+		Kind:     ast.PropertyKindValue,
+		Value:    Expr(value),
+		Computed: true,
+	}
+}
+
+// Params returns a parameter list of plain identifiers.
+func Params(names ...string) ast.ParameterList {
+	list := make(ast.VariableDeclarators, len(names))
+	for i, name := range names {
+		list[i].Target = &ast.BindingTarget{Target: Ident(name)}
+	}
+	return ast.ParameterList{List: list}
+}
+
+// Function returns the anonymous function expression
+// `function (params...) { body... }`.
+func Function(params []string, body ...ast.Stmt) *ast.FunctionLiteral {
+	return &ast.FunctionLiteral{
+		ParameterList: Params(params...),
+		Body:          Block(body...),
+	}
+}
+
+// NamedFunction returns `function name(params...) { body... }`.
+func NamedFunction(name string, params []string, body ...ast.Stmt) *ast.FunctionLiteral {
+	fn := Function(params, body...)
+	fn.Name = Ident(name)
+	return fn
+}
+
+// Arrow returns `(params...) => { body... }`.
+func Arrow(params []string, body ...ast.Stmt) *ast.ArrowFunctionLiteral {
+	return &ast.ArrowFunctionLiteral{
+		ParameterList: Params(params...),
+		Body:          &ast.ConciseBody{Body: Block(body...)},
+	}
+}
+
+// ArrowExpr returns `(params...) => body`.
+func ArrowExpr(params []string, body ast.Expr) *ast.ArrowFunctionLiteral {
+	return &ast.ArrowFunctionLiteral{
+		ParameterList: Params(params...),
+		Body:          &ast.ConciseBody{Body: Expr(body)},
+	}
+}
+
+// IIFE returns `(function () { body... })()`.
+func IIFE(body ...ast.Stmt) *ast.CallExpression {
+	return Call(Function(nil, body...))
+}
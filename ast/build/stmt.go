@@ -0,0 +1,137 @@
+package build
+
+import (
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Stmts wraps every element of stmts.
+func Stmts(stmts ...ast.Stmt) ast.Statements {
+	list := make(ast.Statements, len(stmts))
+	for i, s := range stmts {
+		list[i].Stmt = s
+	}
+	return list
+}
+
+// Program returns a program with the given top-level statements.
+func Program(stmts ...ast.Stmt) *ast.Program {
+	return &ast.Program{Body: Stmts(stmts...)}
+}
+
+// Block returns `{ stmts... }`.
+func Block(stmts ...ast.Stmt) *ast.BlockStatement {
+	return &ast.BlockStatement{List: Stmts(stmts...)}
+}
+
+// ExprStmt returns the statement `e;`.
+func ExprStmt(e ast.Expr) *ast.ExpressionStatement {
+	return &ast.ExpressionStatement{Expression: Expr(e)}
+}
+
+// Declarator returns a declarator binding target, with an optional init.
+func Declarator(target ast.Target, init ast.Expr) ast.VariableDeclarator {
+	d := ast.VariableDeclarator{Target: &ast.BindingTarget{Target: target}}
+	if init != nil {
+		d.Initializer = Expr(init)
+	}
+	return d
+}
+
+// Declare returns a declaration of kind var, let or const.
+func Declare(kind token.Token, decls ...ast.VariableDeclarator) *ast.VariableDeclaration {
+	return &ast.VariableDeclaration{Token: kind, List: decls}
+}
+
+// Var returns `var name = init;`. A nil init declares without initializer.
+func Var(name string, init ast.Expr) *ast.VariableDeclaration {
+	return Declare(token.Var, Declarator(Ident(name), init))
+}
+
+// Let returns `let name = init;`. A nil init declares without initializer.
+func Let(name string, init ast.Expr) *ast.VariableDeclaration {
+	return Declare(token.Let, Declarator(Ident(name), init))
+}
+
+// Const returns `const name = init;`.
+func Const(name string, init ast.Expr) *ast.VariableDeclaration {
+	return Declare(token.Const, Declarator(Ident(name), init))
+}
+
+// FunctionDecl returns the declaration `function name(params...) { body... }`.
+func FunctionDecl(name string, params []string, body ...ast.Stmt) *ast.FunctionDeclaration {
+	return &ast.FunctionDeclaration{Function: NamedFunction(name, params, body...)}
+}
+
+// If returns `if (test) consequent else alternate`. A nil alternate omits
+// the else branch.
+func If(test ast.Expr, consequent, alternate ast.Stmt) *ast.IfStatement {
+	n := &ast.IfStatement{
+		Test:       Expr(test),
+		Consequent: &ast.Statement{Stmt: consequent},
+	}
+	if alternate != nil {
+		n.Alternate = &ast.Statement{Stmt: alternate}
+	}
+	return n
+}
+
+// While returns `while (test) body`.
+func While(test ast.Expr, body ast.Stmt) *ast.WhileStatement {
+	return &ast.WhileStatement{
+		Test: Expr(test),
+		Body: &ast.Statement{Stmt: body},
+	}
+}
+
+// For returns `for (init; test; update) body`. init is either a
+// *ast.VariableDeclaration or an expression, and any of init, test and update
+// may be nil.
+func For(init ast.ForLoopInit, test, update ast.Expr, body ast.Stmt) *ast.ForStatement {
+	n := &ast.ForStatement{
+		Test:   Expr(test),
+		Update: Expr(update),
+		Body:   &ast.Statement{Stmt: body},
+	}
+	if init != nil {
+		n.Initializer = &ast.ForLoopInitializer{Initializer: init}
+	}
+	return n
+}
+
+// Return returns `return arg;`. A nil arg returns nothing.
+func Return(arg ast.Expr) *ast.ReturnStatement {
+	n := &ast.ReturnStatement{}
+	if arg != nil {
+		n.Argument = Expr(arg)
+	}
+	return n
+}
+
+// Throw returns `throw arg;`.
+func Throw(arg ast.Expr) *ast.ThrowStatement {
+	return &ast.ThrowStatement{Argument: Expr(arg)}
+}
+
+// Break returns `break;`, or `break label;` when label is not empty.
+func Break(label string) *ast.BreakStatement {
+	n := &ast.BreakStatement{}
+	if label != "" {
+		n.Label = Ident(label)
+	}
+	return n
+}
+
+// Continue returns `continue;`, or `continue label;` when label is not empty.
+func Continue(label string) *ast.ContinueStatement {
+	n := &ast.ContinueStatement{}
+	if label != "" {
+		n.Label = Ident(label)
+	}
+	return n
+}
+
+// Empty returns the empty statement `;`.
+func Empty() *ast.EmptyStatement {
+	return &ast.EmptyStatement{}
+}
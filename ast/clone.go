@@ -1,11 +1,17 @@
 // Code generated by gen_clone.go; DO NOT EDIT.
 package ast
 
+import "fmt"
+
 func (n *ArrayLiteral) Clone() *ArrayLiteral {
 	return &ArrayLiteral{LeftBracket: n.LeftBracket, RightBracket: n.RightBracket, Value: *n.Value.Clone()}
 }
 func (n *ArrayPattern) Clone() *ArrayPattern {
-	return &ArrayPattern{LeftBracket: n.LeftBracket, RightBracket: n.RightBracket, Elements: *n.Elements.Clone(), Rest: n.Rest.Clone()}
+	var rest *Expression
+	if n.Rest != nil {
+		rest = n.Rest.Clone()
+	}
+	return &ArrayPattern{LeftBracket: n.LeftBracket, RightBracket: n.RightBracket, Elements: *n.Elements.Clone(), Rest: rest}
 }
 func (n *ArrowFunctionLiteral) Clone() *ArrowFunctionLiteral {
 	return &ArrowFunctionLiteral{Start: n.Start, ParameterList: *n.ParameterList.Clone(), Body: n.Body.Clone(), Async: n.Async, ScopeContext: n.ScopeContext}
@@ -25,6 +31,7 @@ func (n *BinaryExpression) Clone() *BinaryExpression {
 func (n *BindingTarget) Clone() *BindingTarget {
 	var clonedTarget Target
 	switch target := n.Target.(type) {
+	case nil:
 	case *ArrayPattern:
 		clonedTarget = target.Clone()
 	case *Identifier:
@@ -35,6 +42,8 @@ func (n *BindingTarget) Clone() *BindingTarget {
 		clonedTarget = target.Clone()
 	case *ObjectPattern:
 		clonedTarget = target.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Target", target))
 	}
 	return &BindingTarget{Target: clonedTarget}
 }
@@ -62,6 +71,9 @@ func (n *CaseStatement) Clone() *CaseStatement {
 	return &CaseStatement{Case: n.Case, Test: test, Consequent: *n.Consequent.Clone()}
 }
 func (n *CaseStatements) Clone() *CaseStatements {
+	if *n == nil {
+		return new(CaseStatements)
+	}
 	ns := make(CaseStatements, len(*n))
 	for i := range *n {
 		ns[i] = *(*n)[i].Clone()
@@ -81,16 +93,22 @@ func (n *ClassDeclaration) Clone() *ClassDeclaration {
 func (n *ClassElement) Clone() *ClassElement {
 	var clonedElement Element
 	switch element := n.Element.(type) {
+	case nil:
 	case *ClassStaticBlock:
 		clonedElement = element.Clone()
 	case *FieldDefinition:
 		clonedElement = element.Clone()
 	case *MethodDefinition:
 		clonedElement = element.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Element", element))
 	}
 	return &ClassElement{Element: clonedElement}
 }
 func (n *ClassElements) Clone() *ClassElements {
+	if *n == nil {
+		return new(ClassElements)
+	}
 	ns := make(ClassElements, len(*n))
 	for i := range *n {
 		ns[i] = *(*n)[i].Clone()
@@ -117,10 +135,13 @@ func (n *ComputedProperty) Clone() *ComputedProperty {
 func (n *ConciseBody) Clone() *ConciseBody {
 	var clonedBody Body
 	switch body := n.Body.(type) {
+	case nil:
 	case *BlockStatement:
 		clonedBody = body.Clone()
 	case *Expression:
 		clonedBody = body.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Body", body))
 	}
 	return &ConciseBody{Body: clonedBody}
 }
@@ -143,9 +164,38 @@ func (n *DoWhileStatement) Clone() *DoWhileStatement {
 func (n *EmptyStatement) Clone() *EmptyStatement {
 	return &EmptyStatement{Semicolon: n.Semicolon}
 }
+func (n *ExportDeclaration) Clone() *ExportDeclaration {
+	var source *StringLiteral
+	if n.Source != nil {
+		source = n.Source.Clone()
+	}
+	var clonedDefault *Expression
+	if n.Default != nil {
+		clonedDefault = n.Default.Clone()
+	}
+	var declaration *Statement
+	if n.Declaration != nil {
+		declaration = n.Declaration.Clone()
+	}
+	return &ExportDeclaration{Export: n.Export, From: n.From, Source: source, Default: clonedDefault, Declaration: declaration, Specifiers: *n.Specifiers.Clone()}
+}
+func (n *ExportSpecifier) Clone() *ExportSpecifier {
+	return &ExportSpecifier{Local: n.Local.Clone(), Exported: n.Exported.Clone()}
+}
+func (n *ExportSpecifiers) Clone() *ExportSpecifiers {
+	if *n == nil {
+		return new(ExportSpecifiers)
+	}
+	ns := make(ExportSpecifiers, len(*n))
+	for i := range *n {
+		ns[i] = *(*n)[i].Clone()
+	}
+	return &ns
+}
 func (n *Expression) Clone() *Expression {
 	var clonedExpr Expr
 	switch expr := n.Expr.(type) {
+	case nil:
 	case *ArrayLiteral:
 		clonedExpr = expr.Clone()
 	case *ArrayPattern:
@@ -220,6 +270,8 @@ func (n *Expression) Clone() *Expression {
 		clonedExpr = expr.Clone()
 	case *YieldExpression:
 		clonedExpr = expr.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Expr", expr))
 	}
 	return &Expression{Expr: clonedExpr}
 }
@@ -227,6 +279,9 @@ func (n *ExpressionStatement) Clone() *ExpressionStatement {
 	return &ExpressionStatement{Expression: n.Expression.Clone(), Comment: n.Comment}
 }
 func (n *Expressions) Clone() *Expressions {
+	if *n == nil {
+		return new(Expressions)
+	}
 	ns := make(Expressions, len(*n))
 	for i := range *n {
 		ns[i] = *(*n)[i].Clone()
@@ -246,20 +301,26 @@ func (n *ForInStatement) Clone() *ForInStatement {
 func (n *ForInto) Clone() *ForInto {
 	var clonedInto Into
 	switch into := n.Into.(type) {
+	case nil:
 	case *Expression:
 		clonedInto = into.Clone()
 	case *VariableDeclaration:
 		clonedInto = into.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Into", into))
 	}
 	return &ForInto{Into: clonedInto}
 }
 func (n *ForLoopInitializer) Clone() *ForLoopInitializer {
 	var clonedForLoopInit ForLoopInit
 	switch forLoopInit := n.Initializer.(type) {
+	case nil:
 	case *Expression:
 		clonedForLoopInit = forLoopInit.Clone()
 	case *VariableDeclaration:
 		clonedForLoopInit = forLoopInit.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as ForLoopInit", forLoopInit))
 	}
 	return &ForLoopInitializer{Initializer: clonedForLoopInit}
 }
@@ -271,7 +332,15 @@ func (n *ForStatement) Clone() *ForStatement {
 	if n.Initializer != nil {
 		initializer = n.Initializer.Clone()
 	}
-	return &ForStatement{For: n.For, Initializer: initializer, Update: n.Update.Clone(), Test: n.Test.Clone(), Body: n.Body.Clone()}
+	var update *Expression
+	if n.Update != nil {
+		update = n.Update.Clone()
+	}
+	var test *Expression
+	if n.Test != nil {
+		test = n.Test.Clone()
+	}
+	return &ForStatement{For: n.For, Initializer: initializer, Update: update, Test: test, Body: n.Body.Clone()}
 }
 func (n *FunctionDeclaration) Clone() *FunctionDeclaration {
 	return &FunctionDeclaration{Function: n.Function.Clone()}
@@ -281,7 +350,7 @@ func (n *FunctionLiteral) Clone() *FunctionLiteral {
 	if n.Name != nil {
 		name = n.Name.Clone()
 	}
-	return &FunctionLiteral{Function: n.Function, Name: name, ParameterList: *n.ParameterList.Clone(), Body: n.Body.Clone(), Async: n.Async, ScopeContext: n.ScopeContext}
+	return &FunctionLiteral{Function: n.Function, Name: name, ParameterList: *n.ParameterList.Clone(), Body: n.Body.Clone(), Async: n.Async, Generator: n.Generator, ScopeContext: n.ScopeContext}
 }
 func (n *Identifier) Clone() *Identifier {
 	return &Identifier{Idx: n.Idx, Name: n.Name, ScopeContext: n.ScopeContext}
@@ -293,6 +362,30 @@ func (n *IfStatement) Clone() *IfStatement {
 	}
 	return &IfStatement{If: n.If, Test: n.Test.Clone(), Consequent: n.Consequent.Clone(), Alternate: alternate}
 }
+func (n *ImportDeclaration) Clone() *ImportDeclaration {
+	var clonedDefault *Identifier
+	if n.Default != nil {
+		clonedDefault = n.Default.Clone()
+	}
+	var namespace *Identifier
+	if n.Namespace != nil {
+		namespace = n.Namespace.Clone()
+	}
+	return &ImportDeclaration{Import: n.Import, From: n.From, Source: n.Source.Clone(), Default: clonedDefault, Namespace: namespace, Specifiers: *n.Specifiers.Clone()}
+}
+func (n *ImportSpecifier) Clone() *ImportSpecifier {
+	return &ImportSpecifier{Imported: n.Imported.Clone(), Local: n.Local.Clone()}
+}
+func (n *ImportSpecifiers) Clone() *ImportSpecifiers {
+	if *n == nil {
+		return new(ImportSpecifiers)
+	}
+	ns := make(ImportSpecifiers, len(*n))
+	for i := range *n {
+		ns[i] = *(*n)[i].Clone()
+	}
+	return &ns
+}
 func (n *InvalidExpression) Clone() *InvalidExpression {
 	return &InvalidExpression{From: n.From, To: n.To}
 }
@@ -305,15 +398,18 @@ func (n *MemberExpression) Clone() *MemberExpression {
 func (n *MemberProperty) Clone() *MemberProperty {
 	var clonedMemberProp MemberProp
 	switch memberProp := n.Prop.(type) {
+	case nil:
 	case *ComputedProperty:
 		clonedMemberProp = memberProp.Clone()
 	case *Identifier:
 		clonedMemberProp = memberProp.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as MemberProp", memberProp))
 	}
 	return &MemberProperty{Prop: clonedMemberProp}
 }
 func (n *MetaProperty) Clone() *MetaProperty {
-	return &MetaProperty{Meta: n.Meta.Clone(), Idx: n.Idx}
+	return &MetaProperty{Meta: n.Meta.Clone(), Property: n.Property.Clone(), Idx: n.Idx}
 }
 func (n *MethodDefinition) Clone() *MethodDefinition {
 	return &MethodDefinition{Idx: n.Idx, Key: n.Key.Clone(), Kind: n.Kind, Body: n.Body.Clone(), Computed: n.Computed, Static: n.Static}
@@ -333,6 +429,7 @@ func (n *ObjectLiteral) Clone() *ObjectLiteral {
 func (n *ObjectPattern) Clone() *ObjectPattern {
 	var clonedExpr Expr
 	switch expr := n.Rest.(type) {
+	case nil:
 	case *ArrayLiteral:
 		clonedExpr = expr.Clone()
 	case *ArrayPattern:
@@ -407,6 +504,8 @@ func (n *ObjectPattern) Clone() *ObjectPattern {
 		clonedExpr = expr.Clone()
 	case *YieldExpression:
 		clonedExpr = expr.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Expr", expr))
 	}
 	return &ObjectPattern{LeftBrace: n.LeftBrace, RightBrace: n.RightBrace, Properties: *n.Properties.Clone(), Rest: clonedExpr}
 }
@@ -419,6 +518,7 @@ func (n *OptionalChain) Clone() *OptionalChain {
 func (n *ParameterList) Clone() *ParameterList {
 	var clonedExpr Expr
 	switch expr := n.Rest.(type) {
+	case nil:
 	case *ArrayLiteral:
 		clonedExpr = expr.Clone()
 	case *ArrayPattern:
@@ -493,6 +593,8 @@ func (n *ParameterList) Clone() *ParameterList {
 		clonedExpr = expr.Clone()
 	case *YieldExpression:
 		clonedExpr = expr.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Expr", expr))
 	}
 	return &ParameterList{Opening: n.Opening, List: *n.List.Clone(), Rest: clonedExpr, Closing: n.Closing}
 }
@@ -506,6 +608,9 @@ func (n *Program) Clone() *Program {
 	return &Program{Body: *n.Body.Clone()}
 }
 func (n *Properties) Clone() *Properties {
+	if *n == nil {
+		return new(Properties)
+	}
 	ns := make(Properties, len(*n))
 	for i := range *n {
 		ns[i] = *(*n)[i].Clone()
@@ -515,12 +620,15 @@ func (n *Properties) Clone() *Properties {
 func (n *Property) Clone() *Property {
 	var clonedProp Prop
 	switch prop := n.Prop.(type) {
+	case nil:
 	case *PropertyKeyed:
 		clonedProp = prop.Clone()
 	case *PropertyShort:
 		clonedProp = prop.Clone()
 	case *SpreadElement:
 		clonedProp = prop.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Prop", prop))
 	}
 	return &Property{Prop: clonedProp}
 }
@@ -528,7 +636,11 @@ func (n *PropertyKeyed) Clone() *PropertyKeyed {
 	return &PropertyKeyed{Key: n.Key.Clone(), Kind: n.Kind, Value: n.Value.Clone(), Computed: n.Computed}
 }
 func (n *PropertyShort) Clone() *PropertyShort {
-	return &PropertyShort{Name: n.Name.Clone(), Initializer: n.Initializer.Clone()}
+	var initializer *Expression
+	if n.Initializer != nil {
+		initializer = n.Initializer.Clone()
+	}
+	return &PropertyShort{Name: n.Name.Clone(), Initializer: initializer}
 }
 func (n *RegExpLiteral) Clone() *RegExpLiteral {
 	return &RegExpLiteral{Idx: n.Idx, Literal: n.Literal, Pattern: n.Pattern, Flags: n.Flags}
@@ -549,6 +661,7 @@ func (n *SpreadElement) Clone() *SpreadElement {
 func (n *Statement) Clone() *Statement {
 	var clonedStmt Stmt
 	switch stmt := n.Stmt.(type) {
+	case nil:
 	case *BadStatement:
 		clonedStmt = stmt.Clone()
 	case *BlockStatement:
@@ -569,6 +682,8 @@ func (n *Statement) Clone() *Statement {
 		clonedStmt = stmt.Clone()
 	case *EmptyStatement:
 		clonedStmt = stmt.Clone()
+	case *ExportDeclaration:
+		clonedStmt = stmt.Clone()
 	case *ExpressionStatement:
 		clonedStmt = stmt.Clone()
 	case *ForInStatement:
@@ -581,6 +696,8 @@ func (n *Statement) Clone() *Statement {
 		clonedStmt = stmt.Clone()
 	case *IfStatement:
 		clonedStmt = stmt.Clone()
+	case *ImportDeclaration:
+		clonedStmt = stmt.Clone()
 	case *LabelledStatement:
 		clonedStmt = stmt.Clone()
 	case *ReturnStatement:
@@ -597,10 +714,15 @@ func (n *Statement) Clone() *Statement {
 		clonedStmt = stmt.Clone()
 	case *WithStatement:
 		clonedStmt = stmt.Clone()
+	default:
+		panic(fmt.Sprintf("ast: cannot clone %T as Stmt", stmt))
 	}
 	return &Statement{Stmt: clonedStmt}
 }
 func (n *Statements) Clone() *Statements {
+	if *n == nil {
+		return new(Statements)
+	}
 	ns := make(Statements, len(*n))
 	for i := range *n {
 		ns[i] = *(*n)[i].Clone()
@@ -620,6 +742,9 @@ func (n *TemplateElement) Clone() *TemplateElement {
 	return &TemplateElement{Idx: n.Idx, Literal: n.Literal, Parsed: n.Parsed, Valid: n.Valid}
 }
 func (n *TemplateElements) Clone() *TemplateElements {
+	if *n == nil {
+		return new(TemplateElements)
+	}
 	ns := make(TemplateElements, len(*n))
 	for i := range *n {
 		ns[i] = *(*n)[i].Clone()
@@ -667,6 +792,9 @@ func (n *VariableDeclarator) Clone() *VariableDeclarator {
 	return &VariableDeclarator{Target: n.Target.Clone(), Initializer: initializer}
 }
 func (n *VariableDeclarators) Clone() *VariableDeclarators {
+	if *n == nil {
+		return new(VariableDeclarators)
+	}
 	ns := make(VariableDeclarators, len(*n))
 	for i := range *n {
 		ns[i] = *(*n)[i].Clone()
@@ -680,5 +808,9 @@ func (n *WithStatement) Clone() *WithStatement {
 	return &WithStatement{With: n.With, Object: n.Object.Clone(), Body: n.Body.Clone()}
 }
 func (n *YieldExpression) Clone() *YieldExpression {
-	return &YieldExpression{Yield: n.Yield, Argument: n.Argument.Clone(), Delegate: n.Delegate}
+	var argument *Expression
+	if n.Argument != nil {
+		argument = n.Argument.Clone()
+	}
+	return &YieldExpression{Yield: n.Yield, Argument: argument, Delegate: n.Delegate}
 }
@@ -0,0 +1,112 @@
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+// pointers adds the addresses of the nodes under v to seen.
+func pointers(v reflect.Value, seen map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return
+		}
+		seen[v.Pointer()] = true
+		pointers(v.Elem(), seen)
+	case reflect.Interface:
+		if !v.IsNil() {
+			pointers(v.Elem(), seen)
+		}
+	case reflect.Slice:
+		if v.Len() > 0 {
+			seen[v.Pointer()] = true
+		}
+		for i := 0; i < v.Len(); i++ {
+			pointers(v.Index(i), seen)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			pointers(v.Field(i), seen)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	tests := []string{
+		`var {a, b: [c, , ...d] = e} = f, g = (h, i = 1) => h + i;`,
+		`class A extends B { #p = 1; static { this.x = 1; } get [k]() { return super.k; } }`,
+		"label: for (const x of y) { try { f`a${x}b`; } catch { continue label; } }",
+		`switch (a?.b) { case 1: new.target; default: delete a[0]; }`,
+		`import d, {x as y, z} from "m"; export {y as w}; export default function () {}`,
+	}
+	for _, src := range tests {
+		p := parse(t, src)
+		resolver.Resolve(p)
+		c := p.Clone()
+		if !ast.Equal(c, p) {
+			t.Errorf("clone of %q differs from it", src)
+		}
+		orig, cloned := map[uintptr]bool{}, map[uintptr]bool{}
+		pointers(reflect.ValueOf(p), orig)
+		pointers(reflect.ValueOf(c), cloned)
+		for ptr := range cloned {
+			if orig[ptr] {
+				t.Errorf("clone of %q shares nodes with it", src)
+				break
+			}
+		}
+	}
+}
+
+// foreignTarget is a Target that package ast does not know.
+type foreignTarget struct {
+	*ast.Identifier
+}
+
+func TestCloneUnknownVariant(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("cloning an unknown Target does not panic")
+		}
+	}()
+	b := &ast.BindingTarget{Target: foreignTarget{&ast.Identifier{Name: "a"}}}
+	b.Clone()
+}
+
+func TestCloneWithOptions(t *testing.T) {
+	p := parse(t, `function f(a) { return a; }`)
+	resolver.Resolve(p)
+	fn := p.Body[0].Stmt.(*ast.FunctionDeclaration).Function
+
+	c := ast.CloneWithOptions(fn, ast.CloneOptions{
+		ResetPositions: true,
+		ScopeContext: func(sc ast.ScopeContext) ast.ScopeContext {
+			if sc == 0 {
+				return 0
+			}
+			return sc + 100
+		},
+	})
+	if c.Function != 0 || c.Body.LeftBrace != 0 || c.Name.Idx != 0 {
+		t.Errorf("positions are not reset")
+	}
+	if fn.Function == 0 || fn.Name.Idx == 0 {
+		t.Errorf("the original lost its positions")
+	}
+	param := c.ParameterList.List[0].Target.Target.(*ast.Identifier)
+	ret := c.Body.List[0].Stmt.(*ast.ReturnStatement).Argument.Expr.(*ast.Identifier)
+	origParam := fn.ParameterList.List[0].Target.Target.(*ast.Identifier)
+	if param.ScopeContext != origParam.ScopeContext+100 || ret.ScopeContext != param.ScopeContext {
+		t.Errorf("scope contexts are %d and %d, want both %d", param.ScopeContext, ret.ScopeContext, origParam.ScopeContext+100)
+	}
+	if c.ScopeContext != fn.ScopeContext+100 {
+		t.Errorf("function scope context is %d, want %d", c.ScopeContext, fn.ScopeContext+100)
+	}
+	if !ast.EqualWithOptions(c, fn, ast.CompareOptions{IgnoreScopeContext: true}) {
+		t.Errorf("the copy differs from the original beyond positions and scope contexts")
+	}
+}
@@ -0,0 +1,362 @@
+package ast
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// The binary encoding starts with binaryMagic, binaryVersion and the schema
+// of the node definitions. binaryVersion changes with the layout of the
+// encoding itself, and the schema with any change to the nodes.
+//
+// Nodes follow depth first as their fields in varints, leaving out what can
+// be told from the rest: positions are differences, strings seen before are
+// references, raw text equal to the value is a mode, zero scope contexts and
+// missing fields are bits in a node's flags, and missing wrappers such as
+// *Expression are variant 0 of the interface they hold.
+const (
+	binaryMagic   = "goFAST"
+	binaryVersion = 2
+)
+
+// ErrBinaryVersion is returned when decoding data written by a different
+// version of the encoding or of the node definitions.
+var ErrBinaryVersion = errors.New("ast: binary data was encoded by a different version")
+
+// MarshalBinary encodes p with its positions, scope contexts and the raw
+// text of its literals. The encoding is versioned: UnmarshalBinary only reads
+// data written by the same version of this package.
+func (p *Program) MarshalBinary() ([]byte, error) {
+	e := &encoder{strings: map[string]uint64{}}
+	e.buf = append(e.buf, binaryMagic...)
+	e.uint(binaryVersion)
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, binarySchema)
+	p.encode(e)
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.buf, nil
+}
+
+// UnmarshalBinary decodes data written by MarshalBinary into p.
+func (p *Program) UnmarshalBinary(data []byte) error {
+	d := &decoder{data: string(data)}
+	if len(d.data) < len(binaryMagic) || d.data[:len(binaryMagic)] != binaryMagic {
+		return errors.New("ast: not a binary encoded program")
+	}
+	d.pos = len(binaryMagic)
+	if d.uint() != binaryVersion || d.uint64() != binarySchema {
+		return ErrBinaryVersion
+	}
+	*p = Program{}
+	p.decode(d)
+	if d.err == nil && d.pos != len(d.data) {
+		d.fail("%d bytes of trailing data", len(d.data)-d.pos)
+	}
+	return d.err
+}
+
+type encoder struct {
+	buf []byte
+	err error
+
+	// Positions are written as the difference from the previous one.
+	last Idx
+
+	// Strings seen before are written as their index.
+	strings map[string]uint64
+
+	// The last string or number written, which raw text usually repeats.
+	prev  string
+	num   float64
+	isNum bool
+}
+
+func (e *encoder) fail(format string, args ...any) {
+	if e.err == nil {
+		e.err = fmt.Errorf("ast: "+format, args...)
+	}
+}
+
+func (e *encoder) uint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *encoder) int(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+// length writes the length of a slice, keeping nil apart from empty, and
+// reports whether elements follow.
+func (e *encoder) length(isNil bool, n int) bool {
+	if isNil {
+		e.uint(0)
+		return false
+	}
+	e.uint(uint64(n) + 1)
+	return n > 0
+}
+
+func (e *encoder) idx(v Idx) {
+	e.int(int64(v - e.last))
+	e.last = v
+}
+
+// float writes integers, the usual numbers in source, as a varint whose low
+// bit is clear, and other numbers as 1 and their bits.
+func (e *encoder) float(v float64) {
+	e.num, e.isNum = v, true
+	if v == math.Trunc(v) && math.Abs(v) < 1<<53 && !(v == 0 && math.Signbit(v)) {
+		i := int64(v)
+		e.uint(uint64(i<<1^i>>63) << 1)
+		return
+	}
+	e.uint(1)
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v))
+}
+
+func (e *encoder) string(v string) {
+	e.flaggedString(v, 0, 0)
+}
+
+// flaggedString writes a string with n bits of flags in the low bits of its
+// reference, which saves the node a flags value of its own.
+func (e *encoder) flaggedString(v string, flags uint64, n uint) {
+	e.prev, e.isNum = v, false
+	if i, ok := e.strings[v]; ok {
+		e.uint((i+1)<<n | flags)
+		return
+	}
+	e.strings[v] = uint64(len(e.strings))
+	e.uint(flags)
+	e.uint(uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// The ways raw text is written.
+const (
+	rawNone = iota
+	rawString
+	rawSame
+	rawDoubleQuoted
+	rawSingleQuoted
+)
+
+// raw writes the raw text of a literal, if any. Raw text that is the string
+// or number before it, quoted or not, is written without repeating it.
+func (e *encoder) raw(v *string) {
+	prev := e.prev
+	if e.isNum {
+		prev = strconv.FormatFloat(e.num, 'g', -1, 64)
+	}
+	switch {
+	case v == nil:
+		e.uint(rawNone)
+	case *v == prev:
+		e.uint(rawSame)
+	case *v == `"`+prev+`"`:
+		e.uint(rawDoubleQuoted)
+	case *v == "'"+prev+"'":
+		e.uint(rawSingleQuoted)
+	default:
+		e.uint(rawString)
+		e.string(*v)
+	}
+}
+
+type decoder struct {
+	data string
+	pos  int
+	err  error
+
+	last    Idx
+	strings []string
+	prev    string
+	num     float64
+	isNum   bool
+
+	arenas arenas
+}
+
+// arena allocates nodes of one type in blocks, as the parser does for
+// wrappers. A node kept alive keeps the rest of its block.
+type arena[T any] struct {
+	block []T
+	size  int
+}
+
+func (a *arena[T]) new() *T {
+	if len(a.block) == 0 {
+		a.grow()
+	}
+	n := &a.block[0]
+	a.block = a.block[1:]
+	return n
+}
+
+// slice returns a slice of l nodes. Its capacity is l, so appending to it
+// does not overwrite other nodes.
+func (a *arena[T]) slice(l int) []T {
+	if l == 0 {
+		return []T{}
+	}
+	if l > len(a.block) {
+		if l > a.size/2 {
+			return make([]T, l)
+		}
+		a.grow()
+	}
+	s := a.block[:l:l]
+	a.block = a.block[l:]
+	return s
+}
+
+func (a *arena[T]) grow() {
+	if a.size < 128 {
+		a.size = a.size*2 + 8
+	}
+	a.block = make([]T, a.size)
+}
+
+// fail records the first error. Reads after it return zero values, which
+// end every loop.
+func (d *decoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("ast: "+format, args...)
+	}
+	d.pos = len(d.data)
+}
+
+func (d *decoder) byte() byte {
+	if d.pos >= len(d.data) {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b
+}
+
+func (d *decoder) uint() uint64 {
+	var v uint64
+	for shift := 0; shift < 64; shift += 7 {
+		b := d.byte()
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v
+		}
+	}
+	d.fail("invalid varint")
+	return 0
+}
+
+func (d *decoder) int() int64 {
+	u := d.uint()
+	v := int64(u >> 1)
+	if u&1 != 0 {
+		v = ^v
+	}
+	return v
+}
+
+func (d *decoder) uint64() uint64 {
+	if len(d.data)-d.pos < 8 {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	var v uint64
+	for i := 7; i >= 0; i-- {
+		v = v<<8 | uint64(d.data[d.pos+i])
+	}
+	d.pos += 8
+	return v
+}
+
+// length reads the length of a slice, and reports whether it is not nil.
+func (d *decoder) length() (int, bool) {
+	n := d.uint()
+	if n == 0 {
+		return 0, false
+	}
+	// Every element takes at least a byte.
+	if n-1 > uint64(len(d.data)-d.pos) {
+		d.fail("invalid length %d", n-1)
+		return 0, false
+	}
+	return int(n - 1), true
+}
+
+func (d *decoder) idx() Idx {
+	d.last += Idx(d.int())
+	return d.last
+}
+
+func (d *decoder) float() float64 {
+	u := d.uint()
+	if u&1 == 0 {
+		u >>= 1
+		d.num = float64(int64(u>>1) ^ -int64(u&1))
+	} else {
+		d.num = math.Float64frombits(d.uint64())
+	}
+	d.isNum = true
+	return d.num
+}
+
+func (d *decoder) string() string {
+	s, _ := d.flaggedString(0)
+	return s
+}
+
+func (d *decoder) flaggedString(n uint) (string, uint64) {
+	d.isNum = false
+	u := d.uint()
+	i, flags := u>>n, u&(1<<n-1)
+	if i > 0 {
+		if i > uint64(len(d.strings)) {
+			d.fail("invalid string reference %d", i)
+			return "", 0
+		}
+		d.prev = d.strings[i-1]
+		return d.prev, flags
+	}
+	l := d.uint()
+	if l > uint64(len(d.data)-d.pos) {
+		d.fail("invalid string length %d", l)
+		return "", 0
+	}
+	s := d.data[d.pos : d.pos+int(l)]
+	d.pos += int(l)
+	d.strings = append(d.strings, s)
+	d.prev = s
+	return s, flags
+}
+
+func (d *decoder) raw() *string {
+	mode := d.uint()
+	if mode == rawNone {
+		return nil
+	}
+	prev := d.prev
+	if d.isNum && mode != rawString {
+		prev = strconv.FormatFloat(d.num, 'g', -1, 64)
+	}
+	var s string
+	switch mode {
+	case rawString:
+		s = d.string()
+	case rawSame:
+		s = prev
+	case rawDoubleQuoted:
+		s = `"` + prev + `"`
+	case rawSingleQuoted:
+		s = "'" + prev + "'"
+	default:
+		d.fail("invalid raw text mode %d", mode)
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,148 @@
+package ast_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func TestBinary(t *testing.T) {
+	tests := []string{
+		``,
+		`var a = 1, b = "s", c = 'q', d = 0x1f, e = 1.5e3, f = -0, g = /x/gi, h = null, i = true;`,
+		`function f(a, b = 1, ...c) { "use strict"; return a + b * c[0]; }`,
+		`const {x, y: [z, , w = 2], ...r} = o; [a, b] = [b, a];`,
+		`for (let i = 0; i < 3; i++) { if (i) continue; else break; } for (k in o); for (v of a);`,
+		"x = `a${b}c`; tag`t\\u`;",
+		`a?.b?.(c)[d]; new C(...args); delete a.b; typeof x; void 0;`,
+		`class A extends B { #p = 1; static s; static { f(); } get g() { return this.#p; } m() { super.m(); } }`,
+		`async function* g() { yield* await x; } (async () => { await 1; })();`,
+		`label: while (true) { try { throw e; } catch ({message}) {} finally { debugger; } }`,
+		`switch (x) { case 1: f(); default: g(); } with (o) { p; }`,
+		`x = {a, b: 1, [c]: 2, "d e": 3, 4: 5, get f() { return 1; }, set f(v) {}, m() {}};`,
+		"var é = \"é\" + \"\ufeff\";",
+		`import a, {b as c, d} from "m"; export {c as e}; export default 1;`,
+	}
+	for _, src := range tests {
+		p := parse(t, src)
+		resolver.Resolve(p)
+		data, err := p.MarshalBinary()
+		if err != nil {
+			t.Errorf("MarshalBinary of %q: %v", src, err)
+			continue
+		}
+		var got ast.Program
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Errorf("UnmarshalBinary of %q: %v", src, err)
+			continue
+		}
+		// DeepEqual compares every field, positions included.
+		if !reflect.DeepEqual(&got, p) {
+			t.Errorf("%q does not survive a round trip", src)
+		}
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	data, err := parse(t, `f(1, "s");`).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p ast.Program
+	if err := p.UnmarshalBinary([]byte("nope")); err == nil {
+		t.Errorf("UnmarshalBinary accepts data without the header")
+	}
+	version := append([]byte(nil), data...)
+	version[len("goFAST")]++
+	if err := p.UnmarshalBinary(version); !errors.Is(err, ast.ErrBinaryVersion) {
+		t.Errorf("UnmarshalBinary of another version = %v, want ErrBinaryVersion", err)
+	}
+	for i := len(data) - 1; i > len("goFAST")+9; i-- {
+		if err := p.UnmarshalBinary(data[:i]); err == nil {
+			t.Errorf("UnmarshalBinary accepts data cut to %d of %d bytes", i, len(data))
+		}
+	}
+	if err := p.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Errorf("UnmarshalBinary accepts trailing data")
+	}
+}
+
+// benchSource returns a program of about n kilobytes in the style of
+// bundled code.
+func benchSource(n int) string {
+	var b strings.Builder
+	for i := 0; b.Len() < n<<10; i++ {
+		fmt.Fprintf(&b, `
+function render%[1]d(props, state) {
+	var items = props.items || [], total = 0;
+	for (var i = 0; i < items.length; i++) {
+		if (items[i].visible && !state.hidden[items[i].id]) {
+			total += items[i].price * (1 - props.discount / 100);
+		}
+	}
+	const label = "Total: " + total.toFixed(2) + " (" + items.length + " items)";
+	return {type: "div", className: "cart-%[1]d", children: [label, state.error ? state.error.message : null]};
+}
+var handler%[1]d = async (event) => {
+	const {target: {value}} = event;
+	try {
+		await store.dispatch({type: "update", key: 'field%[1]d', value: value.trim()});
+	} catch (err) {
+		console.error(err, 0x%[1]x);
+	}
+};
+`, i)
+	}
+	return b.String()
+}
+
+func BenchmarkParse(b *testing.B) {
+	src := benchSource(256)
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.ParseFile(src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalBinary(b *testing.B) {
+	src := benchSource(256)
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(src)))
+	var data []byte
+	for i := 0; i < b.N; i++ {
+		if data, err = p.MarshalBinary(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(data))/float64(len(src)), "size/src")
+}
+
+func BenchmarkUnmarshalBinary(b *testing.B) {
+	src := benchSource(256)
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := p.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		var p ast.Program
+		if err := p.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package ast
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+)
+
+// CompareOptions controls how Equal and Hash treat identifiers.
+//
+// Positions (Idx values), the Raw text of literals and statement comments are
+// never compared.
+type CompareOptions struct {
+	// IgnoreScopeContext skips ScopeContext values on identifiers and scoped nodes.
+	IgnoreScopeContext bool
+	// Renaming compares resolved identifiers modulo a consistent renaming, so
+	// `function f(a) { return a }` equals `function g(b) { return b }`.
+	// Identifiers left unresolved (ScopeContext 0) are free and must match by
+	// name. Property names, private names and meta properties always match
+	// by name, as do the bindings that are also names seen from outside: the
+	// keys of shorthand properties and the names of unaliased imports.
+	Renaming bool
+}
+
+// Equal reports whether a and b are structurally equal, ignoring positions.
+func Equal(a, b VisitableNode) bool {
+	return EqualWithOptions(a, b, CompareOptions{})
+}
+
+// EqualWithOptions is like Equal, but compares identifiers according to opts.
+func EqualWithOptions(a, b VisitableNode, opts CompareOptions) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	c := &comparer{opts: opts}
+	if opts.Renaming {
+		c.forward = make(map[Id]Id)
+		c.backward = make(map[Id]Id)
+	}
+	return equalNode(a, b, c)
+}
+
+// Hash returns a structural hash of n that is stable across runs. Nodes that
+// are Equal have the same hash.
+func Hash(n VisitableNode) uint64 {
+	return HashWithOptions(n, CompareOptions{})
+}
+
+// HashWithOptions is like Hash, but hashes identifiers according to opts.
+// Nodes that are equal under EqualWithOptions with the same opts have the
+// same hash.
+func HashWithOptions(n VisitableNode, opts CompareOptions) uint64 {
+	h := &hasher{h: fnv.New64a(), opts: opts}
+	if opts.Renaming {
+		h.names = make(map[Id]int)
+	}
+	if n == nil {
+		h.nil()
+	} else {
+		hashNode(n, h)
+	}
+	return h.h.Sum64()
+}
+
+type comparer struct {
+	opts CompareOptions
+
+	// Renaming maps in both directions, to keep the renaming a bijection.
+	forward  map[Id]Id
+	backward map[Id]Id
+}
+
+func (c *comparer) scopeContext(a, b ScopeContext) bool {
+	return c.opts.IgnoreScopeContext || c.opts.Renaming || a == b
+}
+
+func (c *comparer) key(n *Identifier) Id {
+	if c.opts.IgnoreScopeContext {
+		return Id{Name: n.Name}
+	}
+	return n.ToId()
+}
+
+func equalFloat(a, b float64) bool {
+	return math.Float64bits(a) == math.Float64bits(b)
+}
+
+type hasher struct {
+	h    hash.Hash64
+	opts CompareOptions
+
+	// Renaming numbers identifiers by first occurrence.
+	names map[Id]int
+
+	buf [8]byte
+}
+
+func (h *hasher) nil() {
+	h.h.Write([]byte{0})
+}
+
+func (h *hasher) tag(name string) {
+	h.h.Write([]byte{1})
+	h.h.Write([]byte(name))
+}
+
+func (h *hasher) int(v int64) {
+	binary.LittleEndian.PutUint64(h.buf[:], uint64(v))
+	h.h.Write(h.buf[:])
+}
+
+func (h *hasher) float(v float64) {
+	h.int(int64(math.Float64bits(v)))
+}
+
+func (h *hasher) bool(v bool) {
+	if v {
+		h.h.Write([]byte{1})
+	} else {
+		h.h.Write([]byte{0})
+	}
+}
+
+func (h *hasher) string(v string) {
+	h.int(int64(len(v)))
+	h.h.Write([]byte(v))
+}
+
+func (h *hasher) scopeContext(v ScopeContext) {
+	if !h.opts.IgnoreScopeContext && !h.opts.Renaming {
+		h.int(int64(v))
+	}
+}
+
+func (h *hasher) name(n *Identifier) {
+	h.string(n.Name)
+	h.scopeContext(n.ScopeContext)
+}
+
+func (n *Identifier) equal(o *Identifier, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if !c.opts.Renaming || n.ScopeContext == 0 || o.ScopeContext == 0 {
+		return n.Name == o.Name && (c.opts.IgnoreScopeContext || n.ScopeContext == o.ScopeContext)
+	}
+
+	a, b := c.key(n), c.key(o)
+	ma, okA := c.forward[a]
+	mb, okB := c.backward[b]
+	if !okA && !okB {
+		c.forward[a], c.backward[b] = b, a
+		return true
+	}
+	return okA && okB && ma == b && mb == a
+}
+
+func (n *Identifier) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Identifier")
+	if !h.opts.Renaming || n.ScopeContext == 0 {
+		h.string(n.Name)
+		if h.opts.Renaming {
+			// Keep free identifiers apart from renamed ones.
+			h.int(0)
+		} else {
+			h.scopeContext(n.ScopeContext)
+		}
+		return
+	}
+
+	key := n.ToId()
+	if h.opts.IgnoreScopeContext {
+		key = Id{Name: n.Name}
+	}
+	idx, ok := h.names[key]
+	if !ok {
+		idx = len(h.names) + 1
+		h.names[key] = idx
+	}
+	h.int(int64(idx))
+}
+
+// equalName compares identifiers that name properties rather than bindings.
+func equalName(a, b *Identifier, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name && c.scopeContext(a.ScopeContext, b.ScopeContext)
+}
+
+func hashName(n *Identifier, h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Identifier")
+	h.name(n)
+}
+
+func (n *MemberProperty) equal(o *MemberProperty, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if a, ok := n.Prop.(*Identifier); ok {
+		b, ok := o.Prop.(*Identifier)
+		return ok && equalName(a, b, c)
+	}
+	return equalMemberProp(n.Prop, o.Prop, c)
+}
+
+func (n *MemberProperty) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("MemberProperty")
+	if id, ok := n.Prop.(*Identifier); ok {
+		hashName(id, h)
+		return
+	}
+	hashMemberProp(n.Prop, h)
+}
+
+func (n *PrivateIdentifier) equal(o *PrivateIdentifier, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalName(n.Identifier, o.Identifier, c)
+}
+
+func (n *PrivateIdentifier) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("PrivateIdentifier")
+	hashName(n.Identifier, h)
+}
+
+func (n *MetaProperty) equal(o *MetaProperty, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalName(n.Meta, o.Meta, c) && equalName(n.Property, o.Property, c)
+}
+
+func (n *MetaProperty) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("MetaProperty")
+	hashName(n.Meta, h)
+	hashName(n.Property, h)
+}
+
+// A shorthand property such as {a} names both a property and a binding, so
+// renaming its identifier changes the property.
+
+func (n *PropertyShort) equal(o *PropertyShort, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalName(n.Name, o.Name, c) &&
+		n.Name.equal(o.Name, c) &&
+		n.Initializer.equal(o.Initializer, c)
+}
+
+func (n *PropertyShort) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("PropertyShort")
+	hashName(n.Name, h)
+	n.Name.hash(h)
+	n.Initializer.hash(h)
+}
+
+// The exported name of an export specifier is a name, like a property key,
+// while its local name is the binding exported.
+
+func (n *ExportSpecifier) equal(o *ExportSpecifier, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Local.equal(o.Local, c) &&
+		equalName(n.Exported, o.Exported, c)
+}
+
+func (n *ExportSpecifier) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ExportSpecifier")
+	n.Local.hash(h)
+	hashName(n.Exported, h)
+}
+
+// The imported name of an import specifier is that of an export of the
+// module imported from. Without an alias, it is also the local binding.
+
+func (n *ImportSpecifier) equal(o *ImportSpecifier, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalName(n.Imported, o.Imported, c) &&
+		n.Local.equal(o.Local, c)
+}
+
+func (n *ImportSpecifier) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ImportSpecifier")
+	hashName(n.Imported, h)
+	n.Local.hash(h)
+}
@@ -0,0 +1,101 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile(%q): %v", src, err)
+	}
+	return p
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		opts ast.CompareOptions
+		want bool
+	}{
+		// Positions, raw text and comments are ignored.
+		{"a + 1;", "a   +   1;", ast.CompareOptions{}, true},
+		{"x = 0x10;", "x = 16;", ast.CompareOptions{}, true},
+		{"x = 'a';", `x = "a";`, ast.CompareOptions{}, true},
+		{"a + 1;", "a + 2;", ast.CompareOptions{}, false},
+		{"a + 1;", "a - 1;", ast.CompareOptions{}, false},
+		{"a + 1;", "b + 1;", ast.CompareOptions{}, false},
+		{"x = -0;", "x = 0;", ast.CompareOptions{}, false},
+		{"f(a, b);", "f(a);", ast.CompareOptions{}, false},
+
+		// Bindings compare modulo renaming, and free variables by name.
+		{"function f(a) { return a; }", "function g(b) { return b; }", ast.CompareOptions{Renaming: true}, true},
+		{"function f(a) { return a; }", "function g(b) { return b; }", ast.CompareOptions{}, false},
+		{"function f(a, b) { return a; }", "function f(a, b) { return b; }", ast.CompareOptions{Renaming: true}, false},
+		{"function f(a) { return a + b; }", "function f(a) { return a + c; }", ast.CompareOptions{Renaming: true}, false},
+		{"var a = 1; var b = a;", "var x = 1; var y = x;", ast.CompareOptions{Renaming: true}, true},
+		{"var a = 1; var b = a;", "var x = 1; var y = y;", ast.CompareOptions{Renaming: true}, false},
+		{"o.a;", "o.b;", ast.CompareOptions{Renaming: true}, false},
+
+		// Names seen from outside are not renamed with their bindings.
+		{"var a; x = {a};", "var b; x = {b};", ast.CompareOptions{Renaming: true}, false},
+		{"var a; x = {a};", "var a; x = {a};", ast.CompareOptions{Renaming: true}, true},
+		{"var a; x = {k: a};", "var b; x = {k: b};", ast.CompareOptions{Renaming: true}, true},
+		{"var [a, b] = c; x = {a};", "var [b, a] = c; x = {a};", ast.CompareOptions{Renaming: true}, false},
+		{`import {x} from "m";`, `import {y} from "m";`, ast.CompareOptions{Renaming: true}, false},
+		{`import {x as a} from "m"; a;`, `import {x as b} from "m"; b;`, ast.CompareOptions{Renaming: true}, true},
+		{`import {x as a} from "m";`, `import {y as a} from "m";`, ast.CompareOptions{Renaming: true}, false},
+	}
+	for _, tt := range tests {
+		a, b := parse(t, tt.a), parse(t, tt.b)
+		if tt.opts.Renaming {
+			resolver.Resolve(a)
+			resolver.Resolve(b)
+		}
+		if got := ast.EqualWithOptions(a, b, tt.opts); got != tt.want {
+			t.Errorf("EqualWithOptions(%q, %q, %+v) = %v, want %v", tt.a, tt.b, tt.opts, got, tt.want)
+		}
+		if tt.want && ast.HashWithOptions(a, tt.opts) != ast.HashWithOptions(b, tt.opts) {
+			t.Errorf("%q and %q are equal with %+v but hash differently", tt.a, tt.b, tt.opts)
+		}
+	}
+}
+
+func TestEqualScopeContext(t *testing.T) {
+	// The same source resolves differently after a declaration is added, so
+	// the identifier a has another context.
+	a, b := parse(t, "a;"), parse(t, "a;")
+	resolver.Resolve(a)
+	b.Body[0].Stmt.(*ast.ExpressionStatement).Expression.Expr.(*ast.Identifier).ScopeContext = 7
+
+	if ast.Equal(a, b) {
+		t.Errorf("Equal ignored ScopeContext")
+	}
+	opts := ast.CompareOptions{IgnoreScopeContext: true}
+	if !ast.EqualWithOptions(a, b, opts) {
+		t.Errorf("EqualWithOptions(%+v) compared ScopeContext", opts)
+	}
+	if ast.HashWithOptions(a, opts) != ast.HashWithOptions(b, opts) {
+		t.Errorf("HashWithOptions(%+v) hashed ScopeContext", opts)
+	}
+}
+
+func TestHash(t *testing.T) {
+	srcs := []string{"a + 1;", "a + 2;", "a - 1;", "1 + a;", "f(a);", "f(a, b);", "x = [a];", "x = {a};", "x = {a: a};"}
+	seen := make(map[uint64]string)
+	for _, src := range srcs {
+		h := ast.Hash(parse(t, src))
+		if h != ast.Hash(parse(t, src)) {
+			t.Errorf("Hash(%q) is not stable", src)
+		}
+		if other, ok := seen[h]; ok {
+			t.Errorf("%q and %q have the same hash", src, other)
+		}
+		seen[h] = src
+	}
+}
@@ -0,0 +1,60 @@
+package ast
+
+import "reflect"
+
+// CloneOptions controls how CloneWithOptions adjusts the copy.
+type CloneOptions struct {
+	// ResetPositions sets every Idx in the copy to zero, for code moved to
+	// where its original positions are meaningless.
+	ResetPositions bool
+	// ScopeContext, if not nil, replaces every ScopeContext value in the copy,
+	// for example with fresh contexts when a function body is duplicated so
+	// the bindings of the two bodies stay apart.
+	ScopeContext func(ScopeContext) ScopeContext
+}
+
+// CloneWithOptions returns a deep copy of n, adjusted according to opts.
+func CloneWithOptions[N interface{ Clone() N }](n N, opts CloneOptions) N {
+	c := n.Clone()
+	if opts.ResetPositions || opts.ScopeContext != nil {
+		adjust(reflect.ValueOf(c), &opts)
+	}
+	return c
+}
+
+var scopeContextType = reflect.TypeOf(ScopeContext(0))
+
+// adjust applies opts to the fields of v and everything below it. The copy
+// made by Clone shares nothing with the original but the raw text of
+// literals, so it is modified in place.
+func adjust(v reflect.Value, opts *CloneOptions) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			adjust(v.Elem(), opts)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			adjust(v.Index(i), opts)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+			switch f.Type() {
+			case idxType:
+				if opts.ResetPositions {
+					f.SetInt(0)
+				}
+			case scopeContextType:
+				if opts.ScopeContext != nil {
+					f.SetInt(int64(opts.ScopeContext(ScopeContext(f.Int()))))
+				}
+			default:
+				adjust(f, opts)
+			}
+		}
+	}
+}
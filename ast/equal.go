@@ -0,0 +1,2629 @@
+// Code generated by gen_equal.go; DO NOT EDIT.
+package ast
+
+import "fmt"
+
+func (n *ArrayLiteral) equal(o *ArrayLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Value.equal(&o.Value, c)
+}
+
+func (n *ArrayLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ArrayLiteral")
+	n.Value.hash(h)
+}
+
+func (n *ArrayPattern) equal(o *ArrayPattern, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Elements.equal(&o.Elements, c) &&
+		n.Rest.equal(o.Rest, c)
+}
+
+func (n *ArrayPattern) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ArrayPattern")
+	n.Elements.hash(h)
+	n.Rest.hash(h)
+}
+
+func (n *ArrowFunctionLiteral) equal(o *ArrowFunctionLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.ParameterList.equal(&o.ParameterList, c) &&
+		n.Body.equal(o.Body, c) &&
+		n.Async == o.Async &&
+		c.scopeContext(n.ScopeContext, o.ScopeContext)
+}
+
+func (n *ArrowFunctionLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ArrowFunctionLiteral")
+	n.ParameterList.hash(h)
+	n.Body.hash(h)
+	h.bool(n.Async)
+	h.scopeContext(n.ScopeContext)
+}
+
+func (n *AssignExpression) equal(o *AssignExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Operator == o.Operator &&
+		n.Left.equal(o.Left, c) &&
+		n.Right.equal(o.Right, c)
+}
+
+func (n *AssignExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("AssignExpression")
+	h.int(int64(n.Operator))
+	n.Left.hash(h)
+	n.Right.hash(h)
+}
+
+func (n *AwaitExpression) equal(o *AwaitExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Argument.equal(o.Argument, c)
+}
+
+func (n *AwaitExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("AwaitExpression")
+	n.Argument.hash(h)
+}
+
+func (n *BadStatement) equal(o *BadStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return true
+}
+
+func (n *BadStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("BadStatement")
+}
+
+func (n *BinaryExpression) equal(o *BinaryExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Operator == o.Operator &&
+		n.Left.equal(o.Left, c) &&
+		n.Right.equal(o.Right, c)
+}
+
+func (n *BinaryExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("BinaryExpression")
+	h.int(int64(n.Operator))
+	n.Left.hash(h)
+	n.Right.hash(h)
+}
+
+func (n *BindingTarget) equal(o *BindingTarget, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalTarget(n.Target, o.Target, c)
+}
+
+func (n *BindingTarget) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("BindingTarget")
+	hashTarget(n.Target, h)
+}
+
+func (n *BlockStatement) equal(o *BlockStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.List.equal(&o.List, c) &&
+		c.scopeContext(n.ScopeContext, o.ScopeContext)
+}
+
+func (n *BlockStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("BlockStatement")
+	n.List.hash(h)
+	h.scopeContext(n.ScopeContext)
+}
+
+func (n *BooleanLiteral) equal(o *BooleanLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Value == o.Value
+}
+
+func (n *BooleanLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("BooleanLiteral")
+	h.bool(n.Value)
+}
+
+func (n *BreakStatement) equal(o *BreakStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Label.equal(o.Label, c)
+}
+
+func (n *BreakStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("BreakStatement")
+	n.Label.hash(h)
+}
+
+func (n *CallExpression) equal(o *CallExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Callee.equal(o.Callee, c) &&
+		n.ArgumentList.equal(&o.ArgumentList, c)
+}
+
+func (n *CallExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("CallExpression")
+	n.Callee.hash(h)
+	n.ArgumentList.hash(h)
+}
+
+func (n *CaseStatement) equal(o *CaseStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Test.equal(o.Test, c) &&
+		n.Consequent.equal(&o.Consequent, c)
+}
+
+func (n *CaseStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("CaseStatement")
+	n.Test.hash(h)
+	n.Consequent.hash(h)
+}
+
+func (n *CaseStatements) equal(o *CaseStatements, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *CaseStatements) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("CaseStatements")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *CatchStatement) equal(o *CatchStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Parameter.equal(o.Parameter, c) &&
+		n.Body.equal(o.Body, c)
+}
+
+func (n *CatchStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("CatchStatement")
+	n.Parameter.hash(h)
+	n.Body.hash(h)
+}
+
+func (n *ClassDeclaration) equal(o *ClassDeclaration, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Class.equal(o.Class, c)
+}
+
+func (n *ClassDeclaration) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ClassDeclaration")
+	n.Class.hash(h)
+}
+
+func (n *ClassElement) equal(o *ClassElement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalElement(n.Element, o.Element, c)
+}
+
+func (n *ClassElement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ClassElement")
+	hashElement(n.Element, h)
+}
+
+func (n *ClassElements) equal(o *ClassElements, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *ClassElements) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ClassElements")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *ClassLiteral) equal(o *ClassLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Name.equal(o.Name, c) &&
+		n.SuperClass.equal(o.SuperClass, c) &&
+		n.Body.equal(&o.Body, c)
+}
+
+func (n *ClassLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ClassLiteral")
+	n.Name.hash(h)
+	n.SuperClass.hash(h)
+	n.Body.hash(h)
+}
+
+func (n *ClassStaticBlock) equal(o *ClassStaticBlock, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Block.equal(o.Block, c)
+}
+
+func (n *ClassStaticBlock) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ClassStaticBlock")
+	n.Block.hash(h)
+}
+
+func (n *ComputedProperty) equal(o *ComputedProperty, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Expr.equal(o.Expr, c)
+}
+
+func (n *ComputedProperty) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ComputedProperty")
+	n.Expr.hash(h)
+}
+
+func (n *ConciseBody) equal(o *ConciseBody, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalBody(n.Body, o.Body, c)
+}
+
+func (n *ConciseBody) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ConciseBody")
+	hashBody(n.Body, h)
+}
+
+func (n *ConditionalExpression) equal(o *ConditionalExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Test.equal(o.Test, c) &&
+		n.Consequent.equal(o.Consequent, c) &&
+		n.Alternate.equal(o.Alternate, c)
+}
+
+func (n *ConditionalExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ConditionalExpression")
+	n.Test.hash(h)
+	n.Consequent.hash(h)
+	n.Alternate.hash(h)
+}
+
+func (n *ContinueStatement) equal(o *ContinueStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Label.equal(o.Label, c)
+}
+
+func (n *ContinueStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ContinueStatement")
+	n.Label.hash(h)
+}
+
+func (n *DebuggerStatement) equal(o *DebuggerStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return true
+}
+
+func (n *DebuggerStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("DebuggerStatement")
+}
+
+func (n *DoWhileStatement) equal(o *DoWhileStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Test.equal(o.Test, c) &&
+		n.Body.equal(o.Body, c)
+}
+
+func (n *DoWhileStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("DoWhileStatement")
+	n.Test.hash(h)
+	n.Body.hash(h)
+}
+
+func (n *EmptyStatement) equal(o *EmptyStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return true
+}
+
+func (n *EmptyStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("EmptyStatement")
+}
+
+func (n *ExportDeclaration) equal(o *ExportDeclaration, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Source.equal(o.Source, c) &&
+		n.Default.equal(o.Default, c) &&
+		n.Declaration.equal(o.Declaration, c) &&
+		n.Specifiers.equal(&o.Specifiers, c)
+}
+
+func (n *ExportDeclaration) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ExportDeclaration")
+	n.Source.hash(h)
+	n.Default.hash(h)
+	n.Declaration.hash(h)
+	n.Specifiers.hash(h)
+}
+
+func (n *ExportSpecifiers) equal(o *ExportSpecifiers, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *ExportSpecifiers) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ExportSpecifiers")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *Expression) equal(o *Expression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalExpr(n.Expr, o.Expr, c)
+}
+
+func (n *Expression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Expression")
+	hashExpr(n.Expr, h)
+}
+
+func (n *ExpressionStatement) equal(o *ExpressionStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Expression.equal(o.Expression, c)
+}
+
+func (n *ExpressionStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ExpressionStatement")
+	n.Expression.hash(h)
+}
+
+func (n *Expressions) equal(o *Expressions, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *Expressions) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Expressions")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *FieldDefinition) equal(o *FieldDefinition, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Key.equal(o.Key, c) &&
+		n.Initializer.equal(o.Initializer, c) &&
+		n.Computed == o.Computed &&
+		n.Static == o.Static
+}
+
+func (n *FieldDefinition) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("FieldDefinition")
+	n.Key.hash(h)
+	n.Initializer.hash(h)
+	h.bool(n.Computed)
+	h.bool(n.Static)
+}
+
+func (n *ForInStatement) equal(o *ForInStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Into.equal(o.Into, c) &&
+		n.Source.equal(o.Source, c) &&
+		n.Body.equal(o.Body, c)
+}
+
+func (n *ForInStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ForInStatement")
+	n.Into.hash(h)
+	n.Source.hash(h)
+	n.Body.hash(h)
+}
+
+func (n *ForInto) equal(o *ForInto, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalInto(n.Into, o.Into, c)
+}
+
+func (n *ForInto) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ForInto")
+	hashInto(n.Into, h)
+}
+
+func (n *ForLoopInitializer) equal(o *ForLoopInitializer, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalForLoopInit(n.Initializer, o.Initializer, c)
+}
+
+func (n *ForLoopInitializer) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ForLoopInitializer")
+	hashForLoopInit(n.Initializer, h)
+}
+
+func (n *ForOfStatement) equal(o *ForOfStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Into.equal(o.Into, c) &&
+		n.Source.equal(o.Source, c) &&
+		n.Body.equal(o.Body, c)
+}
+
+func (n *ForOfStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ForOfStatement")
+	n.Into.hash(h)
+	n.Source.hash(h)
+	n.Body.hash(h)
+}
+
+func (n *ForStatement) equal(o *ForStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Initializer.equal(o.Initializer, c) &&
+		n.Update.equal(o.Update, c) &&
+		n.Test.equal(o.Test, c) &&
+		n.Body.equal(o.Body, c)
+}
+
+func (n *ForStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ForStatement")
+	n.Initializer.hash(h)
+	n.Update.hash(h)
+	n.Test.hash(h)
+	n.Body.hash(h)
+}
+
+func (n *FunctionDeclaration) equal(o *FunctionDeclaration, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Function.equal(o.Function, c)
+}
+
+func (n *FunctionDeclaration) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("FunctionDeclaration")
+	n.Function.hash(h)
+}
+
+func (n *FunctionLiteral) equal(o *FunctionLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Name.equal(o.Name, c) &&
+		n.ParameterList.equal(&o.ParameterList, c) &&
+		n.Body.equal(o.Body, c) &&
+		n.Async == o.Async &&
+		n.Generator == o.Generator &&
+		c.scopeContext(n.ScopeContext, o.ScopeContext)
+}
+
+func (n *FunctionLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("FunctionLiteral")
+	n.Name.hash(h)
+	n.ParameterList.hash(h)
+	n.Body.hash(h)
+	h.bool(n.Async)
+	h.bool(n.Generator)
+	h.scopeContext(n.ScopeContext)
+}
+
+func (n *IfStatement) equal(o *IfStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Test.equal(o.Test, c) &&
+		n.Consequent.equal(o.Consequent, c) &&
+		n.Alternate.equal(o.Alternate, c)
+}
+
+func (n *IfStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("IfStatement")
+	n.Test.hash(h)
+	n.Consequent.hash(h)
+	n.Alternate.hash(h)
+}
+
+func (n *ImportDeclaration) equal(o *ImportDeclaration, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Source.equal(o.Source, c) &&
+		n.Default.equal(o.Default, c) &&
+		n.Namespace.equal(o.Namespace, c) &&
+		n.Specifiers.equal(&o.Specifiers, c)
+}
+
+func (n *ImportDeclaration) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ImportDeclaration")
+	n.Source.hash(h)
+	n.Default.hash(h)
+	n.Namespace.hash(h)
+	n.Specifiers.hash(h)
+}
+
+func (n *ImportSpecifiers) equal(o *ImportSpecifiers, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *ImportSpecifiers) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ImportSpecifiers")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *InvalidExpression) equal(o *InvalidExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return true
+}
+
+func (n *InvalidExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("InvalidExpression")
+}
+
+func (n *LabelledStatement) equal(o *LabelledStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Label.equal(o.Label, c) &&
+		n.Statement.equal(o.Statement, c)
+}
+
+func (n *LabelledStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("LabelledStatement")
+	n.Label.hash(h)
+	n.Statement.hash(h)
+}
+
+func (n *MemberExpression) equal(o *MemberExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Object.equal(o.Object, c) &&
+		n.Property.equal(o.Property, c)
+}
+
+func (n *MemberExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("MemberExpression")
+	n.Object.hash(h)
+	n.Property.hash(h)
+}
+
+func (n *MethodDefinition) equal(o *MethodDefinition, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Key.equal(o.Key, c) &&
+		n.Kind == o.Kind &&
+		n.Body.equal(o.Body, c) &&
+		n.Computed == o.Computed &&
+		n.Static == o.Static
+}
+
+func (n *MethodDefinition) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("MethodDefinition")
+	n.Key.hash(h)
+	h.string(string(n.Kind))
+	n.Body.hash(h)
+	h.bool(n.Computed)
+	h.bool(n.Static)
+}
+
+func (n *NewExpression) equal(o *NewExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Callee.equal(o.Callee, c) &&
+		n.ArgumentList.equal(&o.ArgumentList, c)
+}
+
+func (n *NewExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("NewExpression")
+	n.Callee.hash(h)
+	n.ArgumentList.hash(h)
+}
+
+func (n *NullLiteral) equal(o *NullLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return true
+}
+
+func (n *NullLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("NullLiteral")
+}
+
+func (n *NumberLiteral) equal(o *NumberLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalFloat(n.Value, o.Value)
+}
+
+func (n *NumberLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("NumberLiteral")
+	h.float(n.Value)
+}
+
+func (n *ObjectLiteral) equal(o *ObjectLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Value.equal(&o.Value, c)
+}
+
+func (n *ObjectLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ObjectLiteral")
+	n.Value.hash(h)
+}
+
+func (n *ObjectPattern) equal(o *ObjectPattern, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Properties.equal(&o.Properties, c) &&
+		equalExpr(n.Rest, o.Rest, c)
+}
+
+func (n *ObjectPattern) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ObjectPattern")
+	n.Properties.hash(h)
+	hashExpr(n.Rest, h)
+}
+
+func (n *Optional) equal(o *Optional, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Expr.equal(o.Expr, c)
+}
+
+func (n *Optional) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Optional")
+	n.Expr.hash(h)
+}
+
+func (n *OptionalChain) equal(o *OptionalChain, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Base.equal(o.Base, c)
+}
+
+func (n *OptionalChain) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("OptionalChain")
+	n.Base.hash(h)
+}
+
+func (n *ParameterList) equal(o *ParameterList, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.List.equal(&o.List, c) &&
+		equalExpr(n.Rest, o.Rest, c)
+}
+
+func (n *ParameterList) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ParameterList")
+	n.List.hash(h)
+	hashExpr(n.Rest, h)
+}
+
+func (n *PrivateDotExpression) equal(o *PrivateDotExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Left.equal(o.Left, c) &&
+		n.Identifier.equal(o.Identifier, c)
+}
+
+func (n *PrivateDotExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("PrivateDotExpression")
+	n.Left.hash(h)
+	n.Identifier.hash(h)
+}
+
+func (n *Program) equal(o *Program, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Body.equal(&o.Body, c)
+}
+
+func (n *Program) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Program")
+	n.Body.hash(h)
+}
+
+func (n *Properties) equal(o *Properties, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *Properties) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Properties")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *Property) equal(o *Property, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalProp(n.Prop, o.Prop, c)
+}
+
+func (n *Property) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Property")
+	hashProp(n.Prop, h)
+}
+
+func (n *PropertyKeyed) equal(o *PropertyKeyed, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Key.equal(o.Key, c) &&
+		n.Kind == o.Kind &&
+		n.Value.equal(o.Value, c) &&
+		n.Computed == o.Computed
+}
+
+func (n *PropertyKeyed) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("PropertyKeyed")
+	n.Key.hash(h)
+	h.string(string(n.Kind))
+	n.Value.hash(h)
+	h.bool(n.Computed)
+}
+
+func (n *RegExpLiteral) equal(o *RegExpLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Literal == o.Literal &&
+		n.Pattern == o.Pattern &&
+		n.Flags == o.Flags
+}
+
+func (n *RegExpLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("RegExpLiteral")
+	h.string(string(n.Literal))
+	h.string(string(n.Pattern))
+	h.string(string(n.Flags))
+}
+
+func (n *ReturnStatement) equal(o *ReturnStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Argument.equal(o.Argument, c)
+}
+
+func (n *ReturnStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ReturnStatement")
+	n.Argument.hash(h)
+}
+
+func (n *SequenceExpression) equal(o *SequenceExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Sequence.equal(&o.Sequence, c)
+}
+
+func (n *SequenceExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("SequenceExpression")
+	n.Sequence.hash(h)
+}
+
+func (n *SpreadElement) equal(o *SpreadElement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Expression.equal(o.Expression, c)
+}
+
+func (n *SpreadElement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("SpreadElement")
+	n.Expression.hash(h)
+}
+
+func (n *Statement) equal(o *Statement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return equalStmt(n.Stmt, o.Stmt, c)
+}
+
+func (n *Statement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Statement")
+	hashStmt(n.Stmt, h)
+}
+
+func (n *Statements) equal(o *Statements, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *Statements) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("Statements")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *StringLiteral) equal(o *StringLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Value == o.Value
+}
+
+func (n *StringLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("StringLiteral")
+	h.string(string(n.Value))
+}
+
+func (n *SuperExpression) equal(o *SuperExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return true
+}
+
+func (n *SuperExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("SuperExpression")
+}
+
+func (n *SwitchStatement) equal(o *SwitchStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Discriminant.equal(o.Discriminant, c) &&
+		n.Default == o.Default &&
+		n.Body.equal(&o.Body, c)
+}
+
+func (n *SwitchStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("SwitchStatement")
+	n.Discriminant.hash(h)
+	h.int(int64(n.Default))
+	n.Body.hash(h)
+}
+
+func (n *TemplateElement) equal(o *TemplateElement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Literal == o.Literal &&
+		n.Parsed == o.Parsed &&
+		n.Valid == o.Valid
+}
+
+func (n *TemplateElement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("TemplateElement")
+	h.string(string(n.Literal))
+	h.string(string(n.Parsed))
+	h.bool(n.Valid)
+}
+
+func (n *TemplateElements) equal(o *TemplateElements, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *TemplateElements) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("TemplateElements")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *TemplateLiteral) equal(o *TemplateLiteral, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Tag.equal(o.Tag, c) &&
+		n.Elements.equal(&o.Elements, c) &&
+		n.Expressions.equal(&o.Expressions, c)
+}
+
+func (n *TemplateLiteral) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("TemplateLiteral")
+	n.Tag.hash(h)
+	n.Elements.hash(h)
+	n.Expressions.hash(h)
+}
+
+func (n *ThisExpression) equal(o *ThisExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return true
+}
+
+func (n *ThisExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ThisExpression")
+}
+
+func (n *ThrowStatement) equal(o *ThrowStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Argument.equal(o.Argument, c)
+}
+
+func (n *ThrowStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("ThrowStatement")
+	n.Argument.hash(h)
+}
+
+func (n *TryStatement) equal(o *TryStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Body.equal(o.Body, c) &&
+		n.Catch.equal(o.Catch, c) &&
+		n.Finally.equal(o.Finally, c)
+}
+
+func (n *TryStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("TryStatement")
+	n.Body.hash(h)
+	n.Catch.hash(h)
+	n.Finally.hash(h)
+}
+
+func (n *UnaryExpression) equal(o *UnaryExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Operator == o.Operator &&
+		n.Operand.equal(o.Operand, c)
+}
+
+func (n *UnaryExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("UnaryExpression")
+	h.int(int64(n.Operator))
+	n.Operand.hash(h)
+}
+
+func (n *UpdateExpression) equal(o *UpdateExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Operator == o.Operator &&
+		n.Operand.equal(o.Operand, c) &&
+		n.Postfix == o.Postfix
+}
+
+func (n *UpdateExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("UpdateExpression")
+	h.int(int64(n.Operator))
+	n.Operand.hash(h)
+	h.bool(n.Postfix)
+}
+
+func (n *VariableDeclaration) equal(o *VariableDeclaration, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Token == o.Token &&
+		n.List.equal(&o.List, c)
+}
+
+func (n *VariableDeclaration) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("VariableDeclaration")
+	h.int(int64(n.Token))
+	n.List.hash(h)
+}
+
+func (n *VariableDeclarator) equal(o *VariableDeclarator, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Target.equal(o.Target, c) &&
+		n.Initializer.equal(o.Initializer, c)
+}
+
+func (n *VariableDeclarator) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("VariableDeclarator")
+	n.Target.hash(h)
+	n.Initializer.hash(h)
+}
+
+func (n *VariableDeclarators) equal(o *VariableDeclarators, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if len(*n) != len(*o) {
+		return false
+	}
+	for i := range *n {
+		if !(*n)[i].equal(&(*o)[i], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *VariableDeclarators) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("VariableDeclarators")
+	h.int(int64(len(*n)))
+	for i := range *n {
+		(*n)[i].hash(h)
+	}
+}
+
+func (n *WhileStatement) equal(o *WhileStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Test.equal(o.Test, c) &&
+		n.Body.equal(o.Body, c)
+}
+
+func (n *WhileStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("WhileStatement")
+	n.Test.hash(h)
+	n.Body.hash(h)
+}
+
+func (n *WithStatement) equal(o *WithStatement, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Object.equal(o.Object, c) &&
+		n.Body.equal(o.Body, c)
+}
+
+func (n *WithStatement) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("WithStatement")
+	n.Object.hash(h)
+	n.Body.hash(h)
+}
+
+func (n *YieldExpression) equal(o *YieldExpression, c *comparer) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Argument.equal(o.Argument, c) &&
+		n.Delegate == o.Delegate
+}
+
+func (n *YieldExpression) hash(h *hasher) {
+	if n == nil {
+		h.nil()
+		return
+	}
+	h.tag("YieldExpression")
+	n.Argument.hash(h)
+	h.bool(n.Delegate)
+}
+
+func equalBody(a, b Body, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *BlockStatement:
+		b, ok := b.(*BlockStatement)
+		return ok && a.equal(b, c)
+	case *Expression:
+		b, ok := b.(*Expression)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected Body variant %T", a))
+}
+
+func hashBody(n Body, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *BlockStatement:
+		n.hash(h)
+	case *Expression:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected Body variant %T", n))
+	}
+}
+
+func equalElement(a, b Element, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *ClassStaticBlock:
+		b, ok := b.(*ClassStaticBlock)
+		return ok && a.equal(b, c)
+	case *FieldDefinition:
+		b, ok := b.(*FieldDefinition)
+		return ok && a.equal(b, c)
+	case *MethodDefinition:
+		b, ok := b.(*MethodDefinition)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected Element variant %T", a))
+}
+
+func hashElement(n Element, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *ClassStaticBlock:
+		n.hash(h)
+	case *FieldDefinition:
+		n.hash(h)
+	case *MethodDefinition:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected Element variant %T", n))
+	}
+}
+
+func equalExpr(a, b Expr, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *ArrayLiteral:
+		b, ok := b.(*ArrayLiteral)
+		return ok && a.equal(b, c)
+	case *ArrayPattern:
+		b, ok := b.(*ArrayPattern)
+		return ok && a.equal(b, c)
+	case *ArrowFunctionLiteral:
+		b, ok := b.(*ArrowFunctionLiteral)
+		return ok && a.equal(b, c)
+	case *AssignExpression:
+		b, ok := b.(*AssignExpression)
+		return ok && a.equal(b, c)
+	case *AwaitExpression:
+		b, ok := b.(*AwaitExpression)
+		return ok && a.equal(b, c)
+	case *BinaryExpression:
+		b, ok := b.(*BinaryExpression)
+		return ok && a.equal(b, c)
+	case *BooleanLiteral:
+		b, ok := b.(*BooleanLiteral)
+		return ok && a.equal(b, c)
+	case *CallExpression:
+		b, ok := b.(*CallExpression)
+		return ok && a.equal(b, c)
+	case *ClassLiteral:
+		b, ok := b.(*ClassLiteral)
+		return ok && a.equal(b, c)
+	case *ConditionalExpression:
+		b, ok := b.(*ConditionalExpression)
+		return ok && a.equal(b, c)
+	case *FunctionLiteral:
+		b, ok := b.(*FunctionLiteral)
+		return ok && a.equal(b, c)
+	case *Identifier:
+		b, ok := b.(*Identifier)
+		return ok && a.equal(b, c)
+	case *InvalidExpression:
+		b, ok := b.(*InvalidExpression)
+		return ok && a.equal(b, c)
+	case *MemberExpression:
+		b, ok := b.(*MemberExpression)
+		return ok && a.equal(b, c)
+	case *MetaProperty:
+		b, ok := b.(*MetaProperty)
+		return ok && a.equal(b, c)
+	case *NewExpression:
+		b, ok := b.(*NewExpression)
+		return ok && a.equal(b, c)
+	case *NullLiteral:
+		b, ok := b.(*NullLiteral)
+		return ok && a.equal(b, c)
+	case *NumberLiteral:
+		b, ok := b.(*NumberLiteral)
+		return ok && a.equal(b, c)
+	case *ObjectLiteral:
+		b, ok := b.(*ObjectLiteral)
+		return ok && a.equal(b, c)
+	case *ObjectPattern:
+		b, ok := b.(*ObjectPattern)
+		return ok && a.equal(b, c)
+	case *Optional:
+		b, ok := b.(*Optional)
+		return ok && a.equal(b, c)
+	case *OptionalChain:
+		b, ok := b.(*OptionalChain)
+		return ok && a.equal(b, c)
+	case *PrivateDotExpression:
+		b, ok := b.(*PrivateDotExpression)
+		return ok && a.equal(b, c)
+	case *PrivateIdentifier:
+		b, ok := b.(*PrivateIdentifier)
+		return ok && a.equal(b, c)
+	case *PropertyKeyed:
+		b, ok := b.(*PropertyKeyed)
+		return ok && a.equal(b, c)
+	case *PropertyShort:
+		b, ok := b.(*PropertyShort)
+		return ok && a.equal(b, c)
+	case *RegExpLiteral:
+		b, ok := b.(*RegExpLiteral)
+		return ok && a.equal(b, c)
+	case *SequenceExpression:
+		b, ok := b.(*SequenceExpression)
+		return ok && a.equal(b, c)
+	case *SpreadElement:
+		b, ok := b.(*SpreadElement)
+		return ok && a.equal(b, c)
+	case *StringLiteral:
+		b, ok := b.(*StringLiteral)
+		return ok && a.equal(b, c)
+	case *SuperExpression:
+		b, ok := b.(*SuperExpression)
+		return ok && a.equal(b, c)
+	case *TemplateLiteral:
+		b, ok := b.(*TemplateLiteral)
+		return ok && a.equal(b, c)
+	case *ThisExpression:
+		b, ok := b.(*ThisExpression)
+		return ok && a.equal(b, c)
+	case *UnaryExpression:
+		b, ok := b.(*UnaryExpression)
+		return ok && a.equal(b, c)
+	case *UpdateExpression:
+		b, ok := b.(*UpdateExpression)
+		return ok && a.equal(b, c)
+	case *VariableDeclarator:
+		b, ok := b.(*VariableDeclarator)
+		return ok && a.equal(b, c)
+	case *YieldExpression:
+		b, ok := b.(*YieldExpression)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected Expr variant %T", a))
+}
+
+func hashExpr(n Expr, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *ArrayLiteral:
+		n.hash(h)
+	case *ArrayPattern:
+		n.hash(h)
+	case *ArrowFunctionLiteral:
+		n.hash(h)
+	case *AssignExpression:
+		n.hash(h)
+	case *AwaitExpression:
+		n.hash(h)
+	case *BinaryExpression:
+		n.hash(h)
+	case *BooleanLiteral:
+		n.hash(h)
+	case *CallExpression:
+		n.hash(h)
+	case *ClassLiteral:
+		n.hash(h)
+	case *ConditionalExpression:
+		n.hash(h)
+	case *FunctionLiteral:
+		n.hash(h)
+	case *Identifier:
+		n.hash(h)
+	case *InvalidExpression:
+		n.hash(h)
+	case *MemberExpression:
+		n.hash(h)
+	case *MetaProperty:
+		n.hash(h)
+	case *NewExpression:
+		n.hash(h)
+	case *NullLiteral:
+		n.hash(h)
+	case *NumberLiteral:
+		n.hash(h)
+	case *ObjectLiteral:
+		n.hash(h)
+	case *ObjectPattern:
+		n.hash(h)
+	case *Optional:
+		n.hash(h)
+	case *OptionalChain:
+		n.hash(h)
+	case *PrivateDotExpression:
+		n.hash(h)
+	case *PrivateIdentifier:
+		n.hash(h)
+	case *PropertyKeyed:
+		n.hash(h)
+	case *PropertyShort:
+		n.hash(h)
+	case *RegExpLiteral:
+		n.hash(h)
+	case *SequenceExpression:
+		n.hash(h)
+	case *SpreadElement:
+		n.hash(h)
+	case *StringLiteral:
+		n.hash(h)
+	case *SuperExpression:
+		n.hash(h)
+	case *TemplateLiteral:
+		n.hash(h)
+	case *ThisExpression:
+		n.hash(h)
+	case *UnaryExpression:
+		n.hash(h)
+	case *UpdateExpression:
+		n.hash(h)
+	case *VariableDeclarator:
+		n.hash(h)
+	case *YieldExpression:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected Expr variant %T", n))
+	}
+}
+
+func equalForLoopInit(a, b ForLoopInit, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *Expression:
+		b, ok := b.(*Expression)
+		return ok && a.equal(b, c)
+	case *VariableDeclaration:
+		b, ok := b.(*VariableDeclaration)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected ForLoopInit variant %T", a))
+}
+
+func hashForLoopInit(n ForLoopInit, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *Expression:
+		n.hash(h)
+	case *VariableDeclaration:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected ForLoopInit variant %T", n))
+	}
+}
+
+func equalInto(a, b Into, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *Expression:
+		b, ok := b.(*Expression)
+		return ok && a.equal(b, c)
+	case *VariableDeclaration:
+		b, ok := b.(*VariableDeclaration)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected Into variant %T", a))
+}
+
+func hashInto(n Into, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *Expression:
+		n.hash(h)
+	case *VariableDeclaration:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected Into variant %T", n))
+	}
+}
+
+func equalMemberProp(a, b MemberProp, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *ComputedProperty:
+		b, ok := b.(*ComputedProperty)
+		return ok && a.equal(b, c)
+	case *Identifier:
+		b, ok := b.(*Identifier)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected MemberProp variant %T", a))
+}
+
+func hashMemberProp(n MemberProp, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *ComputedProperty:
+		n.hash(h)
+	case *Identifier:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected MemberProp variant %T", n))
+	}
+}
+
+func equalPattern(a, b Pattern, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *ArrayPattern:
+		b, ok := b.(*ArrayPattern)
+		return ok && a.equal(b, c)
+	case *ObjectPattern:
+		b, ok := b.(*ObjectPattern)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected Pattern variant %T", a))
+}
+
+func hashPattern(n Pattern, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *ArrayPattern:
+		n.hash(h)
+	case *ObjectPattern:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected Pattern variant %T", n))
+	}
+}
+
+func equalProp(a, b Prop, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *PropertyKeyed:
+		b, ok := b.(*PropertyKeyed)
+		return ok && a.equal(b, c)
+	case *PropertyShort:
+		b, ok := b.(*PropertyShort)
+		return ok && a.equal(b, c)
+	case *SpreadElement:
+		b, ok := b.(*SpreadElement)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected Prop variant %T", a))
+}
+
+func hashProp(n Prop, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *PropertyKeyed:
+		n.hash(h)
+	case *PropertyShort:
+		n.hash(h)
+	case *SpreadElement:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected Prop variant %T", n))
+	}
+}
+
+func equalStmt(a, b Stmt, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *BadStatement:
+		b, ok := b.(*BadStatement)
+		return ok && a.equal(b, c)
+	case *BlockStatement:
+		b, ok := b.(*BlockStatement)
+		return ok && a.equal(b, c)
+	case *BreakStatement:
+		b, ok := b.(*BreakStatement)
+		return ok && a.equal(b, c)
+	case *CaseStatement:
+		b, ok := b.(*CaseStatement)
+		return ok && a.equal(b, c)
+	case *CatchStatement:
+		b, ok := b.(*CatchStatement)
+		return ok && a.equal(b, c)
+	case *ClassDeclaration:
+		b, ok := b.(*ClassDeclaration)
+		return ok && a.equal(b, c)
+	case *ContinueStatement:
+		b, ok := b.(*ContinueStatement)
+		return ok && a.equal(b, c)
+	case *DebuggerStatement:
+		b, ok := b.(*DebuggerStatement)
+		return ok && a.equal(b, c)
+	case *DoWhileStatement:
+		b, ok := b.(*DoWhileStatement)
+		return ok && a.equal(b, c)
+	case *EmptyStatement:
+		b, ok := b.(*EmptyStatement)
+		return ok && a.equal(b, c)
+	case *ExportDeclaration:
+		b, ok := b.(*ExportDeclaration)
+		return ok && a.equal(b, c)
+	case *ExpressionStatement:
+		b, ok := b.(*ExpressionStatement)
+		return ok && a.equal(b, c)
+	case *ForInStatement:
+		b, ok := b.(*ForInStatement)
+		return ok && a.equal(b, c)
+	case *ForOfStatement:
+		b, ok := b.(*ForOfStatement)
+		return ok && a.equal(b, c)
+	case *ForStatement:
+		b, ok := b.(*ForStatement)
+		return ok && a.equal(b, c)
+	case *FunctionDeclaration:
+		b, ok := b.(*FunctionDeclaration)
+		return ok && a.equal(b, c)
+	case *IfStatement:
+		b, ok := b.(*IfStatement)
+		return ok && a.equal(b, c)
+	case *ImportDeclaration:
+		b, ok := b.(*ImportDeclaration)
+		return ok && a.equal(b, c)
+	case *LabelledStatement:
+		b, ok := b.(*LabelledStatement)
+		return ok && a.equal(b, c)
+	case *ReturnStatement:
+		b, ok := b.(*ReturnStatement)
+		return ok && a.equal(b, c)
+	case *SwitchStatement:
+		b, ok := b.(*SwitchStatement)
+		return ok && a.equal(b, c)
+	case *ThrowStatement:
+		b, ok := b.(*ThrowStatement)
+		return ok && a.equal(b, c)
+	case *TryStatement:
+		b, ok := b.(*TryStatement)
+		return ok && a.equal(b, c)
+	case *VariableDeclaration:
+		b, ok := b.(*VariableDeclaration)
+		return ok && a.equal(b, c)
+	case *WhileStatement:
+		b, ok := b.(*WhileStatement)
+		return ok && a.equal(b, c)
+	case *WithStatement:
+		b, ok := b.(*WithStatement)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected Stmt variant %T", a))
+}
+
+func hashStmt(n Stmt, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *BadStatement:
+		n.hash(h)
+	case *BlockStatement:
+		n.hash(h)
+	case *BreakStatement:
+		n.hash(h)
+	case *CaseStatement:
+		n.hash(h)
+	case *CatchStatement:
+		n.hash(h)
+	case *ClassDeclaration:
+		n.hash(h)
+	case *ContinueStatement:
+		n.hash(h)
+	case *DebuggerStatement:
+		n.hash(h)
+	case *DoWhileStatement:
+		n.hash(h)
+	case *EmptyStatement:
+		n.hash(h)
+	case *ExportDeclaration:
+		n.hash(h)
+	case *ExpressionStatement:
+		n.hash(h)
+	case *ForInStatement:
+		n.hash(h)
+	case *ForOfStatement:
+		n.hash(h)
+	case *ForStatement:
+		n.hash(h)
+	case *FunctionDeclaration:
+		n.hash(h)
+	case *IfStatement:
+		n.hash(h)
+	case *ImportDeclaration:
+		n.hash(h)
+	case *LabelledStatement:
+		n.hash(h)
+	case *ReturnStatement:
+		n.hash(h)
+	case *SwitchStatement:
+		n.hash(h)
+	case *ThrowStatement:
+		n.hash(h)
+	case *TryStatement:
+		n.hash(h)
+	case *VariableDeclaration:
+		n.hash(h)
+	case *WhileStatement:
+		n.hash(h)
+	case *WithStatement:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected Stmt variant %T", n))
+	}
+}
+
+func equalTarget(a, b Target, c *comparer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch a := a.(type) {
+	case *ArrayPattern:
+		b, ok := b.(*ArrayPattern)
+		return ok && a.equal(b, c)
+	case *Identifier:
+		b, ok := b.(*Identifier)
+		return ok && a.equal(b, c)
+	case *InvalidExpression:
+		b, ok := b.(*InvalidExpression)
+		return ok && a.equal(b, c)
+	case *MemberExpression:
+		b, ok := b.(*MemberExpression)
+		return ok && a.equal(b, c)
+	case *ObjectPattern:
+		b, ok := b.(*ObjectPattern)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected Target variant %T", a))
+}
+
+func hashTarget(n Target, h *hasher) {
+	switch n := n.(type) {
+	case nil:
+		h.nil()
+	case *ArrayPattern:
+		n.hash(h)
+	case *Identifier:
+		n.hash(h)
+	case *InvalidExpression:
+		n.hash(h)
+	case *MemberExpression:
+		n.hash(h)
+	case *ObjectPattern:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected Target variant %T", n))
+	}
+}
+
+func equalNode(a, b VisitableNode, c *comparer) bool {
+	switch a := a.(type) {
+	case *ArrayLiteral:
+		b, ok := b.(*ArrayLiteral)
+		return ok && a.equal(b, c)
+	case *ArrayPattern:
+		b, ok := b.(*ArrayPattern)
+		return ok && a.equal(b, c)
+	case *ArrowFunctionLiteral:
+		b, ok := b.(*ArrowFunctionLiteral)
+		return ok && a.equal(b, c)
+	case *AssignExpression:
+		b, ok := b.(*AssignExpression)
+		return ok && a.equal(b, c)
+	case *AwaitExpression:
+		b, ok := b.(*AwaitExpression)
+		return ok && a.equal(b, c)
+	case *BadStatement:
+		b, ok := b.(*BadStatement)
+		return ok && a.equal(b, c)
+	case *BinaryExpression:
+		b, ok := b.(*BinaryExpression)
+		return ok && a.equal(b, c)
+	case *BindingTarget:
+		b, ok := b.(*BindingTarget)
+		return ok && a.equal(b, c)
+	case *BlockStatement:
+		b, ok := b.(*BlockStatement)
+		return ok && a.equal(b, c)
+	case *BooleanLiteral:
+		b, ok := b.(*BooleanLiteral)
+		return ok && a.equal(b, c)
+	case *BreakStatement:
+		b, ok := b.(*BreakStatement)
+		return ok && a.equal(b, c)
+	case *CallExpression:
+		b, ok := b.(*CallExpression)
+		return ok && a.equal(b, c)
+	case *CaseStatement:
+		b, ok := b.(*CaseStatement)
+		return ok && a.equal(b, c)
+	case *CaseStatements:
+		b, ok := b.(*CaseStatements)
+		return ok && a.equal(b, c)
+	case *CatchStatement:
+		b, ok := b.(*CatchStatement)
+		return ok && a.equal(b, c)
+	case *ClassDeclaration:
+		b, ok := b.(*ClassDeclaration)
+		return ok && a.equal(b, c)
+	case *ClassElement:
+		b, ok := b.(*ClassElement)
+		return ok && a.equal(b, c)
+	case *ClassElements:
+		b, ok := b.(*ClassElements)
+		return ok && a.equal(b, c)
+	case *ClassLiteral:
+		b, ok := b.(*ClassLiteral)
+		return ok && a.equal(b, c)
+	case *ClassStaticBlock:
+		b, ok := b.(*ClassStaticBlock)
+		return ok && a.equal(b, c)
+	case *ComputedProperty:
+		b, ok := b.(*ComputedProperty)
+		return ok && a.equal(b, c)
+	case *ConciseBody:
+		b, ok := b.(*ConciseBody)
+		return ok && a.equal(b, c)
+	case *ConditionalExpression:
+		b, ok := b.(*ConditionalExpression)
+		return ok && a.equal(b, c)
+	case *ContinueStatement:
+		b, ok := b.(*ContinueStatement)
+		return ok && a.equal(b, c)
+	case *DebuggerStatement:
+		b, ok := b.(*DebuggerStatement)
+		return ok && a.equal(b, c)
+	case *DoWhileStatement:
+		b, ok := b.(*DoWhileStatement)
+		return ok && a.equal(b, c)
+	case *EmptyStatement:
+		b, ok := b.(*EmptyStatement)
+		return ok && a.equal(b, c)
+	case *ExportDeclaration:
+		b, ok := b.(*ExportDeclaration)
+		return ok && a.equal(b, c)
+	case *ExportSpecifier:
+		b, ok := b.(*ExportSpecifier)
+		return ok && a.equal(b, c)
+	case *ExportSpecifiers:
+		b, ok := b.(*ExportSpecifiers)
+		return ok && a.equal(b, c)
+	case *Expression:
+		b, ok := b.(*Expression)
+		return ok && a.equal(b, c)
+	case *ExpressionStatement:
+		b, ok := b.(*ExpressionStatement)
+		return ok && a.equal(b, c)
+	case *Expressions:
+		b, ok := b.(*Expressions)
+		return ok && a.equal(b, c)
+	case *FieldDefinition:
+		b, ok := b.(*FieldDefinition)
+		return ok && a.equal(b, c)
+	case *ForInStatement:
+		b, ok := b.(*ForInStatement)
+		return ok && a.equal(b, c)
+	case *ForInto:
+		b, ok := b.(*ForInto)
+		return ok && a.equal(b, c)
+	case *ForLoopInitializer:
+		b, ok := b.(*ForLoopInitializer)
+		return ok && a.equal(b, c)
+	case *ForOfStatement:
+		b, ok := b.(*ForOfStatement)
+		return ok && a.equal(b, c)
+	case *ForStatement:
+		b, ok := b.(*ForStatement)
+		return ok && a.equal(b, c)
+	case *FunctionDeclaration:
+		b, ok := b.(*FunctionDeclaration)
+		return ok && a.equal(b, c)
+	case *FunctionLiteral:
+		b, ok := b.(*FunctionLiteral)
+		return ok && a.equal(b, c)
+	case *Identifier:
+		b, ok := b.(*Identifier)
+		return ok && a.equal(b, c)
+	case *IfStatement:
+		b, ok := b.(*IfStatement)
+		return ok && a.equal(b, c)
+	case *ImportDeclaration:
+		b, ok := b.(*ImportDeclaration)
+		return ok && a.equal(b, c)
+	case *ImportSpecifier:
+		b, ok := b.(*ImportSpecifier)
+		return ok && a.equal(b, c)
+	case *ImportSpecifiers:
+		b, ok := b.(*ImportSpecifiers)
+		return ok && a.equal(b, c)
+	case *InvalidExpression:
+		b, ok := b.(*InvalidExpression)
+		return ok && a.equal(b, c)
+	case *LabelledStatement:
+		b, ok := b.(*LabelledStatement)
+		return ok && a.equal(b, c)
+	case *MemberExpression:
+		b, ok := b.(*MemberExpression)
+		return ok && a.equal(b, c)
+	case *MemberProperty:
+		b, ok := b.(*MemberProperty)
+		return ok && a.equal(b, c)
+	case *MetaProperty:
+		b, ok := b.(*MetaProperty)
+		return ok && a.equal(b, c)
+	case *MethodDefinition:
+		b, ok := b.(*MethodDefinition)
+		return ok && a.equal(b, c)
+	case *NewExpression:
+		b, ok := b.(*NewExpression)
+		return ok && a.equal(b, c)
+	case *NullLiteral:
+		b, ok := b.(*NullLiteral)
+		return ok && a.equal(b, c)
+	case *NumberLiteral:
+		b, ok := b.(*NumberLiteral)
+		return ok && a.equal(b, c)
+	case *ObjectLiteral:
+		b, ok := b.(*ObjectLiteral)
+		return ok && a.equal(b, c)
+	case *ObjectPattern:
+		b, ok := b.(*ObjectPattern)
+		return ok && a.equal(b, c)
+	case *Optional:
+		b, ok := b.(*Optional)
+		return ok && a.equal(b, c)
+	case *OptionalChain:
+		b, ok := b.(*OptionalChain)
+		return ok && a.equal(b, c)
+	case *ParameterList:
+		b, ok := b.(*ParameterList)
+		return ok && a.equal(b, c)
+	case *PrivateDotExpression:
+		b, ok := b.(*PrivateDotExpression)
+		return ok && a.equal(b, c)
+	case *PrivateIdentifier:
+		b, ok := b.(*PrivateIdentifier)
+		return ok && a.equal(b, c)
+	case *Program:
+		b, ok := b.(*Program)
+		return ok && a.equal(b, c)
+	case *Properties:
+		b, ok := b.(*Properties)
+		return ok && a.equal(b, c)
+	case *Property:
+		b, ok := b.(*Property)
+		return ok && a.equal(b, c)
+	case *PropertyKeyed:
+		b, ok := b.(*PropertyKeyed)
+		return ok && a.equal(b, c)
+	case *PropertyShort:
+		b, ok := b.(*PropertyShort)
+		return ok && a.equal(b, c)
+	case *RegExpLiteral:
+		b, ok := b.(*RegExpLiteral)
+		return ok && a.equal(b, c)
+	case *ReturnStatement:
+		b, ok := b.(*ReturnStatement)
+		return ok && a.equal(b, c)
+	case *SequenceExpression:
+		b, ok := b.(*SequenceExpression)
+		return ok && a.equal(b, c)
+	case *SpreadElement:
+		b, ok := b.(*SpreadElement)
+		return ok && a.equal(b, c)
+	case *Statement:
+		b, ok := b.(*Statement)
+		return ok && a.equal(b, c)
+	case *Statements:
+		b, ok := b.(*Statements)
+		return ok && a.equal(b, c)
+	case *StringLiteral:
+		b, ok := b.(*StringLiteral)
+		return ok && a.equal(b, c)
+	case *SuperExpression:
+		b, ok := b.(*SuperExpression)
+		return ok && a.equal(b, c)
+	case *SwitchStatement:
+		b, ok := b.(*SwitchStatement)
+		return ok && a.equal(b, c)
+	case *TemplateElement:
+		b, ok := b.(*TemplateElement)
+		return ok && a.equal(b, c)
+	case *TemplateElements:
+		b, ok := b.(*TemplateElements)
+		return ok && a.equal(b, c)
+	case *TemplateLiteral:
+		b, ok := b.(*TemplateLiteral)
+		return ok && a.equal(b, c)
+	case *ThisExpression:
+		b, ok := b.(*ThisExpression)
+		return ok && a.equal(b, c)
+	case *ThrowStatement:
+		b, ok := b.(*ThrowStatement)
+		return ok && a.equal(b, c)
+	case *TryStatement:
+		b, ok := b.(*TryStatement)
+		return ok && a.equal(b, c)
+	case *UnaryExpression:
+		b, ok := b.(*UnaryExpression)
+		return ok && a.equal(b, c)
+	case *UpdateExpression:
+		b, ok := b.(*UpdateExpression)
+		return ok && a.equal(b, c)
+	case *VariableDeclaration:
+		b, ok := b.(*VariableDeclaration)
+		return ok && a.equal(b, c)
+	case *VariableDeclarator:
+		b, ok := b.(*VariableDeclarator)
+		return ok && a.equal(b, c)
+	case *VariableDeclarators:
+		b, ok := b.(*VariableDeclarators)
+		return ok && a.equal(b, c)
+	case *WhileStatement:
+		b, ok := b.(*WhileStatement)
+		return ok && a.equal(b, c)
+	case *WithStatement:
+		b, ok := b.(*WithStatement)
+		return ok && a.equal(b, c)
+	case *YieldExpression:
+		b, ok := b.(*YieldExpression)
+		return ok && a.equal(b, c)
+	}
+	panic(fmt.Sprintf("ast: unexpected node %T", a))
+}
+
+func hashNode(n VisitableNode, h *hasher) {
+	switch n := n.(type) {
+	case *ArrayLiteral:
+		n.hash(h)
+	case *ArrayPattern:
+		n.hash(h)
+	case *ArrowFunctionLiteral:
+		n.hash(h)
+	case *AssignExpression:
+		n.hash(h)
+	case *AwaitExpression:
+		n.hash(h)
+	case *BadStatement:
+		n.hash(h)
+	case *BinaryExpression:
+		n.hash(h)
+	case *BindingTarget:
+		n.hash(h)
+	case *BlockStatement:
+		n.hash(h)
+	case *BooleanLiteral:
+		n.hash(h)
+	case *BreakStatement:
+		n.hash(h)
+	case *CallExpression:
+		n.hash(h)
+	case *CaseStatement:
+		n.hash(h)
+	case *CaseStatements:
+		n.hash(h)
+	case *CatchStatement:
+		n.hash(h)
+	case *ClassDeclaration:
+		n.hash(h)
+	case *ClassElement:
+		n.hash(h)
+	case *ClassElements:
+		n.hash(h)
+	case *ClassLiteral:
+		n.hash(h)
+	case *ClassStaticBlock:
+		n.hash(h)
+	case *ComputedProperty:
+		n.hash(h)
+	case *ConciseBody:
+		n.hash(h)
+	case *ConditionalExpression:
+		n.hash(h)
+	case *ContinueStatement:
+		n.hash(h)
+	case *DebuggerStatement:
+		n.hash(h)
+	case *DoWhileStatement:
+		n.hash(h)
+	case *EmptyStatement:
+		n.hash(h)
+	case *ExportDeclaration:
+		n.hash(h)
+	case *ExportSpecifier:
+		n.hash(h)
+	case *ExportSpecifiers:
+		n.hash(h)
+	case *Expression:
+		n.hash(h)
+	case *ExpressionStatement:
+		n.hash(h)
+	case *Expressions:
+		n.hash(h)
+	case *FieldDefinition:
+		n.hash(h)
+	case *ForInStatement:
+		n.hash(h)
+	case *ForInto:
+		n.hash(h)
+	case *ForLoopInitializer:
+		n.hash(h)
+	case *ForOfStatement:
+		n.hash(h)
+	case *ForStatement:
+		n.hash(h)
+	case *FunctionDeclaration:
+		n.hash(h)
+	case *FunctionLiteral:
+		n.hash(h)
+	case *Identifier:
+		n.hash(h)
+	case *IfStatement:
+		n.hash(h)
+	case *ImportDeclaration:
+		n.hash(h)
+	case *ImportSpecifier:
+		n.hash(h)
+	case *ImportSpecifiers:
+		n.hash(h)
+	case *InvalidExpression:
+		n.hash(h)
+	case *LabelledStatement:
+		n.hash(h)
+	case *MemberExpression:
+		n.hash(h)
+	case *MemberProperty:
+		n.hash(h)
+	case *MetaProperty:
+		n.hash(h)
+	case *MethodDefinition:
+		n.hash(h)
+	case *NewExpression:
+		n.hash(h)
+	case *NullLiteral:
+		n.hash(h)
+	case *NumberLiteral:
+		n.hash(h)
+	case *ObjectLiteral:
+		n.hash(h)
+	case *ObjectPattern:
+		n.hash(h)
+	case *Optional:
+		n.hash(h)
+	case *OptionalChain:
+		n.hash(h)
+	case *ParameterList:
+		n.hash(h)
+	case *PrivateDotExpression:
+		n.hash(h)
+	case *PrivateIdentifier:
+		n.hash(h)
+	case *Program:
+		n.hash(h)
+	case *Properties:
+		n.hash(h)
+	case *Property:
+		n.hash(h)
+	case *PropertyKeyed:
+		n.hash(h)
+	case *PropertyShort:
+		n.hash(h)
+	case *RegExpLiteral:
+		n.hash(h)
+	case *ReturnStatement:
+		n.hash(h)
+	case *SequenceExpression:
+		n.hash(h)
+	case *SpreadElement:
+		n.hash(h)
+	case *Statement:
+		n.hash(h)
+	case *Statements:
+		n.hash(h)
+	case *StringLiteral:
+		n.hash(h)
+	case *SuperExpression:
+		n.hash(h)
+	case *SwitchStatement:
+		n.hash(h)
+	case *TemplateElement:
+		n.hash(h)
+	case *TemplateElements:
+		n.hash(h)
+	case *TemplateLiteral:
+		n.hash(h)
+	case *ThisExpression:
+		n.hash(h)
+	case *ThrowStatement:
+		n.hash(h)
+	case *TryStatement:
+		n.hash(h)
+	case *UnaryExpression:
+		n.hash(h)
+	case *UpdateExpression:
+		n.hash(h)
+	case *VariableDeclaration:
+		n.hash(h)
+	case *VariableDeclarator:
+		n.hash(h)
+	case *VariableDeclarators:
+		n.hash(h)
+	case *WhileStatement:
+		n.hash(h)
+	case *WithStatement:
+		n.hash(h)
+	case *YieldExpression:
+		n.hash(h)
+	default:
+		panic(fmt.Sprintf("ast: unexpected node %T", n))
+	}
+}
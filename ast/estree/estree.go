@@ -0,0 +1,202 @@
+// Package estree converts programs to and from ESTree JSON, the tree format
+// of acorn, espree and most JavaScript tooling. Babel's variant of it is read
+// as well.
+//
+// The mapping follows ESTree up to ES2022. Nodes specific to go-fAST are
+// translated: ast.Expression and the other wrappers disappear,
+// ast.OptionalChain becomes a ChainExpression whose optional member
+// expressions and calls are marked optional where ast.Optional was,
+// ast.PropertyShort is a shorthand Property, parameter defaults and pattern
+// defaults become AssignmentPattern, and rest parameters and elements become
+// RestElement. Strings and names are written without the U+FEFF the parser
+// marks non-ASCII ones with, and read back with it.
+//
+// Some of ESTree has no counterpart in package ast: export * declarations,
+// import() expressions, for await, BigInt literals and the logical
+// assignment operators. Reading them fails.
+package estree
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/t14raptor/go-fast/ast"
+)
+
+// Options controls the conversion.
+type Options struct {
+	// Source is the text the program was parsed from. When it is set,
+	// Marshal gives every node start, end, loc and range, with offsets and
+	// columns in UTF-16 code units as in JavaScript, and Unmarshal turns the
+	// offsets it reads into positions in Source. Without it, Marshal writes
+	// no positions and Unmarshal takes offsets as byte offsets.
+	Source string
+
+	// Indent, when not empty, is the indentation of the JSON output.
+	Indent string
+}
+
+// Marshal returns the ESTree JSON of program.
+func Marshal(program *ast.Program, opts Options) ([]byte, error) {
+	e := &exporter{}
+	if opts.Source != "" {
+		e.pos = newPositions(opts.Source)
+	}
+	root, err := e.program(program)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Indent == "" {
+		return json.Marshal(root)
+	}
+	return json.MarshalIndent(root, "", opts.Indent)
+}
+
+// Unmarshal reads an ESTree or Babel JSON program, or a Babel File.
+func Unmarshal(data []byte, opts Options) (*ast.Program, error) {
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	i := &importer{}
+	if opts.Source != "" {
+		i.pos = newPositions(opts.Source)
+	}
+	return i.program(root)
+}
+
+// object is a JSON object that keeps its keys in the order they were set.
+type object struct {
+	keys   []string
+	values []any
+
+	// The byte offsets of the node in the source.
+	start, end int
+}
+
+func newObject(typ string) *object {
+	return &object{keys: []string{"type"}, values: []any{typ}}
+}
+
+func (o *object) set(key string, value any) *object {
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+	return o
+}
+
+func (o *object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		b.Write(k)
+		b.WriteByte(':')
+		v, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// positions converts between byte offsets in a source and the UTF-16
+// offsets, lines and columns of ESTree.
+type positions struct {
+	src        string
+	lineStarts []int // byte offsets
+
+	// For sources that are not ASCII, the UTF-16 offset of every byte offset
+	// and the byte offset of every UTF-16 offset.
+	utf16 []int
+	bytes []int
+}
+
+func newPositions(src string) *positions {
+	p := &positions{src: src, lineStarts: []int{0}}
+	ascii := true
+	for i := 0; i < len(src); i++ {
+		if src[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if !ascii {
+		p.utf16 = make([]int, len(src)+1)
+	}
+
+	u := 0
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		next := i + size
+		if !ascii {
+			p.bytes = append(p.bytes, i)
+			if r >= 0x10000 {
+				p.bytes = append(p.bytes, i)
+			}
+			for j := i; j < next; j++ {
+				p.utf16[j] = u
+			}
+		}
+		if r >= 0x10000 {
+			u += 2
+		} else {
+			u++
+		}
+
+		i = next
+		switch r {
+		case '\r':
+			if next < len(src) && src[next] == '\n' {
+				continue
+			}
+			p.lineStarts = append(p.lineStarts, next)
+		case '\n', '\u2028', '\u2029':
+			p.lineStarts = append(p.lineStarts, next)
+		}
+	}
+	if !ascii {
+		p.utf16[len(src)] = u
+		p.bytes = append(p.bytes, len(src))
+	}
+	return p
+}
+
+// offset returns the UTF-16 offset of the byte offset b.
+func (p *positions) offset(b int) int {
+	if p.utf16 == nil {
+		return b
+	}
+	return p.utf16[clamp(b, len(p.utf16)-1)]
+}
+
+// lineColumn returns the 1-based line and the UTF-16 column of the byte
+// offset b.
+func (p *positions) lineColumn(b int) (int, int) {
+	line := sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > b })
+	return line, p.offset(b) - p.offset(p.lineStarts[line-1])
+}
+
+// byteOffset returns the byte offset of the UTF-16 offset u.
+func (p *positions) byteOffset(u int) int {
+	if p.bytes == nil {
+		return u
+	}
+	return p.bytes[clamp(u, len(p.bytes)-1)]
+}
+
+func clamp(i, hi int) int {
+	switch {
+	case i < 0:
+		return 0
+	case i > hi:
+		return hi
+	}
+	return i
+}
@@ -0,0 +1,138 @@
+package estree_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/estree"
+	"github.com/t14raptor/go-fast/parser"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []string{
+		`"use strict"; var a = 1, b = "s", c = /x/g, d = null, e = true;`,
+		`function f(a, b = 1, ...c) { return a + b; }`,
+		`const {x, y: [z, , w = 2], ...r} = o;`,
+		`for (let i = 0; i < 3; i++) { if (i) continue; else break; }`,
+		`for (const k in o) f(k); for (const v of a) g(v);`,
+		"x = `a${b}c`; tag`t`;",
+		`a?.b?.(c)[d]; new C(...args);`,
+		`class A extends B { #p = 1; static s; get g() { return this.#p; } m() { super.m(); } }`,
+		`async function* g() { yield* await x; }`,
+		`label: while (true) { try { throw e; } catch ({message}) {} finally {} }`,
+		`switch (x) { case 1: f(); default: g(); }`,
+		`x = {a, b: 1, [c]: 2, "d e": 3, get f() { return 1; }};`,
+		`var é = "é", s = " "; x = {é: 1, "ü": é}; y = ` + "`é${é}`;",
+		`class C { #é; m() { return this.#é; } }`,
+		`import d, {a as b} from "m"; export function f() {} export const c = 1; export {b, c as e};`,
+		`export {a as b} from "m"; export default class G {}`,
+	}
+	for _, src := range tests {
+		p, err := parser.ParseFile(src)
+		if err != nil {
+			t.Fatalf("ParseFile(%q): %v", src, err)
+		}
+		data, err := estree.Marshal(p, estree.Options{Source: src})
+		if err != nil {
+			t.Errorf("Marshal(%q): %v", src, err)
+			continue
+		}
+		got, err := estree.Unmarshal(data, estree.Options{Source: src})
+		if err != nil {
+			t.Errorf("Unmarshal of %q: %v", src, err)
+			continue
+		}
+		if !ast.Equal(got, p) {
+			t.Errorf("%q does not survive a round trip through\n%s", src, data)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	const src = `export {a as b}; export let c;`
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := estree.Marshal(p, estree.Options{Source: src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"local":{"type":"Identifier","start":8,"end":9,`,
+		`"exported":{"type":"Identifier","start":13,"end":14,`,
+		`"declaration":{"type":"VariableDeclaration","start":24,"end":30,`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Marshal(%q) = %s, want it to contain %s", src, data, want)
+		}
+	}
+}
+
+func TestNonASCII(t *testing.T) {
+	const src = "var é = \"é\" + `ü`;"
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := estree.Marshal(p, estree.Options{Source: src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "\ufeff") || strings.Contains(string(data), `\ufeff`) {
+		t.Errorf("Marshal writes U+FEFF: %s", data)
+	}
+
+	var program struct {
+		Body []struct {
+			Declarations []struct {
+				ID struct {
+					Name       string
+					Start, End int
+				}
+				Init struct {
+					Left struct {
+						Value string
+						Raw   string
+					}
+					Right struct {
+						Quasis []struct {
+							Value struct{ Cooked string }
+						}
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &program); err != nil {
+		t.Fatal(err)
+	}
+	d := program.Body[0].Declarations[0]
+	if d.ID.Name != "é" || d.ID.Start != 4 || d.ID.End != 5 {
+		t.Errorf("identifier is %q at %d-%d, want \"é\" at 4-5", d.ID.Name, d.ID.Start, d.ID.End)
+	}
+	if d.Init.Left.Value != "é" || d.Init.Left.Raw != `"é"` {
+		t.Errorf("string literal has value %q and raw %q", d.Init.Left.Value, d.Init.Left.Raw)
+	}
+	if q := d.Init.Right.Quasis; len(q) != 1 || q[0].Value.Cooked != "ü" {
+		t.Errorf("template quasis are %+v, want one cooked as \"ü\"", q)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	tests := []string{
+		`{"type":"ExportAllDeclaration","source":{"type":"Literal","value":"m"}}`,
+		`{"type":"ExpressionStatement","expression":{"type":"ImportExpression","source":{"type":"Literal","value":"m"}}}`,
+		`{"type":"ForOfStatement","await":true,"left":{"type":"Identifier","name":"x"},"right":{"type":"Identifier","name":"a"},"body":{"type":"EmptyStatement"}}`,
+		`{"type":"ExpressionStatement","expression":{"type":"Literal","value":null,"bigint":"1"}}`,
+		`{"type":"ExpressionStatement","expression":{"type":"AssignmentExpression","operator":"??=","left":{"type":"Identifier","name":"x"},"right":{"type":"Identifier","name":"y"}}}`,
+	}
+	for _, stmt := range tests {
+		data := `{"type":"Program","sourceType":"module","body":[` + stmt + `]}`
+		if _, err := estree.Unmarshal([]byte(data), estree.Options{}); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded", data)
+		}
+	}
+}
@@ -0,0 +1,1074 @@
+package estree
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+	"github.com/t14raptor/go-fast/token"
+)
+
+type exporter struct {
+	pos *positions
+	err error
+}
+
+func (e *exporter) fail(format string, args ...any) {
+	if e.err == nil {
+		e.err = fmt.Errorf("estree: "+format, args...)
+	}
+}
+
+// at records the byte offsets of o, and writes its position when there is a
+// source.
+func (e *exporter) at(o *object, start, end int) *object {
+	if end < start {
+		end = start
+	}
+	o.start, o.end = start, end
+	if e.pos == nil {
+		return o
+	}
+	start, end = clamp(start, len(e.pos.src)), clamp(end, len(e.pos.src))
+
+	sl, sc := e.pos.lineColumn(start)
+	el, ec := e.pos.lineColumn(end)
+	s, en := e.pos.offset(start), e.pos.offset(end)
+	loc := &object{
+		keys: []string{"start", "end"},
+		values: []any{
+			&object{keys: []string{"line", "column"}, values: []any{sl, sc}},
+			&object{keys: []string{"line", "column"}, values: []any{el, ec}},
+		},
+	}
+	o.keys = append(o.keys[:1], append([]string{"start", "end", "loc", "range"}, o.keys[1:]...)...)
+	o.values = append(o.values[:1], append([]any{s, en, loc, []int{s, en}}, o.values[1:]...)...)
+	return o
+}
+
+func off(idx ast.Idx) int {
+	return int(idx) - 1
+}
+
+// skip returns the offset of the first character at or after b that is not
+// white space or part of a comment.
+func (e *exporter) skip(b int) int {
+	if e.pos == nil {
+		return b
+	}
+	src := e.pos.src
+	for b < len(src) {
+		switch {
+		case strings.HasPrefix(src[b:], "//"):
+			for b < len(src) && src[b] != '\n' {
+				b++
+			}
+		case strings.HasPrefix(src[b:], "/*"):
+			end := strings.Index(src[b+2:], "*/")
+			if end < 0 {
+				return len(src)
+			}
+			b += end + 4
+		case strings.IndexByte(" \t\r\n\v\f", src[b]) >= 0:
+			b++
+		default:
+			return b
+		}
+	}
+	return b
+}
+
+// after returns the offset after tok when it follows b, and b otherwise.
+func (e *exporter) after(b int, tok string) int {
+	if e.pos == nil {
+		return b
+	}
+	if i := e.skip(b); strings.HasPrefix(e.pos.src[i:], tok) {
+		return i + len(tok)
+	}
+	return b
+}
+
+// before returns the offset of tok when it precedes b, and b otherwise.
+func (e *exporter) before(b int, tok string) int {
+	if e.pos == nil {
+		return b
+	}
+	i := b
+	for i > 0 && strings.IndexByte(" \t\r\n", e.pos.src[i-1]) >= 0 {
+		i--
+	}
+	if strings.HasSuffix(e.pos.src[:i], tok) {
+		return i - len(tok)
+	}
+	return b
+}
+
+// paren returns the span of o with the parentheses around it, which ESTree
+// leaves out of the node itself but not out of its parent.
+func (e *exporter) paren(o *object) (int, int) {
+	start, end := o.start, o.end
+	for {
+		s, en := e.before(start, "("), e.after(end, ")")
+		if s == start || en == end {
+			return start, end
+		}
+		start, end = s, en
+	}
+}
+
+func (e *exporter) startOf(o *object) int {
+	start, _ := e.paren(o)
+	return start
+}
+
+func (e *exporter) endOf(o *object) int {
+	_, end := e.paren(o)
+	return end
+}
+
+func (e *exporter) program(p *ast.Program) (*object, error) {
+	sourceType := "script"
+	for _, s := range p.Body {
+		switch s.Stmt.(type) {
+		case *ast.ImportDeclaration, *ast.ExportDeclaration:
+			sourceType = "module"
+		}
+	}
+	o := newObject("Program").
+		set("body", e.stmts(p.Body, true)).
+		set("sourceType", sourceType)
+	end := 0
+	if e.pos != nil {
+		end = len(e.pos.src)
+	}
+	e.at(o, 0, end)
+	return o, e.err
+}
+
+// stmts converts a statement list. In function and program bodies, leading
+// string literal statements are directives.
+func (e *exporter) stmts(list ast.Statements, directives bool) []any {
+	out := make([]any, 0, len(list))
+	for _, s := range list {
+		o := e.stmt(s.Stmt)
+		if directives {
+			if d, ok := directive(s.Stmt); ok {
+				o.set("directive", d)
+			} else {
+				directives = false
+			}
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+func directive(s ast.Stmt) (string, bool) {
+	es, ok := s.(*ast.ExpressionStatement)
+	if !ok || es.Expression == nil {
+		return "", false
+	}
+	lit, ok := es.Expression.Expr.(*ast.StringLiteral)
+	if !ok || lit.Raw == nil || len(*lit.Raw) < 2 {
+		return "", false
+	}
+	return (*lit.Raw)[1 : len(*lit.Raw)-1], true
+}
+
+func (e *exporter) stmt(s ast.Stmt) *object {
+	switch n := s.(type) {
+	case *ast.ExpressionStatement:
+		x := e.expr(n.Expression.Expr)
+		return e.at(newObject("ExpressionStatement").set("expression", x), e.startOf(x), e.after(e.endOf(x), ";"))
+
+	case *ast.BlockStatement:
+		return e.block(n)
+
+	case *ast.EmptyStatement:
+		return e.at(newObject("EmptyStatement"), off(n.Semicolon), off(n.Semicolon)+1)
+
+	case *ast.DebuggerStatement:
+		return e.at(newObject("DebuggerStatement"), off(n.Debugger), e.after(off(n.Debugger)+8, ";"))
+
+	case *ast.BreakStatement:
+		return e.jump("BreakStatement", off(n.Idx)+5, n.Idx, n.Label)
+
+	case *ast.ContinueStatement:
+		return e.jump("ContinueStatement", off(n.Idx)+8, n.Idx, n.Label)
+
+	case *ast.IfStatement:
+		test := e.expr(n.Test.Expr)
+		o := newObject("IfStatement").set("test", test)
+		start := off(n.If)
+		if n.If == 0 {
+			// The parser does not record where if statements start.
+			start = e.before(e.before(test.start, "("), "if")
+		}
+		cons := e.stmt(n.Consequent.Stmt)
+		o.set("consequent", cons)
+		end := cons.end
+		if n.Alternate != nil {
+			alt := e.stmt(n.Alternate.Stmt)
+			o.set("alternate", alt)
+			end = alt.end
+		} else {
+			o.set("alternate", nil)
+		}
+		return e.at(o, start, end)
+
+	case *ast.LabelledStatement:
+		label := e.ident(n.Label)
+		body := e.stmt(n.Statement.Stmt)
+		return e.at(newObject("LabeledStatement").set("label", label).set("body", body), label.start, body.end)
+
+	case *ast.ReturnStatement:
+		o := newObject("ReturnStatement")
+		end := off(n.Return) + 6
+		if n.Argument != nil && n.Argument.Expr != nil {
+			arg := e.expr(n.Argument.Expr)
+			o.set("argument", arg)
+			end = e.endOf(arg)
+		} else {
+			o.set("argument", nil)
+		}
+		return e.at(o, off(n.Return), e.after(end, ";"))
+
+	case *ast.ThrowStatement:
+		arg := e.expr(n.Argument.Expr)
+		return e.at(newObject("ThrowStatement").set("argument", arg), off(n.Throw), e.after(e.endOf(arg), ";"))
+
+	case *ast.SwitchStatement:
+		disc := e.expr(n.Discriminant.Expr)
+		cases := make([]any, len(n.Body))
+		end := e.after(e.after(disc.end, ")"), "{")
+		for i := range n.Body {
+			c := e.switchCase(&n.Body[i])
+			cases[i] = c
+			end = c.end
+		}
+		o := newObject("SwitchStatement").set("discriminant", disc).set("cases", cases)
+		return e.at(o, off(n.Switch), e.after(end, "}"))
+
+	case *ast.TryStatement:
+		block := e.block(n.Body)
+		o := newObject("TryStatement").set("block", block)
+		end := block.end
+		if n.Catch != nil {
+			o.set("handler", e.catch(n.Catch))
+			end = off(n.Catch.Body.RightBrace) + 1
+		} else {
+			o.set("handler", nil)
+		}
+		if n.Finally != nil {
+			fin := e.block(n.Finally)
+			o.set("finalizer", fin)
+			end = fin.end
+		} else {
+			o.set("finalizer", nil)
+		}
+		return e.at(o, off(n.Try), end)
+
+	case *ast.WhileStatement:
+		test := e.expr(n.Test.Expr)
+		body := e.stmt(n.Body.Stmt)
+		start := off(n.While)
+		if n.While == 0 {
+			// Nor while statements.
+			start = e.before(e.before(test.start, "("), "while")
+		}
+		return e.at(newObject("WhileStatement").set("test", test).set("body", body), start, body.end)
+
+	case *ast.DoWhileStatement:
+		body := e.stmt(n.Body.Stmt)
+		test := e.expr(n.Test.Expr)
+		o := newObject("DoWhileStatement").set("body", body).set("test", test)
+		start := off(n.Do)
+		if n.Do == 0 {
+			// Nor do statements.
+			start = e.before(body.start, "do")
+		}
+		return e.at(o, start, e.after(e.after(test.end, ")"), ";"))
+
+	case *ast.WithStatement:
+		obj := e.expr(n.Object.Expr)
+		body := e.stmt(n.Body.Stmt)
+		return e.at(newObject("WithStatement").set("object", obj).set("body", body), off(n.With), body.end)
+
+	case *ast.ForStatement:
+		o := newObject("ForStatement")
+		if n.Initializer == nil {
+			o.set("init", nil)
+		} else {
+			switch init := n.Initializer.Initializer.(type) {
+			case *ast.VariableDeclaration:
+				o.set("init", e.declaration(init, false))
+			case *ast.Expression:
+				o.set("init", e.expr(init.Expr))
+			}
+		}
+		o.set("test", e.optExpr(n.Test))
+		o.set("update", e.optExpr(n.Update))
+		body := e.stmt(n.Body.Stmt)
+		o.set("body", body)
+		return e.at(o, off(n.For), body.end)
+
+	case *ast.ForInStatement:
+		return e.forInOf("ForInStatement", n.For, n.Into, n.Source, n.Body)
+
+	case *ast.ForOfStatement:
+		o := e.forInOf("ForOfStatement", n.For, n.Into, n.Source, n.Body)
+		return o.set("await", false)
+
+	case *ast.VariableDeclaration:
+		return e.declaration(n, true)
+
+	case *ast.FunctionDeclaration:
+		return e.function("FunctionDeclaration", n.Function)
+
+	case *ast.ClassDeclaration:
+		return e.class("ClassDeclaration", n.Class)
+
+	case *ast.ImportDeclaration:
+		return e.importDecl(n)
+
+	case *ast.ExportDeclaration:
+		return e.exportDecl(n)
+
+	case *ast.BadStatement:
+		e.fail("bad statement at offset %d", off(n.From))
+	case nil:
+		e.fail("missing statement")
+	default:
+		e.fail("unexpected statement %T", s)
+	}
+	return &object{}
+}
+
+func (e *exporter) jump(typ string, end int, idx ast.Idx, label *ast.Identifier) *object {
+	o := newObject(typ)
+	if label != nil {
+		l := e.ident(label)
+		o.set("label", l)
+		end = l.end
+	} else {
+		o.set("label", nil)
+	}
+	return e.at(o, off(idx), e.after(end, ";"))
+}
+
+func (e *exporter) block(n *ast.BlockStatement) *object {
+	return e.at(newObject("BlockStatement").set("body", e.stmts(n.List, false)), off(n.LeftBrace), off(n.RightBrace)+1)
+}
+
+// functionBody is a block whose leading strings are directives.
+func (e *exporter) functionBody(n *ast.BlockStatement) *object {
+	return e.at(newObject("BlockStatement").set("body", e.stmts(n.List, true)), off(n.LeftBrace), off(n.RightBrace)+1)
+}
+
+func (e *exporter) switchCase(n *ast.CaseStatement) *object {
+	o := newObject("SwitchCase")
+	end := off(n.Case) + 7 // default
+	if n.Test != nil && n.Test.Expr != nil {
+		test := e.expr(n.Test.Expr)
+		o.set("test", test)
+		end = test.end
+	} else {
+		o.set("test", nil)
+	}
+	end = e.after(end, ":")
+	cons := make([]any, len(n.Consequent))
+	for i, s := range n.Consequent {
+		c := e.stmt(s.Stmt)
+		cons[i] = c
+		end = c.end
+	}
+	o.set("consequent", cons)
+	return e.at(o, off(n.Case), end)
+}
+
+func (e *exporter) catch(n *ast.CatchStatement) *object {
+	o := newObject("CatchClause")
+	if n.Parameter != nil {
+		o.set("param", e.pattern(n.Parameter.Target))
+	} else {
+		o.set("param", nil)
+	}
+	body := e.block(n.Body)
+	o.set("body", body)
+	return e.at(o, off(n.Catch), body.end)
+}
+
+func (e *exporter) forInOf(typ string, idx ast.Idx, into *ast.ForInto, source *ast.Expression, body *ast.Statement) *object {
+	o := newObject(typ)
+	switch left := into.Into.(type) {
+	case *ast.VariableDeclaration:
+		o.set("left", e.declaration(left, false))
+	case *ast.Expression:
+		o.set("left", e.pattern(left.Expr))
+	default:
+		e.fail("unexpected for-in/of target %T", into.Into)
+	}
+	o.set("right", e.expr(source.Expr))
+	b := e.stmt(body.Stmt)
+	o.set("body", b)
+	return e.at(o, off(idx), b.end)
+}
+
+func (e *exporter) declaration(n *ast.VariableDeclaration, statement bool) *object {
+	decls := make([]any, len(n.List))
+	end := off(n.Idx) + len(n.Token.String())
+	for i := range n.List {
+		d := &n.List[i]
+		id := e.pattern(d.Target.Target)
+		o := newObject("VariableDeclarator").set("id", id)
+		dEnd := id.end
+		if d.Initializer != nil && d.Initializer.Expr != nil {
+			init := e.expr(d.Initializer.Expr)
+			o.set("init", init)
+			dEnd = e.endOf(init)
+		} else {
+			o.set("init", nil)
+		}
+		decls[i] = e.at(o, id.start, dEnd)
+		end = dEnd
+	}
+	if statement {
+		end = e.after(end, ";")
+	}
+	kind := n.Token.String()
+	start := off(n.Idx)
+	if e.pos != nil && len(decls) > 0 && !strings.HasPrefix(e.pos.src[clamp(start, len(e.pos.src)):], kind) {
+		// In for-in and for-of heads, the parser records the for.
+		start = e.before(decls[0].(*object).start, kind)
+	}
+	o := newObject("VariableDeclaration").set("declarations", decls).set("kind", kind)
+	return e.at(o, start, end)
+}
+
+func (e *exporter) optExpr(x *ast.Expression) *object {
+	if x == nil || x.Expr == nil {
+		return nil
+	}
+	return e.expr(x.Expr)
+}
+
+func (e *exporter) exprs(list ast.Expressions) []any {
+	out := make([]any, len(list))
+	for i, x := range list {
+		if x.Expr != nil {
+			out[i] = e.expr(x.Expr)
+		}
+	}
+	return out
+}
+
+func (e *exporter) ident(n *ast.Identifier) *object {
+	name := ast.StringValue(n.Name)
+	return e.at(newObject("Identifier").set("name", name), off(n.Idx), off(n.Idx)+len(name))
+}
+
+// optIdent returns nil for a missing or anonymous name.
+func (e *exporter) optIdent(n *ast.Identifier) *object {
+	if n == nil || n.Name == "" {
+		return nil
+	}
+	return e.ident(n)
+}
+
+func (e *exporter) privateIdent(n *ast.PrivateIdentifier) *object {
+	start := off(n.Identifier.Idx)
+	name := ast.StringValue(n.Identifier.Name)
+	return e.at(newObject("PrivateIdentifier").set("name", name), start, start+1+len(name))
+}
+
+func (e *exporter) literal(value any, raw string, start int) *object {
+	return e.at(newObject("Literal").set("value", value).set("raw", raw), start, start+len(raw))
+}
+
+// stringLiteral converts a string literal. Its value is stored as
+// ast.StringValue describes.
+func (e *exporter) stringLiteral(n *ast.StringLiteral) *object {
+	value := ast.StringValue(n.Value)
+	raw := build.Quote(value)
+	if n.Raw != nil && len(*n.Raw) >= 2 && (*n.Raw)[0] == (*n.Raw)[len(*n.Raw)-1] && strings.IndexByte(`"'`, (*n.Raw)[0]) >= 0 {
+		raw = *n.Raw
+	}
+	return e.literal(value, raw, off(n.Idx))
+}
+
+func (e *exporter) numberLiteral(n *ast.NumberLiteral) *object {
+	var raw string
+	if n.Raw != nil {
+		raw = *n.Raw
+	} else {
+		raw = strconv.FormatFloat(n.Value, 'g', -1, 64)
+	}
+	var value any = n.Value
+	if math.IsInf(n.Value, 0) || math.IsNaN(n.Value) {
+		// JSON has no such numbers, and JSON.stringify writes null.
+		value = nil
+	}
+	return e.literal(value, raw, off(n.Idx))
+}
+
+// key converts a property key. Plain keys are identifiers, which the parser
+// keeps as string literals whose raw text is unquoted.
+func (e *exporter) key(x ast.Expr, computed bool) *object {
+	if computed {
+		return e.expr(x)
+	}
+	switch k := x.(type) {
+	case *ast.StringLiteral:
+		name := ast.StringValue(k.Value)
+		if k.Raw != nil && *k.Raw == name || k.Raw == nil && build.IsIdentifierName(name) {
+			return e.at(newObject("Identifier").set("name", name), off(k.Idx), off(k.Idx)+len(name))
+		}
+	case *ast.PrivateIdentifier:
+		return e.privateIdent(k)
+	}
+	return e.expr(x)
+}
+
+// keyStart returns the offset of a key, with the bracket of a computed key.
+func (e *exporter) keyStart(key *object, computed bool) int {
+	if computed {
+		return e.before(key.start, "[")
+	}
+	return key.start
+}
+
+func (e *exporter) expr(x ast.Expr) *object {
+	switch n := x.(type) {
+	case *ast.Identifier:
+		return e.ident(n)
+
+	case *ast.StringLiteral:
+		return e.stringLiteral(n)
+
+	case *ast.NumberLiteral:
+		return e.numberLiteral(n)
+
+	case *ast.BooleanLiteral:
+		raw := strconv.FormatBool(n.Value)
+		return e.literal(n.Value, raw, off(n.Idx))
+
+	case *ast.NullLiteral:
+		return e.literal(nil, "null", off(n.Idx))
+
+	case *ast.RegExpLiteral:
+		o := e.literal(nil, n.Literal, off(n.Idx))
+		regex := &object{keys: []string{"pattern", "flags"}, values: []any{n.Pattern, n.Flags}}
+		return o.set("regex", regex)
+
+	case *ast.TemplateLiteral:
+		quasi := e.template(n)
+		if n.Tag == nil || n.Tag.Expr == nil {
+			return quasi
+		}
+		tag := e.expr(n.Tag.Expr)
+		return e.at(newObject("TaggedTemplateExpression").set("tag", tag).set("quasi", quasi), e.startOf(tag), quasi.end)
+
+	case *ast.ThisExpression:
+		return e.at(newObject("ThisExpression"), off(n.Idx), off(n.Idx)+4)
+
+	case *ast.SuperExpression:
+		return e.at(newObject("Super"), off(n.Idx), off(n.Idx)+5)
+
+	case *ast.ArrayLiteral:
+		o := newObject("ArrayExpression").set("elements", e.exprs(n.Value))
+		return e.at(o, off(n.LeftBracket), off(n.RightBracket)+1)
+
+	case *ast.ObjectLiteral:
+		props := make([]any, len(n.Value))
+		for i, p := range n.Value {
+			props[i] = e.property(p.Prop, false)
+		}
+		return e.at(newObject("ObjectExpression").set("properties", props), off(n.LeftBrace), off(n.RightBrace)+1)
+
+	case *ast.ArrayPattern, *ast.ObjectPattern:
+		return e.pattern(x)
+
+	case *ast.FunctionLiteral:
+		return e.function("FunctionExpression", n)
+
+	case *ast.ArrowFunctionLiteral:
+		o := newObject("ArrowFunctionExpression").set("id", nil)
+		var body *object
+		expression := false
+		switch b := n.Body.Body.(type) {
+		case *ast.BlockStatement:
+			body = e.functionBody(b)
+		case *ast.Expression:
+			body = e.expr(b.Expr)
+			expression = true
+		}
+		o.set("expression", expression).
+			set("generator", false).
+			set("async", n.Async).
+			set("params", e.params(&n.ParameterList)).
+			set("body", body)
+		return e.at(o, off(n.Start), e.endOf(body))
+
+	case *ast.ClassLiteral:
+		return e.class("ClassExpression", n)
+
+	case *ast.AssignExpression:
+		left := e.pattern(n.Left.Expr)
+		right := e.expr(n.Right.Expr)
+		op := "="
+		if n.Operator != token.Assign {
+			op = n.Operator.String() + "="
+		}
+		o := newObject("AssignmentExpression").set("operator", op).set("left", left).set("right", right)
+		return e.at(o, e.startOf(left), e.endOf(right))
+
+	case *ast.BinaryExpression:
+		left := e.expr(n.Left.Expr)
+		right := e.expr(n.Right.Expr)
+		typ := "BinaryExpression"
+		switch n.Operator {
+		case token.LogicalAnd, token.LogicalOr, token.Coalesce:
+			typ = "LogicalExpression"
+		}
+		o := newObject(typ).set("operator", n.Operator.String()).set("left", left).set("right", right)
+		return e.at(o, e.startOf(left), e.endOf(right))
+
+	case *ast.UnaryExpression:
+		arg := e.expr(n.Operand.Expr)
+		o := newObject("UnaryExpression").set("operator", n.Operator.String()).set("prefix", true).set("argument", arg)
+		return e.at(o, off(n.Idx), e.endOf(arg))
+
+	case *ast.UpdateExpression:
+		arg := e.expr(n.Operand.Expr)
+		o := newObject("UpdateExpression").set("operator", n.Operator.String()).set("prefix", !n.Postfix).set("argument", arg)
+		if n.Postfix {
+			return e.at(o, e.startOf(arg), e.after(e.endOf(arg), n.Operator.String()))
+		}
+		return e.at(o, off(n.Idx), e.endOf(arg))
+
+	case *ast.ConditionalExpression:
+		test := e.expr(n.Test.Expr)
+		cons := e.expr(n.Consequent.Expr)
+		alt := e.expr(n.Alternate.Expr)
+		o := newObject("ConditionalExpression").set("test", test).set("consequent", cons).set("alternate", alt)
+		return e.at(o, e.startOf(test), e.endOf(alt))
+
+	case *ast.SequenceExpression:
+		list := e.exprs(n.Sequence)
+		start, end := 0, 0
+		if len(list) > 0 {
+			start, end = e.startOf(list[0].(*object)), e.endOf(list[len(list)-1].(*object))
+		}
+		return e.at(newObject("SequenceExpression").set("expressions", list), start, end)
+
+	case *ast.CallExpression:
+		callee, optional := e.chainLink(n.Callee.Expr)
+		o := newObject("CallExpression").
+			set("callee", callee).
+			set("arguments", e.exprs(n.ArgumentList)).
+			set("optional", optional)
+		return e.at(o, e.startOf(callee), off(n.RightParenthesis)+1)
+
+	case *ast.NewExpression:
+		callee := e.expr(n.Callee.Expr)
+		o := newObject("NewExpression").set("callee", callee).set("arguments", e.exprs(n.ArgumentList))
+		end := e.endOf(callee)
+		if n.RightParenthesis > 0 {
+			end = off(n.RightParenthesis) + 1
+		}
+		return e.at(o, off(n.New), end)
+
+	case *ast.MemberExpression:
+		obj, optional := e.chainLink(n.Object.Expr)
+		o := newObject("MemberExpression").set("object", obj)
+		var prop *object
+		computed := false
+		end := 0
+		switch p := n.Property.Prop.(type) {
+		case *ast.Identifier:
+			prop = e.ident(p)
+			end = prop.end
+		case *ast.ComputedProperty:
+			prop = e.expr(p.Expr.Expr)
+			computed = true
+			end = e.after(prop.end, "]")
+		default:
+			e.fail("unexpected member property %T", n.Property.Prop)
+			prop = &object{}
+		}
+		o.set("property", prop).set("computed", computed).set("optional", optional)
+		return e.at(o, e.startOf(obj), end)
+
+	case *ast.PrivateDotExpression:
+		obj, optional := e.chainLink(n.Left.Expr)
+		prop := e.privateIdent(n.Identifier)
+		o := newObject("MemberExpression").
+			set("object", obj).
+			set("property", prop).
+			set("computed", false).
+			set("optional", optional)
+		return e.at(o, e.startOf(obj), prop.end)
+
+	case *ast.OptionalChain:
+		inner := e.expr(n.Base.Expr)
+		return e.at(newObject("ChainExpression").set("expression", inner), inner.start, inner.end)
+
+	case *ast.Optional:
+		// Only calls and member expressions take the mark, in chainLink.
+		return e.expr(n.Expr.Expr)
+
+	case *ast.SpreadElement:
+		arg := e.expr(n.Expression.Expr)
+		return e.at(newObject("SpreadElement").set("argument", arg), e.before(e.startOf(arg), "..."), e.endOf(arg))
+
+	case *ast.YieldExpression:
+		o := newObject("YieldExpression").set("delegate", n.Delegate)
+		end := off(n.Yield) + 5
+		if n.Argument != nil && n.Argument.Expr != nil {
+			arg := e.expr(n.Argument.Expr)
+			o.set("argument", arg)
+			end = e.endOf(arg)
+		} else {
+			o.set("argument", nil)
+		}
+		return e.at(o, off(n.Yield), end)
+
+	case *ast.AwaitExpression:
+		arg := e.expr(n.Argument.Expr)
+		return e.at(newObject("AwaitExpression").set("argument", arg), off(n.Await), e.endOf(arg))
+
+	case *ast.MetaProperty:
+		meta := e.ident(n.Meta)
+		prop := e.ident(n.Property)
+		return e.at(newObject("MetaProperty").set("meta", meta).set("property", prop), meta.start, prop.end)
+
+	case *ast.InvalidExpression:
+		e.fail("invalid expression at offset %d", off(n.From))
+	case nil:
+		e.fail("missing expression")
+	default:
+		e.fail("unexpected expression %T", x)
+	}
+	return &object{}
+}
+
+// chainLink converts the object of a member expression or the callee of a
+// call, and reports whether it is followed by ?.
+func (e *exporter) chainLink(x ast.Expr) (*object, bool) {
+	if o, ok := x.(*ast.Optional); ok {
+		return e.expr(o.Expr.Expr), true
+	}
+	return e.expr(x), false
+}
+
+func (e *exporter) template(n *ast.TemplateLiteral) *object {
+	exprs := e.exprs(n.Expressions)
+	quasis := make([]any, len(n.Elements))
+	start := off(n.OpenQuote) + 1
+	for i, el := range n.Elements {
+		// The positions of elements are found from their neighbours, as the
+		// parser records them one byte late.
+		if i > 0 && i <= len(exprs) {
+			if x, ok := exprs[i-1].(*object); ok {
+				start = e.after(e.endOf(x), "}")
+			}
+		}
+		var cooked any
+		if el.Valid {
+			cooked = ast.StringValue(el.Parsed)
+		}
+		value := &object{keys: []string{"raw", "cooked"}, values: []any{el.Literal, cooked}}
+		o := newObject("TemplateElement").set("value", value).set("tail", i == len(n.Elements)-1)
+		quasis[i] = e.at(o, start, start+len(el.Literal))
+	}
+	o := newObject("TemplateLiteral").set("quasis", quasis).set("expressions", exprs)
+	return e.at(o, off(n.OpenQuote), off(n.CloseQuote)+1)
+}
+
+// pattern converts the target of a binding or an assignment, where
+// assignments are defaults.
+func (e *exporter) pattern(x ast.Expr) *object {
+	switch n := x.(type) {
+	case *ast.AssignExpression:
+		left := e.pattern(n.Left.Expr)
+		right := e.expr(n.Right.Expr)
+		return e.at(newObject("AssignmentPattern").set("left", left).set("right", right), left.start, e.endOf(right))
+
+	case *ast.ArrayPattern:
+		elements := make([]any, 0, len(n.Elements)+1)
+		for _, el := range n.Elements {
+			if el.Expr == nil {
+				elements = append(elements, nil)
+			} else {
+				elements = append(elements, e.pattern(el.Expr))
+			}
+		}
+		if n.Rest != nil && n.Rest.Expr != nil {
+			elements = append(elements, e.rest(n.Rest.Expr))
+		}
+		return e.at(newObject("ArrayPattern").set("elements", elements), off(n.LeftBracket), off(n.RightBracket)+1)
+
+	case *ast.ObjectPattern:
+		props := make([]any, 0, len(n.Properties)+1)
+		for _, p := range n.Properties {
+			props = append(props, e.property(p.Prop, true))
+		}
+		if n.Rest != nil {
+			props = append(props, e.rest(n.Rest))
+		}
+		return e.at(newObject("ObjectPattern").set("properties", props), off(n.LeftBrace), off(n.RightBrace)+1)
+	}
+	return e.expr(x)
+}
+
+func (e *exporter) rest(x ast.Expr) *object {
+	arg := e.pattern(x)
+	return e.at(newObject("RestElement").set("argument", arg), e.before(arg.start, "..."), arg.end)
+}
+
+func (e *exporter) params(n *ast.ParameterList) []any {
+	params := make([]any, 0, len(n.List)+1)
+	for i := range n.List {
+		d := &n.List[i]
+		target := e.pattern(d.Target.Target)
+		if d.Initializer != nil && d.Initializer.Expr != nil {
+			right := e.expr(d.Initializer.Expr)
+			target = e.at(newObject("AssignmentPattern").set("left", target).set("right", right), target.start, e.endOf(right))
+		}
+		params = append(params, target)
+	}
+	if n.Rest != nil {
+		params = append(params, e.rest(n.Rest))
+	}
+	return params
+}
+
+// property converts a property of an object literal or an object pattern.
+func (e *exporter) property(p ast.Prop, pattern bool) *object {
+	switch n := p.(type) {
+	case *ast.SpreadElement:
+		if pattern {
+			return e.rest(n.Expression.Expr)
+		}
+		return e.expr(n)
+
+	case *ast.PropertyShort:
+		key := e.ident(n.Name)
+		value := e.ident(n.Name)
+		if n.Initializer != nil && n.Initializer.Expr != nil {
+			right := e.expr(n.Initializer.Expr)
+			value = e.at(newObject("AssignmentPattern").set("left", value).set("right", right), value.start, e.endOf(right))
+		}
+		o := newObject("Property").
+			set("method", false).
+			set("shorthand", true).
+			set("computed", false).
+			set("key", key).
+			set("value", value).
+			set("kind", "init")
+		return e.at(o, key.start, value.end)
+
+	case *ast.PropertyKeyed:
+		key := e.key(n.Key.Expr, n.Computed)
+		start := e.keyStart(key, n.Computed)
+		var value *object
+		kind := "init"
+		switch n.Kind {
+		case ast.PropertyKindGet, ast.PropertyKindSet, ast.PropertyKindMethod:
+			fn, ok := n.Value.Expr.(*ast.FunctionLiteral)
+			if !ok {
+				e.fail("method %T is not a function", n.Value.Expr)
+				return &object{}
+			}
+			value = e.method(fn)
+			start = off(fn.Function)
+			if n.Kind != ast.PropertyKindMethod {
+				kind = string(n.Kind)
+			}
+		default:
+			if pattern {
+				value = e.pattern(n.Value.Expr)
+			} else {
+				value = e.expr(n.Value.Expr)
+			}
+		}
+		o := newObject("Property").
+			set("method", n.Kind == ast.PropertyKindMethod).
+			set("shorthand", false).
+			set("computed", n.Computed).
+			set("key", key).
+			set("value", value).
+			set("kind", kind)
+		return e.at(o, start, e.endOf(value))
+	}
+	e.fail("unexpected property %T", p)
+	return &object{}
+}
+
+func (e *exporter) function(typ string, n *ast.FunctionLiteral) *object {
+	return e.functionAt(typ, n, off(n.Function))
+}
+
+// method converts the function of a method, which starts at its parameters.
+func (e *exporter) method(n *ast.FunctionLiteral) *object {
+	return e.functionAt("FunctionExpression", n, off(n.ParameterList.Opening))
+}
+
+func (e *exporter) functionAt(typ string, n *ast.FunctionLiteral, start int) *object {
+	body := e.functionBody(n.Body)
+	o := newObject(typ).
+		set("id", e.optIdent(n.Name)).
+		set("expression", false).
+		set("generator", n.Generator).
+		set("async", n.Async).
+		set("params", e.params(&n.ParameterList)).
+		set("body", body)
+	return e.at(o, start, body.end)
+}
+
+func (e *exporter) class(typ string, n *ast.ClassLiteral) *object {
+	o := newObject(typ).set("id", e.optIdent(n.Name))
+	bodyStart := off(n.Class) + 5
+	if id := o.values[1]; id != (*object)(nil) {
+		bodyStart = id.(*object).end
+	}
+	if n.SuperClass != nil && n.SuperClass.Expr != nil {
+		super := e.expr(n.SuperClass.Expr)
+		o.set("superClass", super)
+		bodyStart = super.end
+	} else {
+		o.set("superClass", nil)
+	}
+	if i := e.skip(bodyStart); e.pos != nil && i < len(e.pos.src) && e.pos.src[i] == '{' {
+		bodyStart = i
+	}
+
+	elements := make([]any, len(n.Body))
+	for i, el := range n.Body {
+		elements[i] = e.classElement(el.Element)
+	}
+	body := e.at(newObject("ClassBody").set("body", elements), bodyStart, off(n.RightBrace)+1)
+	o.set("body", body)
+	return e.at(o, off(n.Class), body.end)
+}
+
+func (e *exporter) classElement(el ast.Element) *object {
+	switch n := el.(type) {
+	case *ast.MethodDefinition:
+		key := e.key(n.Key.Expr, n.Computed)
+		kind := string(n.Kind)
+		if kind == string(ast.PropertyKindMethod) && !n.Static && !n.Computed && isKeyNamed(n.Key.Expr, "constructor") {
+			kind = "constructor"
+		}
+		value := e.method(n.Body)
+		o := newObject("MethodDefinition").
+			set("static", n.Static).
+			set("computed", n.Computed).
+			set("key", key).
+			set("kind", kind).
+			set("value", value)
+		return e.at(o, off(n.Idx), value.end)
+
+	case *ast.FieldDefinition:
+		key := e.key(n.Key.Expr, n.Computed)
+		o := newObject("PropertyDefinition").
+			set("static", n.Static).
+			set("computed", n.Computed).
+			set("key", key)
+		end := e.after(key.end, "]")
+		if !n.Computed {
+			end = key.end
+		}
+		if n.Initializer != nil && n.Initializer.Expr != nil {
+			value := e.expr(n.Initializer.Expr)
+			o.set("value", value)
+			end = e.endOf(value)
+		} else {
+			o.set("value", nil)
+		}
+		return e.at(o, off(n.Idx), e.after(end, ";"))
+
+	case *ast.ClassStaticBlock:
+		o := newObject("StaticBlock").set("body", e.stmts(n.Block.List, false))
+		return e.at(o, off(n.Static), off(n.Block.RightBrace)+1)
+	}
+	e.fail("unexpected class element %T", el)
+	return &object{}
+}
+
+func isKeyNamed(x ast.Expr, name string) bool {
+	switch k := x.(type) {
+	case *ast.StringLiteral:
+		return ast.StringValue(k.Value) == name
+	case *ast.Identifier:
+		return ast.StringValue(k.Name) == name
+	}
+	return false
+}
+
+func (e *exporter) importDecl(n *ast.ImportDeclaration) *object {
+	specs := make([]any, 0, len(n.Specifiers)+2)
+	if n.Default != nil {
+		local := e.ident(n.Default)
+		specs = append(specs, e.at(newObject("ImportDefaultSpecifier").set("local", local), local.start, local.end))
+	}
+	if n.Namespace != nil {
+		local := e.ident(n.Namespace)
+		start := e.before(e.before(local.start, "as"), "*")
+		specs = append(specs, e.at(newObject("ImportNamespaceSpecifier").set("local", local), start, local.end))
+	}
+	for _, s := range n.Specifiers {
+		imported := e.ident(s.Imported)
+		local := e.ident(s.Local)
+		o := newObject("ImportSpecifier").set("imported", imported).set("local", local)
+		specs = append(specs, e.at(o, imported.start, local.end))
+	}
+	source := e.stringLiteral(n.Source)
+	o := newObject("ImportDeclaration").set("specifiers", specs).set("source", source)
+	return e.at(o, off(n.Import), e.after(source.end, ";"))
+}
+
+func (e *exporter) exportDecl(n *ast.ExportDeclaration) *object {
+	if n.Default != nil && n.Default.Expr != nil {
+		var decl *object
+		switch d := n.Default.Expr.(type) {
+		case *ast.FunctionLiteral:
+			decl = e.function("FunctionDeclaration", d)
+		case *ast.ClassLiteral:
+			decl = e.class("ClassDeclaration", d)
+		default:
+			decl = e.expr(d)
+		}
+		o := newObject("ExportDefaultDeclaration").set("declaration", decl)
+		return e.at(o, off(n.Export), e.after(e.endOf(decl), ";"))
+	}
+
+	if n.Declaration != nil {
+		decl := e.stmt(n.Declaration.Stmt)
+		o := newObject("ExportNamedDeclaration").set("declaration", decl).set("specifiers", []any{}).set("source", nil)
+		return e.at(o, off(n.Export), decl.end)
+	}
+
+	specs := make([]any, len(n.Specifiers))
+	end := e.after(off(n.Export)+6, "{")
+	for i, s := range n.Specifiers {
+		local := e.ident(s.Local)
+		exported := e.ident(s.Exported)
+		specs[i] = e.at(newObject("ExportSpecifier").set("local", local).set("exported", exported), local.start, exported.end)
+		end = e.after(exported.end, ",")
+	}
+	end = e.after(end, "}")
+	o := newObject("ExportNamedDeclaration").set("declaration", nil).set("specifiers", specs)
+	if n.Source != nil {
+		source := e.stringLiteral(n.Source)
+		o.set("source", source)
+		end = source.end
+	} else {
+		o.set("source", nil)
+	}
+	return e.at(o, off(n.Export), e.after(end, ";"))
+}
@@ -0,0 +1,954 @@
+package estree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+	"github.com/t14raptor/go-fast/token"
+)
+
+var (
+	binaryOperators = operators(
+		token.Plus, token.Minus, token.Multiply, token.Exponent, token.Slash, token.Remainder,
+		token.And, token.Or, token.ExclusiveOr, token.ShiftLeft, token.ShiftRight, token.UnsignedShiftRight,
+		token.LogicalAnd, token.LogicalOr, token.Coalesce,
+		token.Equal, token.StrictEqual, token.NotEqual, token.StrictNotEqual,
+		token.Less, token.Greater, token.LessOrEqual, token.GreaterOrEqual,
+		token.In, token.InstanceOf,
+	)
+	unaryOperators = operators(
+		token.Minus, token.Plus, token.Not, token.BitwiseNot, token.Typeof, token.Void, token.Delete,
+	)
+	updateOperators = operators(token.Increment, token.Decrement)
+	variableKinds   = operators(token.Var, token.Let, token.Const)
+)
+
+func operators(list ...token.Token) map[string]token.Token {
+	m := make(map[string]token.Token, len(list))
+	for _, tok := range list {
+		m[tok.String()] = tok
+	}
+	return m
+}
+
+type importer struct {
+	pos *positions
+	err error
+}
+
+func (i *importer) fail(format string, args ...any) {
+	if i.err == nil {
+		i.err = fmt.Errorf("estree: "+format, args...)
+	}
+}
+
+// idx returns the position of the start of m.
+func (i *importer) idx(m map[string]any) ast.Idx {
+	return i.position(m, "start", 1)
+}
+
+// last returns the position of the last character of m, such as a closing
+// brace.
+func (i *importer) last(m map[string]any) ast.Idx {
+	return i.position(m, "end", 0)
+}
+
+func (i *importer) position(m map[string]any, key string, delta int) ast.Idx {
+	f, ok := m[key].(float64)
+	if !ok {
+		return 0
+	}
+	b := int(f)
+	if i.pos != nil {
+		b = i.pos.byteOffset(b)
+	}
+	return ast.Idx(b + delta)
+}
+
+func typeOf(m map[string]any) string {
+	t, _ := m["type"].(string)
+	return t
+}
+
+func child(m map[string]any, key string) map[string]any {
+	c, _ := m[key].(map[string]any)
+	return c
+}
+
+func children(m map[string]any, key string) []map[string]any {
+	list, _ := m[key].([]any)
+	out := make([]map[string]any, len(list))
+	for j, c := range list {
+		out[j], _ = c.(map[string]any)
+	}
+	return out
+}
+
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func flag(m map[string]any, key string) bool {
+	b, _ := m[key].(bool)
+	return b
+}
+
+// raw returns the source text of a literal, which Babel keeps in extra.
+func raw(m map[string]any) (string, bool) {
+	if r, ok := m["raw"].(string); ok {
+		return r, true
+	}
+	r, ok := child(m, "extra")["raw"].(string)
+	return r, ok
+}
+
+func (i *importer) program(m map[string]any) (*ast.Program, error) {
+	if typeOf(m) == "File" {
+		m = child(m, "program")
+	}
+	if typeOf(m) != "Program" {
+		return nil, fmt.Errorf("estree: root is %q, not a Program", typeOf(m))
+	}
+	p := &ast.Program{Body: i.body(m)}
+	if i.err != nil {
+		return nil, i.err
+	}
+	return p, nil
+}
+
+// body converts the statements of a program or block, with Babel's
+// directives in front.
+func (i *importer) body(m map[string]any) ast.Statements {
+	var list ast.Statements
+	for _, d := range children(m, "directives") {
+		lit := child(d, "value")
+		value := str(lit, "value")
+		r, ok := raw(lit)
+		if !ok {
+			r = build.Quote(value)
+		}
+		s := &ast.StringLiteral{Idx: i.idx(lit), Value: ast.StoredString(value), Raw: &r}
+		list = append(list, ast.Statement{Stmt: &ast.ExpressionStatement{Expression: &ast.Expression{Expr: s}}})
+	}
+	for _, s := range children(m, "body") {
+		list = append(list, ast.Statement{Stmt: i.stmt(s)})
+	}
+	return list
+}
+
+func (i *importer) block(m map[string]any) *ast.BlockStatement {
+	if typeOf(m) != "BlockStatement" {
+		i.fail("%q is not a BlockStatement", typeOf(m))
+		return &ast.BlockStatement{}
+	}
+	return &ast.BlockStatement{LeftBrace: i.idx(m), List: i.body(m), RightBrace: i.last(m)}
+}
+
+func (i *importer) statement(m map[string]any) *ast.Statement {
+	return &ast.Statement{Stmt: i.stmt(m)}
+}
+
+func (i *importer) optStatement(m map[string]any) *ast.Statement {
+	if m == nil {
+		return nil
+	}
+	return i.statement(m)
+}
+
+func (i *importer) stmt(m map[string]any) ast.Stmt {
+	switch typeOf(m) {
+	case "ExpressionStatement":
+		return &ast.ExpressionStatement{Expression: i.expression(child(m, "expression"))}
+
+	case "BlockStatement":
+		return i.block(m)
+
+	case "EmptyStatement":
+		return &ast.EmptyStatement{Semicolon: i.idx(m)}
+
+	case "DebuggerStatement":
+		return &ast.DebuggerStatement{Debugger: i.idx(m)}
+
+	case "BreakStatement":
+		return &ast.BreakStatement{Idx: i.idx(m), Label: i.optIdent(child(m, "label"))}
+
+	case "ContinueStatement":
+		return &ast.ContinueStatement{Idx: i.idx(m), Label: i.optIdent(child(m, "label"))}
+
+	case "IfStatement":
+		return &ast.IfStatement{
+			If:         i.idx(m),
+			Test:       i.expression(child(m, "test")),
+			Consequent: i.statement(child(m, "consequent")),
+			Alternate:  i.optStatement(child(m, "alternate")),
+		}
+
+	case "LabeledStatement":
+		label := i.ident(child(m, "label"))
+		return &ast.LabelledStatement{
+			Label:     label,
+			Colon:     label.Idx + ast.Idx(len(label.Name)),
+			Statement: i.statement(child(m, "body")),
+		}
+
+	case "ReturnStatement":
+		return &ast.ReturnStatement{Return: i.idx(m), Argument: i.optExpression(child(m, "argument"))}
+
+	case "ThrowStatement":
+		return &ast.ThrowStatement{Throw: i.idx(m), Argument: i.expression(child(m, "argument"))}
+
+	case "SwitchStatement":
+		n := &ast.SwitchStatement{
+			Switch:       i.idx(m),
+			Discriminant: i.expression(child(m, "discriminant")),
+			Default:      -1,
+		}
+		for j, c := range children(m, "cases") {
+			cs := ast.CaseStatement{Case: i.idx(c), Test: i.optExpression(child(c, "test"))}
+			if cs.Test == nil {
+				n.Default = j
+			}
+			for _, s := range children(c, "consequent") {
+				cs.Consequent = append(cs.Consequent, ast.Statement{Stmt: i.stmt(s)})
+			}
+			n.Body = append(n.Body, cs)
+		}
+		return n
+
+	case "TryStatement":
+		n := &ast.TryStatement{Try: i.idx(m), Body: i.block(child(m, "block"))}
+		if h := child(m, "handler"); h != nil {
+			n.Catch = &ast.CatchStatement{Catch: i.idx(h), Body: i.block(child(h, "body"))}
+			if p := child(h, "param"); p != nil {
+				n.Catch.Parameter = &ast.BindingTarget{Target: i.target(p)}
+			}
+		}
+		if f := child(m, "finalizer"); f != nil {
+			n.Finally = i.block(f)
+		}
+		return n
+
+	case "WhileStatement":
+		return &ast.WhileStatement{
+			While: i.idx(m),
+			Test:  i.expression(child(m, "test")),
+			Body:  i.statement(child(m, "body")),
+		}
+
+	case "DoWhileStatement":
+		return &ast.DoWhileStatement{
+			Do:   i.idx(m),
+			Body: i.statement(child(m, "body")),
+			Test: i.expression(child(m, "test")),
+		}
+
+	case "WithStatement":
+		return &ast.WithStatement{
+			With:   i.idx(m),
+			Object: i.expression(child(m, "object")),
+			Body:   i.statement(child(m, "body")),
+		}
+
+	case "ForStatement":
+		n := &ast.ForStatement{
+			For:    i.idx(m),
+			Test:   i.slot(child(m, "test")),
+			Update: i.slot(child(m, "update")),
+			Body:   i.statement(child(m, "body")),
+		}
+		if init := child(m, "init"); init != nil {
+			if typeOf(init) == "VariableDeclaration" {
+				n.Initializer = &ast.ForLoopInitializer{Initializer: i.declaration(init)}
+			} else {
+				n.Initializer = &ast.ForLoopInitializer{Initializer: i.expression(init)}
+			}
+		}
+		return n
+
+	case "ForInStatement":
+		return &ast.ForInStatement{
+			For:    i.idx(m),
+			Into:   i.forInto(child(m, "left")),
+			Source: i.expression(child(m, "right")),
+			Body:   i.statement(child(m, "body")),
+		}
+
+	case "ForOfStatement":
+		if flag(m, "await") {
+			i.fail("for await is not supported")
+		}
+		return &ast.ForOfStatement{
+			For:    i.idx(m),
+			Into:   i.forInto(child(m, "left")),
+			Source: i.expression(child(m, "right")),
+			Body:   i.statement(child(m, "body")),
+		}
+
+	case "VariableDeclaration":
+		return i.declaration(m)
+
+	case "FunctionDeclaration":
+		return &ast.FunctionDeclaration{Function: i.function(m)}
+
+	case "ClassDeclaration":
+		return &ast.ClassDeclaration{Class: i.class(m)}
+
+	case "ImportDeclaration":
+		return i.importDecl(m)
+
+	case "ExportNamedDeclaration":
+		return i.exportNamed(m)
+
+	case "ExportDefaultDeclaration":
+		d := child(m, "declaration")
+		var x ast.Expr
+		switch typeOf(d) {
+		case "FunctionDeclaration":
+			x = i.function(d)
+		case "ClassDeclaration":
+			x = i.class(d)
+		default:
+			x = i.expr(d)
+		}
+		return &ast.ExportDeclaration{Export: i.idx(m), Default: &ast.Expression{Expr: x}}
+	}
+	i.unsupported(m)
+	return &ast.BadStatement{From: i.idx(m), To: i.last(m) + 1}
+}
+
+func (i *importer) unsupported(m map[string]any) {
+	if m == nil {
+		i.fail("missing node")
+	} else {
+		i.fail("%q is not supported", typeOf(m))
+	}
+}
+
+func (i *importer) forInto(m map[string]any) *ast.ForInto {
+	if typeOf(m) == "VariableDeclaration" {
+		return &ast.ForInto{Into: i.declaration(m)}
+	}
+	return &ast.ForInto{Into: &ast.Expression{Expr: i.pattern(m)}}
+}
+
+func (i *importer) declaration(m map[string]any) *ast.VariableDeclaration {
+	kind := str(m, "kind")
+	tok, ok := variableKinds[kind]
+	if !ok {
+		i.fail("unknown declaration kind %q", kind)
+	}
+	n := &ast.VariableDeclaration{Idx: i.idx(m), Token: tok}
+	for _, d := range children(m, "declarations") {
+		n.List = append(n.List, ast.VariableDeclarator{
+			Target:      &ast.BindingTarget{Target: i.target(child(d, "id"))},
+			Initializer: i.optExpression(child(d, "init")),
+		})
+	}
+	return n
+}
+
+func (i *importer) expression(m map[string]any) *ast.Expression {
+	return &ast.Expression{Expr: i.expr(m)}
+}
+
+func (i *importer) optExpression(m map[string]any) *ast.Expression {
+	if m == nil {
+		return nil
+	}
+	return i.expression(m)
+}
+
+// slot converts an optional expression in a field the parser always fills,
+// leaving it empty when m is missing.
+func (i *importer) slot(m map[string]any) *ast.Expression {
+	if m == nil {
+		return &ast.Expression{}
+	}
+	return i.expression(m)
+}
+
+func (i *importer) exprs(list []map[string]any) ast.Expressions {
+	out := make(ast.Expressions, len(list))
+	for j, m := range list {
+		if m != nil {
+			out[j].Expr = i.expr(m)
+		}
+	}
+	return out
+}
+
+func (i *importer) ident(m map[string]any) *ast.Identifier {
+	if typeOf(m) != "Identifier" {
+		i.unsupported(m)
+		return &ast.Identifier{}
+	}
+	return &ast.Identifier{Idx: i.idx(m), Name: ast.StoredString(str(m, "name"))}
+}
+
+func (i *importer) optIdent(m map[string]any) *ast.Identifier {
+	if m == nil {
+		return nil
+	}
+	return i.ident(m)
+}
+
+// privateIdent converts an ESTree PrivateIdentifier or a Babel PrivateName.
+func (i *importer) privateIdent(m map[string]any) *ast.PrivateIdentifier {
+	name := str(m, "name")
+	if typeOf(m) == "PrivateName" {
+		name = str(child(m, "id"), "name")
+	}
+	return &ast.PrivateIdentifier{Identifier: &ast.Identifier{Idx: i.idx(m), Name: ast.StoredString(name)}}
+}
+
+func (i *importer) stringLiteral(m map[string]any) *ast.StringLiteral {
+	value := str(m, "value")
+	r, ok := raw(m)
+	if !ok {
+		r = build.Quote(value)
+	}
+	return &ast.StringLiteral{Idx: i.idx(m), Value: ast.StoredString(value), Raw: &r}
+}
+
+func (i *importer) numberLiteral(m map[string]any, value float64) *ast.NumberLiteral {
+	n := build.NumLit(value)
+	n.Idx = i.idx(m)
+	if r, ok := raw(m); ok {
+		n.Raw = &r
+	}
+	return n
+}
+
+func (i *importer) regExpLiteral(m map[string]any, pattern, flags string) *ast.RegExpLiteral {
+	n := build.Regex(pattern, flags)
+	n.Idx = i.idx(m)
+	return n
+}
+
+// literal converts an ESTree Literal.
+func (i *importer) literal(m map[string]any) ast.Expr {
+	switch value := m["value"].(type) {
+	case string:
+		return i.stringLiteral(m)
+	case float64:
+		return i.numberLiteral(m, value)
+	case bool:
+		return &ast.BooleanLiteral{Idx: i.idx(m), Value: value}
+	}
+	if re := child(m, "regex"); re != nil {
+		return i.regExpLiteral(m, str(re, "pattern"), str(re, "flags"))
+	}
+	if _, ok := m["bigint"]; ok {
+		i.fail("BigInt literals are not supported")
+		return &ast.InvalidExpression{From: i.idx(m), To: i.last(m) + 1}
+	}
+	// A number JSON cannot hold, such as 1e999, is written as null.
+	if r, ok := raw(m); ok && r != "null" {
+		f, err := strconv.ParseFloat(r, 64)
+		if err != nil && !strings.Contains(err.Error(), "range") {
+			i.fail("literal %s has no value", r)
+		}
+		return i.numberLiteral(m, f)
+	}
+	return &ast.NullLiteral{Idx: i.idx(m)}
+}
+
+func (i *importer) expr(m map[string]any) ast.Expr {
+	switch typeOf(m) {
+	case "Identifier":
+		return i.ident(m)
+
+	case "Literal":
+		return i.literal(m)
+
+	case "StringLiteral":
+		return i.stringLiteral(m)
+
+	case "NumericLiteral":
+		value, _ := m["value"].(float64)
+		return i.numberLiteral(m, value)
+
+	case "BooleanLiteral":
+		return &ast.BooleanLiteral{Idx: i.idx(m), Value: flag(m, "value")}
+
+	case "NullLiteral":
+		return &ast.NullLiteral{Idx: i.idx(m)}
+
+	case "RegExpLiteral":
+		return i.regExpLiteral(m, str(m, "pattern"), str(m, "flags"))
+
+	case "TemplateLiteral":
+		return i.template(m)
+
+	case "TaggedTemplateExpression":
+		n := i.template(child(m, "quasi"))
+		n.Tag = i.expression(child(m, "tag"))
+		return n
+
+	case "ThisExpression":
+		return &ast.ThisExpression{Idx: i.idx(m)}
+
+	case "Super":
+		return &ast.SuperExpression{Idx: i.idx(m)}
+
+	case "ArrayExpression":
+		return &ast.ArrayLiteral{
+			LeftBracket:  i.idx(m),
+			RightBracket: i.last(m),
+			Value:        i.exprs(children(m, "elements")),
+		}
+
+	case "ObjectExpression":
+		n := &ast.ObjectLiteral{LeftBrace: i.idx(m), RightBrace: i.last(m)}
+		for _, p := range children(m, "properties") {
+			n.Value = append(n.Value, ast.Property{Prop: i.property(p, false)})
+		}
+		return n
+
+	case "FunctionExpression":
+		return i.function(m)
+
+	case "ArrowFunctionExpression":
+		n := &ast.ArrowFunctionLiteral{
+			Start:         i.idx(m),
+			ParameterList: i.params(m),
+			Async:         flag(m, "async"),
+		}
+		if body := child(m, "body"); typeOf(body) == "BlockStatement" {
+			n.Body = &ast.ConciseBody{Body: i.block(body)}
+		} else {
+			n.Body = &ast.ConciseBody{Body: i.expression(body)}
+		}
+		return n
+
+	case "ClassExpression":
+		return i.class(m)
+
+	case "AssignmentExpression":
+		op := str(m, "operator")
+		tok := token.Assign
+		if op != "=" {
+			var ok bool
+			tok, ok = binaryOperators[strings.TrimSuffix(op, "=")]
+			if !ok || !strings.HasSuffix(op, "=") || tok == token.LogicalAnd || tok == token.LogicalOr || tok == token.Coalesce {
+				i.fail("assignment operator %q is not supported", op)
+			}
+		}
+		return &ast.AssignExpression{
+			Operator: tok,
+			Left:     &ast.Expression{Expr: i.pattern(child(m, "left"))},
+			Right:    i.expression(child(m, "right")),
+		}
+
+	case "BinaryExpression", "LogicalExpression":
+		op := str(m, "operator")
+		tok, ok := binaryOperators[op]
+		if !ok {
+			i.fail("binary operator %q is not supported", op)
+		}
+		return &ast.BinaryExpression{
+			Operator: tok,
+			Left:     i.expression(child(m, "left")),
+			Right:    i.expression(child(m, "right")),
+		}
+
+	case "UnaryExpression":
+		op := str(m, "operator")
+		tok, ok := unaryOperators[op]
+		if !ok {
+			i.fail("unary operator %q is not supported", op)
+		}
+		return &ast.UnaryExpression{Operator: tok, Idx: i.idx(m), Operand: i.expression(child(m, "argument"))}
+
+	case "UpdateExpression":
+		op := str(m, "operator")
+		tok, ok := updateOperators[op]
+		if !ok {
+			i.fail("update operator %q is not supported", op)
+		}
+		return &ast.UpdateExpression{
+			Operator: tok,
+			Idx:      i.idx(m),
+			Operand:  i.expression(child(m, "argument")),
+			Postfix:  !flag(m, "prefix"),
+		}
+
+	case "ConditionalExpression":
+		return &ast.ConditionalExpression{
+			Test:       i.expression(child(m, "test")),
+			Consequent: i.expression(child(m, "consequent")),
+			Alternate:  i.expression(child(m, "alternate")),
+		}
+
+	case "SequenceExpression":
+		return &ast.SequenceExpression{Sequence: i.exprs(children(m, "expressions"))}
+
+	case "ParenthesizedExpression":
+		return i.expr(child(m, "expression"))
+
+	case "CallExpression", "MemberExpression":
+		return i.link(m)
+
+	case "OptionalCallExpression", "OptionalMemberExpression":
+		// Babel marks every link of a chain, and has no node for the chain.
+		return &ast.OptionalChain{Base: &ast.Expression{Expr: i.link(m)}}
+
+	case "ChainExpression":
+		return &ast.OptionalChain{Base: i.expression(child(m, "expression"))}
+
+	case "NewExpression":
+		return &ast.NewExpression{
+			New:              i.idx(m),
+			Callee:           i.expression(child(m, "callee")),
+			ArgumentList:     i.exprs(children(m, "arguments")),
+			RightParenthesis: i.last(m),
+		}
+
+	case "SpreadElement":
+		return &ast.SpreadElement{Expression: i.expression(child(m, "argument"))}
+
+	case "YieldExpression":
+		return &ast.YieldExpression{
+			Yield:    i.idx(m),
+			Argument: i.optExpression(child(m, "argument")),
+			Delegate: flag(m, "delegate"),
+		}
+
+	case "AwaitExpression":
+		return &ast.AwaitExpression{Await: i.idx(m), Argument: i.expression(child(m, "argument"))}
+
+	case "MetaProperty":
+		meta := i.ident(child(m, "meta"))
+		return &ast.MetaProperty{Meta: meta, Property: i.ident(child(m, "property")), Idx: meta.Idx}
+
+	case "ObjectPattern", "ArrayPattern", "AssignmentPattern":
+		return i.pattern(m)
+	}
+	i.unsupported(m)
+	return &ast.InvalidExpression{From: i.idx(m), To: i.last(m) + 1}
+}
+
+// link converts a call or member expression, which may be a link of an
+// optional chain. The object or callee of an optional link is wrapped in
+// ast.Optional, and links further down the chain are not chains themselves.
+func (i *importer) link(m map[string]any) ast.Expr {
+	babel := strings.HasPrefix(typeOf(m), "Optional")
+	inner := func(c map[string]any) *ast.Expression {
+		var x ast.Expr
+		if babel && strings.HasPrefix(typeOf(c), "Optional") {
+			x = i.link(c)
+		} else {
+			x = i.expr(c)
+		}
+		if flag(m, "optional") {
+			x = &ast.Optional{Expr: &ast.Expression{Expr: x}}
+		}
+		return &ast.Expression{Expr: x}
+	}
+
+	switch typeOf(m) {
+	case "CallExpression", "OptionalCallExpression":
+		return &ast.CallExpression{
+			Callee:           inner(child(m, "callee")),
+			ArgumentList:     i.exprs(children(m, "arguments")),
+			RightParenthesis: i.last(m),
+		}
+	}
+
+	object := inner(child(m, "object"))
+	prop := child(m, "property")
+	switch {
+	case flag(m, "computed"):
+		return &ast.MemberExpression{
+			Object:   object,
+			Property: &ast.MemberProperty{Prop: &ast.ComputedProperty{Expr: i.expression(prop)}},
+		}
+	case typeOf(prop) == "PrivateIdentifier" || typeOf(prop) == "PrivateName":
+		return &ast.PrivateDotExpression{Left: object, Identifier: i.privateIdent(prop)}
+	}
+	return &ast.MemberExpression{Object: object, Property: &ast.MemberProperty{Prop: i.ident(prop)}}
+}
+
+func (i *importer) template(m map[string]any) *ast.TemplateLiteral {
+	n := &ast.TemplateLiteral{
+		OpenQuote:   i.idx(m),
+		CloseQuote:  i.last(m),
+		Expressions: i.exprs(children(m, "expressions")),
+	}
+	for _, q := range children(m, "quasis") {
+		value := child(q, "value")
+		cooked, valid := value["cooked"].(string)
+		n.Elements = append(n.Elements, ast.TemplateElement{
+			Idx:     i.idx(q),
+			Literal: str(value, "raw"),
+			Parsed:  ast.StoredString(cooked),
+			Valid:   valid,
+		})
+	}
+	return n
+}
+
+// target converts a binding target.
+func (i *importer) target(m map[string]any) ast.Target {
+	if t, ok := i.pattern(m).(ast.Target); ok {
+		return t
+	}
+	i.fail("%q is not a binding target", typeOf(m))
+	return &ast.InvalidExpression{From: i.idx(m), To: i.last(m) + 1}
+}
+
+// pattern converts the target of a binding or an assignment. Defaults are
+// assignments.
+func (i *importer) pattern(m map[string]any) ast.Expr {
+	switch typeOf(m) {
+	case "AssignmentPattern":
+		return &ast.AssignExpression{
+			Operator: token.Assign,
+			Left:     &ast.Expression{Expr: i.pattern(child(m, "left"))},
+			Right:    i.expression(child(m, "right")),
+		}
+
+	case "ArrayPattern":
+		n := &ast.ArrayPattern{LeftBracket: i.idx(m), RightBracket: i.last(m), Rest: &ast.Expression{}}
+		for _, el := range children(m, "elements") {
+			switch {
+			case el == nil:
+				n.Elements = append(n.Elements, ast.Expression{})
+			case typeOf(el) == "RestElement":
+				n.Rest = &ast.Expression{Expr: i.pattern(child(el, "argument"))}
+			default:
+				n.Elements = append(n.Elements, ast.Expression{Expr: i.pattern(el)})
+			}
+		}
+		return n
+
+	case "ObjectPattern":
+		n := &ast.ObjectPattern{LeftBrace: i.idx(m), RightBrace: i.last(m)}
+		for _, p := range children(m, "properties") {
+			if typeOf(p) == "RestElement" {
+				n.Rest = i.pattern(child(p, "argument"))
+				continue
+			}
+			n.Properties = append(n.Properties, ast.Property{Prop: i.property(p, true)})
+		}
+		return n
+
+	case "ParenthesizedExpression":
+		return i.pattern(child(m, "expression"))
+	}
+	return i.expr(m)
+}
+
+func (i *importer) params(m map[string]any) ast.ParameterList {
+	var list ast.ParameterList
+	for _, p := range children(m, "params") {
+		switch typeOf(p) {
+		case "RestElement":
+			list.Rest = i.pattern(child(p, "argument"))
+		case "AssignmentPattern":
+			list.List = append(list.List, ast.VariableDeclarator{
+				Target:      &ast.BindingTarget{Target: i.target(child(p, "left"))},
+				Initializer: i.expression(child(p, "right")),
+			})
+		default:
+			list.List = append(list.List, ast.VariableDeclarator{Target: &ast.BindingTarget{Target: i.target(p)}})
+		}
+	}
+	return list
+}
+
+// key converts a property key. Plain identifier keys become string literals
+// with unquoted raw text, as the parser makes them.
+func (i *importer) key(m map[string]any, computed bool) *ast.Expression {
+	if computed {
+		return i.expression(m)
+	}
+	switch typeOf(m) {
+	case "Identifier":
+		name := str(m, "name")
+		return &ast.Expression{Expr: &ast.StringLiteral{Idx: i.idx(m), Value: ast.StoredString(name), Raw: &name}}
+	case "PrivateIdentifier", "PrivateName":
+		return &ast.Expression{Expr: i.privateIdent(m)}
+	}
+	return i.expression(m)
+}
+
+// property converts a property of an object literal or an object pattern,
+// in ESTree or Babel form.
+func (i *importer) property(m map[string]any, pattern bool) ast.Prop {
+	switch typeOf(m) {
+	case "SpreadElement", "RestElement":
+		return &ast.SpreadElement{Expression: &ast.Expression{Expr: i.pattern(child(m, "argument"))}}
+
+	case "ObjectMethod":
+		kind := ast.PropertyKind(str(m, "kind"))
+		return &ast.PropertyKeyed{
+			Key:      i.key(child(m, "key"), flag(m, "computed")),
+			Kind:     kind,
+			Value:    &ast.Expression{Expr: i.method(m)},
+			Computed: flag(m, "computed"),
+		}
+
+	case "Property", "ObjectProperty":
+		value := child(m, "value")
+		if flag(m, "shorthand") {
+			name := value
+			init := &ast.Expression{}
+			if typeOf(value) == "AssignmentPattern" {
+				name = child(value, "left")
+				init = i.expression(child(value, "right"))
+			}
+			return &ast.PropertyShort{Name: i.ident(name), Initializer: init}
+		}
+
+		n := &ast.PropertyKeyed{
+			Key:      i.key(child(m, "key"), flag(m, "computed")),
+			Kind:     ast.PropertyKindValue,
+			Computed: flag(m, "computed"),
+		}
+		switch kind := str(m, "kind"); {
+		case kind == "get" || kind == "set":
+			n.Kind = ast.PropertyKind(kind)
+		case flag(m, "method"):
+			n.Kind = ast.PropertyKindMethod
+		}
+		switch {
+		case n.Kind != ast.PropertyKindValue:
+			n.Value = &ast.Expression{Expr: i.method(value)}
+		case pattern:
+			n.Value = &ast.Expression{Expr: i.pattern(value)}
+		default:
+			n.Value = i.expression(value)
+		}
+		return n
+	}
+	i.unsupported(m)
+	return &ast.PropertyKeyed{Key: &ast.Expression{}, Value: &ast.Expression{}}
+}
+
+// function converts a function declaration or expression, or a Babel
+// method, which holds its function inline.
+func (i *importer) function(m map[string]any) *ast.FunctionLiteral {
+	n := &ast.FunctionLiteral{
+		Function:      i.idx(m),
+		Name:          &ast.Identifier{},
+		ParameterList: i.params(m),
+		Body:          i.block(child(m, "body")),
+		Async:         flag(m, "async"),
+		Generator:     flag(m, "generator"),
+	}
+	if id := child(m, "id"); id != nil {
+		n.Name = i.ident(id)
+	}
+	return n
+}
+
+// method converts the function of a method, which has no name.
+func (i *importer) method(m map[string]any) *ast.FunctionLiteral {
+	fn := i.function(m)
+	fn.Name = nil
+	return fn
+}
+
+func (i *importer) class(m map[string]any) *ast.ClassLiteral {
+	body := child(m, "body")
+	n := &ast.ClassLiteral{
+		Class:      i.idx(m),
+		RightBrace: i.last(body),
+		Name:       &ast.Identifier{},
+		SuperClass: i.optExpression(child(m, "superClass")),
+	}
+	if id := child(m, "id"); id != nil {
+		n.Name = i.ident(id)
+	}
+	for _, el := range children(body, "body") {
+		n.Body = append(n.Body, ast.ClassElement{Element: i.classElement(el)})
+	}
+	return n
+}
+
+func (i *importer) classElement(m map[string]any) ast.Element {
+	computed := flag(m, "computed")
+	switch typeOf(m) {
+	case "MethodDefinition", "ClassMethod", "ClassPrivateMethod":
+		kind := ast.PropertyKind(str(m, "kind"))
+		if kind == "constructor" {
+			kind = ast.PropertyKindMethod
+		}
+		fn := m
+		if typeOf(m) == "MethodDefinition" {
+			fn = child(m, "value")
+		}
+		return &ast.MethodDefinition{
+			Idx:      i.idx(m),
+			Key:      i.key(child(m, "key"), computed),
+			Kind:     kind,
+			Body:     i.method(fn),
+			Computed: computed,
+			Static:   flag(m, "static"),
+		}
+
+	case "PropertyDefinition", "ClassProperty", "ClassPrivateProperty":
+		return &ast.FieldDefinition{
+			Idx:         i.idx(m),
+			Key:         i.key(child(m, "key"), computed),
+			Initializer: i.slot(child(m, "value")),
+			Computed:    computed,
+			Static:      flag(m, "static"),
+		}
+
+	case "StaticBlock":
+		return &ast.ClassStaticBlock{
+			Static: i.idx(m),
+			Block:  &ast.BlockStatement{LeftBrace: i.idx(m) + 7, List: i.body(m), RightBrace: i.last(m)},
+		}
+	}
+	i.unsupported(m)
+	return &ast.ClassStaticBlock{Block: &ast.BlockStatement{}}
+}
+
+func (i *importer) importDecl(m map[string]any) *ast.ImportDeclaration {
+	n := &ast.ImportDeclaration{Import: i.idx(m), Source: i.stringLiteral(child(m, "source"))}
+	for _, s := range children(m, "specifiers") {
+		switch typeOf(s) {
+		case "ImportDefaultSpecifier":
+			n.Default = i.ident(child(s, "local"))
+		case "ImportNamespaceSpecifier":
+			n.Namespace = i.ident(child(s, "local"))
+		case "ImportSpecifier":
+			n.Specifiers = append(n.Specifiers, ast.ImportSpecifier{
+				Imported: i.ident(child(s, "imported")),
+				Local:    i.ident(child(s, "local")),
+			})
+		default:
+			i.unsupported(s)
+		}
+	}
+	return n
+}
+
+func (i *importer) exportNamed(m map[string]any) *ast.ExportDeclaration {
+	n := &ast.ExportDeclaration{Export: i.idx(m)}
+	if d := child(m, "declaration"); d != nil {
+		n.Declaration = &ast.Statement{Stmt: i.stmt(d)}
+		return n
+	}
+	if source := child(m, "source"); source != nil {
+		n.Source = i.stringLiteral(source)
+	}
+	for _, s := range children(m, "specifiers") {
+		if typeOf(s) != "ExportSpecifier" {
+			i.unsupported(s)
+			continue
+		}
+		n.Specifiers = append(n.Specifiers, ast.ExportSpecifier{
+			Local:    i.ident(child(s, "local")),
+			Exported: i.ident(child(s, "exported")),
+		})
+	}
+	return n
+}
@@ -33,7 +33,7 @@ type (
 
 	YieldExpression struct {
 		Yield    Idx
-		Argument *Expression
+		Argument *Expression `optional:"true"`
 		Delegate bool
 	}
 
@@ -52,7 +52,7 @@ type (
 		LeftBracket  Idx
 		RightBracket Idx
 		Elements     Expressions
-		Rest         *Expression
+		Rest         *Expression `optional:"true"`
 	}
 
 	AssignExpression struct {
@@ -0,0 +1,517 @@
+//go:build ignore
+
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"hash/fnv"
+	"io/fs"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Generates binary.go
+
+type NodeType int
+
+const (
+	NodeTypeStruct NodeType = iota
+	NodeTypeSlice
+)
+
+type EncodableNodeType struct {
+	Type     NodeType
+	Name     string
+	Children []Child
+	// Elem is the element type of slices.
+	Elem string
+}
+
+type EncodableInterface struct {
+	Name       string
+	UniqueFunc string
+	Structs    []string
+}
+
+type FieldKind int
+
+const (
+	FieldKindIdx FieldKind = iota
+	FieldKindString
+	FieldKindRaw
+	FieldKindBool
+	FieldKindInt
+	FieldKindUint
+	FieldKindFloat
+	FieldKindNode
+	FieldKindPointer
+	FieldKindSlice
+	FieldKindInterface
+	// FieldKindScope is a ScopeContext, written only when it is not zero.
+	FieldKindScope
+	// FieldKindWrapper is a pointer to a wrapper: a node holding nothing but
+	// an interface, like Expression. Its presence is part of the variant.
+	FieldKindWrapper
+)
+
+type Child struct {
+	FieldName string
+	FieldType string
+	Kind      FieldKind
+}
+
+func main() {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, "./ast", func(info fs.FileInfo) bool {
+		return info.Name() != "binary.go"
+	}, parser.ParseComments)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	var nodes []EncodableNodeType
+	var interfaces []EncodableInterface
+	for name, file := range pkgs["ast"].Files {
+		if strings.HasSuffix(name, "clone.go") || strings.HasSuffix(name, "visit.go") || strings.HasSuffix(name, "equal.go") {
+			continue
+		}
+		nodes = append(nodes, findEncodableNodes(file)...)
+		interfaces = append(interfaces, findEncodableInterfaces(file)...)
+	}
+	for _, file := range pkgs["ast"].Files {
+		findStructsForInterfaces(file, interfaces)
+	}
+
+	slices.SortFunc(nodes, func(a, b EncodableNodeType) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(interfaces, func(a, b EncodableInterface) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	for i := range interfaces {
+		slices.Sort(interfaces[i].Structs)
+	}
+	for i := range nodes {
+		for j := range nodes[i].Children {
+			if slices.ContainsFunc(interfaces, func(a EncodableInterface) bool {
+				return a.Name == nodes[i].Children[j].FieldType
+			}) {
+				nodes[i].Children[j].Kind = FieldKindInterface
+			}
+		}
+	}
+	wrappers := map[string]Child{}
+	for _, node := range nodes {
+		if node.Type == NodeTypeStruct && len(node.Children) == 1 && node.Children[0].Kind == FieldKindInterface {
+			wrappers[node.Name] = node.Children[0]
+		}
+	}
+	for i := range nodes {
+		for j, child := range nodes[i].Children {
+			if _, ok := wrappers[child.FieldType]; ok && child.Kind == FieldKindPointer {
+				nodes[i].Children[j].Kind = FieldKindWrapper
+			}
+		}
+	}
+
+	s := bytes.NewBuffer([]byte("// Code generated by gen_binary.go; DO NOT EDIT.\n"))
+	s.WriteString("package ast\n\nimport \"github.com/t14raptor/go-fast/token\"\n\n")
+	fmt.Fprintf(s, "// binarySchema identifies the node definitions the encoding was generated\n")
+	fmt.Fprintf(s, "// from. Data written from other definitions is rejected.\n")
+	fmt.Fprintf(s, "const binarySchema = 0x%016x\n\n", schema(nodes, interfaces))
+
+	s.WriteString("// arenas allocates the nodes of a decoder.\n")
+	s.WriteString("type arenas struct {\n")
+	for _, node := range nodes {
+		if node.Type == NodeTypeStruct {
+			fmt.Fprintf(s, "%s arena[%s]\n", node.Name, node.Name)
+		}
+	}
+	s.WriteString("}\n\n")
+
+	for _, node := range nodes {
+		writeEncode(s, node, wrappers)
+		writeDecode(s, node, wrappers)
+	}
+	for _, intf := range interfaces {
+		writeInterfaceEncode(s, intf)
+		writeInterfaceDecode(s, intf)
+	}
+
+	out, err := format.Source(s.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, s.Bytes())
+	}
+	os.WriteFile("ast/binary.go", out, 0644)
+}
+
+// schema hashes the layout of every node and the variants of every
+// interface, in encoding order.
+func schema(nodes []EncodableNodeType, interfaces []EncodableInterface) uint64 {
+	h := fnv.New64a()
+	for _, node := range nodes {
+		fmt.Fprintf(h, "%s %d{", node.Name, node.Type)
+		for _, child := range node.Children {
+			fmt.Fprintf(h, "%s %s %d;", child.FieldName, child.FieldType, child.Kind)
+		}
+		fmt.Fprintf(h, "}\n")
+	}
+	for _, intf := range interfaces {
+		fmt.Fprintf(h, "%s %s\n", intf.Name, strings.Join(intf.Structs, " "))
+	}
+	return h.Sum64()
+}
+
+// flagged returns the fields that are written as bits of a single flags
+// value: booleans, whether optional fields are present and whether scope
+// contexts are not zero.
+func flagged(node EncodableNodeType) map[string]int {
+	bits := map[string]int{}
+	for _, child := range node.Children {
+		switch child.Kind {
+		case FieldKindBool, FieldKindPointer, FieldKindScope:
+			bits[child.FieldName] = len(bits)
+		}
+	}
+	return bits
+}
+
+// carrier returns the string field that carries the flags of node in the low
+// bits of its reference, which is written first. Nodes without one write
+// their flags as a number of their own.
+func carrier(node EncodableNodeType) (Child, bool) {
+	if len(flagged(node)) == 0 {
+		return Child{}, false
+	}
+	for _, child := range node.Children {
+		if child.Kind == FieldKindString {
+			return child, true
+		}
+	}
+	return Child{}, false
+}
+
+func writeEncode(s *bytes.Buffer, node EncodableNodeType, wrappers map[string]Child) {
+	fmt.Fprintf(s, "func (n *%s) encode(e *encoder) {\n", node.Name)
+	switch node.Type {
+	case NodeTypeStruct:
+		bits := flagged(node)
+		str, carried := carrier(node)
+		if len(bits) > 0 {
+			s.WriteString("var flags uint64\n")
+			for _, child := range node.Children {
+				bit, ok := bits[child.FieldName]
+				if !ok {
+					continue
+				}
+				cond := "n." + child.FieldName
+				if child.Kind == FieldKindPointer {
+					cond += " != nil"
+				} else if child.Kind == FieldKindScope {
+					cond += " != 0"
+				}
+				fmt.Fprintf(s, "if %s {\nflags |= 1 << %d\n}\n", cond, bit)
+			}
+			if carried {
+				fmt.Fprintf(s, "e.flaggedString(string(n.%s), flags, %d)\n", str.FieldName, len(bits))
+			} else {
+				s.WriteString("e.uint(flags)\n")
+			}
+		}
+		for _, child := range node.Children {
+			f := child.FieldName
+			switch child.Kind {
+			case FieldKindIdx:
+				fmt.Fprintf(s, "e.idx(n.%s)\n", f)
+			case FieldKindString:
+				if !carried || f != str.FieldName {
+					fmt.Fprintf(s, "e.string(string(n.%s))\n", f)
+				}
+			case FieldKindRaw:
+				fmt.Fprintf(s, "e.raw(n.%s)\n", f)
+			case FieldKindInt:
+				fmt.Fprintf(s, "e.int(int64(n.%s))\n", f)
+			case FieldKindScope:
+				fmt.Fprintf(s, "if n.%s != 0 {\ne.int(int64(n.%s))\n}\n", f, f)
+			case FieldKindUint:
+				fmt.Fprintf(s, "e.uint(uint64(n.%s))\n", f)
+			case FieldKindFloat:
+				fmt.Fprintf(s, "e.float(n.%s)\n", f)
+			case FieldKindNode:
+				fmt.Fprintf(s, "n.%s.encode(e)\n", f)
+			case FieldKindPointer:
+				fmt.Fprintf(s, "if n.%s != nil {\nn.%s.encode(e)\n}\n", f, f)
+			case FieldKindWrapper:
+				w := wrappers[child.FieldType]
+				fmt.Fprintf(s, "if n.%s == nil {\ne.uint(0)\n} else {\nencode%s(e, n.%s.%s)\n}\n", f, w.FieldType, f, w.FieldName)
+			case FieldKindInterface:
+				fmt.Fprintf(s, "encode%s(e, n.%s)\n", child.FieldType, f)
+			case FieldKindSlice:
+				fmt.Fprintf(s, "if e.length(n.%s == nil, len(n.%s)) {\n", f, f)
+				fmt.Fprintf(s, "for i := range n.%s {\nn.%s[i].encode(e)\n}\n}\n", f, f)
+			}
+		}
+	case NodeTypeSlice:
+		s.WriteString("if e.length(*n == nil, len(*n)) {\n")
+		s.WriteString("for i := range *n {\n(*n)[i].encode(e)\n}\n}\n")
+	}
+	s.WriteString("}\n\n")
+}
+
+func writeDecode(s *bytes.Buffer, node EncodableNodeType, wrappers map[string]Child) {
+	fmt.Fprintf(s, "func (n *%s) decode(d *decoder) {\n", node.Name)
+	switch node.Type {
+	case NodeTypeStruct:
+		bits := flagged(node)
+		str, carried := carrier(node)
+		if carried {
+			s.WriteString("str, flags := d.flaggedString(")
+			fmt.Fprintf(s, "%d)\n", len(bits))
+			if str.FieldType == "string" {
+				fmt.Fprintf(s, "n.%s = str\n", str.FieldName)
+			} else {
+				fmt.Fprintf(s, "n.%s = %s(str)\n", str.FieldName, str.FieldType)
+			}
+		} else if len(bits) > 0 {
+			s.WriteString("flags := d.uint()\n")
+		}
+		for _, child := range node.Children {
+			f, t := child.FieldName, child.FieldType
+			switch child.Kind {
+			case FieldKindIdx:
+				fmt.Fprintf(s, "n.%s = d.idx()\n", f)
+			case FieldKindString:
+				if carried && f == str.FieldName {
+					continue
+				}
+				if t == "string" {
+					fmt.Fprintf(s, "n.%s = d.string()\n", f)
+				} else {
+					fmt.Fprintf(s, "n.%s = %s(d.string())\n", f, t)
+				}
+			case FieldKindRaw:
+				fmt.Fprintf(s, "n.%s = d.raw()\n", f)
+			case FieldKindBool:
+				fmt.Fprintf(s, "n.%s = flags&(1<<%d) != 0\n", f, bits[f])
+			case FieldKindInt:
+				fmt.Fprintf(s, "n.%s = %s(d.int())\n", f, t)
+			case FieldKindScope:
+				fmt.Fprintf(s, "if flags&(1<<%d) != 0 {\nn.%s = %s(d.int())\n}\n", bits[f], f, t)
+			case FieldKindUint:
+				fmt.Fprintf(s, "n.%s = %s(d.uint())\n", f, t)
+			case FieldKindFloat:
+				fmt.Fprintf(s, "n.%s = d.float()\n", f)
+			case FieldKindNode:
+				fmt.Fprintf(s, "n.%s.decode(d)\n", f)
+			case FieldKindPointer:
+				fmt.Fprintf(s, "if flags&(1<<%d) != 0 {\nn.%s = d.arenas.%s.new()\nn.%s.decode(d)\n}\n", bits[f], f, t, f)
+			case FieldKindWrapper:
+				w := wrappers[t]
+				fmt.Fprintf(s, "if v := d.uint(); v != 0 {\nn.%s = d.arenas.%s.new()\nn.%s.%s = decode%s(d, v)\n}\n", f, t, f, w.FieldName, w.FieldType)
+			case FieldKindInterface:
+				fmt.Fprintf(s, "n.%s = decode%s(d, d.uint())\n", f, t)
+			case FieldKindSlice:
+				fmt.Fprintf(s, "if l, ok := d.length(); ok {\nn.%s = d.arenas.%s.slice(l)\n", f, t)
+				fmt.Fprintf(s, "for i := range n.%s {\nn.%s[i].decode(d)\n}\n}\n", f, f)
+			}
+		}
+	case NodeTypeSlice:
+		fmt.Fprintf(s, "if l, ok := d.length(); ok {\n*n = d.arenas.%s.slice(l)\n", node.Elem)
+		s.WriteString("for i := range *n {\n(*n)[i].decode(d)\n}\n}\n")
+	}
+	s.WriteString("}\n\n")
+}
+
+// Variants are numbered from 2: 1 is a nil interface, and 0 a nil pointer to
+// a wrapper holding the interface.
+
+func writeInterfaceEncode(s *bytes.Buffer, intf EncodableInterface) {
+	fmt.Fprintf(s, "func encode%s(e *encoder, n %s) {\n", intf.Name, intf.Name)
+	s.WriteString("switch n := n.(type) {\ncase nil:\ne.uint(1)\n")
+	for i, name := range intf.Structs {
+		fmt.Fprintf(s, "case *%s:\ne.uint(%d)\nn.encode(e)\n", name, i+2)
+	}
+	s.WriteString("default:\n")
+	fmt.Fprintf(s, "e.fail(\"unexpected %s variant %%T\", n)\n", intf.Name)
+	s.WriteString("}\n}\n\n")
+}
+
+func writeInterfaceDecode(s *bytes.Buffer, intf EncodableInterface) {
+	fmt.Fprintf(s, "func decode%s(d *decoder, v uint64) %s {\n", intf.Name, intf.Name)
+	s.WriteString("switch v {\ncase 1:\nreturn nil\n")
+	for i, name := range intf.Structs {
+		fmt.Fprintf(s, "case %d:\nn := d.arenas.%s.new()\nn.decode(d)\nreturn n\n", i+2, name)
+	}
+	s.WriteString("default:\n")
+	fmt.Fprintf(s, "d.fail(\"unknown %s variant %%d\", v)\n", intf.Name)
+	s.WriteString("return nil\n}\n}\n\n")
+}
+
+func findEncodableInterfaces(f *ast.File) []EncodableInterface {
+	var interfaces []EncodableInterface
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			switch typeSpec.Name.Name {
+			case "Node", "VisitableNode", "CloneableNode":
+				continue
+			}
+
+			switch t := typeSpec.Type.(type) {
+			case *ast.InterfaceType:
+				idx := slices.IndexFunc(t.Methods.List, func(a *ast.Field) bool {
+					if len(a.Names) == 0 {
+						return false
+					}
+					return strings.HasPrefix(a.Names[0].Name, "_")
+				})
+				if idx == -1 {
+					continue
+				}
+				interfaces = append(interfaces, EncodableInterface{
+					Name:       typeSpec.Name.Name,
+					UniqueFunc: t.Methods.List[idx].Names[0].Name,
+				})
+			}
+		}
+	}
+	return interfaces
+}
+
+func findStructsForInterfaces(f *ast.File, interfaces []EncodableInterface) {
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		ident := receiverName(funcDecl)
+		if ident == "" {
+			continue
+		}
+		idx := slices.IndexFunc(interfaces, func(a EncodableInterface) bool {
+			return a.UniqueFunc == funcDecl.Name.Name
+		})
+		if idx == -1 {
+			continue
+		}
+		interfaces[idx].Structs = append(interfaces[idx].Structs, ident)
+	}
+}
+
+func receiverName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	starExpr, ok := funcDecl.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := starExpr.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return ident.Name
+}
+
+func findEncodableNodes(f *ast.File) (types []EncodableNodeType) {
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || !typeSpec.Name.IsExported() {
+				continue
+			}
+
+			switch typeSpec.Name.Name {
+			case "ScopeContext", "Id", "CompareOptions", "CloneOptions", "Problem":
+				continue
+			}
+
+			switch t := typeSpec.Type.(type) {
+			case *ast.StructType:
+				types = append(types, EncodableNodeType{
+					Type:     NodeTypeStruct,
+					Name:     typeSpec.Name.Name,
+					Children: findStructChildren(t.Fields.List),
+				})
+			case *ast.ArrayType:
+				types = append(types, EncodableNodeType{
+					Type: NodeTypeSlice,
+					Name: typeSpec.Name.Name,
+					Elem: t.Elt.(*ast.Ident).Name,
+				})
+			}
+		}
+	}
+	return types
+}
+
+func findStructChildren(fields []*ast.Field) (children []Child) {
+	for _, field := range fields {
+		names := field.Names
+		if len(names) == 0 {
+			// Embedded field, named after its type.
+			if ident, ok := field.Type.(*ast.Ident); ok {
+				names = []*ast.Ident{ident}
+			}
+		}
+		for _, name := range names {
+			children = append(children, newChild(name.Name, field.Type))
+		}
+	}
+	return children
+}
+
+func newChild(fieldName string, fieldType ast.Expr) Child {
+	switch t := fieldType.(type) {
+	case *ast.SelectorExpr:
+		// token.Token
+		return Child{FieldName: fieldName, FieldType: "token." + t.Sel.Name, Kind: FieldKindUint}
+	case *ast.Ident:
+		switch t.Name {
+		case "Idx":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindIdx}
+		case "string", "PropertyKind":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindString}
+		case "bool":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindBool}
+		case "int":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindInt}
+		case "ScopeContext":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindScope}
+		case "float64":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindFloat}
+		}
+		return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindNode}
+	case *ast.StarExpr:
+		ident := t.X.(*ast.Ident)
+		if ident.Name == "string" {
+			// Raw source text of literals.
+			return Child{FieldName: fieldName, FieldType: ident.Name, Kind: FieldKindRaw}
+		}
+		return Child{FieldName: fieldName, FieldType: ident.Name, Kind: FieldKindPointer}
+	case *ast.ArrayType:
+		return Child{FieldName: fieldName, FieldType: t.Elt.(*ast.Ident).Name, Kind: FieldKindSlice}
+	}
+	log.Fatalf("unsupported field %s of type %T", fieldName, fieldType)
+	return Child{}
+}
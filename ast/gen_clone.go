@@ -124,7 +124,7 @@ func main() {
 								Tok: token.VAR,
 								Specs: []ast.Spec{
 									&ast.ValueSpec{
-										Names: []*ast.Ident{ast.NewIdent(strings.ToLower(child.FieldName))},
+										Names: []*ast.Ident{localIdent(child.FieldName)},
 										Type:  &ast.StarExpr{X: ast.NewIdent(child.FieldType)},
 									},
 								},
@@ -138,7 +138,7 @@ func main() {
 							Body: &ast.BlockStmt{
 								List: []ast.Stmt{
 									&ast.AssignStmt{
-										Lhs: []ast.Expr{ast.NewIdent(strings.ToLower(child.FieldName))},
+										Lhs: []ast.Expr{localIdent(child.FieldName)},
 										Tok: token.ASSIGN,
 										Rhs: []ast.Expr{&ast.CallExpr{
 											Fun: newSelectorExpr(
@@ -152,7 +152,7 @@ func main() {
 						})
 					fields = append(fields, &ast.KeyValueExpr{
 						Key:   ast.NewIdent(child.FieldName),
-						Value: ast.NewIdent(strings.ToLower(child.FieldName)),
+						Value: localIdent(child.FieldName),
 					})
 					continue
 				}
@@ -188,10 +188,26 @@ func main() {
 				},
 			})
 		case NodeTypeSlice:
+			// if *n == nil {
+			//     return new(T)
+			// }
+			// ns := make(T, len(*n))
 			// for i := range *n {
-			//     (*n)[i].VisitWith(v)
+			//     ns[i] = *(*n)[i].Clone()
 			// }
-			visitChildrenBlock.List = append(visitChildrenBlock.List, &ast.AssignStmt{
+			visitChildrenBlock.List = append(visitChildrenBlock.List, &ast.IfStmt{
+				Cond: &ast.BinaryExpr{
+					X:  &ast.StarExpr{X: ast.NewIdent("n")},
+					Op: token.EQL,
+					Y:  ast.NewIdent("nil"),
+				},
+				Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{
+					Results: []ast.Expr{&ast.CallExpr{
+						Fun:  ast.NewIdent("new"),
+						Args: []ast.Expr{ast.NewIdent(node.Name)},
+					}},
+				}}},
+			}, &ast.AssignStmt{
 				Lhs: []ast.Expr{ast.NewIdent("ns")},
 				Tok: token.DEFINE,
 				Rhs: []ast.Expr{&ast.CallExpr{
@@ -241,6 +257,12 @@ func main() {
 
 	genPkg := &ast.File{
 		Name: ast.NewIdent("ast"),
+		Decls: []ast.Decl{
+			&ast.GenDecl{
+				Tok:   token.IMPORT,
+				Specs: []ast.Spec{&ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: `"fmt"`}}},
+			},
+		},
 	}
 
 	genPkg.Decls = append(genPkg.Decls, visitMethods...)
@@ -327,12 +349,12 @@ func findCloneableNodes(f *ast.File) (types []CloneableNodeType) {
 		}
 		for _, spec := range genDecl.Specs {
 			typeSpec, ok := spec.(*ast.TypeSpec)
-			if !ok {
+			if !ok || !typeSpec.Name.IsExported() {
 				continue
 			}
 
 			switch typeSpec.Name.Name {
-			case "ScopeContext", "Id":
+			case "ScopeContext", "Id", "CompareOptions", "CloneOptions", "Problem":
 				continue
 			}
 
@@ -357,34 +379,34 @@ func findCloneableNodes(f *ast.File) (types []CloneableNodeType) {
 func findStructChildren(fields []*ast.Field) (children []Child) {
 	for _, field := range fields {
 		optional := field.Tag != nil && field.Tag.Value == "`optional:\"true\"`"
-		if len(field.Names) != 0 {
-			fmt.Println(field.Names[0].Name)
-		}
 
-		switch fieldType := field.Type.(type) {
-		case *ast.SelectorExpr:
-			children = append(children, newChild(field.Names[0].Name, "", false, false, optional))
-		case *ast.Ident:
-			if len(field.Names) == 0 {
-				children = append(children, newChild(fieldType.Name, fieldType.Name, true, false, optional))
-				continue
-			}
-
-			switch fieldType.Name {
-			case "Idx", "any", "bool", "int", "ScopeContext", "string", "PropertyKind", "Token", "float64":
-				children = append(children, newChild(field.Names[0].Name, fieldType.Name, false, false, optional))
-			default:
-				children = append(children, newChild(field.Names[0].Name, fieldType.Name, true, false, optional))
+		if len(field.Names) == 0 {
+			if ident, ok := field.Type.(*ast.Ident); ok {
+				children = append(children, newChild(ident.Name, ident.Name, true, false, optional))
 			}
-		case *ast.StarExpr:
-			if ident, ok := fieldType.X.(*ast.Ident); ok {
-				if ident.Name == "string" {
-					children = append(children, newChild(field.Names[0].Name, ident.Name, false, false, optional))
-					continue
+			continue
+		}
+		for _, name := range field.Names {
+			switch fieldType := field.Type.(type) {
+			case *ast.SelectorExpr:
+				children = append(children, newChild(name.Name, "", false, false, optional))
+			case *ast.Ident:
+				switch fieldType.Name {
+				case "Idx", "any", "bool", "int", "ScopeContext", "string", "PropertyKind", "Token", "float64":
+					children = append(children, newChild(name.Name, fieldType.Name, false, false, optional))
+				default:
+					children = append(children, newChild(name.Name, fieldType.Name, true, false, optional))
+				}
+			case *ast.StarExpr:
+				if ident, ok := fieldType.X.(*ast.Ident); ok {
+					if ident.Name == "string" {
+						children = append(children, newChild(name.Name, ident.Name, false, false, optional))
+						continue
+					}
+					children = append(children, newChild(name.Name, ident.Name, true, true, optional))
+				} else {
+					children = append(children, newChild(name.Name, "", true, false, optional))
 				}
-				children = append(children, newChild(field.Names[0].Name, ident.Name, true, true, optional))
-			} else {
-				children = append(children, newChild(field.Names[0].Name, "", true, false, optional))
 			}
 		}
 	}
@@ -404,6 +426,16 @@ func newSelectorExpr(x ast.Expr, sel string) *ast.SelectorExpr {
 	return &ast.SelectorExpr{X: x, Sel: ast.NewIdent(sel)}
 }
 
+// localIdent names the variable holding the clone of a field, which must not
+// be a keyword such as default.
+func localIdent(field string) *ast.Ident {
+	name := strings.ToLower(field)
+	if token.IsKeyword(name) {
+		name = "cloned" + field
+	}
+	return ast.NewIdent(name)
+}
+
 func lowerIdent(name string) *ast.Ident {
 	return ast.NewIdent(strings.ToLower(name[:1]) + name[1:])
 }
@@ -421,7 +453,10 @@ func newCloner(expr ast.Expr, intf *CloneableInterface) (*ast.DeclStmt, *ast.Typ
 		},
 	}
 
-	var cases []ast.Stmt
+	// A nil value stays nil.
+	cases := []ast.Stmt{
+		&ast.CaseClause{List: []ast.Expr{ast.NewIdent("nil")}},
+	}
 
 	for _, structName := range intf.Structs {
 		caseClause := &ast.CaseClause{
@@ -446,6 +481,23 @@ func newCloner(expr ast.Expr, intf *CloneableInterface) (*ast.DeclStmt, *ast.Typ
 		cases = append(cases, caseClause)
 	}
 
+	// Anything else is a type missing from the generator input, which must
+	// not be dropped silently.
+	cases = append(cases, &ast.CaseClause{
+		Body: []ast.Stmt{
+			&ast.ExprStmt{X: &ast.CallExpr{
+				Fun: ast.NewIdent("panic"),
+				Args: []ast.Expr{&ast.CallExpr{
+					Fun: newSelectorExpr(ast.NewIdent("fmt"), "Sprintf"),
+					Args: []ast.Expr{
+						&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", "ast: cannot clone %T as "+intf.Name)},
+						lowerIdent(intf.Name),
+					},
+				}},
+			}},
+		},
+	})
+
 	switchStmt := &ast.TypeSwitchStmt{
 		Assign: &ast.AssignStmt{
 			Lhs: []ast.Expr{
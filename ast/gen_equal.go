@@ -0,0 +1,429 @@
+//go:build ignore
+
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Generates equal.go
+
+type NodeType int
+
+const (
+	NodeTypeStruct NodeType = iota
+	NodeTypeSlice
+)
+
+type ComparableNodeType struct {
+	Type     NodeType
+	Name     string
+	Children []Child
+}
+
+type ComparableInterface struct {
+	Name       string
+	UniqueFunc string
+	Structs    []string
+}
+
+type FieldKind int
+
+const (
+	FieldKindSkip FieldKind = iota
+	FieldKindValue
+	FieldKindFloat
+	FieldKindScopeContext
+	FieldKindNode
+	FieldKindPointer
+	FieldKindSlice
+	FieldKindInterface
+)
+
+type Child struct {
+	FieldName string
+	FieldType string
+	Kind      FieldKind
+}
+
+func main() {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, "./ast", func(info fs.FileInfo) bool {
+		return info.Name() != "equal.go"
+	}, parser.ParseComments)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	var nodes []ComparableNodeType
+	var interfaces []ComparableInterface
+	manual := map[string]bool{}
+	visitable := map[string]bool{}
+	for name, file := range pkgs["ast"].Files {
+		findMethods(file, "equal", manual)
+		findMethods(file, "VisitWith", visitable)
+		if strings.HasSuffix(name, "clone.go") || strings.HasSuffix(name, "visit.go") {
+			continue
+		}
+		nodes = append(nodes, findComparableNodes(file)...)
+		interfaces = append(interfaces, findComparableInterfaces(file)...)
+	}
+	for _, file := range pkgs["ast"].Files {
+		findStructsForInterfaces(file, interfaces)
+	}
+
+	slices.SortFunc(nodes, func(a, b ComparableNodeType) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(interfaces, func(a, b ComparableInterface) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	for i := range interfaces {
+		slices.Sort(interfaces[i].Structs)
+	}
+	for i := range nodes {
+		for j := range nodes[i].Children {
+			if slices.ContainsFunc(interfaces, func(a ComparableInterface) bool {
+				return a.Name == nodes[i].Children[j].FieldType
+			}) {
+				nodes[i].Children[j].Kind = FieldKindInterface
+			}
+		}
+	}
+
+	s := bytes.NewBuffer([]byte("// Code generated by gen_equal.go; DO NOT EDIT.\n"))
+	s.WriteString("package ast\n\nimport \"fmt\"\n\n")
+
+	for _, node := range nodes {
+		if manual[node.Name] {
+			continue
+		}
+		writeEqual(s, node)
+		writeHash(s, node)
+	}
+	for _, intf := range interfaces {
+		writeInterfaceEqual(s, intf)
+		writeInterfaceHash(s, intf)
+	}
+	nodes = slices.DeleteFunc(nodes, func(a ComparableNodeType) bool {
+		return !visitable[a.Name]
+	})
+	writeNodeEqual(s, nodes)
+	writeNodeHash(s, nodes)
+
+	out, err := format.Source(s.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, s.Bytes())
+	}
+	os.WriteFile("ast/equal.go", out, 0644)
+}
+
+func writeEqual(s *bytes.Buffer, node ComparableNodeType) {
+	fmt.Fprintf(s, "func (n *%s) equal(o *%s, c *comparer) bool {\n", node.Name, node.Name)
+	s.WriteString("if n == nil || o == nil {\nreturn n == o\n}\n")
+	switch node.Type {
+	case NodeTypeStruct:
+		var conds []string
+		for _, child := range node.Children {
+			f := child.FieldName
+			switch child.Kind {
+			case FieldKindValue:
+				conds = append(conds, fmt.Sprintf("n.%s == o.%s", f, f))
+			case FieldKindFloat:
+				conds = append(conds, fmt.Sprintf("equalFloat(n.%s, o.%s)", f, f))
+			case FieldKindScopeContext:
+				conds = append(conds, fmt.Sprintf("c.scopeContext(n.%s, o.%s)", f, f))
+			case FieldKindNode:
+				conds = append(conds, fmt.Sprintf("n.%s.equal(&o.%s, c)", f, f))
+			case FieldKindPointer:
+				conds = append(conds, fmt.Sprintf("n.%s.equal(o.%s, c)", f, f))
+			case FieldKindInterface:
+				conds = append(conds, fmt.Sprintf("equal%s(n.%s, o.%s, c)", child.FieldType, f, f))
+			case FieldKindSlice:
+				fmt.Fprintf(s, "if len(n.%s) != len(o.%s) {\nreturn false\n}\n", f, f)
+				fmt.Fprintf(s, "for i := range n.%s {\nif !n.%s[i].equal(&o.%s[i], c) {\nreturn false\n}\n}\n", f, f, f)
+			}
+		}
+		if len(conds) == 0 {
+			s.WriteString("return true\n")
+		} else {
+			fmt.Fprintf(s, "return %s\n", strings.Join(conds, " &&\n"))
+		}
+	case NodeTypeSlice:
+		s.WriteString("if len(*n) != len(*o) {\nreturn false\n}\n")
+		s.WriteString("for i := range *n {\nif !(*n)[i].equal(&(*o)[i], c) {\nreturn false\n}\n}\n")
+		s.WriteString("return true\n")
+	}
+	s.WriteString("}\n\n")
+}
+
+func writeHash(s *bytes.Buffer, node ComparableNodeType) {
+	fmt.Fprintf(s, "func (n *%s) hash(h *hasher) {\n", node.Name)
+	s.WriteString("if n == nil {\nh.nil()\nreturn\n}\n")
+	fmt.Fprintf(s, "h.tag(%q)\n", node.Name)
+	switch node.Type {
+	case NodeTypeStruct:
+		for _, child := range node.Children {
+			f := child.FieldName
+			switch child.Kind {
+			case FieldKindValue:
+				switch child.FieldType {
+				case "string", "PropertyKind":
+					fmt.Fprintf(s, "h.string(string(n.%s))\n", f)
+				case "bool":
+					fmt.Fprintf(s, "h.bool(n.%s)\n", f)
+				default:
+					fmt.Fprintf(s, "h.int(int64(n.%s))\n", f)
+				}
+			case FieldKindFloat:
+				fmt.Fprintf(s, "h.float(n.%s)\n", f)
+			case FieldKindScopeContext:
+				fmt.Fprintf(s, "h.scopeContext(n.%s)\n", f)
+			case FieldKindNode:
+				fmt.Fprintf(s, "n.%s.hash(h)\n", f)
+			case FieldKindPointer:
+				fmt.Fprintf(s, "n.%s.hash(h)\n", f)
+			case FieldKindInterface:
+				fmt.Fprintf(s, "hash%s(n.%s, h)\n", child.FieldType, f)
+			case FieldKindSlice:
+				fmt.Fprintf(s, "h.int(int64(len(n.%s)))\n", f)
+				fmt.Fprintf(s, "for i := range n.%s {\nn.%s[i].hash(h)\n}\n", f, f)
+			}
+		}
+	case NodeTypeSlice:
+		s.WriteString("h.int(int64(len(*n)))\n")
+		s.WriteString("for i := range *n {\n(*n)[i].hash(h)\n}\n")
+	}
+	s.WriteString("}\n\n")
+}
+
+func writeInterfaceEqual(s *bytes.Buffer, intf ComparableInterface) {
+	fmt.Fprintf(s, "func equal%s(a, b %s, c *comparer) bool {\n", intf.Name, intf.Name)
+	s.WriteString("if a == nil || b == nil {\nreturn a == nil && b == nil\n}\n")
+	s.WriteString("switch a := a.(type) {\n")
+	for _, name := range intf.Structs {
+		fmt.Fprintf(s, "case *%s:\nb, ok := b.(*%s)\nreturn ok && a.equal(b, c)\n", name, name)
+	}
+	s.WriteString("}\n")
+	fmt.Fprintf(s, "panic(fmt.Sprintf(\"ast: unexpected %s variant %%T\", a))\n", intf.Name)
+	s.WriteString("}\n\n")
+}
+
+func writeInterfaceHash(s *bytes.Buffer, intf ComparableInterface) {
+	fmt.Fprintf(s, "func hash%s(n %s, h *hasher) {\n", intf.Name, intf.Name)
+	s.WriteString("switch n := n.(type) {\ncase nil:\nh.nil()\n")
+	for _, name := range intf.Structs {
+		fmt.Fprintf(s, "case *%s:\nn.hash(h)\n", name)
+	}
+	s.WriteString("default:\n")
+	fmt.Fprintf(s, "panic(fmt.Sprintf(\"ast: unexpected %s variant %%T\", n))\n", intf.Name)
+	s.WriteString("}\n}\n\n")
+}
+
+func writeNodeEqual(s *bytes.Buffer, nodes []ComparableNodeType) {
+	s.WriteString("func equalNode(a, b VisitableNode, c *comparer) bool {\n")
+	s.WriteString("switch a := a.(type) {\n")
+	for _, node := range nodes {
+		fmt.Fprintf(s, "case *%s:\nb, ok := b.(*%s)\nreturn ok && a.equal(b, c)\n", node.Name, node.Name)
+	}
+	s.WriteString("}\n")
+	s.WriteString("panic(fmt.Sprintf(\"ast: unexpected node %T\", a))\n")
+	s.WriteString("}\n\n")
+}
+
+func writeNodeHash(s *bytes.Buffer, nodes []ComparableNodeType) {
+	s.WriteString("func hashNode(n VisitableNode, h *hasher) {\n")
+	s.WriteString("switch n := n.(type) {\n")
+	for _, node := range nodes {
+		fmt.Fprintf(s, "case *%s:\nn.hash(h)\n", node.Name)
+	}
+	s.WriteString("default:\n")
+	s.WriteString("panic(fmt.Sprintf(\"ast: unexpected node %T\", n))\n")
+	s.WriteString("}\n}\n")
+}
+
+func findComparableInterfaces(f *ast.File) []ComparableInterface {
+	var interfaces []ComparableInterface
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			switch typeSpec.Name.Name {
+			case "Node", "VisitableNode", "CloneableNode":
+				continue
+			}
+
+			switch t := typeSpec.Type.(type) {
+			case *ast.InterfaceType:
+				idx := slices.IndexFunc(t.Methods.List, func(a *ast.Field) bool {
+					if len(a.Names) == 0 {
+						return false
+					}
+					return strings.HasPrefix(a.Names[0].Name, "_")
+				})
+				if idx == -1 {
+					continue
+				}
+				interfaces = append(interfaces, ComparableInterface{
+					Name:       typeSpec.Name.Name,
+					UniqueFunc: t.Methods.List[idx].Names[0].Name,
+				})
+			}
+		}
+	}
+	return interfaces
+}
+
+func findStructsForInterfaces(f *ast.File, interfaces []ComparableInterface) {
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		ident := receiverName(funcDecl)
+		if ident == "" {
+			continue
+		}
+		idx := slices.IndexFunc(interfaces, func(a ComparableInterface) bool {
+			return a.UniqueFunc == funcDecl.Name.Name
+		})
+		if idx == -1 {
+			continue
+		}
+		interfaces[idx].Structs = append(interfaces[idx].Structs, ident)
+	}
+}
+
+// findMethods records the types that declare a method with the given name.
+// Nodes with a hand-written equal method are expected to hand-write hash too.
+func findMethods(f *ast.File, method string, found map[string]bool) {
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Name.Name != method {
+			continue
+		}
+		if ident := receiverName(funcDecl); ident != "" {
+			found[ident] = true
+		}
+	}
+}
+
+func receiverName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	starExpr, ok := funcDecl.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := starExpr.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return ident.Name
+}
+
+func findComparableNodes(f *ast.File) (types []ComparableNodeType) {
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || !typeSpec.Name.IsExported() {
+				continue
+			}
+
+			switch typeSpec.Name.Name {
+			case "ScopeContext", "Id", "CompareOptions", "CloneOptions", "Problem":
+				continue
+			}
+
+			switch t := typeSpec.Type.(type) {
+			case *ast.StructType:
+				types = append(types, ComparableNodeType{
+					Type:     NodeTypeStruct,
+					Name:     typeSpec.Name.Name,
+					Children: findStructChildren(t.Fields.List),
+				})
+			case *ast.ArrayType:
+				types = append(types, ComparableNodeType{
+					Type: NodeTypeSlice,
+					Name: typeSpec.Name.Name,
+				})
+			}
+		}
+	}
+	return types
+}
+
+func findStructChildren(fields []*ast.Field) (children []Child) {
+	for _, field := range fields {
+		names := field.Names
+		if len(names) == 0 {
+			// Embedded field, named after its type.
+			if ident, ok := field.Type.(*ast.Ident); ok {
+				names = []*ast.Ident{ident}
+			}
+		}
+		for _, name := range names {
+			children = append(children, newChild(name.Name, field.Type))
+		}
+	}
+	return children
+}
+
+func newChild(fieldName string, fieldType ast.Expr) Child {
+	switch t := fieldType.(type) {
+	case *ast.SelectorExpr:
+		// token.Token
+		return Child{FieldName: fieldName, FieldType: t.Sel.Name, Kind: FieldKindValue}
+	case *ast.Ident:
+		switch t.Name {
+		case "Idx":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindSkip}
+		case "string":
+			if fieldName == "Comment" {
+				return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindSkip}
+			}
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindValue}
+		case "bool", "int", "PropertyKind":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindValue}
+		case "float64":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindFloat}
+		case "ScopeContext":
+			return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindScopeContext}
+		}
+		return Child{FieldName: fieldName, FieldType: t.Name, Kind: FieldKindNode}
+	case *ast.StarExpr:
+		ident := t.X.(*ast.Ident)
+		if ident.Name == "string" {
+			// Raw source text of literals.
+			return Child{FieldName: fieldName, FieldType: ident.Name, Kind: FieldKindSkip}
+		}
+		return Child{FieldName: fieldName, FieldType: ident.Name, Kind: FieldKindPointer}
+	case *ast.ArrayType:
+		return Child{FieldName: fieldName, FieldType: t.Elt.(*ast.Ident).Name, Kind: FieldKindSlice}
+	}
+	log.Fatalf("unsupported field %s of type %T", fieldName, fieldType)
+	return Child{}
+}
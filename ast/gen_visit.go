@@ -216,12 +216,12 @@ func findVisitableNodes(f *ast.File) (types []VisitableNodeType) {
 		}
 		for _, spec := range genDecl.Specs {
 			typeSpec, ok := spec.(*ast.TypeSpec)
-			if !ok {
+			if !ok || !typeSpec.Name.IsExported() {
 				continue
 			}
 
 			switch typeSpec.Name.Name {
-			case "ScopeContext", "Id":
+			case "ScopeContext", "Id", "CompareOptions", "CloneOptions", "Problem":
 				continue
 			}
 
@@ -247,10 +247,6 @@ func findStructChildren(fields []*ast.Field) (children []Child) {
 	for _, field := range fields {
 		optional := field.Tag != nil && field.Tag.Value == "`optional:\"true\"`"
 
-		if len(field.Names) != 0 {
-			fmt.Println(field.Names[0].Name)
-		}
-
 		switch fieldType := field.Type.(type) {
 		case *ast.Ident:
 			if len(field.Names) == 0 {
@@ -261,14 +257,17 @@ func findStructChildren(fields []*ast.Field) (children []Child) {
 			switch fieldType.Name {
 			case "Idx", "any", "bool", "int", "ScopeContext", "string", "PropertyKind", "float64":
 			default:
-				fmt.Println(fieldType.Name)
-				children = append(children, newChild(field.Names[0].Name, optional))
+				for _, name := range field.Names {
+					children = append(children, newChild(name.Name, optional))
+				}
 			}
 		case *ast.StarExpr:
 			if ident, ok := fieldType.X.(*ast.Ident); ok && ident.Name == "string" {
 				continue
 			}
-			children = append(children, newChild(field.Names[0].Name, optional))
+			for _, name := range field.Names {
+				children = append(children, newChild(name.Name, optional))
+			}
 		}
 	}
 	return children
@@ -1,5 +1,10 @@
 package ast
 
+import (
+	"strings"
+	"unicode/utf8"
+)
+
 type (
 	BooleanLiteral struct {
 		Idx   Idx
@@ -38,3 +43,22 @@ func (*NullLiteral) _expr()    {}
 func (*NumberLiteral) _expr()  {}
 func (*RegExpLiteral) _expr()  {}
 func (*StringLiteral) _expr()  {}
+
+// The parser stores the values of string literals and templates, and names,
+// that contain non-ASCII characters with a leading U+FEFF. StringValue and
+// StoredString convert between that form and the string itself.
+
+// StringValue returns the string stored as s.
+func StringValue(s string) string {
+	return strings.TrimPrefix(s, "\ufeff")
+}
+
+// StoredString returns the form the parser stores the string s in.
+func StoredString(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return "\ufeff" + s
+		}
+	}
+	return s
+}
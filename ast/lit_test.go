@@ -0,0 +1,21 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+)
+
+func TestStoredString(t *testing.T) {
+	for _, src := range []string{`""`, `"a"`, `"é"`, `"aé"`, "\"\ufeffa\""} {
+		p := parse(t, src+";")
+		stored := p.Body[0].Stmt.(*ast.ExpressionStatement).Expression.Expr.(*ast.StringLiteral).Value
+		value := ast.StringValue(stored)
+		if got := ast.StoredString(value); got != stored {
+			t.Errorf("StoredString(%q) = %q, want %q as parsed from %s", value, got, stored, src)
+		}
+	}
+	if got := ast.StringValue(ast.StoredString("é")); got != "é" {
+		t.Errorf("StringValue(StoredString(%q)) = %q", "é", got)
+	}
+}
@@ -0,0 +1,64 @@
+package ast
+
+type (
+	// ImportDeclaration represents an import statement
+	ImportDeclaration struct {
+		Import     Idx
+		From       Idx
+		Source     *StringLiteral
+		Default    *Identifier      `optional:"true"` // For "import foo from 'bar'"
+		Namespace  *Identifier      `optional:"true"` // For "import * as foo from 'bar'"
+		Specifiers ImportSpecifiers // For "import { foo } from 'bar'"
+	}
+
+	ImportSpecifiers []ImportSpecifier
+
+	// ImportSpecifier represents a named import
+	ImportSpecifier struct {
+		Imported *Identifier // The imported name
+		Local    *Identifier // The local binding name
+	}
+
+	// ExportDeclaration represents an export statement
+	ExportDeclaration struct {
+		Export      Idx
+		From        Idx
+		Source      *StringLiteral `optional:"true"`
+		Default     *Expression    `optional:"true"` // For "export default expr", where a named function or class declares its name
+		Declaration *Statement     `optional:"true"` // For "export function f() {}" and "export const a = 1"
+		Specifiers  ExportSpecifiers
+	}
+
+	ExportSpecifiers []ExportSpecifier
+
+	// ExportSpecifier represents a named export. For "export { a as b }",
+	// Local is a and Exported is b; without an alias both are the same
+	// identifier.
+	//
+	// These fields used to be Imported and Local, in that order: the old
+	// Local is now Exported, and the old Imported is now Local.
+	ExportSpecifier struct {
+		Local    *Identifier // The local binding name
+		Exported *Identifier // The exported name
+	}
+)
+
+func (*ImportDeclaration) _stmt() {}
+func (*ExportDeclaration) _stmt() {}
+
+func (n *ImportDeclaration) Idx0() Idx { return n.Import }
+func (n *ImportDeclaration) Idx1() Idx { return n.Source.Idx1() }
+
+func (n *ExportDeclaration) Idx0() Idx { return n.Export }
+func (n *ExportDeclaration) Idx1() Idx {
+	if n.Source != nil {
+		return n.Source.Idx1()
+	}
+	if n.Default != nil && n.Default.Expr != nil {
+		return n.Default.Expr.Idx1()
+	}
+	if n.Declaration != nil && n.Declaration.Stmt != nil {
+		return n.Declaration.Stmt.Idx1()
+	}
+	return n.Export
+}
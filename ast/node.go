@@ -1,6 +1,9 @@
 package ast
 
-//go:generate go run ast/gen_visit.go
+//go:generate go run -C .. ast/gen_visit.go
+//go:generate go run -C .. ast/gen_clone.go
+//go:generate go run -C .. ast/gen_equal.go
+//go:generate go run -C .. ast/gen_binary.go
 
 // Idx is a compact encoding of a source position within JS code.
 type Idx int
@@ -110,7 +113,7 @@ func (b *BinaryExpression) Idx1() Idx      { return b.Right.Expr.Idx1() }
 func (b *BooleanLiteral) Idx1() Idx        { return Idx(int(b.Idx) + 4) }
 func (n *CallExpression) Idx1() Idx        { return n.RightParenthesis + 1 }
 func (n *ConditionalExpression) Idx1() Idx { return n.Test.Expr.Idx1() }
-func (p *PrivateDotExpression) Idx1() Idx  { return p.Idx1() }
+func (p *PrivateDotExpression) Idx1() Idx  { return p.Identifier.Idx1() }
 func (f *FunctionLiteral) Idx1() Idx       { return f.Body.Idx1() }
 func (c *ClassLiteral) Idx1() Idx          { return c.RightBrace + 1 }
 func (a *ArrowFunctionLiteral) Idx1() Idx  { return a.Body.Idx1() }
@@ -150,7 +153,8 @@ func (n *PrivateIdentifier) Idx0() Idx {
 	return n.Identifier.Idx0()
 }
 func (n *PrivateIdentifier) Idx1() Idx {
-	return n.Identifier.Idx1()
+	// The name does not include the #.
+	return n.Identifier.Idx1() + 1
 }
 
 func (n *BadStatement) Idx1() Idx        { return n.To }
@@ -229,86 +233,17 @@ func (y *YieldExpression) Idx1() Idx {
 	return y.Yield + 5
 }
 func (n *ForLoopInitializer) Idx1() Idx { return 0 }
-func (n *ConciseBody) Idx0() Idx        { return n.Idx0() }
-func (n *ConciseBody) Idx1() Idx        { return n.Idx1() }
 
-// ImportSpecifier represents a named import
-type ImportSpecifier struct {
-	Imported *Identifier // The imported name
-	Local    *Identifier // The local binding name
-}
-
-type ExportSpecifier struct {
-	Imported *Identifier // The imported name
-	Local    *Identifier // The local binding name
-}
-
-// ImportDeclaration represents an import statement
-type ImportDeclaration struct {
-	Import     Idx
-	From       Idx
-	Source     *StringLiteral
-	Default    *Identifier       // For "import foo from 'bar'"
-	Specifiers []ImportSpecifier // For "import { foo } from 'bar'"
-}
-
-func (n *ImportDeclaration) _stmt()    {}
-func (n *ImportDeclaration) Idx0() Idx { return n.Import }
-func (n *ImportDeclaration) Idx1() Idx { return n.Source.Idx1() }
-func (n *ImportDeclaration) VisitWith(v Visitor) {
-	if n != nil {
-		v.VisitImportDeclaration(n)
-	}
-}
-func (n *ImportDeclaration) VisitChildrenWith(v Visitor) {
-	if n.Source != nil {
-		v.VisitStringLiteral(n.Source)
-	}
-	if n.Default != nil {
-		v.VisitIdentifier(n.Default)
+func (n *ConciseBody) Idx0() Idx {
+	if body, ok := n.Body.(*Expression); ok {
+		return body.Expr.Idx0()
 	}
-	for _, spec := range n.Specifiers {
-		if spec.Imported != nil {
-			v.VisitIdentifier(spec.Imported)
-		}
-		if spec.Local != nil {
-			v.VisitIdentifier(spec.Local)
-		}
-	}
-}
-
-// ExportDeclaration represents an export statement
-type ExportDeclaration struct {
-	Export     Idx
-	From       Idx
-	Source     *StringLiteral
-	Default    *Expression
-	Specifiers []ExportSpecifier
+	return n.Body.(*BlockStatement).Idx0()
 }
 
-func (n *ExportDeclaration) _stmt()    {}
-func (n *ExportDeclaration) Idx0() Idx { return n.Export }
-func (n *ExportDeclaration) Idx1() Idx {
-	if n.Source != nil {
-		return n.Source.Idx1()
-	}
-	if n.Default != nil && n.Default.Expr != nil {
-		if idx1, ok := n.Default.Expr.(interface{ Idx1() Idx }); ok {
-			return idx1.Idx1()
-		}
-	}
-	return n.Export
-}
-func (n *ExportDeclaration) VisitWith(v Visitor) {
-	if n != nil {
-		v.VisitExportDeclaration(n)
-	}
-}
-func (n *ExportDeclaration) VisitChildrenWith(v Visitor) {
-	if n.Source != nil {
-		v.VisitStringLiteral(n.Source)
-	}
-	if n.Default != nil {
-		v.VisitExpression(n.Default)
+func (n *ConciseBody) Idx1() Idx {
+	if body, ok := n.Body.(*Expression); ok {
+		return body.Expr.Idx1()
 	}
+	return n.Body.(*BlockStatement).Idx1()
 }
@@ -0,0 +1,58 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/parser"
+)
+
+type spanCollector struct {
+	ast.NoopVisitor
+	src   string
+	spans map[string][]string
+}
+
+func (c *spanCollector) add(kind string, n ast.Node) {
+	c.spans[kind] = append(c.spans[kind], c.src[n.Idx0()-1:n.Idx1()-1])
+}
+
+func (c *spanCollector) VisitConciseBody(n *ast.ConciseBody) {
+	c.add("ConciseBody", n)
+	n.VisitChildrenWith(c)
+}
+
+func (c *spanCollector) VisitPrivateDotExpression(n *ast.PrivateDotExpression) {
+	c.add("PrivateDotExpression", n)
+	n.VisitChildrenWith(c)
+}
+
+func (c *spanCollector) VisitPrivateIdentifier(n *ast.PrivateIdentifier) {
+	c.add("PrivateIdentifier", n)
+}
+
+func TestIdx(t *testing.T) {
+	tests := []struct {
+		src  string
+		kind string
+		want string
+	}{
+		{"f = a => a + 1;", "ConciseBody", "a + 1"},
+		{"f = () => { return 1 };", "ConciseBody", "{ return 1 }"},
+		{"class C { #x; m() { return this.#x; } }", "PrivateDotExpression", "this.#x"},
+		{"class C { #x; m() { return this.#x; } }", "PrivateIdentifier", "#x"},
+	}
+	for _, tt := range tests {
+		p, err := parser.ParseFile(tt.src)
+		if err != nil {
+			t.Fatalf("ParseFile(%q): %v", tt.src, err)
+		}
+		c := &spanCollector{src: tt.src, spans: map[string][]string{}}
+		c.V = c
+		p.VisitWith(c)
+		spans := c.spans[tt.kind]
+		if len(spans) == 0 || spans[len(spans)-1] != tt.want {
+			t.Errorf("%s spans of %q are %q, want %q last", tt.kind, tt.src, spans, tt.want)
+		}
+	}
+}
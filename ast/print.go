@@ -0,0 +1,160 @@
+package ast
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// PrintMode controls the output of Fprint.
+type PrintMode uint
+
+const (
+	// HidePositions leaves out Idx fields.
+	HidePositions PrintMode = 1 << iota
+	// HideNil leaves out nil pointers, interfaces and slices.
+	HideNil
+)
+
+// Fprint writes an indented dump of the node n to w, for debugging. Every
+// struct is printed with its type and fields, including wrappers such as
+// Expression, so the output mirrors the paths a visitor takes. Literal values
+// are quoted, positions are printed as Idx values and tokens by their text.
+func Fprint(w io.Writer, n VisitableNode, mode PrintMode) error {
+	p := &printer{w: bufio.NewWriter(w), mode: mode, seen: map[uintptr]bool{}}
+	p.value(reflect.ValueOf(n))
+	p.printf("\n")
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
+
+type printer struct {
+	w      *bufio.Writer
+	mode   PrintMode
+	indent int
+	err    error
+
+	// The pointers on the path from the root, to stop at cycles.
+	seen map[uintptr]bool
+}
+
+func (p *printer) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func (p *printer) newline() {
+	p.printf("\n")
+	for i := 0; i < p.indent; i++ {
+		p.printf(".  ")
+	}
+}
+
+var (
+	idxType      = reflect.TypeOf(Idx(0))
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// hidden reports whether the field value v is left out.
+func (p *printer) hidden(v reflect.Value) bool {
+	if p.mode&HidePositions != 0 && v.Type() == idxType {
+		return true
+	}
+	if p.mode&HideNil != 0 {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice:
+			return v.IsNil()
+		}
+	}
+	return false
+}
+
+func (p *printer) value(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		p.printf("nil")
+
+	case reflect.Interface:
+		if v.IsNil() {
+			p.printf("nil")
+			return
+		}
+		p.value(v.Elem())
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			p.printf("nil")
+			return
+		}
+		if v.Elem().Kind() != reflect.Struct {
+			// The raw text of literals.
+			p.value(v.Elem())
+			return
+		}
+		ptr := v.Pointer()
+		if p.seen[ptr] {
+			p.printf("%s (cycle)", v.Type())
+			return
+		}
+		p.seen[ptr] = true
+		p.printf("*")
+		p.value(v.Elem())
+		delete(p.seen, ptr)
+
+	case reflect.Struct:
+		p.printf("%s {", v.Type())
+		p.indent++
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := v.Field(i)
+			if !t.Field(i).IsExported() || p.hidden(f) {
+				continue
+			}
+			p.newline()
+			p.printf("%s: ", t.Field(i).Name)
+			p.value(f)
+		}
+		p.indent--
+		if t.NumField() > 0 {
+			p.newline()
+		}
+		p.printf("}")
+
+	case reflect.Slice:
+		if v.IsNil() {
+			p.printf("%s nil", v.Type())
+			return
+		}
+		p.printf("%s (len = %d) {", v.Type(), v.Len())
+		p.indent++
+		for i := 0; i < v.Len(); i++ {
+			p.newline()
+			p.printf("%d: ", i)
+			p.value(v.Index(i))
+		}
+		p.indent--
+		if v.Len() > 0 {
+			p.newline()
+		}
+		p.printf("}")
+
+	case reflect.String:
+		p.printf("%s", strconv.Quote(v.String()))
+
+	case reflect.Float32, reflect.Float64:
+		p.printf("%s", strconv.FormatFloat(v.Float(), 'g', -1, 64))
+
+	default:
+		if v.Type().Implements(stringerType) && v.Type() != idxType {
+			// Tokens print as their text.
+			p.printf("%s", v.Interface().(fmt.Stringer).String())
+			return
+		}
+		p.printf("%v", v.Interface())
+	}
+}
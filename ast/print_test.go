@@ -0,0 +1,104 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/resolver"
+	"github.com/t14raptor/go-fast/token"
+)
+
+func TestFprint(t *testing.T) {
+	p := parse(t, `a = "x";`)
+	resolver.Resolve(p)
+	tests := []struct {
+		mode ast.PrintMode
+		want string
+	}{
+		{0, `*ast.Program {
+.  Body: ast.Statements (len = 1) {
+.  .  0: ast.Statement {
+.  .  .  Stmt: *ast.ExpressionStatement {
+.  .  .  .  Expression: *ast.Expression {
+.  .  .  .  .  Expr: *ast.AssignExpression {
+.  .  .  .  .  .  Operator: =
+.  .  .  .  .  .  Left: *ast.Expression {
+.  .  .  .  .  .  .  Expr: *ast.Identifier {
+.  .  .  .  .  .  .  .  Idx: 1
+.  .  .  .  .  .  .  .  Name: "a"
+.  .  .  .  .  .  .  .  ScopeContext: 0
+.  .  .  .  .  .  .  }
+.  .  .  .  .  .  }
+.  .  .  .  .  .  Right: *ast.Expression {
+.  .  .  .  .  .  .  Expr: *ast.StringLiteral {
+.  .  .  .  .  .  .  .  Idx: 5
+.  .  .  .  .  .  .  .  Value: "x"
+.  .  .  .  .  .  .  .  Raw: "\"x\""
+.  .  .  .  .  .  .  }
+.  .  .  .  .  .  }
+.  .  .  .  .  }
+.  .  .  .  }
+.  .  .  .  Comment: ""
+.  .  .  }
+.  .  }
+.  }
+}
+`},
+		{ast.HidePositions, `
+.  .  .  .  .  .  .  Expr: *ast.Identifier {
+.  .  .  .  .  .  .  .  Name: "a"
+`},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := ast.Fprint(&b, p, tt.mode); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b.String(), tt.want) {
+			t.Errorf("Fprint with mode %d gives\n%s\nwant it to contain\n%s", tt.mode, b.String(), tt.want)
+		}
+	}
+}
+
+func TestFprintMode(t *testing.T) {
+	p := parse(t, `function f(a) { let b = a; return b; } import {c} from "m";`)
+	resolver.Resolve(p)
+	tests := []struct {
+		mode    ast.PrintMode
+		want    []string
+		notWant []string
+	}{
+		{0, []string{"Idx: ", "Rest: nil", "ScopeContext: 2", "*ast.ImportDeclaration", "Imported: *ast.Identifier"}, nil},
+		{ast.HidePositions, []string{"Rest: nil", "ScopeContext: 2"}, []string{"Idx: ", "LeftBrace: "}},
+		{ast.HideNil, []string{"Idx: ", "Token: let"}, []string{": nil", "Rest: "}},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := ast.Fprint(&b, p, tt.mode); err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(b.String(), s) {
+				t.Errorf("Fprint with mode %d does not print %q", tt.mode, s)
+			}
+		}
+		for _, s := range tt.notWant {
+			if strings.Contains(b.String(), s) {
+				t.Errorf("Fprint with mode %d prints %q", tt.mode, s)
+			}
+		}
+	}
+}
+
+func TestFprintCycle(t *testing.T) {
+	e := &ast.Expression{}
+	e.Expr = &ast.UnaryExpression{Operator: token.Not, Operand: e}
+	var b strings.Builder
+	if err := ast.Fprint(&b, e, ast.HidePositions); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Operand: *ast.Expression (cycle)") {
+		t.Errorf("Fprint of a cycle gives\n%s", b.String())
+	}
+}
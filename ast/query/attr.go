@@ -0,0 +1,119 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/t14raptor/go-fast/ast"
+)
+
+// resolve follows a path of field names from n. Names match Go field names
+// without regard to case, wrappers like ast.Expression are looked through,
+// and slices have a length and numbered elements. It returns a float64,
+// string or bool for scalar fields and the value itself otherwise, and false
+// when the path does not lead to a non-nil value.
+func resolve(n ast.VisitableNode, path []string) (any, bool) {
+	v := unwrap(reflect.ValueOf(n))
+	for _, name := range path {
+		if !v.IsValid() {
+			return nil, false
+		}
+		switch v.Kind() {
+		case reflect.Slice:
+			if name == "length" {
+				v = reflect.ValueOf(v.Len())
+				continue
+			}
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= v.Len() {
+				return nil, false
+			}
+			v = v.Index(i)
+		case reflect.Struct:
+			f, ok := fieldByName(v.Type(), name)
+			if !ok {
+				return nil, false
+			}
+			v = v.Field(f)
+		default:
+			return nil, false
+		}
+		v = unwrap(v)
+	}
+	if !v.IsValid() {
+		return nil, false
+	}
+	return scalar(v), true
+}
+
+// unwrap dereferences pointers and interfaces and looks through wrapper
+// nodes. It returns the zero Value for nil.
+func unwrap(v reflect.Value) reflect.Value {
+	for {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		case reflect.Struct:
+			if shapeOfType(v.Type()) != shapeWrapper {
+				return v
+			}
+			v = v.Field(0)
+		default:
+			return v
+		}
+	}
+}
+
+func fieldByName(t reflect.Type, name string) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.IsExported() && strings.EqualFold(f.Name, name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+func scalar(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.String:
+		// Compare strings and names without the mark of non-ASCII ones.
+		return ast.StringValue(v.String())
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Operators and other tokens compare by their text.
+		if v.Type().Implements(stringerType) {
+			return v.Interface().(fmt.Stringer).String()
+		}
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	return v.Interface()
+}
+
+// text returns the text a string or regular expression value is compared
+// with. Nodes have none.
+func text(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	}
+	return "", false
+}
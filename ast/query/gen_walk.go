@@ -0,0 +1,67 @@
+//go:build ignore
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+)
+
+// Generates walk.go from the Visitor interface in ast/visit.go. Run it from
+// the repository root.
+
+func main() {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "./ast/visit.go", nil, 0)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	var names []string
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || typeSpec.Name.Name != "Visitor" {
+				continue
+			}
+			for _, method := range typeSpec.Type.(*ast.InterfaceType).Methods.List {
+				names = append(names, strings.TrimPrefix(method.Names[0].Name, "Visit"))
+			}
+		}
+	}
+	if len(names) == 0 {
+		log.Fatal("no Visitor interface in ast/visit.go")
+	}
+
+	s := bytes.NewBufferString("// Code generated by gen_walk.go; DO NOT EDIT.\n\n")
+	s.WriteString("package query\n\n")
+	s.WriteString("import \"github.com/t14raptor/go-fast/ast\"\n\n")
+
+	s.WriteString("// nodeTypes holds the names of the node types a selector may name.\n")
+	s.WriteString("var nodeTypes = map[string]bool{\n")
+	for _, name := range names {
+		fmt.Fprintf(s, "%q: true,\n", name)
+	}
+	s.WriteString("}\n\n")
+
+	for _, name := range names {
+		fmt.Fprintf(s, "func (w *walker) Visit%s(n *ast.%s) {\nw.visit(n)\n}\n", name, name)
+	}
+
+	out, err := format.Source(s.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, s.Bytes())
+	}
+	os.WriteFile("ast/query/walk.go", out, 0644)
+}
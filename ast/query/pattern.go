@@ -0,0 +1,254 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/parser"
+)
+
+// Pattern is a compiled pattern. It is safe for concurrent use.
+type Pattern struct {
+	src  string
+	root reflect.Value // the pattern node, as an ast.Expr or ast.Stmt
+	stmt bool
+}
+
+// Match is a node matched by a pattern, with the nodes its wildcards
+// captured by name, without the leading $.
+type Match struct {
+	Node     ast.VisitableNode
+	Captures map[string]ast.VisitableNode
+}
+
+// The wrapper allows return, yield and await in patterns.
+const (
+	wrapPrefix = "async function* query() {\n"
+	wrapSuffix = "\n}"
+)
+
+// CompilePattern parses a pattern. src is an expression, or else a single
+// statement.
+func CompilePattern(src string) (*Pattern, error) {
+	p := &Pattern{src: src}
+	if stmts, err := parseBody("return (" + src + "\n);"); err == nil && len(stmts) == 1 {
+		if ret, ok := stmts[0].Stmt.(*ast.ReturnStatement); ok && ret.Argument != nil {
+			p.root = reflect.ValueOf(&ret.Argument.Expr).Elem()
+			return p, nil
+		}
+	}
+
+	stmts, err := parseBody(src)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	if len(stmts) != 1 {
+		return nil, fmt.Errorf("query: %q is not an expression or a single statement", src)
+	}
+	p.root = reflect.ValueOf(&stmts[0].Stmt).Elem()
+	p.stmt = true
+	return p, nil
+}
+
+// MustCompilePattern is like CompilePattern, but panics on error.
+func MustCompilePattern(src string) *Pattern {
+	p, err := CompilePattern(src)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func parseBody(src string) (ast.Statements, error) {
+	program, err := parser.ParseFile(wrapPrefix + src + wrapSuffix)
+	if err != nil {
+		return nil, err
+	}
+	return program.Body[0].Stmt.(*ast.FunctionDeclaration).Function.Body.List, nil
+}
+
+// String returns the source of p.
+func (p *Pattern) String() string {
+	return p.src
+}
+
+// Match reports whether n matches p, and what the wildcards captured.
+func (p *Pattern) Match(n ast.VisitableNode) (Match, bool) {
+	m := &matcher{captures: map[string]ast.VisitableNode{}}
+	var ok bool
+	if p.stmt {
+		s, isStmt := n.(ast.Stmt)
+		ok = isStmt && m.match(p.root, reflect.ValueOf(&s).Elem())
+	} else {
+		e, isExpr := n.(ast.Expr)
+		ok = isExpr && m.match(p.root, reflect.ValueOf(&e).Elem())
+	}
+	if !ok {
+		return Match{}, false
+	}
+	return Match{Node: n, Captures: m.captures}, true
+}
+
+// FindAll returns the matches of p under root, root included, in document
+// order.
+func (p *Pattern) FindAll(root ast.VisitableNode) []Match {
+	var matches []Match
+	for _, e := range build(root).all {
+		if m, ok := p.Match(e.node); ok {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// wildcard returns the name of the wildcard $name or $$name, and whether it
+// matches a run of list elements.
+func wildcard(name string) (string, bool, bool) {
+	switch {
+	case strings.HasPrefix(name, "$$") && len(name) > 2:
+		return name[2:], true, true
+	case strings.HasPrefix(name, "$") && len(name) > 1:
+		return name[1:], false, true
+	}
+	return "", false, false
+}
+
+var (
+	idxType          = reflect.TypeOf(ast.Idx(0))
+	scopeContextType = reflect.TypeOf(ast.ScopeContext(0))
+)
+
+// wildcardOf returns the name of the wildcard the pattern value p stands
+// for, if any. An identifier is one in any position, an unquoted property key
+// is one, and so is an expression statement of a lone wildcard.
+func wildcardOf(p reflect.Value) (string, bool, bool) {
+	if p.Kind() == reflect.Interface {
+		if p.IsNil() {
+			return "", false, false
+		}
+		p = p.Elem()
+	}
+	switch n := p.Interface().(type) {
+	case *ast.Identifier:
+		if n != nil {
+			return wildcard(n.Name)
+		}
+	case *ast.StringLiteral:
+		if n != nil && n.Raw != nil && *n.Raw == n.Value {
+			return wildcard(n.Value)
+		}
+	case *ast.ExpressionStatement:
+		if n != nil && n.Expression != nil {
+			return wildcardOf(reflect.ValueOf(n.Expression.Expr))
+		}
+	case ast.Expression:
+		return wildcardOf(reflect.ValueOf(n.Expr))
+	case ast.Statement:
+		return wildcardOf(reflect.ValueOf(n.Stmt))
+	}
+	return "", false, false
+}
+
+type matcher struct {
+	captures map[string]ast.VisitableNode
+}
+
+// capture binds name to n. A name seen before must capture an equal node.
+func (m *matcher) capture(name string, n ast.VisitableNode) bool {
+	if name == "_" {
+		return true
+	}
+	if prev, ok := m.captures[name]; ok {
+		return ast.EqualWithOptions(prev, n, ast.CompareOptions{IgnoreScopeContext: true})
+	}
+	m.captures[name] = n
+	return true
+}
+
+// node returns the node held by the interface or pointer v.
+func node(v reflect.Value) (ast.VisitableNode, bool) {
+	if v.IsNil() {
+		return nil, false
+	}
+	n, ok := v.Interface().(ast.VisitableNode)
+	return n, ok
+}
+
+func (m *matcher) match(p, c reflect.Value) bool {
+	if k := p.Kind(); k == reflect.Interface || k == reflect.Pointer {
+		if name, list, ok := wildcardOf(p); ok && !list {
+			n, ok := node(c)
+			return ok && m.capture(name, n)
+		}
+	}
+
+	switch p.Kind() {
+	case reflect.Interface:
+		if p.IsNil() || c.IsNil() {
+			return p.IsNil() && c.IsNil()
+		}
+		if p.Elem().Type() != c.Elem().Type() {
+			return false
+		}
+		return m.match(p.Elem(), c.Elem())
+
+	case reflect.Pointer:
+		if p.IsNil() || c.IsNil() {
+			return p.IsNil() && c.IsNil()
+		}
+		return m.match(p.Elem(), c.Elem())
+
+	case reflect.Struct:
+		t := p.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			switch {
+			case f.Type == idxType, f.Type == scopeContextType:
+				continue
+			case f.Name == "Raw" || f.Name == "Comment":
+				// Source text and comments are not part of the shape.
+				continue
+			}
+			if !m.match(p.Field(i), c.Field(i)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Slice:
+		return m.matchList(p, 0, c, 0)
+
+	case reflect.Float32, reflect.Float64:
+		return p.Float() == c.Float()
+	}
+	return p.Interface() == c.Interface()
+}
+
+// matchList matches the elements of slices from i and j on. A $$name element
+// matches any run of elements, shortest first.
+func (m *matcher) matchList(p reflect.Value, i int, c reflect.Value, j int) bool {
+	if i == p.Len() {
+		return j == c.Len()
+	}
+	name, list, ok := wildcardOf(p.Index(i))
+	if !ok || !list {
+		return j < c.Len() && m.match(p.Index(i), c.Index(j)) && m.matchList(p, i+1, c, j+1)
+	}
+
+	for end := j; end <= c.Len(); end++ {
+		saved := make(map[string]ast.VisitableNode, len(m.captures))
+		for k, v := range m.captures {
+			saved[k] = v
+		}
+		run := reflect.New(c.Type())
+		run.Elem().Set(c.Slice(j, end))
+		n, isNode := run.Interface().(ast.VisitableNode)
+		if isNode && m.capture(name, n) && m.matchList(p, i+1, c, end) {
+			return true
+		}
+		m.captures = saved
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+// Package query finds nodes in a syntax tree, either with esquery style
+// selectors or with patterns written as JavaScript.
+//
+// Selectors name the node types of package ast:
+//
+//	CallExpression[callee.name="_0x1a2b"] > Literal
+//
+// They support the type and * selectors, attributes ([attr], [attr="s"],
+// [attr=/re/i], [attr>=2] and !=), the combinators ' ', >, + and ~, selector
+// lists, and the pseudo-classes :not, :matches, :is, :has, :first-child,
+// :last-child, :nth-child(n), :nth-last-child(n), :scope, :expression,
+// :statement, :pattern, :function and :declaration. The type Literal stands
+// for the string, number, boolean, null and regular expression literals.
+//
+// Selectors see the tree without the wrapper nodes ast.Expression,
+// ast.Statement and their kind, and without slice nodes like ast.Statements,
+// whose elements are siblings instead. Attribute names are the Go field names
+// in any case, looked up through wrappers, so callee.name is the Name of the
+// identifier in the Callee of a call. Slices have a length and numbered
+// elements, and tokens compare by their text, as in [operator="+"].
+//
+// Patterns are an expression or a single statement in which $name is a
+// wildcard that matches any node in its position and captures it, and $_
+// matches without capturing. A wildcard used twice must match equal nodes. In
+// argument lists, array literals and statement lists, $$name matches a run of
+// elements, and captures it as an ast.Expressions or ast.Statements:
+//
+//	query.MustCompilePattern("$obj.push($$items)")
+//
+// Both run over the tree with the visitor of package ast.
+package query
+
+import "github.com/t14raptor/go-fast/ast"
+
+// Select returns the nodes under root that match the selector src.
+func Select(root ast.VisitableNode, src string) ([]ast.VisitableNode, error) {
+	s, err := Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	return s.Select(root), nil
+}
+
+// FindAll returns the matches of the pattern src under root.
+func FindAll(root ast.VisitableNode, src string) ([]Match, error) {
+	p, err := CompilePattern(src)
+	if err != nil {
+		return nil, err
+	}
+	return p.FindAll(root), nil
+}
@@ -0,0 +1,109 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/query"
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/parser"
+)
+
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile(%q): %v", src, err)
+	}
+	return p
+}
+
+func TestSelect(t *testing.T) {
+	const src = `
+		_0x1a2b(1, "é");
+		var a = [1, 2, 3], é = "x";
+		function f(x) { return x + 1; }
+		if (a) { g(); h(); }
+	`
+	tests := []struct {
+		sel  string
+		want int
+	}{
+		{"CallExpression", 3},
+		{`CallExpression[callee.name="_0x1a2b"] > Literal`, 2},
+		{`StringLiteral[value="é"]`, 1},
+		{`StringLiteral[value=/^é$/]`, 1},
+		{`Identifier[name="é"]`, 1},
+		{`ArrayLiteral[value.length=3]`, 1},
+		{`ArrayLiteral > NumberLiteral[value>=2]`, 2},
+		{`BinaryExpression[operator="+"]`, 1},
+		{`Literal`, 7},
+		{`FunctionDeclaration Identifier`, 3},
+		{`BlockStatement > ExpressionStatement:first-child`, 1},
+		{`ExpressionStatement + ExpressionStatement`, 1},
+		{`VariableDeclaration ~ FunctionDeclaration`, 1},
+		{`FunctionDeclaration:has(ReturnStatement)`, 1},
+		{`CallExpression:not([argumentList.length=0])`, 1},
+		{`IfStatement, ReturnStatement`, 2},
+		{`[name="nope"]`, 0},
+	}
+	p := parse(t, src)
+	for _, tt := range tests {
+		got, err := query.Select(p, tt.sel)
+		if err != nil {
+			t.Errorf("Select(%q): %v", tt.sel, err)
+			continue
+		}
+		if len(got) != tt.want {
+			t.Errorf("Select(%q) matched %d nodes, want %d", tt.sel, len(got), tt.want)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	for _, sel := range []string{"", "[", `[name="a]`, ":nope", "A >"} {
+		if _, err := query.Compile(sel); err == nil {
+			t.Errorf("Compile(%q) succeeded", sel)
+		}
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	const src = `a.push(1, 2); b.push(); a.push(a); x = y + y; x = y + z;`
+	tests := []struct {
+		pattern string
+		want    []string // the generated capture of the first wildcard
+	}{
+		{"$obj.push($$items)", []string{"a", "b", "a"}},
+		{"$obj.push($x)", []string{"a"}},
+		{"$x.push($x)", []string{"a"}},
+		{"x = $y + $y", []string{"y"}},
+		{"$_ = $v + z", []string{"y"}},
+	}
+	p := parse(t, src)
+	for _, tt := range tests {
+		matches, err := query.FindAll(p, tt.pattern)
+		if err != nil {
+			t.Errorf("FindAll(%q): %v", tt.pattern, err)
+			continue
+		}
+		if len(matches) != len(tt.want) {
+			t.Errorf("FindAll(%q) found %d matches, want %d", tt.pattern, len(matches), len(tt.want))
+			continue
+		}
+		for i, m := range matches {
+			var name string
+			for k := range m.Captures {
+				if k != "items" {
+					name = k
+				}
+			}
+			if name == "" {
+				continue
+			}
+			if got := generator.Generate(m.Captures[name]); got != tt.want[i] {
+				t.Errorf("FindAll(%q)[%d] captured $%s = %q, want %q", tt.pattern, i, name, got, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,548 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/t14raptor/go-fast/ast"
+)
+
+// Selector is a compiled selector. It is safe for concurrent use.
+type Selector struct {
+	src  string
+	alts []*complexSel
+}
+
+// complexSel is a chain of compound selectors, matched right to left.
+type complexSel struct {
+	parts []compound
+	// combs[i] joins parts[i] and parts[i+1]: ' ', '>', '+' or '~'.
+	combs []byte
+}
+
+// compound is a list of tests one node has to pass.
+type compound []test
+
+// test checks a single node. scope is the node a :has selector runs from,
+// and is nil at the top level.
+type test func(e, scope *entry) bool
+
+// Compile parses a selector.
+func Compile(src string) (*Selector, error) {
+	p := &selParser{src: src}
+	alts, err := p.parseList(false)
+	if err == nil && p.pos < len(p.src) {
+		err = p.errorf("unexpected %q", p.src[p.pos])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Selector{src: src, alts: alts}, nil
+}
+
+// MustCompile is like Compile, but panics on error.
+func MustCompile(src string) *Selector {
+	s, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String returns the source of s.
+func (s *Selector) String() string {
+	return s.src
+}
+
+// Select returns the nodes under root, root included, that match s, in
+// document order.
+func (s *Selector) Select(root ast.VisitableNode) []ast.VisitableNode {
+	var nodes []ast.VisitableNode
+	for _, e := range build(root).all {
+		if matchList(e, s.alts, nil) {
+			nodes = append(nodes, e.node)
+		}
+	}
+	return nodes
+}
+
+func matchList(e *entry, alts []*complexSel, scope *entry) bool {
+	for _, c := range alts {
+		if c.match(e, len(c.parts)-1, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *complexSel) match(e *entry, i int, scope *entry) bool {
+	for _, t := range c.parts[i] {
+		if !t(e, scope) {
+			return false
+		}
+	}
+	if i == 0 {
+		return true
+	}
+	if e == scope {
+		// Nothing outside the scope takes part in a relative selector.
+		return false
+	}
+
+	switch c.combs[i-1] {
+	case ' ':
+		for a := e.parent; a != nil; a = a.parent {
+			if c.match(a, i-1, scope) {
+				return true
+			}
+			if a == scope {
+				break
+			}
+		}
+	case '>':
+		return e.parent != nil && c.match(e.parent, i-1, scope)
+	case '+':
+		return e.list != nil && e.index > 0 && c.match(e.list.entries[e.index-1], i-1, scope)
+	case '~':
+		if e.list != nil {
+			for _, sib := range e.list.entries[:e.index] {
+				if c.match(sib, i-1, scope) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+type selParser struct {
+	src string
+	pos int
+}
+
+func (p *selParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("query: %s at offset %d in %q", fmt.Sprintf(format, args...), p.pos, p.src)
+}
+
+func (p *selParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *selParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selParser) expect(c byte) error {
+	p.skipSpace()
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c == '$' || c == '-' ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func (p *selParser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) && isNameByte(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// parseList parses selectors separated by commas. Relative selectors, as in
+// :has, may start with a combinator and are anchored at the scope node.
+func (p *selParser) parseList(relative bool) ([]*complexSel, error) {
+	var alts []*complexSel
+	for {
+		c, err := p.parseComplex(relative)
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, c)
+		p.skipSpace()
+		if p.peek() != ',' {
+			return alts, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *selParser) parseComplex(relative bool) (*complexSel, error) {
+	c := &complexSel{}
+	p.skipSpace()
+	if relative {
+		comb := byte(' ')
+		if b := p.peek(); b == '>' || b == '+' || b == '~' {
+			comb = b
+			p.pos++
+			p.skipSpace()
+		}
+		c.parts = append(c.parts, compound{isScope})
+		c.combs = append(c.combs, comb)
+	}
+
+	for {
+		part, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		c.parts = append(c.parts, part)
+
+		space := p.skipSpace()
+		b := p.peek()
+		switch {
+		case b == 0 || b == ',' || b == ')':
+			return c, nil
+		case b == '>' || b == '+' || b == '~':
+			p.pos++
+			p.skipSpace()
+			c.combs = append(c.combs, b)
+		case space:
+			c.combs = append(c.combs, ' ')
+		default:
+			return nil, p.errorf("unexpected %q", b)
+		}
+	}
+}
+
+func (p *selParser) parseCompound() (compound, error) {
+	var part compound
+	if p.peek() == '*' {
+		p.pos++
+		part = append(part, func(*entry, *entry) bool { return true })
+	} else if isNameByte(p.peek()) {
+		t, err := p.typeTest(p.name())
+		if err != nil {
+			return nil, err
+		}
+		part = append(part, t)
+	}
+
+	for {
+		var (
+			t   test
+			err error
+		)
+		switch p.peek() {
+		case '[':
+			p.pos++
+			t, err = p.parseAttr()
+		case ':':
+			p.pos++
+			t, err = p.parsePseudo()
+		default:
+			if len(part) == 0 {
+				return nil, p.errorf("expected a selector")
+			}
+			return part, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		part = append(part, t)
+	}
+}
+
+// literalTypes are the nodes the Literal type selector stands for.
+var literalTypes = map[string]bool{
+	"BooleanLiteral": true,
+	"NullLiteral":    true,
+	"NumberLiteral":  true,
+	"RegExpLiteral":  true,
+	"StringLiteral":  true,
+}
+
+func (p *selParser) typeTest(name string) (test, error) {
+	if name == "Literal" {
+		return func(e, _ *entry) bool { return literalTypes[typeName(e.node)] }, nil
+	}
+	if !nodeTypes[name] {
+		return nil, p.errorf("unknown node type %q", name)
+	}
+	return func(e, _ *entry) bool { return typeName(e.node) == name }, nil
+}
+
+func (p *selParser) parsePseudo() (test, error) {
+	name := strings.ToLower(p.name())
+	switch name {
+	case "not", "matches", "is", "has":
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		alts, err := p.parseList(name == "has")
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		switch name {
+		case "not":
+			return func(e, scope *entry) bool { return !matchList(e, alts, scope) }, nil
+		case "has":
+			return func(e, _ *entry) bool { return hasMatch(e, e, alts) }, nil
+		}
+		return func(e, scope *entry) bool { return matchList(e, alts, scope) }, nil
+
+	case "nth-child", "nth-last-child":
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		n, err := strconv.Atoi(p.name())
+		if err != nil || n < 1 {
+			return nil, p.errorf("expected a positive index")
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		if name == "nth-child" {
+			return nthChild(n, false), nil
+		}
+		return nthChild(n, true), nil
+
+	case "first-child":
+		return nthChild(1, false), nil
+	case "last-child":
+		return nthChild(1, true), nil
+	case "scope":
+		return isScope, nil
+
+	case "expression":
+		return func(e, _ *entry) bool { _, ok := e.node.(ast.Expr); return ok }, nil
+	case "statement":
+		return func(e, _ *entry) bool { _, ok := e.node.(ast.Stmt); return ok }, nil
+	case "pattern":
+		return func(e, _ *entry) bool { _, ok := e.node.(ast.Pattern); return ok }, nil
+	case "function":
+		return func(e, _ *entry) bool {
+			switch e.node.(type) {
+			case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral, *ast.FunctionDeclaration:
+				return true
+			}
+			return false
+		}, nil
+	case "declaration":
+		return func(e, _ *entry) bool {
+			switch e.node.(type) {
+			case *ast.VariableDeclaration, *ast.FunctionDeclaration, *ast.ClassDeclaration,
+				*ast.ImportDeclaration, *ast.ExportDeclaration:
+				return true
+			}
+			return false
+		}, nil
+	}
+	return nil, p.errorf("unknown pseudo-class %q", name)
+}
+
+// isScope matches the node a relative selector runs from, or a root at the
+// top level.
+func isScope(e, scope *entry) bool {
+	if scope == nil {
+		return e.parent == nil
+	}
+	return e == scope
+}
+
+func nthChild(n int, last bool) test {
+	return func(e, _ *entry) bool {
+		if e.list == nil {
+			return false
+		}
+		if last {
+			return len(e.list.entries)-e.index == n
+		}
+		return e.index+1 == n
+	}
+}
+
+func hasMatch(e, scope *entry, alts []*complexSel) bool {
+	for _, child := range e.children {
+		if matchList(child, alts, scope) || hasMatch(child, scope, alts) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *selParser) parseAttr() (test, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && (isNameByte(p.src[p.pos]) || p.src[p.pos] == '.') {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, p.errorf("expected an attribute name")
+	}
+	path := strings.Split(p.src[start:p.pos], ".")
+
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		return func(e, _ *entry) bool {
+			_, ok := resolve(e.node, path)
+			return ok
+		}, nil
+	}
+
+	var op string
+	for _, candidate := range []string{"!=", "<=", ">=", "=", "<", ">"} {
+		if strings.HasPrefix(p.src[p.pos:], candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expected an operator")
+	}
+	p.pos += len(op)
+	p.skipSpace()
+
+	cmp, err := p.parseValue(op)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(']'); err != nil {
+		return nil, err
+	}
+	return func(e, _ *entry) bool {
+		v, ok := resolve(e.node, path)
+		return ok && cmp(v)
+	}, nil
+}
+
+// parseValue parses the value of an attribute selector, and returns the
+// comparison with a resolved attribute.
+func (p *selParser) parseValue(op string) (func(v any) bool, error) {
+	equality := op == "=" || op == "!="
+	negate := op == "!="
+
+	switch b := p.peek(); {
+	case b == '"' || b == '\'':
+		if !equality {
+			return nil, p.errorf("%s needs a number", op)
+		}
+		s, err := p.parseString(b)
+		if err != nil {
+			return nil, err
+		}
+		return func(v any) bool {
+			t, ok := text(v)
+			return (ok && t == s) != negate
+		}, nil
+
+	case b == '/':
+		if !equality {
+			return nil, p.errorf("%s needs a number", op)
+		}
+		re, err := p.parseRegexp()
+		if err != nil {
+			return nil, err
+		}
+		return func(v any) bool {
+			t, ok := text(v)
+			return (ok && re.MatchString(t)) != negate
+		}, nil
+	}
+
+	var word string
+	if b := p.peek(); b == '-' || b == '.' || '0' <= b && b <= '9' {
+		start := p.pos
+		for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		word = p.src[start:p.pos]
+	} else {
+		word = p.name()
+	}
+	if word == "" {
+		return nil, p.errorf("expected a value")
+	}
+	if n, err := strconv.ParseFloat(word, 64); err == nil {
+		return func(v any) bool {
+			f, ok := v.(float64)
+			if !ok {
+				return false
+			}
+			switch op {
+			case "=":
+				return f == n
+			case "!=":
+				return f != n
+			case "<":
+				return f < n
+			case ">":
+				return f > n
+			case "<=":
+				return f <= n
+			}
+			return f >= n
+		}, nil
+	}
+	if !equality {
+		return nil, p.errorf("%s needs a number", op)
+	}
+	return func(v any) bool {
+		t, ok := text(v)
+		return (ok && t == word) != negate
+	}, nil
+}
+
+func (p *selParser) parseString(quote byte) (string, error) {
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.pos < len(p.src) {
+				b.WriteByte(p.src[p.pos])
+				p.pos++
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *selParser) parseRegexp() (*regexp.Regexp, error) {
+	p.pos++
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != '/' {
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unterminated regular expression")
+	}
+	pattern := p.src[start:p.pos]
+	p.pos++
+	if flags := p.name(); flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	return re, nil
+}
@@ -0,0 +1,107 @@
+package query
+
+//go:generate go run -C ../.. ast/query/gen_walk.go
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/t14raptor/go-fast/ast"
+)
+
+// entry is a node of the tree selectors run on. It leaves out the wrappers
+// around interfaces, such as ast.Expression and ast.Statement, and the slice
+// nodes, such as ast.Statements, whose elements become siblings.
+type entry struct {
+	node     ast.VisitableNode
+	parent   *entry
+	children []*entry
+
+	// list holds the siblings of the entry when it is an element of a
+	// slice node, at index.
+	list  *list
+	index int
+}
+
+type list struct {
+	entries []*entry
+}
+
+// walker builds the entries of a tree. Its methods are in walk.go.
+type walker struct {
+	roots  []*entry
+	all    []*entry // in document order
+	parent *entry
+	list   *list
+}
+
+func build(root ast.VisitableNode) *walker {
+	w := &walker{}
+	root.VisitWith(w)
+	return w
+}
+
+func (w *walker) visit(n ast.VisitableNode) {
+	switch shapeOf(n) {
+	case shapeList:
+		outer := w.list
+		w.list = &list{}
+		n.VisitChildrenWith(w)
+		w.list = outer
+	case shapeWrapper:
+		n.VisitChildrenWith(w)
+	default:
+		e := &entry{node: n, parent: w.parent}
+		if w.parent != nil {
+			w.parent.children = append(w.parent.children, e)
+		} else {
+			w.roots = append(w.roots, e)
+		}
+		if w.list != nil {
+			e.list, e.index = w.list, len(w.list.entries)
+			w.list.entries = append(w.list.entries, e)
+		}
+		w.all = append(w.all, e)
+
+		parent, outer := w.parent, w.list
+		w.parent, w.list = e, nil
+		n.VisitChildrenWith(w)
+		w.parent, w.list = parent, outer
+	}
+}
+
+type shape int
+
+const (
+	shapeNode shape = iota
+	shapeWrapper
+	shapeList
+)
+
+var shapes sync.Map // reflect.Type -> shape
+
+func shapeOf(n ast.VisitableNode) shape {
+	return shapeOfType(reflect.TypeOf(n).Elem())
+}
+
+// shapeOfType tells slices and structs holding a single interface apart from
+// other nodes.
+func shapeOfType(t reflect.Type) shape {
+	if s, ok := shapes.Load(t); ok {
+		return s.(shape)
+	}
+	s := shapeNode
+	switch {
+	case t.Kind() == reflect.Slice:
+		s = shapeList
+	case t.Kind() == reflect.Struct && t.NumField() == 1 && t.Field(0).Type.Kind() == reflect.Interface:
+		s = shapeWrapper
+	}
+	shapes.Store(t, s)
+	return s
+}
+
+// typeName returns the name of the node type of n, like "CallExpression".
+func typeName(n ast.VisitableNode) string {
+	return reflect.TypeOf(n).Elem().Name()
+}
@@ -0,0 +1,351 @@
+// Package quote builds AST nodes from JavaScript source templates.
+//
+// A template is ordinary JavaScript in which identifiers of the form $name are
+// placeholders. The position of a placeholder decides what it may be bound to:
+//
+//   - In an expression position, $name takes an ast.Expr, an *ast.Expression
+//     or a string, which becomes an identifier. In an argument list, array
+//     literal or sequence, it may also take ast.Expressions or []ast.Expr,
+//     which are spliced in place.
+//   - As a statement of its own (`$name;`), it takes an ast.Stmt, an
+//     *ast.Statement, ast.Statements, []ast.Stmt, or an ast.Expr that becomes
+//     an expression statement. Lists are spliced in place.
+//   - In an identifier position, such as a declared name, a parameter, a
+//     label, a property key or the name after a dot, it takes a string or an
+//     *ast.Identifier.
+//
+// Write $$name for a literal identifier $name.
+//
+// Templates are parsed once and cached. Every instantiation returns a fresh
+// tree, and bound nodes are cloned at every use, so neither the template nor
+// the bindings are shared with the result.
+//
+//	call, err := quote.Expr("$callee.apply($this, $args)", quote.Bindings{
+//		"callee": fn,
+//		"this":   build.This(),
+//		"args":   build.Ident("arguments"),
+//	})
+package quote
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/parser"
+)
+
+// Bindings maps placeholder names, without the leading $, to the values
+// substituted for them.
+type Bindings map[string]any
+
+// Kind is the set of positions a placeholder appears in.
+type Kind uint8
+
+const (
+	// KindExpr is an expression position.
+	KindExpr Kind = 1 << iota
+	// KindStmts is a statement of its own, which may expand to a list.
+	KindStmts
+	// KindIdent is a position that only holds an identifier.
+	KindIdent
+)
+
+func (k Kind) String() string {
+	var names []string
+	if k&KindExpr != 0 {
+		names = append(names, "expression")
+	}
+	if k&KindStmts != 0 {
+		names = append(names, "statement list")
+	}
+	if k&KindIdent != 0 {
+		names = append(names, "identifier")
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, " and ")
+}
+
+// Template is a compiled template. It is safe for concurrent use.
+type Template struct {
+	src  string
+	expr *ast.Expression // expression templates
+	body ast.Statements  // statement templates
+
+	kinds map[string]Kind
+	// spliced holds the names whose expression uses are all in list positions.
+	spliced map[string]bool
+}
+
+type cacheKey struct {
+	expr bool
+	src  string
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[cacheKey]*Template{}
+)
+
+// The wrapper allows return, yield and await in templates.
+const (
+	wrapPrefix = "async function* quote() {\n"
+	wrapSuffix = "\n}"
+)
+
+// Compile returns the template for a list of statements.
+func Compile(src string) (*Template, error) {
+	return compile(src, false)
+}
+
+// CompileExpr returns the template for a single expression.
+func CompileExpr(src string) (*Template, error) {
+	return compile(src, true)
+}
+
+func compile(src string, expr bool) (*Template, error) {
+	key := cacheKey{expr: expr, src: src}
+	cacheMu.Lock()
+	t, ok := cache[key]
+	cacheMu.Unlock()
+	if ok {
+		return t, nil
+	}
+
+	wrapped := src
+	if expr {
+		wrapped = "return (" + src + "\n);"
+	}
+	program, err := parser.ParseFile(wrapPrefix + wrapped + wrapSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("quote: %w", err)
+	}
+	fn := program.Body[0].Stmt.(*ast.FunctionDeclaration).Function
+
+	t = &Template{
+		src:     src,
+		kinds:   map[string]Kind{},
+		spliced: map[string]bool{},
+	}
+	if expr {
+		if len(fn.Body.List) != 1 {
+			return nil, fmt.Errorf("quote: %q is not a single expression", src)
+		}
+		ret, ok := fn.Body.List[0].Stmt.(*ast.ReturnStatement)
+		if !ok || ret.Argument == nil {
+			return nil, fmt.Errorf("quote: %q is not a single expression", src)
+		}
+		t.expr = ret.Argument
+		w := &walker{t: t, record: true}
+		w.V = w
+		t.expr.VisitWith(w)
+	} else {
+		t.body = fn.Body.List
+		w := &walker{t: t, record: true}
+		w.V = w
+		t.body.VisitWith(w)
+	}
+
+	cacheMu.Lock()
+	if cached, ok := cache[key]; ok {
+		t = cached
+	} else {
+		cache[key] = t
+	}
+	cacheMu.Unlock()
+	return t, nil
+}
+
+// Placeholders returns the placeholder names of t, without the leading $,
+// and the positions each appears in.
+func (t *Template) Placeholders() map[string]Kind {
+	kinds := make(map[string]Kind, len(t.kinds))
+	for name, k := range t.kinds {
+		kinds[name] = k
+	}
+	return kinds
+}
+
+// Expr instantiates an expression template.
+func (t *Template) Expr(b Bindings) (ast.Expr, error) {
+	if t.expr == nil {
+		return nil, fmt.Errorf("quote: %q is not an expression template", t.src)
+	}
+	if err := t.check(b); err != nil {
+		return nil, err
+	}
+	e := t.expr.Clone()
+	w := &walker{t: t, b: b}
+	w.V = w
+	e.VisitWith(w)
+	return e.Expr, nil
+}
+
+// Stmts instantiates a statement template.
+func (t *Template) Stmts(b Bindings) (ast.Statements, error) {
+	if t.expr != nil {
+		return nil, fmt.Errorf("quote: %q is not a statement template", t.src)
+	}
+	if err := t.check(b); err != nil {
+		return nil, err
+	}
+	body := t.body.Clone()
+	w := &walker{t: t, b: b}
+	w.V = w
+	body.VisitWith(w)
+	return *body, nil
+}
+
+// check reports missing bindings and bindings whose type does not fit every
+// position their placeholder appears in.
+func (t *Template) check(b Bindings) error {
+	names := make([]string, 0, len(t.kinds))
+	for name := range t.kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		v, ok := b[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("quote: no binding for $%s", name))
+			continue
+		}
+		kind := t.kinds[name]
+		if kind&KindExpr != 0 && !fitsExpr(v, t.spliced[name]) ||
+			kind&KindStmts != 0 && !fitsStmts(v) ||
+			kind&KindIdent != 0 && !fitsIdent(v) {
+			errs = append(errs, fmt.Errorf("quote: $%s is used as %s, and cannot be bound to %T", name, kind, v))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func fitsExpr(v any, spliced bool) bool {
+	switch v := v.(type) {
+	case ast.Expr:
+		return v != nil
+	case *ast.Expression:
+		return v != nil && v.Expr != nil
+	case string:
+		return v != ""
+	case ast.Expressions, []ast.Expr:
+		return spliced
+	}
+	return false
+}
+
+func fitsStmts(v any) bool {
+	switch v := v.(type) {
+	case ast.Stmt:
+		return v != nil
+	case *ast.Statement:
+		return v != nil && v.Stmt != nil
+	case ast.Statements, []ast.Stmt:
+		return true
+	case ast.Expr:
+		return v != nil
+	}
+	return false
+}
+
+func fitsIdent(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return v != ""
+	case *ast.Identifier:
+		return v != nil
+	}
+	return false
+}
+
+// Expr compiles src as an expression template and instantiates it with b.
+func Expr(src string, b Bindings) (ast.Expr, error) {
+	t, err := CompileExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	return t.Expr(b)
+}
+
+// Stmts compiles src as a statement template and instantiates it with b.
+func Stmts(src string, b Bindings) (ast.Statements, error) {
+	t, err := Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	return t.Stmts(b)
+}
+
+// Stmt is like Stmts, but the instantiated template must be exactly one
+// statement.
+func Stmt(src string, b Bindings) (ast.Stmt, error) {
+	stmts, err := Stmts(src, b)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) != 1 {
+		return nil, fmt.Errorf("quote: %q gives %d statements, not one", src, len(stmts))
+	}
+	return stmts[0].Stmt, nil
+}
+
+// MustExpr is like Expr, but panics on error.
+func MustExpr(src string, b Bindings) ast.Expr {
+	e, err := Expr(src, b)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// MustStmts is like Stmts, but panics on error.
+func MustStmts(src string, b Bindings) ast.Statements {
+	stmts, err := Stmts(src, b)
+	if err != nil {
+		panic(err)
+	}
+	return stmts
+}
+
+// MustStmt is like Stmt, but panics on error.
+func MustStmt(src string, b Bindings) ast.Stmt {
+	s, err := Stmt(src, b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// placeholder returns the name of the placeholder $name.
+func placeholder(name string) (string, bool) {
+	if len(name) < 2 || name[0] != '$' || name[1] == '$' {
+		return "", false
+	}
+	return name[1:], true
+}
+
+// unescape turns $$name into $name.
+func unescape(name string) string {
+	if strings.HasPrefix(name, "$$") {
+		return name[1:]
+	}
+	return name
+}
+
+func exprPlaceholder(e *ast.Expression) (string, bool) {
+	if e == nil {
+		return "", false
+	}
+	id, ok := e.Expr.(*ast.Identifier)
+	if !ok {
+		return "", false
+	}
+	return placeholder(id.Name)
+}
@@ -0,0 +1,163 @@
+package quote_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+	"github.com/t14raptor/go-fast/ast/quote"
+	"github.com/t14raptor/go-fast/parser"
+)
+
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile(%q): %v", src, err)
+	}
+	return p
+}
+
+func TestExpr(t *testing.T) {
+	tests := []struct {
+		src  string
+		b    quote.Bindings
+		want string
+	}{
+		{"$callee.apply($this, $args)", quote.Bindings{
+			"callee": build.Path("a", "b"),
+			"this":   build.This(),
+			"args":   "arguments",
+		}, "a.b.apply(this, arguments)"},
+		{"f($args, 1)", quote.Bindings{
+			"args": []ast.Expr{build.Ident("a"), build.Ident("b")},
+		}, "f(a, b, 1)"},
+		{"[0, $xs]", quote.Bindings{"xs": ast.Expressions{}}, "[0]"},
+		{"$x.$name", quote.Bindings{"x": &ast.Expression{Expr: build.Ident("o")}, "name": "p"}, "o.p"},
+		{"{$key: $x}", quote.Bindings{"key": &ast.Identifier{Name: "k"}, "x": build.Num(1)}, "({k: 1})"},
+		{"$$x + $y", quote.Bindings{"y": build.Str("s")}, `$x + "s"`},
+		{"($x, $x)", quote.Bindings{"x": build.Call(build.Ident("g"))}, "(g(), g())"},
+	}
+	for _, tt := range tests {
+		e, err := quote.Expr(tt.src, tt.b)
+		if err != nil {
+			t.Errorf("Expr(%q): %v", tt.src, err)
+			continue
+		}
+		want := parse(t, tt.want+";")
+		if !ast.Equal(build.Program(build.ExprStmt(e)), want) {
+			t.Errorf("Expr(%q) differs from %q", tt.src, tt.want)
+		}
+	}
+}
+
+func TestStmts(t *testing.T) {
+	tests := []struct {
+		src  string
+		b    quote.Bindings
+		want string
+	}{
+		{"$pre; f();", quote.Bindings{
+			"pre": []ast.Stmt{build.Var("a", build.Num(1)), build.Var("b", nil)},
+		}, "var a = 1; var b; f();"},
+		{"$s;", quote.Bindings{"s": build.Call(build.Ident("f"))}, "f();"},
+		{"$s;", quote.Bindings{"s": ast.Statements{}}, ""},
+		{"function $name($p) { return $p; }", quote.Bindings{
+			"name": "f",
+			"p":    &ast.Identifier{Name: "x"},
+		}, "function f(x) { return x; }"},
+		{"$label: for (;;) break $label;", quote.Bindings{"label": "l"}, "l: for (;;) break l;"},
+	}
+	for _, tt := range tests {
+		stmts, err := quote.Stmts(tt.src, tt.b)
+		if err != nil {
+			t.Errorf("Stmts(%q): %v", tt.src, err)
+			continue
+		}
+		if !ast.Equal(&ast.Program{Body: stmts}, parse(t, tt.want)) {
+			t.Errorf("Stmts(%q) differs from %q", tt.src, tt.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		expr bool
+		b    quote.Bindings
+		want string
+	}{
+		{"$a + $b", true, quote.Bindings{"a": "x"}, "no binding for $b"},
+		{"f($a + 1)", true, quote.Bindings{"a": []ast.Expr{build.Ident("x")}}, "$a is used as expression"},
+		{"var $a;", false, quote.Bindings{"a": build.Num(1)}, "$a is used as identifier"},
+		{"a(", true, nil, "quote: "},
+	}
+	for _, tt := range tests {
+		var err error
+		if tt.expr {
+			_, err = quote.Expr(tt.src, tt.b)
+		} else {
+			_, err = quote.Stmts(tt.src, tt.b)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("instantiating %q: got error %v, want one containing %q", tt.src, err, tt.want)
+		}
+	}
+
+	if _, err := quote.Stmt("a; b;", nil); err == nil {
+		t.Errorf("Stmt of two statements succeeded")
+	}
+	tmpl, err := quote.Compile("a;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Expr(nil); err == nil {
+		t.Errorf("Expr of a statement template succeeded")
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	tmpl, err := quote.Compile("function $f($p) { $body; return $p + $$q; }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := tmpl.Placeholders()
+	want := map[string]quote.Kind{
+		"f":    quote.KindIdent,
+		"p":    quote.KindIdent | quote.KindExpr,
+		"body": quote.KindStmts,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Placeholders() = %v, want %v", got, want)
+	}
+	for name, k := range want {
+		if got[name] != k {
+			t.Errorf("Placeholders()[%q] = %s, want %s", name, got[name], k)
+		}
+	}
+}
+
+func TestFresh(t *testing.T) {
+	t1, err := quote.CompileExpr("$x + $x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if t2, _ := quote.CompileExpr("$x + $x"); t2 != t1 {
+		t.Errorf("CompileExpr did not cache the template")
+	}
+
+	x := build.Ident("a")
+	e1, err := t1.Expr(quote.Bindings{"x": x})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e2, _ := t1.Expr(quote.Bindings{"x": x})
+	b1, b2 := e1.(*ast.BinaryExpression), e2.(*ast.BinaryExpression)
+	if b1 == b2 {
+		t.Errorf("instantiations share their root")
+	}
+	if b1.Left.Expr == x || b1.Left.Expr == b1.Right.Expr || b1.Left.Expr == b2.Left.Expr {
+		t.Errorf("bound node is not cloned at every use")
+	}
+}
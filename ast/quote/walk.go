@@ -0,0 +1,208 @@
+package quote
+
+import (
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+)
+
+// walker finds the placeholders of a template when recording, and
+// substitutes them otherwise. Substituted nodes are never walked.
+type walker struct {
+	ast.NoopVisitor
+
+	t      *Template
+	b      Bindings
+	record bool
+
+	// plain holds the names used in an expression position that is not a list.
+	plain map[string]bool
+}
+
+func (w *walker) use(name string, kind Kind, list bool) {
+	w.t.kinds[name] |= kind
+	if kind != KindExpr {
+		return
+	}
+	if w.plain == nil {
+		w.plain = map[string]bool{}
+	}
+	if list && !w.plain[name] {
+		w.t.spliced[name] = true
+	} else {
+		w.plain[name] = true
+		delete(w.t.spliced, name)
+	}
+}
+
+func (w *walker) VisitStatements(n *ast.Statements) {
+	out := make(ast.Statements, 0, len(*n))
+	for i := range *n {
+		s := &(*n)[i]
+		name, ok := stmtPlaceholder(s)
+		switch {
+		case !ok:
+			s.VisitWith(w)
+			out = append(out, *s)
+		case w.record:
+			w.use(name, KindStmts, false)
+			out = append(out, *s)
+		default:
+			out = append(out, stmtsOf(w.b[name])...)
+		}
+	}
+	*n = out
+}
+
+func (w *walker) VisitExpressions(n *ast.Expressions) {
+	out := make(ast.Expressions, 0, len(*n))
+	for i := range *n {
+		e := &(*n)[i]
+		name, ok := exprPlaceholder(e)
+		switch {
+		case !ok:
+			e.VisitWith(w)
+			out = append(out, *e)
+		case w.record:
+			w.use(name, KindExpr, true)
+			out = append(out, *e)
+		default:
+			for _, x := range exprsOf(w.b[name]) {
+				out = append(out, ast.Expression{Expr: x})
+			}
+		}
+	}
+	*n = out
+}
+
+func (w *walker) VisitExpression(n *ast.Expression) {
+	name, ok := exprPlaceholder(n)
+	switch {
+	case !ok:
+		n.VisitChildrenWith(w)
+	case w.record:
+		w.use(name, KindExpr, false)
+	default:
+		n.Expr = exprOf(w.b[name])
+	}
+}
+
+func (w *walker) VisitIdentifier(n *ast.Identifier) {
+	name, ok := placeholder(n.Name)
+	switch {
+	case !ok:
+		if !w.record {
+			n.Name = unescape(n.Name)
+		}
+	case w.record:
+		w.use(name, KindIdent, false)
+	default:
+		switch v := w.b[name].(type) {
+		case string:
+			n.Name = v
+		case *ast.Identifier:
+			n.Name, n.ScopeContext = v.Name, v.ScopeContext
+		}
+	}
+}
+
+// VisitPropertyKeyed handles plain keys, which the parser keeps as string
+// literals.
+func (w *walker) VisitPropertyKeyed(n *ast.PropertyKeyed) {
+	key, ok := n.Key.Expr.(*ast.StringLiteral)
+	if n.Computed || !ok || key.Raw == nil || *key.Raw != key.Value {
+		n.VisitChildrenWith(w)
+		return
+	}
+
+	name, ok := placeholder(key.Value)
+	switch {
+	case !ok:
+		if value := unescape(key.Value); !w.record && value != key.Value {
+			raw := value
+			n.Key.Expr = &ast.StringLiteral{Idx: key.Idx, Value: value, Raw: &raw}
+		}
+	case w.record:
+		w.use(name, KindIdent, false)
+	default:
+		var value string
+		switch v := w.b[name].(type) {
+		case string:
+			value = v
+		case *ast.Identifier:
+			value = v.Name
+		}
+		if build.IsIdentifierName(value) {
+			raw := value
+			n.Key.Expr = &ast.StringLiteral{Idx: key.Idx, Value: value, Raw: &raw}
+		} else {
+			n.Key.Expr = build.Str(value)
+		}
+	}
+	n.Value.VisitWith(w)
+}
+
+func stmtPlaceholder(s *ast.Statement) (string, bool) {
+	es, ok := s.Stmt.(*ast.ExpressionStatement)
+	if !ok {
+		return "", false
+	}
+	return exprPlaceholder(es.Expression)
+}
+
+func cloneExpr(e ast.Expr) ast.Expr {
+	return (&ast.Expression{Expr: e}).Clone().Expr
+}
+
+func cloneStmt(s ast.Stmt) ast.Stmt {
+	return (&ast.Statement{Stmt: s}).Clone().Stmt
+}
+
+func exprOf(v any) ast.Expr {
+	switch v := v.(type) {
+	case ast.Expr:
+		return cloneExpr(v)
+	case *ast.Expression:
+		return cloneExpr(v.Expr)
+	case string:
+		return build.Ident(v)
+	}
+	return nil
+}
+
+func exprsOf(v any) []ast.Expr {
+	switch v := v.(type) {
+	case ast.Expressions:
+		list := make([]ast.Expr, len(v))
+		for i := range v {
+			list[i] = cloneExpr(v[i].Expr)
+		}
+		return list
+	case []ast.Expr:
+		list := make([]ast.Expr, len(v))
+		for i, e := range v {
+			list[i] = cloneExpr(e)
+		}
+		return list
+	}
+	return []ast.Expr{exprOf(v)}
+}
+
+func stmtsOf(v any) ast.Statements {
+	switch v := v.(type) {
+	case ast.Stmt:
+		return build.Stmts(cloneStmt(v))
+	case *ast.Statement:
+		return build.Stmts(cloneStmt(v.Stmt))
+	case ast.Statements:
+		return *v.Clone()
+	case []ast.Stmt:
+		list := make(ast.Statements, len(v))
+		for i, s := range v {
+			list[i].Stmt = cloneStmt(s)
+		}
+		return list
+	case ast.Expr:
+		return build.Stmts(build.ExprStmt(cloneExpr(v)))
+	}
+	return nil
+}
@@ -122,8 +122,8 @@ type (
 	ForStatement struct {
 		For         Idx
 		Initializer *ForLoopInitializer `optional:"true"`
-		Update      *Expression
-		Test        *Expression
+		Update      *Expression         `optional:"true"`
+		Test        *Expression         `optional:"true"`
 		Body        *Statement
 	}
 
@@ -0,0 +1,519 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Problem is a broken invariant found by Validate.
+type Problem struct {
+	// Path is the chain of fields from the validated node to the problem,
+	// such as Body[2].Stmt.Expression.Expr.Left.
+	Path string
+	// Node is the node at Path, or the node holding the field for a
+	// missing node.
+	Node    VisitableNode
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+// Validate checks the structural invariants of the tree n, which the
+// generator and the other passes rely on and the parser always keeps, and
+// returns the problems found. It is meant for trees built or changed by hand.
+//
+// It checks that
+//   - fields not tagged optional hold a node, and wrappers such as Expression
+//     are not empty there;
+//   - every node is a variant legal in its context, for example only
+//     identifiers and patterns in declarations, only assignable expressions
+//     on the left of assignments, and import and export declarations only in
+//     the body of a program;
+//   - operators, declaration kinds and property kinds suit their node.
+func Validate(n VisitableNode) []Problem {
+	v := &validator{}
+	root := reflect.ValueOf(n)
+	if root.Kind() == reflect.Pointer && root.IsNil() {
+		return nil
+	}
+	v.walk(root, "", slotAny)
+	return v.problems
+}
+
+// slot is the kind of node a field accepts.
+type slot int
+
+const (
+	slotAny slot = iota
+	slotExpr
+	slotChain        // an expression inside an optional chain
+	slotArg          // an expression or spread element
+	slotElement      // an array literal element, which may be a hole
+	slotInLeft       // the left operand of in, which may be a private name
+	slotKey          // a class element key, which may be a private name
+	slotStmt         // a statement
+	slotModuleItem   // a statement or module declaration
+	slotDeclaration  // a declaration exported by an export declaration
+	slotBinding      // an identifier or pattern being declared
+	slotBindingElem  // a binding with an optional default value
+	slotAssign       // the target of =
+	slotSimpleAssign // the target of compound assignment or update
+	slotAssignElem   // an assignment target with an optional default value
+	slotForInto      // the head of for-in and for-of
+	slotLiteralProp  // a property of an object literal
+	slotBindingProp  // a property of an object binding pattern
+	slotAssignProp   // a property of an object assignment pattern
+)
+
+var slotNames = [...]string{
+	slotAny:          "a node",
+	slotExpr:         "an expression",
+	slotChain:        "an expression",
+	slotArg:          "an expression or spread element",
+	slotElement:      "an expression or spread element",
+	slotInLeft:       "an expression or private name",
+	slotKey:          "a property name",
+	slotStmt:         "a statement",
+	slotModuleItem:   "a statement or module declaration",
+	slotDeclaration:  "a variable, function or class declaration",
+	slotBinding:      "an identifier or pattern",
+	slotBindingElem:  "an identifier or pattern with an optional default",
+	slotAssign:       "an assignment target",
+	slotSimpleAssign: "an identifier or member expression",
+	slotAssignElem:   "an assignment target with an optional default",
+	slotForInto:      "a declaration or assignment target",
+	slotLiteralProp:  "a property or spread element",
+	slotBindingProp:  "a pattern property",
+	slotAssignProp:   "a pattern property",
+}
+
+// binding reports whether nodes in the slot declare names rather than
+// assign to them.
+func (s slot) binding() bool {
+	return s == slotBinding || s == slotBindingElem || s == slotBindingProp
+}
+
+type validator struct {
+	problems []Problem
+}
+
+func (v *validator) report(path string, n VisitableNode, format string, args ...any) {
+	v.problems = append(v.problems, Problem{Path: path, Node: n, Message: fmt.Sprintf(format, args...)})
+}
+
+var (
+	expressionType = reflect.TypeOf(Expression{})
+	statementType  = reflect.TypeOf(Statement{})
+)
+
+// walk validates the value x, found at path in a field accepting s.
+func (v *validator) walk(x reflect.Value, path string, s slot) {
+	switch x.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !x.IsNil() {
+			v.walk(x.Elem(), path, s)
+		}
+	case reflect.Slice:
+		for i := 0; i < x.Len(); i++ {
+			elem := x.Index(i)
+			if wrapper(elem.Type()) && elem.Field(0).IsNil() {
+				if !v.holes(s) {
+					v.report(path+"["+strconv.Itoa(i)+"]", nil, "missing %s", slotNames[s])
+				}
+				continue
+			}
+			v.walk(elem, path+"["+strconv.Itoa(i)+"]", s)
+		}
+	case reflect.Struct:
+		if wrapper(x.Type()) {
+			// The wrapper only holds the node, which the context applies to.
+			if s == slotAny {
+				switch x.Type() {
+				case expressionType:
+					s = slotExpr
+				case statementType:
+					s = slotStmt
+				}
+			}
+			inner := x.Field(0)
+			if inner.IsNil() {
+				v.report(path, nil, "missing %s", slotNames[s])
+				return
+			}
+			v.walk(inner, join(path, x.Type().Field(0).Name), s)
+			return
+		}
+		n, _ := x.Addr().Interface().(VisitableNode)
+		if !v.allowed(n, s) {
+			v.report(path, n, "expected %s, found %T", slotNames[s], n)
+		}
+		v.check(n, path, s)
+		v.fields(x, n, path, s)
+	}
+}
+
+// holes reports whether elements of a list in slot s may be left out.
+func (v *validator) holes(s slot) bool {
+	return s == slotElement || s == slotBindingElem || s == slotAssignElem
+}
+
+// fields validates the fields of the node n, held in x, which is in slot s.
+func (v *validator) fields(x reflect.Value, n VisitableNode, path string, s slot) {
+	t := x.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, f := t.Field(i), x.Field(i)
+		switch f.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Struct:
+		default:
+			continue
+		}
+		if f.Kind() == reflect.Pointer && f.Type().Elem().Kind() != reflect.Struct {
+			// The raw text of literals.
+			continue
+		}
+
+		p := join(path, field.Name)
+		optional := field.Tag.Get("optional") == "true"
+		if (f.Kind() == reflect.Pointer || f.Kind() == reflect.Interface) && f.IsNil() {
+			if !optional {
+				v.report(p, n, "missing %s", field.Name)
+			}
+			continue
+		}
+		if f.Kind() == reflect.Pointer && wrapper(f.Type().Elem()) && f.Elem().Field(0).IsNil() {
+			if !optional {
+				v.report(p, n, "missing %s", field.Name)
+			}
+			continue
+		}
+		v.walk(f, p, childSlot(n, s, field.Name))
+	}
+}
+
+// childSlot returns what the field of n accepts, given that n is in slot s.
+func childSlot(n VisitableNode, s slot, field string) slot {
+	switch n := n.(type) {
+	case *Program:
+		return slotModuleItem
+	case *ArrayLiteral:
+		return slotElement
+	case *CallExpression:
+		if field == "Callee" && s == slotChain {
+			return slotChain
+		}
+		if field == "ArgumentList" {
+			return slotArg
+		}
+	case *NewExpression:
+		if field == "ArgumentList" {
+			return slotArg
+		}
+	case *MemberExpression:
+		if field == "Object" && s == slotChain {
+			return slotChain
+		}
+	case *PrivateDotExpression:
+		if field == "Left" && s == slotChain {
+			return slotChain
+		}
+	case *Optional:
+		return slotChain
+	case *OptionalChain:
+		return slotChain
+	case *BinaryExpression:
+		if field == "Left" && n.Operator == token.In {
+			return slotInLeft
+		}
+	case *AssignExpression:
+		if field == "Left" {
+			switch {
+			case s == slotBindingElem:
+				return slotBinding
+			case n.Operator == token.Assign:
+				return slotAssign
+			default:
+				return slotSimpleAssign
+			}
+		}
+	case *UpdateExpression:
+		return slotSimpleAssign
+	case *ArrayPattern:
+		switch {
+		case field == "Elements" && s.binding():
+			return slotBindingElem
+		case field == "Elements":
+			return slotAssignElem
+		case s.binding():
+			return slotBinding
+		default:
+			return slotAssign
+		}
+	case *ObjectPattern:
+		switch {
+		case field == "Properties" && s.binding():
+			return slotBindingProp
+		case field == "Properties":
+			return slotAssignProp
+		case s.binding():
+			return slotBinding
+		default:
+			return slotSimpleAssign
+		}
+	case *ObjectLiteral:
+		return slotLiteralProp
+	case *PropertyKeyed:
+		if field == "Value" {
+			switch s {
+			case slotBindingProp:
+				return slotBindingElem
+			case slotAssignProp:
+				return slotAssignElem
+			}
+		}
+	case *FieldDefinition:
+		if field == "Key" {
+			return slotKey
+		}
+	case *MethodDefinition:
+		if field == "Key" {
+			return slotKey
+		}
+	case *VariableDeclarator:
+		if field == "Target" {
+			return slotBinding
+		}
+	case *ParameterList:
+		if field == "Rest" {
+			return slotBinding
+		}
+	case *CatchStatement:
+		if field == "Parameter" {
+			return slotBinding
+		}
+	case *ForInStatement:
+		if field == "Into" {
+			return slotForInto
+		}
+	case *ForOfStatement:
+		if field == "Into" {
+			return slotForInto
+		}
+	case *ExportDeclaration:
+		if field == "Declaration" {
+			return slotDeclaration
+		}
+	}
+	return slotAny
+}
+
+// allowed reports whether n may be in slot s.
+func (v *validator) allowed(n VisitableNode, s slot) bool {
+	switch s {
+	case slotAny:
+		return true
+	case slotExpr:
+		return expression(n)
+	case slotChain:
+		_, ok := n.(*Optional)
+		return ok || expression(n)
+	case slotArg, slotElement:
+		_, ok := n.(*SpreadElement)
+		return ok || expression(n)
+	case slotInLeft, slotKey:
+		_, ok := n.(*PrivateIdentifier)
+		return ok || expression(n)
+	case slotStmt:
+		return statement(n)
+	case slotModuleItem:
+		switch n.(type) {
+		case *ImportDeclaration, *ExportDeclaration:
+			return true
+		}
+		return statement(n)
+	case slotDeclaration:
+		switch n.(type) {
+		case *VariableDeclaration, *FunctionDeclaration, *ClassDeclaration:
+			return true
+		}
+	case slotBinding:
+		switch n.(type) {
+		case *Identifier, *ObjectPattern, *ArrayPattern:
+			return true
+		}
+	case slotBindingElem:
+		if n, ok := n.(*AssignExpression); ok {
+			return n.Operator == token.Assign
+		}
+		return v.allowed(n, slotBinding)
+	case slotAssign:
+		switch n.(type) {
+		case *ObjectPattern, *ArrayPattern:
+			return true
+		}
+		return v.allowed(n, slotSimpleAssign)
+	case slotSimpleAssign:
+		switch n.(type) {
+		case *Identifier, *MemberExpression, *PrivateDotExpression:
+			return true
+		}
+	case slotAssignElem:
+		if n, ok := n.(*AssignExpression); ok {
+			return n.Operator == token.Assign
+		}
+		return v.allowed(n, slotAssign)
+	case slotForInto:
+		if _, ok := n.(*VariableDeclaration); ok {
+			return true
+		}
+		return v.allowed(n, slotAssign)
+	case slotLiteralProp:
+		switch n.(type) {
+		case *PropertyShort, *PropertyKeyed, *SpreadElement:
+			return true
+		}
+	case slotBindingProp, slotAssignProp:
+		switch n.(type) {
+		case *PropertyShort, *PropertyKeyed:
+			return true
+		}
+	}
+	return false
+}
+
+// expression reports whether n may be used as an expression on its own.
+func expression(n VisitableNode) bool {
+	switch n.(type) {
+	case *PropertyShort, *PropertyKeyed, *VariableDeclarator, *SpreadElement,
+		*PrivateIdentifier, *ObjectPattern, *ArrayPattern, *Optional, *InvalidExpression:
+		return false
+	}
+	_, ok := n.(Expr)
+	return ok
+}
+
+// statement reports whether n may be used as a statement on its own.
+func statement(n VisitableNode) bool {
+	switch n.(type) {
+	case *CaseStatement, *CatchStatement, *ImportDeclaration, *ExportDeclaration, *BadStatement:
+		return false
+	}
+	_, ok := n.(Stmt)
+	return ok
+}
+
+// check validates the invariants of n that do not depend on its children.
+func (v *validator) check(n VisitableNode, path string, s slot) {
+	switch n := n.(type) {
+	case *BinaryExpression:
+		if !binaryOperators[n.Operator] {
+			v.report(path, n, "invalid binary operator %s", n.Operator)
+		}
+	case *AssignExpression:
+		if n.Operator != token.Assign && !compoundOperators[n.Operator] {
+			v.report(path, n, "invalid assignment operator %s", n.Operator)
+		}
+	case *UnaryExpression:
+		switch n.Operator {
+		case token.Minus, token.Plus, token.Not, token.BitwiseNot, token.Typeof, token.Void, token.Delete:
+		default:
+			v.report(path, n, "invalid unary operator %s", n.Operator)
+		}
+	case *UpdateExpression:
+		if n.Operator != token.Increment && n.Operator != token.Decrement {
+			v.report(path, n, "invalid update operator %s", n.Operator)
+		}
+	case *SequenceExpression:
+		if len(n.Sequence) == 0 {
+			v.report(path, n, "empty sequence")
+		}
+	case *TemplateLiteral:
+		if len(n.Elements) != len(n.Expressions)+1 {
+			v.report(path, n, "%d template elements for %d expressions", len(n.Elements), len(n.Expressions))
+		}
+	case *MetaProperty:
+		if n.Meta != nil && n.Property != nil {
+			switch n.Meta.Name + "." + n.Property.Name {
+			case "new.target", "import.meta":
+			default:
+				v.report(path, n, "invalid meta property %s.%s", n.Meta.Name, n.Property.Name)
+			}
+		}
+	case *PropertyShort:
+		if s == slotLiteralProp && n.Initializer != nil && n.Initializer.Expr != nil {
+			v.report(path, n, "default value outside a pattern")
+		}
+	case *PropertyKeyed:
+		switch n.Kind {
+		case PropertyKindValue, PropertyKindMethod, PropertyKindGet, PropertyKindSet:
+		default:
+			v.report(path, n, "invalid property kind %q", n.Kind)
+		}
+		if n.Kind != PropertyKindValue && s != slotLiteralProp {
+			v.report(path, n, "%s in a pattern", n.Kind)
+		}
+	case *MethodDefinition:
+		switch n.Kind {
+		case PropertyKindMethod, PropertyKindGet, PropertyKindSet:
+		default:
+			v.report(path, n, "invalid method kind %q", n.Kind)
+		}
+	case *VariableDeclaration:
+		switch n.Token {
+		case token.Var, token.Let, token.Const:
+		default:
+			v.report(path, n, "invalid declaration kind %s", n.Token)
+		}
+		if len(n.List) == 0 {
+			v.report(path, n, "declaration without declarators")
+		}
+		if s == slotForInto && len(n.List) != 1 {
+			v.report(path, n, "%d declarators in a for-in or for-of head", len(n.List))
+		}
+	case *SwitchStatement:
+		if n.Default >= len(n.Body) || n.Default < -1 {
+			v.report(path, n, "default case %d out of range", n.Default)
+		} else if n.Default >= 0 && n.Body[n.Default].Test != nil {
+			v.report(path, n, "default case %d has a test", n.Default)
+		}
+	}
+}
+
+var binaryOperators = map[token.Token]bool{
+	token.Plus: true, token.Minus: true, token.Multiply: true, token.Exponent: true,
+	token.Slash: true, token.Remainder: true, token.And: true, token.Or: true,
+	token.ExclusiveOr: true, token.ShiftLeft: true, token.ShiftRight: true,
+	token.UnsignedShiftRight: true, token.LogicalAnd: true, token.LogicalOr: true,
+	token.Coalesce: true, token.Equal: true, token.StrictEqual: true, token.NotEqual: true,
+	token.StrictNotEqual: true, token.Less: true, token.Greater: true,
+	token.LessOrEqual: true, token.GreaterOrEqual: true, token.InstanceOf: true, token.In: true,
+}
+
+// compoundOperators are the operators of compound assignment, which are
+// stored as the binary operator they apply.
+var compoundOperators = map[token.Token]bool{
+	token.Plus: true, token.Minus: true, token.Multiply: true, token.Exponent: true,
+	token.Slash: true, token.Remainder: true, token.And: true, token.Or: true,
+	token.ExclusiveOr: true, token.ShiftLeft: true, token.ShiftRight: true,
+	token.UnsignedShiftRight: true,
+}
+
+// wrapper reports whether t is a struct only holding an interface, such as
+// Expression or BindingTarget.
+func wrapper(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.NumField() == 1 && t.Field(0).Type.Kind() == reflect.Interface
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
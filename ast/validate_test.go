@@ -0,0 +1,99 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
+)
+
+func TestValidateParsed(t *testing.T) {
+	for _, src := range []string{
+		`a = b + c;`,
+		`import x, {y as z} from "m"; export {x};`,
+		`export function f() {} export const a = 1; export default class {}`,
+		`for (const [a, b = 1] of c) { ({d, ...e} = f); }`,
+		`class C { #p = 1; static m() { return this.#p in o; } }`,
+		"switch (x) { case 1: break; default: `${y}`; }",
+		`label: for (;;) { a?.b?.(c); break label; }`,
+	} {
+		if problems := ast.Validate(parse(t, src)); len(problems) != 0 {
+			t.Errorf("Validate(%s) = %v, want no problems", src, problems)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	exprStmt := func(p *ast.Program) *ast.Expression {
+		return p.Body[0].Stmt.(*ast.ExpressionStatement).Expression
+	}
+	tests := []struct {
+		src    string
+		change func(p *ast.Program)
+		want   []string
+	}{
+		{
+			`a + b;`,
+			func(p *ast.Program) {
+				exprStmt(p).Expr.(*ast.BinaryExpression).Left = nil
+			},
+			[]string{"Body[0].Stmt.Expression.Expr.Left: missing Left"},
+		},
+		{
+			`a + b;`,
+			func(p *ast.Program) {
+				exprStmt(p).Expr.(*ast.BinaryExpression).Right.Expr = nil
+			},
+			[]string{"Body[0].Stmt.Expression.Expr.Right: missing Right"},
+		},
+		{
+			`a + b;`,
+			func(p *ast.Program) {
+				exprStmt(p).Expr.(*ast.BinaryExpression).Operator = token.Not
+			},
+			[]string{"Body[0].Stmt.Expression.Expr: invalid binary operator " + token.Not.String()},
+		},
+		{
+			`a = 1;`,
+			func(p *ast.Program) {
+				exprStmt(p).Expr.(*ast.AssignExpression).Left.Expr = &ast.NumberLiteral{Value: 2}
+			},
+			[]string{"Body[0].Stmt.Expression.Expr.Left.Expr: expected an assignment target, found *ast.NumberLiteral"},
+		},
+		{
+			`export const a = 1;`,
+			func(p *ast.Program) {
+				export := p.Body[0].Stmt.(*ast.ExportDeclaration)
+				export.Declaration.Stmt = &ast.ExpressionStatement{Expression: &ast.Expression{Expr: &ast.Identifier{Name: "a"}}}
+			},
+			[]string{"Body[0].Stmt.Declaration.Stmt: expected a variable, function or class declaration, found *ast.ExpressionStatement"},
+		},
+		{
+			`import x from "m"; {}`,
+			func(p *ast.Program) {
+				block := p.Body[1].Stmt.(*ast.BlockStatement)
+				block.List = append(block.List, p.Body[0])
+				p.Body = p.Body[1:]
+			},
+			[]string{"Body[0].Stmt.List[0].Stmt: expected a statement, found *ast.ImportDeclaration"},
+		},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		tt.change(p)
+		var got []string
+		for _, problem := range ast.Validate(p) {
+			got = append(got, problem.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("Validate(%s) after change:\ngot  %q\nwant %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestValidateNil(t *testing.T) {
+	if problems := ast.Validate((*ast.Program)(nil)); problems != nil {
+		t.Errorf("Validate(nil) = %v", problems)
+	}
+}
@@ -29,6 +29,9 @@ type Visitor interface {
 	VisitDebuggerStatement(n *DebuggerStatement)
 	VisitDoWhileStatement(n *DoWhileStatement)
 	VisitEmptyStatement(n *EmptyStatement)
+	VisitExportDeclaration(n *ExportDeclaration)
+	VisitExportSpecifier(n *ExportSpecifier)
+	VisitExportSpecifiers(n *ExportSpecifiers)
 	VisitExpression(n *Expression)
 	VisitExpressionStatement(n *ExpressionStatement)
 	VisitExpressions(n *Expressions)
@@ -42,6 +45,9 @@ type Visitor interface {
 	VisitFunctionLiteral(n *FunctionLiteral)
 	VisitIdentifier(n *Identifier)
 	VisitIfStatement(n *IfStatement)
+	VisitImportDeclaration(n *ImportDeclaration)
+	VisitImportSpecifier(n *ImportSpecifier)
+	VisitImportSpecifiers(n *ImportSpecifiers)
 	VisitInvalidExpression(n *InvalidExpression)
 	VisitLabelledStatement(n *LabelledStatement)
 	VisitMemberExpression(n *MemberExpression)
@@ -86,8 +92,6 @@ type Visitor interface {
 	VisitWhileStatement(n *WhileStatement)
 	VisitWithStatement(n *WithStatement)
 	VisitYieldExpression(n *YieldExpression)
-	VisitImportDeclaration(n *ImportDeclaration)
-	VisitExportDeclaration(n *ExportDeclaration)
 }
 type NoopVisitor struct {
 	V Visitor
@@ -174,6 +178,15 @@ func (nv *NoopVisitor) VisitDoWhileStatement(n *DoWhileStatement) {
 func (nv *NoopVisitor) VisitEmptyStatement(n *EmptyStatement) {
 	n.VisitChildrenWith(nv.V)
 }
+func (nv *NoopVisitor) VisitExportDeclaration(n *ExportDeclaration) {
+	n.VisitChildrenWith(nv.V)
+}
+func (nv *NoopVisitor) VisitExportSpecifier(n *ExportSpecifier) {
+	n.VisitChildrenWith(nv.V)
+}
+func (nv *NoopVisitor) VisitExportSpecifiers(n *ExportSpecifiers) {
+	n.VisitChildrenWith(nv.V)
+}
 func (nv *NoopVisitor) VisitExpression(n *Expression) {
 	n.VisitChildrenWith(nv.V)
 }
@@ -213,6 +226,15 @@ func (nv *NoopVisitor) VisitIdentifier(n *Identifier) {
 func (nv *NoopVisitor) VisitIfStatement(n *IfStatement) {
 	n.VisitChildrenWith(nv.V)
 }
+func (nv *NoopVisitor) VisitImportDeclaration(n *ImportDeclaration) {
+	n.VisitChildrenWith(nv.V)
+}
+func (nv *NoopVisitor) VisitImportSpecifier(n *ImportSpecifier) {
+	n.VisitChildrenWith(nv.V)
+}
+func (nv *NoopVisitor) VisitImportSpecifiers(n *ImportSpecifiers) {
+	n.VisitChildrenWith(nv.V)
+}
 func (nv *NoopVisitor) VisitInvalidExpression(n *InvalidExpression) {
 	n.VisitChildrenWith(nv.V)
 }
@@ -345,12 +367,6 @@ func (nv *NoopVisitor) VisitWithStatement(n *WithStatement) {
 func (nv *NoopVisitor) VisitYieldExpression(n *YieldExpression) {
 	n.VisitChildrenWith(nv.V)
 }
-func (nv *NoopVisitor) VisitImportDeclaration(n *ImportDeclaration) {
-	n.VisitChildrenWith(nv.V)
-}
-func (nv *NoopVisitor) VisitExportDeclaration(n *ExportDeclaration) {
-	n.VisitChildrenWith(nv.V)
-}
 func (n *ArrayLiteral) VisitWith(v Visitor) {
 	v.VisitArrayLiteral(n)
 }
@@ -362,7 +378,9 @@ func (n *ArrayPattern) VisitWith(v Visitor) {
 }
 func (n *ArrayPattern) VisitChildrenWith(v Visitor) {
 	n.Elements.VisitWith(v)
-	n.Rest.VisitWith(v)
+	if n.Rest != nil {
+		n.Rest.VisitWith(v)
+	}
 }
 func (n *ArrowFunctionLiteral) VisitWith(v Visitor) {
 	v.VisitArrowFunctionLiteral(n)
@@ -537,6 +555,36 @@ func (n *EmptyStatement) VisitWith(v Visitor) {
 }
 func (n *EmptyStatement) VisitChildrenWith(v Visitor) {
 }
+func (n *ExportDeclaration) VisitWith(v Visitor) {
+	v.VisitExportDeclaration(n)
+}
+func (n *ExportDeclaration) VisitChildrenWith(v Visitor) {
+	if n.Source != nil {
+		n.Source.VisitWith(v)
+	}
+	if n.Default != nil {
+		n.Default.VisitWith(v)
+	}
+	if n.Declaration != nil {
+		n.Declaration.VisitWith(v)
+	}
+	n.Specifiers.VisitWith(v)
+}
+func (n *ExportSpecifier) VisitWith(v Visitor) {
+	v.VisitExportSpecifier(n)
+}
+func (n *ExportSpecifier) VisitChildrenWith(v Visitor) {
+	n.Local.VisitWith(v)
+	n.Exported.VisitWith(v)
+}
+func (n *ExportSpecifiers) VisitWith(v Visitor) {
+	v.VisitExportSpecifiers(n)
+}
+func (n *ExportSpecifiers) VisitChildrenWith(v Visitor) {
+	for i := 0; i < len(*n); i++ {
+		(*n)[i].VisitWith(v)
+	}
+}
 func (n *Expression) VisitWith(v Visitor) {
 	v.VisitExpression(n)
 }
@@ -603,8 +651,12 @@ func (n *ForStatement) VisitChildrenWith(v Visitor) {
 	if n.Initializer != nil {
 		n.Initializer.VisitWith(v)
 	}
-	n.Update.VisitWith(v)
-	n.Test.VisitWith(v)
+	if n.Update != nil {
+		n.Update.VisitWith(v)
+	}
+	if n.Test != nil {
+		n.Test.VisitWith(v)
+	}
 	n.Body.VisitWith(v)
 }
 func (n *FunctionDeclaration) VisitWith(v Visitor) {
@@ -638,6 +690,34 @@ func (n *IfStatement) VisitChildrenWith(v Visitor) {
 		n.Alternate.VisitWith(v)
 	}
 }
+func (n *ImportDeclaration) VisitWith(v Visitor) {
+	v.VisitImportDeclaration(n)
+}
+func (n *ImportDeclaration) VisitChildrenWith(v Visitor) {
+	n.Source.VisitWith(v)
+	if n.Default != nil {
+		n.Default.VisitWith(v)
+	}
+	if n.Namespace != nil {
+		n.Namespace.VisitWith(v)
+	}
+	n.Specifiers.VisitWith(v)
+}
+func (n *ImportSpecifier) VisitWith(v Visitor) {
+	v.VisitImportSpecifier(n)
+}
+func (n *ImportSpecifier) VisitChildrenWith(v Visitor) {
+	n.Imported.VisitWith(v)
+	n.Local.VisitWith(v)
+}
+func (n *ImportSpecifiers) VisitWith(v Visitor) {
+	v.VisitImportSpecifiers(n)
+}
+func (n *ImportSpecifiers) VisitChildrenWith(v Visitor) {
+	for i := 0; i < len(*n); i++ {
+		(*n)[i].VisitWith(v)
+	}
+}
 func (n *InvalidExpression) VisitWith(v Visitor) {
 	v.VisitInvalidExpression(n)
 }
@@ -668,6 +748,7 @@ func (n *MetaProperty) VisitWith(v Visitor) {
 }
 func (n *MetaProperty) VisitChildrenWith(v Visitor) {
 	n.Meta.VisitWith(v)
+	n.Property.VisitWith(v)
 }
 func (n *MethodDefinition) VisitWith(v Visitor) {
 	v.VisitMethodDefinition(n)
@@ -774,7 +855,9 @@ func (n *PropertyShort) VisitWith(v Visitor) {
 }
 func (n *PropertyShort) VisitChildrenWith(v Visitor) {
 	n.Name.VisitWith(v)
-	n.Initializer.VisitWith(v)
+	if n.Initializer != nil {
+		n.Initializer.VisitWith(v)
+	}
 }
 func (n *RegExpLiteral) VisitWith(v Visitor) {
 	v.VisitRegExpLiteral(n)
@@ -933,5 +1016,7 @@ func (n *YieldExpression) VisitWith(v Visitor) {
 	v.VisitYieldExpression(n)
 }
 func (n *YieldExpression) VisitChildrenWith(v Visitor) {
-	n.Argument.VisitWith(v)
+	if n.Argument != nil {
+		n.Argument.VisitWith(v)
+	}
 }
@@ -0,0 +1,541 @@
+package callgraph
+
+import (
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/jsvalue"
+	"github.com/t14raptor/go-fast/resolver"
+	"github.com/t14raptor/go-fast/token"
+)
+
+type builder struct {
+	ast.NoopVisitor
+
+	graph    *Graph
+	resolver *resolver.Resolver
+
+	// stack holds the functions being visited and classes the classes,
+	// innermost last.
+	stack   []*Node
+	classes []*ast.ClassLiteral
+	// supers maps the super calls to the class they are in.
+	supers map[*Site]*ast.ClassLiteral
+
+	// decls maps the identifiers declared with a value to it, or to nil if
+	// the value is unknown, and assigns the targets of = to the value.
+	decls   map[*ast.Identifier]ast.Expr
+	assigns map[*ast.Identifier]ast.Expr
+	// props holds the values assigned by = to the properties of objects,
+	// classes and functions, by key, and dynamic those with properties
+	// assigned with keys not known statically.
+	props   map[ast.VisitableNode]map[string][]ast.Expr
+	dynamic map[ast.VisitableNode]bool
+	// writes holds the member expressions assigned by =, resolved once the
+	// values of all bindings are known.
+	writes []*ast.AssignExpression
+
+	// names holds the names of functions found before visiting them.
+	names  map[ast.VisitableNode]string
+	values map[*resolver.Binding]*bindingValues
+}
+
+// bindingValues holds the values a binding may hold.
+type bindingValues struct {
+	values  []ast.Expr
+	unknown bool
+}
+
+func newBuilder(r *resolver.Resolver) *builder {
+	b := &builder{
+		graph:    &Graph{nodes: make(map[ast.VisitableNode]*Node)},
+		resolver: r,
+		supers:   make(map[*Site]*ast.ClassLiteral),
+		decls:    make(map[*ast.Identifier]ast.Expr),
+		assigns:  make(map[*ast.Identifier]ast.Expr),
+		props:    make(map[ast.VisitableNode]map[string][]ast.Expr),
+		dynamic:  make(map[ast.VisitableNode]bool),
+		names:    make(map[ast.VisitableNode]string),
+		values:   make(map[*resolver.Binding]*bindingValues),
+	}
+	b.V = b
+	return b
+}
+
+func (b *builder) node(fn ast.VisitableNode, name string) *Node {
+	n := &Node{Func: fn, Name: name}
+	b.graph.Nodes = append(b.graph.Nodes, n)
+	b.graph.nodes[fn] = n
+	return n
+}
+
+func (b *builder) function(fn ast.VisitableNode, name string) {
+	if name == "" {
+		name = b.names[fn]
+	}
+	b.stack = append(b.stack, b.node(fn, name))
+	fn.VisitChildrenWith(b)
+	b.stack = b.stack[:len(b.stack)-1]
+}
+
+func (b *builder) site(call ast.Expr) *Site {
+	caller := b.stack[len(b.stack)-1]
+	s := &Site{Call: call, Caller: caller}
+	caller.Sites = append(caller.Sites, s)
+	return s
+}
+
+// name records the name of value, if it is a function.
+func (b *builder) name(value *ast.Expression, name string) {
+	if value == nil {
+		return
+	}
+	switch fn := value.Expr.(type) {
+	case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral:
+		if _, ok := b.names[fn]; !ok {
+			b.names[fn] = name
+		}
+	}
+}
+
+func (b *builder) VisitProgram(n *ast.Program) {
+	b.graph.Root = b.node(n, "")
+	b.stack = append(b.stack, b.graph.Root)
+	n.VisitChildrenWith(b)
+	b.stack = b.stack[:0]
+}
+
+func (b *builder) VisitFunctionLiteral(n *ast.FunctionLiteral) {
+	name := ""
+	if n.Name != nil {
+		name = n.Name.Name
+		b.decls[n.Name] = n
+	}
+	b.function(n, name)
+}
+
+func (b *builder) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {
+	b.function(n, "")
+}
+
+func (b *builder) VisitClassLiteral(n *ast.ClassLiteral) {
+	if n.Name != nil {
+		b.decls[n.Name] = n
+	}
+	for _, elem := range n.Body {
+		switch elem := elem.Element.(type) {
+		case *ast.MethodDefinition:
+			if key, ok := propertyKey(elem.Key, elem.Computed); ok {
+				if _, named := b.names[elem.Body]; !named {
+					b.names[elem.Body] = key
+				}
+			}
+		case *ast.FieldDefinition:
+			if key, ok := propertyKey(elem.Key, elem.Computed); ok {
+				b.name(elem.Initializer, key)
+			}
+		}
+	}
+	b.classes = append(b.classes, n)
+	n.VisitChildrenWith(b)
+	b.classes = b.classes[:len(b.classes)-1]
+}
+
+func (b *builder) VisitObjectLiteral(n *ast.ObjectLiteral) {
+	for _, prop := range n.Value {
+		if prop, ok := prop.Prop.(*ast.PropertyKeyed); ok {
+			if key, ok := propertyKey(prop.Key, prop.Computed); ok {
+				b.name(prop.Value, key)
+			}
+		}
+	}
+	n.VisitChildrenWith(b)
+}
+
+func (b *builder) VisitVariableDeclaration(n *ast.VariableDeclaration) {
+	for _, d := range n.List {
+		if id, ok := d.Target.Target.(*ast.Identifier); ok {
+			if d.Initializer != nil {
+				b.decls[id] = d.Initializer.Expr
+				b.name(d.Initializer, id.Name)
+			}
+			continue
+		}
+		// Destructured values are unknown.
+		resolver.BindingIdents(d.Target.Target, func(id *ast.Identifier) {
+			b.decls[id] = nil
+		})
+	}
+	n.VisitChildrenWith(b)
+}
+
+func (b *builder) VisitForInStatement(n *ast.ForInStatement) {
+	b.forInto(n.Into)
+	n.VisitChildrenWith(b)
+}
+
+func (b *builder) VisitForOfStatement(n *ast.ForOfStatement) {
+	b.forInto(n.Into)
+	n.VisitChildrenWith(b)
+}
+
+// forInto marks the bindings declared in the head of a for-in or for-of
+// statement as unknown.
+func (b *builder) forInto(into *ast.ForInto) {
+	if decl, ok := into.Into.(*ast.VariableDeclaration); ok {
+		for _, d := range decl.List {
+			resolver.BindingIdents(d.Target.Target, func(id *ast.Identifier) {
+				b.decls[id] = nil
+			})
+		}
+	}
+}
+
+func (b *builder) VisitAssignExpression(n *ast.AssignExpression) {
+	if n.Operator == token.Assign {
+		switch left := n.Left.Expr.(type) {
+		case *ast.Identifier:
+			b.assigns[left] = n.Right.Expr
+			b.name(n.Right, left.Name)
+		case *ast.MemberExpression:
+			b.writes = append(b.writes, n)
+			if key, ok := memberKey(left.Property); ok {
+				b.name(n.Right, key)
+			}
+		}
+	}
+	n.VisitChildrenWith(b)
+}
+
+func (b *builder) VisitCallExpression(n *ast.CallExpression) {
+	s := b.site(n)
+	if _, ok := n.Callee.Expr.(*ast.SuperExpression); ok && len(b.classes) > 0 {
+		b.supers[s] = b.classes[len(b.classes)-1]
+	}
+	n.VisitChildrenWith(b)
+}
+
+func (b *builder) VisitNewExpression(n *ast.NewExpression) {
+	b.site(n)
+	n.VisitChildrenWith(b)
+}
+
+// link records the property writes, then resolves the callees of the sites.
+func (b *builder) link() {
+	for _, w := range b.writes {
+		m := w.Left.Expr.(*ast.MemberExpression)
+		key, static := memberKey(m.Property)
+		objects, _ := b.resolve(m.Object.Expr, make(map[*resolver.Binding]bool))
+		for _, obj := range objects {
+			if !static {
+				b.dynamic[obj] = true
+				continue
+			}
+			if b.props[obj] == nil {
+				b.props[obj] = make(map[string][]ast.Expr)
+			}
+			b.props[obj][key] = append(b.props[obj][key], w.Right.Expr)
+		}
+	}
+
+	for _, n := range b.graph.Nodes {
+		for _, s := range n.Sites {
+			var values []ast.VisitableNode
+			unknown := false
+			switch call := s.Call.(type) {
+			case *ast.CallExpression:
+				if class := b.supers[s]; class != nil {
+					values, unknown = b.superclass(class, make(map[*resolver.Binding]bool))
+				} else {
+					values, unknown = b.callee(call.Callee.Expr)
+				}
+			case *ast.NewExpression:
+				values, unknown = b.resolve(call.Callee.Expr, make(map[*resolver.Binding]bool))
+			}
+			b.call(s, values, unknown)
+		}
+	}
+}
+
+// call links s to the functions the values it calls run.
+func (b *builder) call(s *Site, values []ast.VisitableNode, unknown bool) {
+	s.Dynamic = unknown
+	seen := make(map[ast.VisitableNode]bool)
+	for len(values) > 0 {
+		v := values[0]
+		values = values[1:]
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		switch v := v.(type) {
+		case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral:
+			if n := b.graph.nodes[v]; n != nil {
+				s.Callees = append(s.Callees, n)
+				n.Callers = append(n.Callers, s)
+			}
+		case *ast.ClassLiteral:
+			if ctor := constructor(v); ctor != nil {
+				values = append(values, ctor)
+			} else if v.SuperClass != nil {
+				parents, unknown := b.superclass(v, make(map[*resolver.Binding]bool))
+				values = append(values, parents...)
+				s.Dynamic = s.Dynamic || unknown
+			}
+		default:
+			s.Dynamic = true
+		}
+	}
+}
+
+// callee resolves the callee of a call, looking through call and apply.
+func (b *builder) callee(callee ast.Expr) ([]ast.VisitableNode, bool) {
+	if m, ok := callee.(*ast.MemberExpression); ok {
+		if key, _ := memberKey(m.Property); key == "call" || key == "apply" {
+			values, unknown := b.resolve(m.Object.Expr, make(map[*resolver.Binding]bool))
+			if !unknown && len(values) > 0 && functions(values) {
+				return values, false
+			}
+		}
+	}
+	return b.resolve(callee, make(map[*resolver.Binding]bool))
+}
+
+func (b *builder) superclass(class *ast.ClassLiteral, seen map[*resolver.Binding]bool) ([]ast.VisitableNode, bool) {
+	if class.SuperClass == nil {
+		return nil, true
+	}
+	return b.resolve(class.SuperClass.Expr, seen)
+}
+
+// resolve returns the functions, classes and object literals e may evaluate
+// to, and whether it may evaluate to other values. seen holds the bindings
+// followed so far.
+func (b *builder) resolve(e ast.Expr, seen map[*resolver.Binding]bool) ([]ast.VisitableNode, bool) {
+	switch e := e.(type) {
+	case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral, *ast.ClassLiteral, *ast.ObjectLiteral:
+		return []ast.VisitableNode{e}, false
+	case *ast.Identifier:
+		binding := b.resolver.Binding(e)
+		if binding == nil {
+			return nil, true
+		}
+		if seen[binding] {
+			return nil, false
+		}
+		seen[binding] = true
+		bv := b.bindingValues(binding)
+		return b.resolveAll(bv.values, bv.unknown, seen)
+	case *ast.SequenceExpression:
+		if len(e.Sequence) > 0 {
+			return b.resolve(e.Sequence[len(e.Sequence)-1].Expr, seen)
+		}
+	case *ast.ConditionalExpression:
+		return b.resolveAll([]ast.Expr{e.Consequent.Expr, e.Alternate.Expr}, false, seen)
+	case *ast.BinaryExpression:
+		switch e.Operator {
+		case token.LogicalAnd, token.LogicalOr, token.Coalesce:
+			// The left operand is the value unless falsy or nullish.
+			return b.resolveAll([]ast.Expr{e.Left.Expr, e.Right.Expr}, false, seen)
+		}
+	case *ast.AssignExpression:
+		if e.Operator == token.Assign {
+			return b.resolve(e.Right.Expr, seen)
+		}
+	case *ast.MemberExpression:
+		key, ok := memberKey(e.Property)
+		if !ok {
+			break
+		}
+		objects, unknown := b.resolve(e.Object.Expr, seen)
+		var values []ast.VisitableNode
+		for _, obj := range objects {
+			vs, u := b.property(obj, key, seen)
+			values = append(values, vs...)
+			unknown = unknown || u
+		}
+		return values, unknown
+	case *ast.CallExpression:
+		// f.bind(...) makes a function calling f.
+		if m, ok := e.Callee.Expr.(*ast.MemberExpression); ok {
+			if key, _ := memberKey(m.Property); key == "bind" {
+				values, unknown := b.resolve(m.Object.Expr, seen)
+				if functions(values) {
+					return values, unknown
+				}
+			}
+		}
+	}
+	return nil, true
+}
+
+func (b *builder) resolveAll(exprs []ast.Expr, unknown bool, seen map[*resolver.Binding]bool) ([]ast.VisitableNode, bool) {
+	var values []ast.VisitableNode
+	for _, e := range exprs {
+		vs, u := b.resolve(e, seen)
+		values = append(values, vs...)
+		unknown = unknown || u
+	}
+	return values, unknown
+}
+
+// property resolves the property key of obj.
+func (b *builder) property(obj ast.VisitableNode, key string, seen map[*resolver.Binding]bool) ([]ast.VisitableNode, bool) {
+	var own []ast.Expr
+	found, unknown := false, b.dynamic[obj]
+	switch obj := obj.(type) {
+	case *ast.ObjectLiteral:
+		// The last property with the key wins, unless a spread or computed
+		// key after it may replace it.
+		for _, prop := range obj.Value {
+			switch prop := prop.Prop.(type) {
+			case *ast.PropertyKeyed:
+				k, ok := propertyKey(prop.Key, prop.Computed)
+				switch {
+				case !ok:
+					unknown = true
+				case k != key:
+				case prop.Kind == ast.PropertyKindValue || prop.Kind == ast.PropertyKindMethod:
+					own, found, unknown = []ast.Expr{prop.Value.Expr}, true, b.dynamic[obj]
+				default:
+					own, found, unknown = nil, true, true
+				}
+			case *ast.PropertyShort:
+				if prop.Name.Name == key {
+					own, found, unknown = []ast.Expr{prop.Name}, true, b.dynamic[obj]
+				}
+			case *ast.SpreadElement:
+				unknown = true
+			}
+		}
+	case *ast.ClassLiteral:
+		for _, elem := range obj.Body {
+			switch elem := elem.Element.(type) {
+			case *ast.MethodDefinition:
+				k, ok := propertyKey(elem.Key, elem.Computed)
+				if elem.Static && ok && k == key {
+					found = true
+					if elem.Kind == ast.PropertyKindMethod {
+						own = append(own, elem.Body)
+					} else {
+						unknown = true
+					}
+				}
+			case *ast.FieldDefinition:
+				k, ok := propertyKey(elem.Key, elem.Computed)
+				if elem.Static && ok && k == key && elem.Initializer != nil {
+					found = true
+					own = append(own, elem.Initializer.Expr)
+				}
+			}
+		}
+	}
+
+	assigned := b.props[obj][key]
+	if !found && len(assigned) == 0 {
+		// Inherited, or missing.
+		return nil, true
+	}
+	return b.resolveAll(append(own, assigned...), unknown, seen)
+}
+
+// bindingValues returns the values b may hold.
+func (b *builder) bindingValues(binding *resolver.Binding) *bindingValues {
+	if bv := b.values[binding]; bv != nil {
+		return bv
+	}
+	bv := &bindingValues{}
+	b.values[binding] = bv
+
+	switch binding.Kind {
+	case resolver.DeclKindParam, resolver.DeclKindCatch, resolver.DeclKindImport, resolver.DeclKindArguments:
+		bv.unknown = true
+	}
+	if binding.Scope.Eval {
+		bv.unknown = true
+	}
+	for _, id := range binding.Decls {
+		if v, ok := b.decls[id]; ok {
+			if v == nil {
+				bv.unknown = true
+			} else {
+				bv.values = append(bv.values, v)
+			}
+		}
+	}
+	for _, ref := range binding.References {
+		if ref.With {
+			bv.unknown = true
+		}
+		if ref.Kind == resolver.RefKindRead {
+			continue
+		}
+		if v, ok := b.assigns[ref.Ident]; ok {
+			bv.values = append(bv.values, v)
+		} else {
+			bv.unknown = true
+		}
+	}
+	return bv
+}
+
+// functions reports whether values are all functions.
+func functions(values []ast.VisitableNode) bool {
+	for _, v := range values {
+		switch v.(type) {
+		case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// constructor returns the constructor method of class, or nil.
+func constructor(class *ast.ClassLiteral) *ast.FunctionLiteral {
+	for _, elem := range class.Body {
+		if m, ok := elem.Element.(*ast.MethodDefinition); ok && !m.Static {
+			if key, ok := propertyKey(m.Key, m.Computed); ok && key == "constructor" {
+				return m.Body
+			}
+		}
+	}
+	return nil
+}
+
+// memberKey returns the name of the property accessed by a member
+// expression, if known statically.
+func memberKey(p *ast.MemberProperty) (string, bool) {
+	switch p := p.Prop.(type) {
+	case *ast.Identifier:
+		return p.Name, true
+	case *ast.ComputedProperty:
+		return literalKey(p.Expr.Expr)
+	}
+	return "", false
+}
+
+// propertyKey returns the name of the property defined by a key of an
+// object literal or class, if known statically.
+func propertyKey(key *ast.Expression, computed bool) (string, bool) {
+	if computed {
+		return literalKey(key.Expr)
+	}
+	switch k := key.Expr.(type) {
+	case *ast.StringLiteral:
+		return k.Value, true
+	case *ast.NumberLiteral:
+		return literalKey(k)
+	}
+	return "", false
+}
+
+// literalKey returns the property name e converts to if it is a string or
+// number literal.
+func literalKey(e ast.Expr) (string, bool) {
+	switch e := e.(type) {
+	case *ast.StringLiteral:
+		return e.Value, true
+	case *ast.NumberLiteral:
+		return jsvalue.FormatNumber(e.Value, 10), true
+	}
+	return "", false
+}
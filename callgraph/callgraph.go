@@ -0,0 +1,112 @@
+// Package callgraph builds the call graph of a program: the functions each
+// call in it may invoke, as far as can be told without running it.
+package callgraph
+
+import (
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+// Graph is the call graph of a program.
+type Graph struct {
+	// Root is the node of the top-level code of the program, and Nodes holds
+	// it followed by the nodes of the functions, in source order.
+	Root  *Node
+	Nodes []*Node
+
+	nodes map[ast.VisitableNode]*Node
+}
+
+// Node is a function of the call graph.
+type Node struct {
+	// Func is the *ast.Program, *ast.FunctionLiteral or
+	// *ast.ArrowFunctionLiteral. The code of class static blocks and field
+	// initializers belongs to the function the class is in.
+	Func ast.VisitableNode
+	// Name is the name the function is declared with or first assigned to,
+	// such as a variable or property, or empty.
+	Name string
+
+	// Sites holds the calls in the function, in source order, and Callers
+	// the calls that may invoke it.
+	Sites   []*Site
+	Callers []*Site
+}
+
+// Site is a call.
+type Site struct {
+	// Call is the *ast.CallExpression or *ast.NewExpression, and Caller the
+	// function it is in.
+	Call   ast.Expr
+	Caller *Node
+
+	// Callees holds the functions the call may invoke, in source order.
+	// Dynamic is set if it may invoke others as well, such as functions that
+	// are not part of the program, passed as arguments or computed.
+	Callees []*Node
+	Dynamic bool
+}
+
+// Build builds the call graph of p, which r resolved.
+//
+// The values a binding may hold are those it is declared with and assigned
+// by =, wherever they are in the program: the callee of a call to a binding
+// declared or assigned otherwise, such as a parameter, is dynamic. The
+// properties of an object literal are its own and those assigned by = to a
+// key known statically, and a class has its static methods and fields. Calls
+// through call or apply and of functions made by bind invoke the function
+// they are called on, new and super invoke the constructor of a class, and
+// the implicit constructor of a derived class that of its parent. Other
+// writes to objects, such as by Object.assign, are not seen.
+func Build(p *ast.Program, r *resolver.Resolver) *Graph {
+	b := newBuilder(r)
+	p.VisitWith(b)
+	b.link()
+	return b.graph
+}
+
+// Lookup returns the node of fn, an *ast.Program, *ast.FunctionLiteral or
+// *ast.ArrowFunctionLiteral, or nil.
+func (g *Graph) Lookup(fn ast.VisitableNode) *Node {
+	return g.nodes[fn]
+}
+
+// Reachable returns the nodes reachable from roots by calls, roots first,
+// breadth first following the calls of each function in source order. With
+// no roots, it starts from the top-level code of the program. Dynamic calls
+// are not followed.
+func (g *Graph) Reachable(roots ...*Node) []*Node {
+	if len(roots) == 0 {
+		roots = []*Node{g.Root}
+	}
+	seen := make(map[*Node]bool)
+	var order []*Node
+	for _, n := range roots {
+		if !seen[n] {
+			seen[n] = true
+			order = append(order, n)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, site := range order[i].Sites {
+			for _, callee := range site.Callees {
+				if !seen[callee] {
+					seen[callee] = true
+					order = append(order, callee)
+				}
+			}
+		}
+	}
+	return order
+}
+
+// Reaches reports whether a chain of calls leads from one node to another.
+// A node reaches itself.
+func (g *Graph) Reaches(from, to *Node) bool {
+	for _, n := range g.Reachable(from) {
+		if n == to {
+			return true
+		}
+	}
+	return false
+}
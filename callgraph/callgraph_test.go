@@ -0,0 +1,168 @@
+package callgraph_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/callgraph"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func build(t *testing.T, src string) *callgraph.Graph {
+	t.Helper()
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile(%q): %v", src, err)
+	}
+	return callgraph.Build(p, resolver.Resolve(p))
+}
+
+func name(n *callgraph.Node) string {
+	if n.Name != "" {
+		return n.Name
+	}
+	if _, ok := n.Func.(*ast.Program); ok {
+		return "main"
+	}
+	return "_"
+}
+
+// describe lists the functions of g with the callees of their calls, marked
+// with ? if dynamic.
+func describe(t *testing.T, g *callgraph.Graph) string {
+	var lines []string
+	for _, n := range g.Nodes {
+		var sites []string
+		for _, s := range n.Sites {
+			if s.Caller != n {
+				t.Errorf("a call in %s has caller %s", name(n), name(s.Caller))
+			}
+			var callees []string
+			for _, c := range s.Callees {
+				callees = append(callees, name(c))
+				if !slices.Contains(c.Callers, s) {
+					t.Errorf("a call in %s to %s is not among its callers", name(n), name(c))
+				}
+			}
+			site := strings.Join(callees, ",")
+			if s.Dynamic {
+				site += "?"
+			}
+			sites = append(sites, site)
+		}
+		lines = append(lines, name(n)+": "+strings.Join(sites, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`function f() { g(); } function g() {} f();`, `
+main: f
+f: g
+g: `},
+		// Aliases, with every value assigned by =.
+		{`var h = f; h = c ? g : k; h(); function f() {} function g() {} function k() {}`, `
+main: f,g,k
+f: 
+g: 
+k: `},
+		{`var a = () => b(); var b = () => {}; a();`, `
+main: a
+a: b
+b: `},
+		// Calls of globals, parameters and destructured values are dynamic.
+		{`g(); function f(p) { p(); } var {d} = o; d();`, `
+main: ? ?
+f: ?`},
+		// Object literal method tables and properties assigned by =.
+		{`var o = { a: f, b() {}, "c": () => {} }; o.x = g; o.a(); o.b(); o["c"](); o.x(); o.y(); function f() {} function g() {}`, `
+main: f b c g ?
+b: 
+c: 
+f: 
+g: `},
+		// Number keys are the names they convert to.
+		{`var o = { 1: f, 1.5: g, [2.5]: k, "1e+21": m }; o[1](); o["1.5"](); o[2.5](); o[1e21](); o[0.1](); function f() {} function g() {} function k() {} function m() {}`, `
+main: f g k m ?
+f: 
+g: 
+k: 
+m: `},
+		// A computed key or spread after a property may replace it.
+		{`var o = { a: f, [k]: g }; o.a(); var p = { ...q, a: f }; p.a(); function f() {} function g() {}`, `
+main: f? f
+f: 
+g: `},
+		{`f.call(x); f.apply(x, []); f.bind(x)(); function f() {}`, `
+main: f f f ?
+f: `},
+		// new and super invoke constructors, inherited by derived classes
+		// without one.
+		{`function A() {} class B extends A {} class C extends B { constructor() { super(); } static s() {} } new B(); new C(); C.s();`, `
+main: A constructor s
+A: 
+constructor: A
+s: `},
+	}
+	for _, tt := range tests {
+		g := build(t, tt.src)
+		if got, want := describe(t, g), strings.TrimPrefix(tt.want, "\n"); got != want {
+			t.Errorf("call graph of %s:\n%s\nwant\n%s", tt.src, got, want)
+		}
+	}
+}
+
+func TestReachable(t *testing.T) {
+	p, err := parser.ParseFile(`function a() { b(); c(); } function b() { a(); } function c() {} function d() { b(); } a();`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := callgraph.Build(p, resolver.Resolve(p))
+	nodes := make(map[string]*callgraph.Node)
+	for _, n := range g.Nodes {
+		nodes[name(n)] = n
+	}
+	for _, stmt := range p.Body {
+		if decl, ok := stmt.Stmt.(*ast.FunctionDeclaration); ok && g.Lookup(decl.Function) != nodes[decl.Function.Name.Name] {
+			t.Errorf("Lookup(%s) is not its node", decl.Function.Name.Name)
+		}
+	}
+	if g.Lookup(p) != g.Root || g.Root != g.Nodes[0] {
+		t.Errorf("the root is not the node of the program")
+	}
+
+	names := func(ns []*callgraph.Node) string {
+		var s []string
+		for _, n := range ns {
+			s = append(s, name(n))
+		}
+		return strings.Join(s, " ")
+	}
+	if got := names(g.Reachable()); got != "main a b c" {
+		t.Errorf("Reachable() = %s, want main a b c", got)
+	}
+	if got := names(g.Reachable(nodes["d"], nodes["c"])); got != "d c b a" {
+		t.Errorf("Reachable(d, c) = %s, want d c b a", got)
+	}
+	for _, tt := range []struct {
+		from, to string
+		want     bool
+	}{
+		{"a", "c", true},
+		{"b", "c", true},
+		{"c", "c", true},
+		{"c", "a", false},
+		{"a", "d", false},
+	} {
+		if got := g.Reaches(nodes[tt.from], nodes[tt.to]); got != tt.want {
+			t.Errorf("Reaches(%s, %s) = %v", tt.from, tt.to, got)
+		}
+	}
+}
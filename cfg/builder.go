@@ -0,0 +1,612 @@
+package cfg
+
+import (
+	"slices"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
+)
+
+type builder struct {
+	g   *Graph
+	cur *Block
+
+	// targets holds the statements break and continue may jump to, and tries
+	// the try statements, innermost last.
+	targets []*target
+	tries   []*tryContext
+}
+
+type target struct {
+	labels []string
+	// brk is where break goes, and cont where continue goes in a loop.
+	brk, cont *Block
+	// unlabeled is set for loops and switch statements, which a break
+	// without label leaves.
+	unlabeled bool
+	// tries is the number of try statements around the target.
+	tries int
+}
+
+type tryPart int
+
+const (
+	tryPartBody tryPart = iota
+	tryPartCatch
+	tryPartFinally
+)
+
+type tryContext struct {
+	catch, finally *Block
+	part           tryPart
+	// pending holds the jumps and exceptions going through the finally
+	// block, which go on from its end.
+	pending []jump
+}
+
+type jump struct {
+	to    *Block
+	kind  EdgeKind
+	tries int
+	throw bool
+}
+
+func newBuilder(n ast.VisitableNode) *builder {
+	b := &builder{g: &Graph{Node: n}}
+	b.g.Entry = b.newBlock()
+	b.g.Exit = b.newBlock()
+	b.cur = b.g.Entry
+	return b
+}
+
+func (b *builder) finish() *Graph {
+	b.edge(b.cur, b.g.Exit, EdgeKindNormal)
+
+	// Drop the empty blocks left behind by jumps.
+	blocks := b.g.Blocks[:0]
+	for _, blk := range b.g.Blocks {
+		blk.Live = b.live(blk)
+		if blk.Live || len(blk.Nodes) > 0 || blk == b.g.Exit {
+			blk.Index = len(blocks)
+			blocks = append(blocks, blk)
+		}
+	}
+	b.g.Blocks = blocks
+	return b.g
+}
+
+func (b *builder) newBlock() *Block {
+	blk := &Block{Index: len(b.g.Blocks)}
+	b.g.Blocks = append(b.g.Blocks, blk)
+	return blk
+}
+
+// live reports whether blk can be reached so far. Edges are only added
+// from blocks that can, and to a block before the edges leaving it, so
+// blocks that cannot be reached have no edges.
+func (b *builder) live(blk *Block) bool {
+	return blk == b.g.Entry || len(blk.Preds) > 0
+}
+
+func (b *builder) edge(from, to *Block, kind EdgeKind) {
+	if !b.live(from) {
+		return
+	}
+	for _, e := range from.Succs {
+		if e.To == to && e.Kind == kind {
+			return
+		}
+	}
+	e := &Edge{From: from, To: to, Kind: kind}
+	from.Succs = append(from.Succs, e)
+	to.Preds = append(to.Preds, e)
+}
+
+// goTo ends the current block with an edge to blk, which becomes current.
+func (b *builder) goTo(blk *Block) {
+	b.edge(b.cur, blk, EdgeKindNormal)
+	b.cur = blk
+}
+
+// branch ends the current block with a branch on cond.
+func (b *builder) branch(cond ast.Expr, t, f *Block) {
+	b.cur.Cond = cond
+	b.edge(b.cur, t, EdgeKindTrue)
+	b.edge(b.cur, f, EdgeKindFalse)
+}
+
+// dead starts a block following a jump, which cannot be reached.
+func (b *builder) dead() {
+	b.cur = b.newBlock()
+}
+
+func (b *builder) add(n ast.VisitableNode) {
+	if len(b.cur.Nodes) == 0 && len(b.tries) > 0 {
+		// Any node may throw.
+		b.throw(b.cur)
+	}
+	b.cur.Nodes = append(b.cur.Nodes, n)
+}
+
+// jump adds an edge from blk to a target outside tries try statements,
+// through the finally blocks in between.
+func (b *builder) jump(from *Block, j jump) {
+	for i := len(b.tries) - 1; i >= j.tries; i-- {
+		t := b.tries[i]
+		if t.finally != nil && t.part != tryPartFinally {
+			b.through(from, t, j)
+			return
+		}
+	}
+	b.edge(from, j.to, j.kind)
+}
+
+// throw adds an edge from blk to the handler of the exceptions it throws.
+func (b *builder) throw(from *Block) {
+	for i := len(b.tries) - 1; i >= 0; i-- {
+		t := b.tries[i]
+		if t.part == tryPartBody && t.catch != nil {
+			b.edge(from, t.catch, EdgeKindException)
+			return
+		}
+		if t.finally != nil && t.part != tryPartFinally {
+			b.through(from, t, jump{kind: EdgeKindException, throw: true})
+			return
+		}
+	}
+	b.edge(from, b.g.Exit, EdgeKindException)
+}
+
+// through adds an edge from blk to the finally block of t, for j to go on
+// from its end.
+func (b *builder) through(from *Block, t *tryContext, j jump) {
+	if !b.live(from) {
+		return
+	}
+	b.edge(from, t.finally, j.kind)
+	if !slices.Contains(t.pending, j) {
+		t.pending = append(t.pending, j)
+	}
+}
+
+func (b *builder) params(n *ast.ParameterList) {
+	if len(n.List) > 0 || n.Rest != nil {
+		b.eval(n)
+	}
+}
+
+func (b *builder) stmts(list ast.Statements) {
+	for _, s := range list {
+		b.stmt(s.Stmt, nil)
+	}
+}
+
+func (b *builder) stmt(s ast.Stmt, labels []string) {
+	switch s := s.(type) {
+	case *ast.LabelledStatement:
+		b.stmt(s.Statement.Stmt, append(labels, s.Label.Name))
+		return
+	case *ast.WhileStatement:
+		b.whileStmt(s, labels)
+		return
+	case *ast.DoWhileStatement:
+		b.doWhileStmt(s, labels)
+		return
+	case *ast.ForStatement:
+		b.forStmt(s, labels)
+		return
+	case *ast.ForInStatement:
+		b.forIntoStmt(s.Into, s.Source, s.Body, labels)
+		return
+	case *ast.ForOfStatement:
+		b.forIntoStmt(s.Into, s.Source, s.Body, labels)
+		return
+	}
+
+	if len(labels) > 0 {
+		// break label leaves any labelled statement.
+		after := b.newBlock()
+		b.targets = append(b.targets, &target{labels: labels, brk: after, tries: len(b.tries)})
+		b.stmt(s, nil)
+		b.targets = b.targets[:len(b.targets)-1]
+		b.goTo(after)
+		return
+	}
+
+	switch s := s.(type) {
+	case *ast.BlockStatement:
+		b.stmts(s.List)
+	case *ast.IfStatement:
+		b.ifStmt(s)
+	case *ast.SwitchStatement:
+		b.switchStmt(s)
+	case *ast.TryStatement:
+		b.tryStmt(s)
+	case *ast.WithStatement:
+		b.eval(s.Object.Expr)
+		b.stmt(s.Body.Stmt, nil)
+	case *ast.ReturnStatement:
+		b.eval(s)
+		b.jump(b.cur, jump{to: b.g.Exit, kind: EdgeKindNormal})
+		b.dead()
+	case *ast.ThrowStatement:
+		b.eval(s)
+		b.throw(b.cur)
+		b.dead()
+	case *ast.BreakStatement:
+		b.add(s)
+		if t := b.target(s.Label, false); t != nil {
+			b.jump(b.cur, jump{to: t.brk, tries: t.tries})
+		}
+		b.dead()
+	case *ast.ContinueStatement:
+		b.add(s)
+		if t := b.target(s.Label, true); t != nil {
+			b.jump(b.cur, jump{to: t.cont, tries: t.tries})
+		}
+		b.dead()
+	case *ast.EmptyStatement, *ast.FunctionDeclaration, *ast.ImportDeclaration:
+		// Nothing happens here: functions and imports are bound on entry.
+	case *ast.ExportDeclaration:
+		switch {
+		case s.Declaration != nil:
+			b.stmt(s.Declaration.Stmt, nil)
+		case s.Default != nil:
+			b.eval(s)
+		}
+	default:
+		b.eval(s)
+	}
+}
+
+// target returns the statement a break or continue with label goes to.
+func (b *builder) target(label *ast.Identifier, cont bool) *target {
+	for i := len(b.targets) - 1; i >= 0; i-- {
+		t := b.targets[i]
+		if cont && t.cont == nil {
+			continue
+		}
+		if label == nil && (t.unlabeled || cont) || label != nil && slices.Contains(t.labels, label.Name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// loop visits the body of a loop continuing at cont and ending at brk.
+func (b *builder) loop(body *ast.Statement, labels []string, brk, cont *Block) {
+	b.targets = append(b.targets, &target{labels: labels, brk: brk, cont: cont, unlabeled: true, tries: len(b.tries)})
+	b.stmt(body.Stmt, nil)
+	b.targets = b.targets[:len(b.targets)-1]
+}
+
+func (b *builder) ifStmt(n *ast.IfStatement) {
+	b.eval(n.Test.Expr)
+	then, after := b.newBlock(), b.newBlock()
+	els := after
+	if n.Alternate != nil {
+		els = b.newBlock()
+	}
+	b.branch(n.Test.Expr, then, els)
+
+	b.cur = then
+	b.stmt(n.Consequent.Stmt, nil)
+	b.edge(b.cur, after, EdgeKindNormal)
+	if n.Alternate != nil {
+		b.cur = els
+		b.stmt(n.Alternate.Stmt, nil)
+		b.edge(b.cur, after, EdgeKindNormal)
+	}
+	b.cur = after
+}
+
+func (b *builder) whileStmt(n *ast.WhileStatement, labels []string) {
+	head, body, after := b.newBlock(), b.newBlock(), b.newBlock()
+	b.goTo(head)
+	b.eval(n.Test.Expr)
+	b.branch(n.Test.Expr, body, after)
+
+	b.cur = body
+	b.loop(n.Body, labels, after, head)
+	b.edge(b.cur, head, EdgeKindNormal)
+	b.cur = after
+}
+
+func (b *builder) doWhileStmt(n *ast.DoWhileStatement, labels []string) {
+	body, test, after := b.newBlock(), b.newBlock(), b.newBlock()
+	b.goTo(body)
+	b.loop(n.Body, labels, after, test)
+
+	b.goTo(test)
+	b.eval(n.Test.Expr)
+	b.branch(n.Test.Expr, body, after)
+	b.cur = after
+}
+
+func (b *builder) forStmt(n *ast.ForStatement, labels []string) {
+	if n.Initializer != nil {
+		b.eval(n.Initializer.Initializer)
+	}
+	// The parser leaves an empty expression for a missing test or update.
+	var test, update ast.Expr
+	if n.Test != nil {
+		test = n.Test.Expr
+	}
+	if n.Update != nil {
+		update = n.Update.Expr
+	}
+
+	head, body, after := b.newBlock(), b.newBlock(), b.newBlock()
+	cont := head
+	if update != nil {
+		cont = b.newBlock()
+	}
+
+	b.goTo(head)
+	if test != nil {
+		b.eval(test)
+		b.branch(test, body, after)
+	} else {
+		b.edge(b.cur, body, EdgeKindNormal)
+	}
+
+	b.cur = body
+	b.loop(n.Body, labels, after, cont)
+	if update != nil {
+		b.goTo(cont)
+		b.eval(update)
+	}
+	b.edge(b.cur, head, EdgeKindNormal)
+	b.cur = after
+}
+
+// forIntoStmt builds a for-in or for-of loop. The head branches to the body,
+// which starts by assigning the next value to into, or leaves the loop.
+func (b *builder) forIntoStmt(into *ast.ForInto, source *ast.Expression, body *ast.Statement, labels []string) {
+	b.eval(source.Expr)
+	head, blk, after := b.newBlock(), b.newBlock(), b.newBlock()
+	b.goTo(head)
+	b.edge(head, blk, EdgeKindTrue)
+	b.edge(head, after, EdgeKindFalse)
+
+	b.cur = blk
+	switch into := into.Into.(type) {
+	case *ast.VariableDeclaration:
+		b.add(into)
+	case *ast.Expression:
+		b.add(into.Expr)
+	}
+	b.loop(body, labels, after, head)
+	b.edge(b.cur, head, EdgeKindNormal)
+	b.cur = after
+}
+
+// switchStmt builds a switch statement as a chain of blocks testing the
+// cases in order, each branching to its body or to the next test. The bodies
+// fall through to one another.
+func (b *builder) switchStmt(n *ast.SwitchStatement) {
+	b.eval(n.Discriminant.Expr)
+	after := b.newBlock()
+	bodies := make([]*Block, len(n.Body))
+	for i := range bodies {
+		bodies[i] = b.newBlock()
+	}
+
+	for i, c := range n.Body {
+		if c.Test == nil {
+			continue
+		}
+		b.eval(c.Test.Expr)
+		next := b.newBlock()
+		b.branch(c.Test.Expr, bodies[i], next)
+		b.cur = next
+	}
+	if n.Default >= 0 {
+		b.edge(b.cur, bodies[n.Default], EdgeKindNormal)
+	} else {
+		b.edge(b.cur, after, EdgeKindNormal)
+	}
+
+	b.targets = append(b.targets, &target{brk: after, unlabeled: true, tries: len(b.tries)})
+	for i, c := range n.Body {
+		if i > 0 {
+			b.edge(b.cur, bodies[i], EdgeKindNormal)
+		}
+		b.cur = bodies[i]
+		b.stmts(c.Consequent)
+	}
+	b.targets = b.targets[:len(b.targets)-1]
+	if len(n.Body) > 0 {
+		b.edge(b.cur, after, EdgeKindNormal)
+	}
+	b.cur = after
+}
+
+func (b *builder) tryStmt(n *ast.TryStatement) {
+	t := &tryContext{}
+	if n.Catch != nil {
+		t.catch = b.newBlock()
+	}
+	if n.Finally != nil {
+		t.finally = b.newBlock()
+	}
+	after := b.newBlock()
+
+	b.tries = append(b.tries, t)
+	b.goTo(b.newBlock())
+	b.stmts(n.Body.List)
+	ends := []*Block{b.cur}
+	if n.Catch != nil {
+		t.part = tryPartCatch
+		b.cur = t.catch
+		if n.Catch.Parameter != nil {
+			b.add(n.Catch.Parameter)
+		}
+		b.stmts(n.Catch.Body.List)
+		ends = append(ends, b.cur)
+	}
+	t.part = tryPartFinally
+	b.tries = b.tries[:len(b.tries)-1]
+
+	if n.Finally == nil {
+		for _, end := range ends {
+			b.edge(end, after, EdgeKindNormal)
+		}
+		b.cur = after
+		return
+	}
+
+	normal := false
+	for _, end := range ends {
+		if b.live(end) {
+			b.edge(end, t.finally, EdgeKindNormal)
+			normal = true
+		}
+	}
+	b.cur = t.finally
+	b.stmts(n.Finally.List)
+	for _, j := range t.pending {
+		if j.throw {
+			b.throw(b.cur)
+		} else {
+			b.jump(b.cur, j)
+		}
+	}
+	// The finally block goes on after the statement only if it was entered
+	// by the end of the try block or catch clause.
+	if normal {
+		b.edge(b.cur, after, EdgeKindNormal)
+	}
+	b.cur = after
+}
+
+// eval adds the evaluation of n to the graph. If n contains short-circuit
+// operators, its parts are evaluated in order first.
+func (b *builder) eval(n ast.VisitableNode) {
+	if e, ok := n.(*ast.Expression); ok {
+		n = e.Expr
+	}
+	if !shortCircuits(n) {
+		b.add(n)
+		return
+	}
+
+	switch n := n.(type) {
+	case *ast.BinaryExpression:
+		switch n.Operator {
+		case token.LogicalAnd, token.LogicalOr, token.Coalesce:
+			b.logical(n)
+			return
+		}
+	case *ast.ConditionalExpression:
+		b.conditional(n)
+		return
+	case *ast.AssignExpression:
+		if !isMember(n.Left.Expr) {
+			// The target is not evaluated but written.
+			b.eval(n.Right.Expr)
+			b.add(n)
+			return
+		}
+	}
+
+	c := &childCollector{}
+	c.V = c
+	n.VisitChildrenWith(c)
+	for _, e := range c.children {
+		b.eval(e)
+	}
+	b.add(n)
+}
+
+func (b *builder) logical(n *ast.BinaryExpression) {
+	b.eval(n.Left.Expr)
+	rhs, join := b.newBlock(), b.newBlock()
+	switch n.Operator {
+	case token.LogicalAnd:
+		b.branch(n.Left.Expr, rhs, join)
+	case token.LogicalOr:
+		b.branch(n.Left.Expr, join, rhs)
+	case token.Coalesce:
+		b.cur.Nullish = true
+		b.branch(n.Left.Expr, rhs, join)
+	}
+
+	b.cur = rhs
+	b.eval(n.Right.Expr)
+	b.goTo(join)
+	b.add(n)
+}
+
+func (b *builder) conditional(n *ast.ConditionalExpression) {
+	b.eval(n.Test.Expr)
+	cons, alt, join := b.newBlock(), b.newBlock(), b.newBlock()
+	b.branch(n.Test.Expr, cons, alt)
+
+	b.cur = cons
+	b.eval(n.Consequent.Expr)
+	b.edge(b.cur, join, EdgeKindNormal)
+	b.cur = alt
+	b.eval(n.Alternate.Expr)
+	b.goTo(join)
+	b.add(n)
+}
+
+// shortCircuits reports whether n contains short-circuit operators outside
+// nested functions and classes.
+func shortCircuits(n ast.VisitableNode) bool {
+	f := &shortCircuitFinder{}
+	f.V = f
+	n.VisitWith(f)
+	return f.found
+}
+
+type shortCircuitFinder struct {
+	ast.NoopVisitor
+
+	found bool
+}
+
+func (f *shortCircuitFinder) VisitBinaryExpression(n *ast.BinaryExpression) {
+	switch n.Operator {
+	case token.LogicalAnd, token.LogicalOr, token.Coalesce:
+		f.found = true
+		return
+	}
+	n.VisitChildrenWith(f)
+}
+
+func (f *shortCircuitFinder) VisitConditionalExpression(n *ast.ConditionalExpression) {
+	f.found = true
+}
+
+func (f *shortCircuitFinder) VisitFunctionLiteral(n *ast.FunctionLiteral) {}
+
+func (f *shortCircuitFinder) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {}
+
+func (f *shortCircuitFinder) VisitClassLiteral(n *ast.ClassLiteral) {}
+
+func isMember(n ast.Expr) bool {
+	switch n.(type) {
+	case *ast.MemberExpression, *ast.PrivateDotExpression:
+		return true
+	}
+	return false
+}
+
+// childCollector collects the outermost expressions below a node, but for
+// the empty ones of array holes and those in binding patterns, which are not
+// split.
+type childCollector struct {
+	ast.NoopVisitor
+
+	children []*ast.Expression
+}
+
+func (c *childCollector) VisitBindingTarget(n *ast.BindingTarget) {}
+
+func (c *childCollector) VisitExpression(n *ast.Expression) {
+	if n.Expr != nil {
+		c.children = append(c.children, n)
+	}
+}
@@ -0,0 +1,165 @@
+// Package cfg builds control flow graphs of the functions of a program.
+//
+// A graph is made of basic blocks holding the statements and expressions
+// evaluated one after the other, linked by edges to the blocks control may
+// go to next. Control statements contribute their tests rather than
+// themselves, and expressions using &&, ||, ?? or ?: are split into the
+// blocks evaluating their operands.
+package cfg
+
+import (
+	"fmt"
+
+	"github.com/t14raptor/go-fast/ast"
+)
+
+// Graph is the control flow graph of a function or program.
+type Graph struct {
+	// Node is the *ast.Program, *ast.FunctionLiteral,
+	// *ast.ArrowFunctionLiteral, *ast.ClassStaticBlock or *ast.FieldDefinition
+	// the graph is built from.
+	Node ast.VisitableNode
+
+	// Entry is the first block and Exit the block returns, uncaught throws
+	// and the end of the body go to. Exit holds no nodes.
+	Entry  *Block
+	Exit   *Block
+	Blocks []*Block
+}
+
+// Block is a basic block.
+type Block struct {
+	// Index is the position of the block in the Blocks of its graph.
+	Index int
+
+	// Nodes holds the statements and expressions evaluated in order. An
+	// expression made of short-circuit operands comes after the blocks
+	// evaluating them, and stands for the operation combining their values.
+	// Assignment targets other than member expressions are not evaluated on
+	// their own, and the default values of binding patterns are not split.
+	Nodes []ast.VisitableNode
+
+	// Cond is set on blocks branching on the value of an expression, their
+	// last node: EdgeKindTrue edges are taken if it is truthy and
+	// EdgeKindFalse ones otherwise. If Nullish is set, the test is whether it
+	// is null or undefined instead, as for the left operand of ??. The test
+	// of a case clause is whether it equals the discriminant. The head of a
+	// for-in or for-of loop branches without Cond, to the body while values
+	// are left.
+	Cond    ast.Expr
+	Nullish bool
+
+	Succs []*Edge
+	Preds []*Edge
+
+	// Live is set if the block can be reached from the entry. The nodes of
+	// other blocks are dead code.
+	Live bool
+}
+
+// EdgeKind tells when control goes along an edge.
+type EdgeKind int
+
+const (
+	EdgeKindNormal EdgeKind = iota
+	EdgeKindTrue
+	EdgeKindFalse
+	// EdgeKindException edges go from blocks that may throw to the handler
+	// catching the exception, or from a throw statement.
+	EdgeKindException
+)
+
+var edgeKindNames = [...]string{
+	EdgeKindNormal:    "normal",
+	EdgeKindTrue:      "true",
+	EdgeKindFalse:     "false",
+	EdgeKindException: "exception",
+}
+
+func (k EdgeKind) String() string { return edgeKindNames[k] }
+
+// Edge is a transfer of control between two blocks.
+type Edge struct {
+	From, To *Block
+	Kind     EdgeKind
+}
+
+// Build builds the control flow graph of n, which must be an *ast.Program,
+// *ast.FunctionLiteral, *ast.ArrowFunctionLiteral, *ast.ClassStaticBlock or
+// *ast.FieldDefinition. The graph of a field evaluates its initializer, and
+// is empty if it has none. Nested functions are left out: they appear as the
+// expressions or declarations creating them.
+//
+// Blocks in a try statement have an exception edge to its catch clause, or
+// finally block, and so do throw statements. Jumps leaving a finally block go
+// through it, and its end has an edge to every place they go to.
+func Build(n ast.VisitableNode) *Graph {
+	b := newBuilder(n)
+	switch n := n.(type) {
+	case *ast.Program:
+		b.stmts(n.Body)
+	case *ast.FunctionLiteral:
+		b.params(&n.ParameterList)
+		b.stmts(n.Body.List)
+	case *ast.ArrowFunctionLiteral:
+		b.params(&n.ParameterList)
+		switch body := n.Body.Body.(type) {
+		case *ast.BlockStatement:
+			b.stmts(body.List)
+		case *ast.Expression:
+			b.eval(body.Expr)
+		}
+	case *ast.ClassStaticBlock:
+		b.stmts(n.Block.List)
+	case *ast.FieldDefinition:
+		if n.Initializer != nil && n.Initializer.Expr != nil {
+			b.eval(n.Initializer.Expr)
+		}
+	default:
+		panic(fmt.Sprintf("cfg: cannot build the graph of a %T", n))
+	}
+	return b.finish()
+}
+
+// BuildAll builds the graphs of p and of the functions, class static blocks
+// and field initializers in it, in source order.
+func BuildAll(p *ast.Program) []*Graph {
+	f := &functionFinder{}
+	f.V = f
+	p.VisitChildrenWith(f)
+
+	graphs := []*Graph{Build(p)}
+	for _, fn := range f.functions {
+		graphs = append(graphs, Build(fn))
+	}
+	return graphs
+}
+
+type functionFinder struct {
+	ast.NoopVisitor
+
+	functions []ast.VisitableNode
+}
+
+func (f *functionFinder) VisitFunctionLiteral(n *ast.FunctionLiteral) {
+	f.functions = append(f.functions, n)
+	n.VisitChildrenWith(f)
+}
+
+func (f *functionFinder) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {
+	f.functions = append(f.functions, n)
+	n.VisitChildrenWith(f)
+}
+
+func (f *functionFinder) VisitClassStaticBlock(n *ast.ClassStaticBlock) {
+	f.functions = append(f.functions, n)
+	n.VisitChildrenWith(f)
+}
+
+func (f *functionFinder) VisitFieldDefinition(n *ast.FieldDefinition) {
+	n.Key.VisitWith(f)
+	if n.Initializer != nil && n.Initializer.Expr != nil {
+		f.functions = append(f.functions, n)
+		n.Initializer.VisitWith(f)
+	}
+}
@@ -0,0 +1,237 @@
+package cfg_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/cfg"
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/parser"
+)
+
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile(%q): %v", src, err)
+	}
+	return p
+}
+
+func blockName(g *cfg.Graph, b *cfg.Block) string {
+	switch b {
+	case g.Entry:
+		return "entry"
+	case g.Exit:
+		return "exit"
+	}
+	return fmt.Sprintf("b%d", b.Index)
+}
+
+// describe lists the blocks of g other than the exit, one per line, as
+// name: nodes -> successors.
+func describe(g *cfg.Graph) string {
+	var lines []string
+	for _, b := range g.Blocks {
+		if b == g.Exit {
+			continue
+		}
+		var nodes []string
+		for _, n := range b.Nodes {
+			nodes = append(nodes, strings.Join(strings.Fields(generator.Generate(n)), " "))
+		}
+		var succs []string
+		for _, e := range b.Succs {
+			s := blockName(g, e.To)
+			if e.Kind != cfg.EdgeKindNormal {
+				s += ":" + e.Kind.String()
+			}
+			succs = append(succs, s)
+		}
+		line := blockName(g, b) + ": " + strings.Join(nodes, " | ") + " -> " + strings.Join(succs, " ")
+		if !b.Live {
+			line += " (dead)"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkEdges checks that the edges of g are linked both ways and the blocks
+// numbered by position.
+func checkEdges(t *testing.T, g *cfg.Graph) {
+	t.Helper()
+	for i, b := range g.Blocks {
+		if b.Index != i {
+			t.Errorf("block %d has index %d", i, b.Index)
+		}
+		for _, e := range b.Succs {
+			if e.From != b || !slices.Contains(e.To.Preds, e) {
+				t.Errorf("edge %s -> %s is not among the predecessors of its target", blockName(g, e.From), blockName(g, e.To))
+			}
+		}
+		for _, e := range b.Preds {
+			if e.To != b || !slices.Contains(e.From.Succs, e) {
+				t.Errorf("edge %s -> %s is not among the successors of its source", blockName(g, e.From), blockName(g, e.To))
+			}
+		}
+	}
+	if len(g.Exit.Nodes) != 0 || len(g.Exit.Succs) != 0 {
+		t.Errorf("the exit block has nodes or successors")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string // the graph of the last function, or of the program
+	}{
+		{`a(); if (b) c(); else d(); e();`, `
+entry: a(); | b -> b2:true b4:false
+b2: c(); -> b3
+b3: e(); -> exit
+b4: d(); -> b3`},
+		{`while (a) { if (b) break; c(); } d();`, `
+entry:  -> b2
+b2: a -> b3:true b4:false
+b3: b -> b5:true b6:false
+b4: d(); -> exit
+b5: break; -> b4
+b6: c(); -> b2`},
+		{`do { a(); } while (b); c();`, `
+entry:  -> b2
+b2: a(); -> b3
+b3: b -> b2:true b4:false
+b4: c(); -> exit`},
+		{`for (const k in o) { a(k); } b();`, `
+entry: o -> b2
+b2:  -> b3:true b4:false
+b3: const k; | a(k); -> b2
+b4: b(); -> exit`},
+		{`x = a && b || c;`, `
+entry: a -> b2:true b3:false
+b2: b -> b3
+b3: a && b -> b5:true b4:false
+b4: c -> b5
+b5: a && b || c | x = a && b || c | x = a && b || c; -> exit`},
+		{`x = a ?? b;`, `
+entry: a -> b2:true b3:false
+b2: b -> b3
+b3: a ?? b | x = a ?? b | x = a ?? b; -> exit`},
+		{`x = a ? b : c;`, `
+entry: a -> b2:true b3:false
+b2: b -> b4
+b3: c -> b4
+b4: a ? b : c | x = a ? b : c | x = a ? b : c; -> exit`},
+		// Case tests in order, then fallthrough from a() to b().
+		{`switch (x) { case 1: a(); case 2: b(); break; default: c(); }`, `
+entry: x | 1 -> b3:true b6:false
+b2:  -> exit
+b3: a(); -> b4
+b4: b(); | break; -> b2
+b5: c(); -> b2
+b6: 2 -> b4:true b7:false
+b7:  -> b5`},
+		{`outer: for (;;) { for (;;) { if (a) continue outer; break outer; } } d();`, `
+entry:  -> b2
+b2:  -> b3
+b3:  -> b5
+b4: d(); -> exit
+b5:  -> b6
+b6: a -> b7:true b8:false
+b7: continue outer; -> b2
+b8: break outer; -> b4`},
+		{`try { a(); } catch (e) { b(); } finally { c(); } d();`, `
+entry:  -> b5
+b2: e | b(); -> b3:exception b3
+b3: c(); -> exit:exception b4
+b4: d(); -> exit
+b5: a(); -> b2:exception b3`},
+		{`try { throw e; } catch (e) { a(); }`, `
+entry:  -> b4
+b2: e | a(); -> exit:exception b3
+b3:  -> exit
+b4: throw e; -> b2:exception`},
+		// The return goes through the finally block.
+		{`function f() { try { return a(); } finally { b(); } }`, `
+entry:  -> b3
+b2: b(); -> exit:exception exit
+b3: return a(); -> b2:exception b2`},
+		{`function f() { return; a(); }`, `
+entry: return; -> exit
+b2: a(); ->  (dead)`},
+		{`throw e; a();`, `
+entry: throw e; -> exit:exception
+b2: a(); ->  (dead)`},
+		{`export const a = f(); export default b;`, `
+entry: const a = f(); | b -> exit`},
+		// The parameters are evaluated on entry.
+		{`x => x ? 1 : 2;`, `
+entry: (x) | x -> b2:true b3:false
+b2: 1 -> b4
+b3: 2 -> b4
+b4: x ? 1 : 2 -> exit`},
+	}
+	for _, tt := range tests {
+		graphs := cfg.BuildAll(parse(t, tt.src))
+		g := graphs[len(graphs)-1]
+		checkEdges(t, g)
+		if got, want := describe(g), strings.TrimPrefix(tt.want, "\n"); got != want {
+			t.Errorf("graph of %s:\n%s\nwant\n%s", tt.src, got, want)
+		}
+	}
+}
+
+func TestBuildAll(t *testing.T) {
+	p := parse(t, `function f() { return () => 1; } class C { static { a(); } x = a ? b : c; [k] = () => 1; y; m() {} }`)
+	graphs := cfg.BuildAll(p)
+	var got []string
+	for _, g := range graphs {
+		got = append(got, fmt.Sprintf("%T", g.Node))
+	}
+	want := "*ast.Program *ast.FunctionLiteral *ast.ArrowFunctionLiteral *ast.ClassStaticBlock " +
+		"*ast.FieldDefinition *ast.FieldDefinition *ast.ArrowFunctionLiteral *ast.FunctionLiteral"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("BuildAll built graphs of %s, want %s", strings.Join(got, " "), want)
+	}
+
+	field := `entry: a -> b2:true b3:false
+b2: b -> b4
+b3: c -> b4
+b4: a ? b : c -> exit`
+	if got := describe(graphs[4]); got != field {
+		t.Errorf("graph of the initializer of x:\n%s\nwant\n%s", got, field)
+	}
+	if got := describe(graphs[5]); got != "entry: () => 1 -> exit" {
+		t.Errorf("graph of the initializer of [k]:\n%s", got)
+	}
+}
+
+func TestBuildPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.HasPrefix(fmt.Sprint(r), "cfg: ") {
+			t.Errorf("Build(*ast.BlockStatement) panicked with %v", r)
+		}
+	}()
+	cfg.Build(&ast.BlockStatement{})
+}
+
+func TestDot(t *testing.T) {
+	g := cfg.BuildAll(parse(t, `function f() { try { if (a) throw e; } catch (e) {} return; b(); }`))[1]
+	dot := g.Dot()
+	for _, want := range []string{
+		"digraph cfg {\n",
+		`[label="entry\l`,
+		`[label="exit\l"]`,
+		`[label=true]`,
+		`[label=exception style=dashed]`,
+		`\lb();\l" color=gray fontcolor=gray]`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Dot() = %s\nwant it to contain %s", dot, want)
+		}
+	}
+}
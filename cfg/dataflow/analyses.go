@@ -0,0 +1,112 @@
+package dataflow
+
+import "github.com/t14raptor/go-fast/ast"
+
+// ReachingDefinitions computes the definitions of v that may reach each
+// point of its graph: those with a path to it along which the binding is not
+// defined again. Facts are sets of Def indexes. The definitions on entry
+// reach the points the bindings may not have been assigned yet.
+func ReachingDefinitions(v *Variables) *Result[Set] {
+	return Solve(v.Graph, &Problem[Set]{
+		Direction: DirectionForward,
+		Boundary: func() Set {
+			var s Set
+			for i := range v.Bindings {
+				s.add(i)
+			}
+			return s
+		},
+		Top:   func() Set { return Set{} },
+		Meet:  Set.Union,
+		Equal: Set.Equal,
+		Transfer: func(n ast.VisitableNode, f Set) Set {
+			accesses := v.accesses[n]
+			if len(accesses) == 0 {
+				return f
+			}
+			f = f.clone()
+			for _, a := range accesses {
+				if a.def != nil {
+					v.define(&f, a.def)
+				}
+			}
+			return f
+		},
+	})
+}
+
+// define adds d to the reaching definitions f, replacing the other
+// definitions of its binding unless it may not happen.
+func (v *Variables) define(f *Set, d *Def) {
+	if !d.May {
+		f.removeAll(v.defs[v.index[d.Binding]])
+	}
+	f.add(d.Index)
+}
+
+// Liveness computes the bindings of v that are live at each point of its
+// graph: those whose value may be read along some path from it before being
+// defined again. Facts are sets of indexes into Bindings.
+func Liveness(v *Variables) *Result[Set] {
+	return Solve(v.Graph, &Problem[Set]{
+		Direction: DirectionBackward,
+		Boundary:  func() Set { return Set{} },
+		Top:       func() Set { return Set{} },
+		Meet:      Set.Union,
+		Equal:     Set.Equal,
+		Transfer: func(n ast.VisitableNode, f Set) Set {
+			accesses := v.accesses[n]
+			if len(accesses) == 0 {
+				return f
+			}
+			f = f.clone()
+			for i := len(accesses) - 1; i >= 0; i-- {
+				switch a := accesses[i]; {
+				case a.use != nil:
+					f.add(v.index[a.use.Binding])
+				case !a.def.May:
+					f.remove(v.index[a.def.Binding])
+				}
+			}
+			return f
+		},
+	})
+}
+
+// Chains links the definitions and uses of a graph.
+type Chains struct {
+	// Defs holds the definitions reaching each use, and Uses the uses each
+	// definition reaches, in index order. A definition reaching no use is a
+	// dead store.
+	Defs map[*Use][]*Def
+	Uses map[*Def][]*Use
+}
+
+// BuildChains builds the def-use and use-def chains of v from its reaching
+// definitions.
+func BuildChains(v *Variables) *Chains {
+	c := &Chains{
+		Defs: make(map[*Use][]*Def),
+		Uses: make(map[*Def][]*Use),
+	}
+	reaching := ReachingDefinitions(v)
+	for _, blk := range v.Graph.Blocks {
+		reaching.Walk(blk, func(n ast.VisitableNode, before, _ Set) {
+			// Definitions earlier in the node reach the uses after them.
+			f := before.clone()
+			for _, a := range v.accesses[n] {
+				if a.def != nil {
+					v.define(&f, a.def)
+					continue
+				}
+				u := a.use
+				for _, i := range f.Intersection(v.defs[v.index[u.Binding]]).Elems() {
+					d := v.Defs[i]
+					c.Defs[u] = append(c.Defs[u], d)
+					c.Uses[d] = append(c.Uses[d], u)
+				}
+			}
+		})
+	}
+	return c
+}
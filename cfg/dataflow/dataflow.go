@@ -0,0 +1,229 @@
+// Package dataflow solves data-flow problems over the control flow graphs
+// built by package cfg, and computes reaching definitions, liveness and
+// def-use chains for the bindings found by package resolver.
+package dataflow
+
+import (
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/cfg"
+)
+
+// Direction tells which way facts flow through a graph.
+type Direction int
+
+const (
+	DirectionForward Direction = iota
+	DirectionBackward
+)
+
+var directionNames = [...]string{
+	DirectionForward:  "forward",
+	DirectionBackward: "backward",
+}
+
+func (d Direction) String() string { return directionNames[d] }
+
+// Problem describes a data-flow problem over facts of type F. The functions
+// must not modify the facts they are given.
+type Problem[F any] struct {
+	Direction Direction
+
+	// Boundary returns the fact on entry to the graph for a forward problem,
+	// and on exit from it for a backward one.
+	Boundary func() F
+	// Top returns the fact blocks start from, the identity of Meet.
+	Top func() F
+	// Meet combines the facts reaching a point along several paths.
+	Meet func(a, b F) F
+	// Transfer returns the fact past n given the fact before it, in the
+	// direction of the problem.
+	Transfer func(n ast.VisitableNode, f F) F
+	Equal    func(a, b F) bool
+}
+
+// Result is the solution of a problem: the facts at the start and end of
+// each block, indexed by Block.Index.
+type Result[F any] struct {
+	In  []F
+	Out []F
+
+	graph   *cfg.Graph
+	problem *Problem[F]
+	// exc holds the meet of the facts at every point of each block of a
+	// forward problem, which flows along its exception edges: the block may
+	// throw from any of its nodes.
+	exc []F
+}
+
+// Solve solves p over g with a worklist, until the facts stop changing.
+//
+// Exception edges carry the facts of every point of the block they leave.
+// Going backward, the facts at the start of the handlers reached by a block
+// meet those at every point of the block.
+func Solve[F any](g *cfg.Graph, p *Problem[F]) *Result[F] {
+	n := len(g.Blocks)
+	r := &Result[F]{
+		In:      make([]F, n),
+		Out:     make([]F, n),
+		graph:   g,
+		problem: p,
+		exc:     make([]F, n),
+	}
+	for i := range g.Blocks {
+		r.In[i], r.Out[i], r.exc[i] = p.Top(), p.Top(), p.Top()
+	}
+
+	order := postorder(g)
+	if p.Direction == DirectionForward {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+	queued := make([]bool, n)
+	for _, blk := range order {
+		queued[blk.Index] = true
+	}
+
+	for len(order) > 0 {
+		blk := order[0]
+		order = order[1:]
+		queued[blk.Index] = false
+
+		if p.Direction == DirectionForward {
+			if !r.forward(blk) {
+				continue
+			}
+			for _, e := range blk.Succs {
+				if !queued[e.To.Index] {
+					queued[e.To.Index] = true
+					order = append(order, e.To)
+				}
+			}
+		} else {
+			if !r.backward(blk) {
+				continue
+			}
+			for _, e := range blk.Preds {
+				if !queued[e.From.Index] {
+					queued[e.From.Index] = true
+					order = append(order, e.From)
+				}
+			}
+		}
+	}
+	return r
+}
+
+// forward updates the facts of blk, and reports whether those flowing out of
+// it changed.
+func (r *Result[F]) forward(blk *cfg.Block) bool {
+	p := r.problem
+	in := p.Top()
+	if blk == r.graph.Entry {
+		in = p.Boundary()
+	}
+	for _, e := range blk.Preds {
+		if e.Kind == cfg.EdgeKindException {
+			in = p.Meet(in, r.exc[e.From.Index])
+		} else {
+			in = p.Meet(in, r.Out[e.From.Index])
+		}
+	}
+
+	f, exc := in, in
+	for _, n := range blk.Nodes {
+		f = p.Transfer(n, f)
+		exc = p.Meet(exc, f)
+	}
+	changed := !p.Equal(f, r.Out[blk.Index]) || !p.Equal(exc, r.exc[blk.Index])
+	r.In[blk.Index], r.Out[blk.Index], r.exc[blk.Index] = in, f, exc
+	return changed
+}
+
+// backward updates the facts of blk, and reports whether the fact at its
+// start changed.
+func (r *Result[F]) backward(blk *cfg.Block) bool {
+	p := r.problem
+	facts := r.facts(blk)
+	in := facts[0]
+	changed := !p.Equal(in, r.In[blk.Index])
+	r.In[blk.Index], r.Out[blk.Index] = in, facts[len(facts)-1]
+	return changed
+}
+
+// facts returns the facts of a backward problem before each node of blk and
+// at its end.
+func (r *Result[F]) facts(blk *cfg.Block) []F {
+	p := r.problem
+	out := p.Top()
+	if blk == r.graph.Exit {
+		out = p.Boundary()
+	}
+	handlers, throws := p.Top(), false
+	for _, e := range blk.Succs {
+		if e.Kind == cfg.EdgeKindException {
+			handlers = p.Meet(handlers, r.In[e.To.Index])
+			throws = true
+		} else {
+			out = p.Meet(out, r.In[e.To.Index])
+		}
+	}
+
+	facts := make([]F, len(blk.Nodes)+1)
+	f := out
+	if throws {
+		f = p.Meet(f, handlers)
+	}
+	facts[len(blk.Nodes)] = f
+	for i := len(blk.Nodes) - 1; i >= 0; i-- {
+		f = p.Transfer(blk.Nodes[i], f)
+		if throws {
+			f = p.Meet(f, handlers)
+		}
+		facts[i] = f
+	}
+	return facts
+}
+
+// Walk calls fn for the nodes of blk in order, with the facts before and
+// after each in execution order.
+func (r *Result[F]) Walk(blk *cfg.Block, fn func(n ast.VisitableNode, before, after F)) {
+	if r.problem.Direction == DirectionForward {
+		f := r.In[blk.Index]
+		for _, n := range blk.Nodes {
+			next := r.problem.Transfer(n, f)
+			fn(n, f, next)
+			f = next
+		}
+		return
+	}
+
+	facts := r.facts(blk)
+	for i, n := range blk.Nodes {
+		fn(n, facts[i], facts[i+1])
+	}
+}
+
+// postorder returns the blocks of g in postorder from the entry, followed by
+// those that cannot be reached.
+func postorder(g *cfg.Graph) []*cfg.Block {
+	order := make([]*cfg.Block, 0, len(g.Blocks))
+	seen := make([]bool, len(g.Blocks))
+	var visit func(blk *cfg.Block)
+	visit = func(blk *cfg.Block) {
+		seen[blk.Index] = true
+		for _, e := range blk.Succs {
+			if !seen[e.To.Index] {
+				visit(e.To)
+			}
+		}
+		order = append(order, blk)
+	}
+	visit(g.Entry)
+	for _, blk := range g.Blocks {
+		if !seen[blk.Index] {
+			visit(blk)
+		}
+	}
+	return order
+}
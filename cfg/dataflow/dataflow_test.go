@@ -0,0 +1,276 @@
+package dataflow_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/cfg"
+	"github.com/t14raptor/go-fast/cfg/dataflow"
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile(%q): %v", src, err)
+	}
+	return p
+}
+
+func blockName(g *cfg.Graph, b *cfg.Block) string {
+	switch b {
+	case g.Entry:
+		return "entry"
+	case g.Exit:
+		return "exit"
+	}
+	return fmt.Sprintf("b%d", b.Index)
+}
+
+// collect returns the variables of the graph of the first function of src,
+// or of the program if it has none.
+func collect(t *testing.T, src string) *dataflow.Variables {
+	t.Helper()
+	p := parse(t, src)
+	graphs := cfg.BuildAll(p)
+	g := graphs[0]
+	if len(graphs) > 1 {
+		g = graphs[1]
+	}
+	return dataflow.Collect(g, resolver.Resolve(p))
+}
+
+// label names the definitions of each binding x as x0 on entry, then x1, x2
+// and so on.
+func label(v *dataflow.Variables, d *dataflow.Def) string {
+	n := 0
+	for _, e := range v.Defs[:d.Index] {
+		if e.Binding == d.Binding {
+			n++
+		}
+	}
+	s := fmt.Sprintf("%s%d", d.Binding.Name, n)
+	if d.May {
+		s += "?"
+	}
+	return s
+}
+
+func generate(n ast.VisitableNode) string {
+	return strings.Join(strings.Fields(generator.Generate(n)), " ")
+}
+
+func names(v *dataflow.Variables, s dataflow.Set) string {
+	var out []string
+	for _, i := range s.Elems() {
+		out = append(out, v.Bindings[i].Name)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestCollect(t *testing.T) {
+	tests := []struct {
+		src      string
+		bindings string
+		defs     string
+	}{
+		// The parameters are defined by the parameter list.
+		{`function f(a) { var b = a; let c; c = b; }`, "a b c", "a0 b0 c0 a1 b1 c1 c2"},
+		// Declarations without value do not define vars.
+		{`function f() { var a; let b; a; b; }`, "a b", "a0 b0 b1"},
+		{`function f(a) { a += 1; a++; }`, "a", "a0 a1 a2 a3"},
+		{`function f(a, b = a) { [a = b, {b}] = c; }`, "a b", "a0 b0 a1 b1 a2 b2"},
+		// Assignments in default values may not happen.
+		{`function f(a, b = (a = 1)) { [c = (a = 2)] = d; }`, "a b", "a0 b0 a1 a2? b1 a3?"},
+		// Bindings used from nested functions, by eval or with statements
+		// and through arguments are not tracked.
+		{`function f(a) { var b; () => b; }`, "a", "a0 a1"},
+		{`function f(a) { var b; eval(""); b; }`, "", ""},
+		{`function f(a) { var b; with (o) b; }`, "a", "a0 a1"},
+		{`function f(a) { arguments; a = 1; }`, "", ""},
+		{`function f(a) { "use strict"; arguments; a = 1; }`, "a", "a0 a1 a2"},
+		{`var f = function g() { g; };`, "", ""},
+		{`for (const k of o) k;`, "k", "k0 k1"},
+		{`function f() { class C { [a = 1]() {} } let a; }`, "C a", "C0 a0 C1 a1 a2"},
+	}
+	for _, tt := range tests {
+		v := collect(t, tt.src)
+		var bindings, defs []string
+		for i, b := range v.Bindings {
+			bindings = append(bindings, b.Name)
+			if v.Index(b) != i {
+				t.Errorf("%s: Index(%s) = %d, want %d", tt.src, b.Name, v.Index(b), i)
+			}
+		}
+		for i, d := range v.Defs {
+			defs = append(defs, label(v, d))
+			if d.Index != i {
+				t.Errorf("%s: definition %d has index %d", tt.src, i, d.Index)
+			}
+		}
+		if got := strings.Join(bindings, " "); got != tt.bindings {
+			t.Errorf("%s: bindings %s, want %s", tt.src, got, tt.bindings)
+		}
+		if got := strings.Join(defs, " "); got != tt.defs {
+			t.Errorf("%s: definitions %s, want %s", tt.src, got, tt.defs)
+		}
+	}
+}
+
+func TestCollectEntry(t *testing.T) {
+	v := collect(t, `function f(a) { let b = a; }`)
+	for i, b := range v.Bindings {
+		d := v.Defs[i]
+		if d.Binding != b || d.Node != nil || d.Ident != b.Decls[0] || d.Block != v.Graph.Entry {
+			t.Errorf("the definition of %s on entry is %+v", b.Name, d)
+		}
+	}
+	if v.Index(nil) != -1 {
+		t.Errorf("Index(nil) = %d, want -1", v.Index(nil))
+	}
+}
+
+func TestChains(t *testing.T) {
+	tests := []struct {
+		src  string
+		uses string // the definitions reaching each use
+		dead string // the definitions reaching no use
+	}{
+		{`function f(a) { a = 1; a; a = 2; }`, "a:a2", "a0 a1 a3"},
+		{`function f(a) { if (b) a = 1; a; }`, "a:a1,a2", "a0"},
+		{`function f() { let a = 1; while (b) { a; a = 2; } }`, "a:a1,a2", "a0"},
+		{`function f(a) { a += a; }`, "a:a1 a:a1", "a0 a2"},
+		// A definition that may not happen does not replace the others.
+		{`function f(a, b = (a = 1)) { a; }`, "a:a1,a2?", "a0 b0 b1"},
+		{`function f(a = 1) { a; }`, "a:a1", "a0"},
+		// The uses after a definition in the same node see it.
+		{`function f() { let a; (a = 1, a); }`, "a:a2", "a0 a1"},
+		// The catch block may start after any point of the try block.
+		{`function f() { let a = 1; try { a = 2; g(); a = 3; } catch { a; } }`, "a:a1,a2,a3", "a0"},
+		{`function f() { let a = 1; try { return; } finally { a; } }`, "a:a1", "a0"},
+		{`function f() { var a = 1; for (a in o) a; }`, "a:a2", "a0 a1"},
+	}
+	for _, tt := range tests {
+		v := collect(t, tt.src)
+		c := dataflow.BuildChains(v)
+		var uses, dead []string
+		for _, u := range v.Uses {
+			var defs []string
+			for _, d := range c.Defs[u] {
+				defs = append(defs, label(v, d))
+				if !slices.Contains(c.Uses[d], u) {
+					t.Errorf("%s: %s reaches a use of %s but does not list it", tt.src, label(v, d), u.Binding.Name)
+				}
+			}
+			uses = append(uses, u.Binding.Name+":"+strings.Join(defs, ","))
+		}
+		for _, d := range v.Defs {
+			if len(c.Uses[d]) == 0 {
+				dead = append(dead, label(v, d))
+			}
+		}
+		if got := strings.Join(uses, " "); got != tt.uses {
+			t.Errorf("%s: uses %s, want %s", tt.src, got, tt.uses)
+		}
+		if got := strings.Join(dead, " "); got != tt.dead {
+			t.Errorf("%s: dead definitions %s, want %s", tt.src, got, tt.dead)
+		}
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string // the bindings live after each node
+	}{
+		{`function f(a, b) { let c = a; b = c; return b; }`, "(a, b): a | let c = a;: c | b = c;: b | return b;: "},
+		{`function f(a) { if (b) a = 1; return a; }`, "(a): a | b: a | a = 1;: a | return a;: "},
+		{`function f(a) { while (b) { a; } }`, "(a): a | b: a | a;: a"},
+		// The value of a is read by the handler after a = 2 may throw.
+		{`function f(a) { try { a = 2; g(); a = 3; } catch { a; } }`, "(a): a | a;:  | a = 2;: a | g();: a | a = 3;: a"},
+	}
+	for _, tt := range tests {
+		v := collect(t, tt.src)
+		live := dataflow.Liveness(v)
+		var out []string
+		for _, blk := range v.Graph.Blocks {
+			live.Walk(blk, func(n ast.VisitableNode, _, after dataflow.Set) {
+				out = append(out, fmt.Sprintf("%s: %s", generate(n), names(v, after)))
+			})
+		}
+		if got := strings.Join(out, " | "); got != tt.want {
+			t.Errorf("%s: liveness %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+// distance returns the problem of the fewest nodes evaluated from the entry,
+// or before the exit.
+func distance(dir dataflow.Direction) *dataflow.Problem[int] {
+	return &dataflow.Problem[int]{
+		Direction: dir,
+		Boundary:  func() int { return 0 },
+		Top:       func() int { return 1 << 30 },
+		Meet: func(a, b int) int {
+			if b < a {
+				return b
+			}
+			return a
+		},
+		Transfer: func(n ast.VisitableNode, f int) int { return f + 1 },
+		Equal:    func(a, b int) bool { return a == b },
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		src      string
+		forward  string // the facts at the start and end of each block
+		backward string
+	}{
+		{`a(); if (b) c(); d();`, "entry:0-2 exit:3-3 b2:2-3 b3:2-3", "entry:3-1 exit:0-0 b2:2-1 b3:1-0"},
+		{`while (a) b();`, "entry:0-0 exit:1-1 b2:0-1 b3:1-2 b4:1-1", "entry:1-1 exit:0-0 b2:1-0 b3:2-1 b4:0-0"},
+		// The handler may start after any node of the try block, and any
+		// node of the handler may throw to the exit.
+		{`try { a(); b(); } catch { c(); }`, "entry:0-0 exit:0-0 b2:0-1 b3:1-1 b4:0-2", "entry:0-0 exit:0-0 b2:0-0 b3:0-0 b4:0-0"},
+	}
+	for _, tt := range tests {
+		g := cfg.Build(parse(t, tt.src))
+		for dir, want := range map[dataflow.Direction]string{
+			dataflow.DirectionForward:  tt.forward,
+			dataflow.DirectionBackward: tt.backward,
+		} {
+			r := dataflow.Solve(g, distance(dir))
+			var got []string
+			for _, blk := range g.Blocks {
+				got = append(got, fmt.Sprintf("%s:%d-%d", blockName(g, blk), r.In[blk.Index], r.Out[blk.Index]))
+			}
+			if strings.Join(got, " ") != want {
+				t.Errorf("%s: %s facts %s, want %s", tt.src, dir, strings.Join(got, " "), want)
+			}
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	g := cfg.Build(parse(t, `a(); b(); c();`))
+	for dir, want := range map[dataflow.Direction]string{
+		dataflow.DirectionForward:  "a(); 0-1 b(); 1-2 c(); 2-3",
+		dataflow.DirectionBackward: "a(); 3-2 b(); 2-1 c(); 1-0",
+	} {
+		r := dataflow.Solve(g, distance(dir))
+		var got []string
+		r.Walk(g.Entry, func(n ast.VisitableNode, before, after int) {
+			got = append(got, fmt.Sprintf("%s %d-%d", generate(n), before, after))
+		})
+		if strings.Join(got, " ") != want {
+			t.Errorf("%s walk %s, want %s", dir, strings.Join(got, " "), want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package dataflow
+
+import "math/bits"
+
+// Set is a set of small non-negative integers, such as the indexes of
+// definitions or bindings. Its methods do not modify their receiver.
+type Set struct {
+	words []uint64
+}
+
+// Has reports whether i is in s.
+func (s Set) Has(i int) bool {
+	w := i / 64
+	return w < len(s.words) && s.words[w]&(1<<(i%64)) != 0
+}
+
+// Len returns the number of elements of s.
+func (s Set) Len() int {
+	n := 0
+	for _, w := range s.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Elems returns the elements of s in increasing order.
+func (s Set) Elems() []int {
+	var elems []int
+	for i, w := range s.words {
+		for w != 0 {
+			elems = append(elems, i*64+bits.TrailingZeros64(w))
+			w &= w - 1
+		}
+	}
+	return elems
+}
+
+// Union returns the elements in s or t.
+func (s Set) Union(t Set) Set {
+	if len(s.words) < len(t.words) {
+		s, t = t, s
+	}
+	u := s.clone()
+	for i, w := range t.words {
+		u.words[i] |= w
+	}
+	return u
+}
+
+// Difference returns the elements in s but not in t.
+func (s Set) Difference(t Set) Set {
+	d := s.clone()
+	d.removeAll(t)
+	return d
+}
+
+// Intersection returns the elements in both s and t.
+func (s Set) Intersection(t Set) Set {
+	if len(s.words) > len(t.words) {
+		s, t = t, s
+	}
+	n := s.clone()
+	for i := range n.words {
+		n.words[i] &= t.words[i]
+	}
+	return n
+}
+
+// Equal reports whether s and t have the same elements.
+func (s Set) Equal(t Set) bool {
+	if len(s.words) < len(t.words) {
+		s, t = t, s
+	}
+	for i, w := range s.words {
+		if i < len(t.words) && t.words[i] != w || i >= len(t.words) && w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Set) clone() Set {
+	return Set{words: append([]uint64(nil), s.words...)}
+}
+
+// add and remove modify s, which must not be shared.
+
+func (s *Set) add(i int) {
+	for len(s.words) <= i/64 {
+		s.words = append(s.words, 0)
+	}
+	s.words[i/64] |= 1 << (i % 64)
+}
+
+func (s *Set) remove(i int) {
+	if w := i / 64; w < len(s.words) {
+		s.words[w] &^= 1 << (i % 64)
+	}
+}
+
+func (s *Set) removeAll(t Set) {
+	for i := range s.words {
+		if i < len(t.words) {
+			s.words[i] &^= t.words[i]
+		}
+	}
+}
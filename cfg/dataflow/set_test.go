@@ -0,0 +1,90 @@
+package dataflow_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/cfg/dataflow"
+)
+
+func TestSet(t *testing.T) {
+	// a=1 and b=1 are definitions 4 and 5, after those of the parameters.
+	v := collect(t, `function f(a, b) { if (c) a = 1; else b = 1; }`)
+	reaching := dataflow.ReachingDefinitions(v)
+	g := v.Graph
+	var then, els dataflow.Set
+	for _, blk := range g.Blocks {
+		for _, n := range blk.Nodes {
+			switch generate(n) {
+			case "a = 1;":
+				then = reaching.Out[blk.Index]
+			case "b = 1;":
+				els = reaching.Out[blk.Index]
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		s    dataflow.Set
+		want []int
+	}{
+		{"then", then, []int{3, 4}},
+		{"else", els, []int{2, 5}},
+		{"exit", reaching.In[g.Exit.Index], []int{2, 3, 4, 5}},
+		{"then ∪ else", then.Union(els), []int{2, 3, 4, 5}},
+		{"then ∩ else", then.Intersection(els), nil},
+		{"exit \\ then", reaching.In[g.Exit.Index].Difference(then), []int{2, 5}},
+		{"then ∩ exit", then.Intersection(reaching.In[g.Exit.Index]), []int{3, 4}},
+		{"empty", dataflow.Set{}, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Elems(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+		if tt.s.Len() != len(tt.want) {
+			t.Errorf("%s has %d elements, want %d", tt.name, tt.s.Len(), len(tt.want))
+		}
+		for i := 0; i < 8; i++ {
+			if tt.s.Has(i) != slices.Contains(tt.want, i) {
+				t.Errorf("%s.Has(%d) = %v", tt.name, i, !slices.Contains(tt.want, i))
+			}
+		}
+	}
+	if !then.Union(els).Equal(reaching.In[g.Exit.Index]) || then.Equal(els) {
+		t.Errorf("Equal is wrong")
+	}
+	if !then.Intersection(els).Equal(dataflow.Set{}) || !(dataflow.Set{}).Equal(then.Intersection(els)) {
+		t.Errorf("an empty set is not equal to the zero Set")
+	}
+}
+
+func TestSetLarge(t *testing.T) {
+	// Sets span several words past 64 definitions.
+	var src strings.Builder
+	src.WriteString("function f(a) { if (b) {")
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&src, "a = %d;", i)
+	}
+	src.WriteString("} }")
+	v := collect(t, src.String())
+	reaching := dataflow.ReachingDefinitions(v)
+	entry, exit := reaching.Out[v.Graph.Entry.Index], reaching.In[v.Graph.Exit.Index]
+	if got := exit.Elems(); !slices.Equal(got, []int{1, 101}) {
+		t.Errorf("reaching definitions %v, want [1 101]", got)
+	}
+	if !exit.Has(101) || exit.Has(100) || exit.Has(1000) {
+		t.Errorf("Has is wrong for %v", exit.Elems())
+	}
+	if u := entry.Union(exit); !slices.Equal(u.Elems(), []int{1, 101}) || !u.Equal(exit) || !exit.Equal(u) || exit.Equal(entry) {
+		t.Errorf("%v ∪ %v = %v", entry.Elems(), exit.Elems(), u.Elems())
+	}
+	if d := exit.Difference(entry); !slices.Equal(d.Elems(), []int{101}) {
+		t.Errorf("%v \\ %v = %v", exit.Elems(), entry.Elems(), d.Elems())
+	}
+	if i := entry.Intersection(exit); !slices.Equal(i.Elems(), []int{1}) {
+		t.Errorf("%v ∩ %v = %v", entry.Elems(), exit.Elems(), i.Elems())
+	}
+}
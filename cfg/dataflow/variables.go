@@ -0,0 +1,389 @@
+package dataflow
+
+import (
+	"slices"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/cfg"
+	"github.com/t14raptor/go-fast/resolver"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Def is a definition: a point where a binding is given a value.
+type Def struct {
+	// Index is the position of the definition in the Defs of its Variables.
+	Index   int
+	Binding *resolver.Binding
+	// Ident is the identifier assigned or declared, and Node the node of
+	// Block evaluating it. The definitions on entry, giving each binding the
+	// value it has when the function starts, have a nil Node and the first
+	// declaration of the binding as Ident.
+	Ident *ast.Identifier
+	Node  ast.VisitableNode
+	Block *cfg.Block
+	// May is set if evaluating Node may not assign the binding: the
+	// assignment is in a default value or an operand that is not always
+	// evaluated. Such definitions do not replace the earlier ones.
+	May bool
+}
+
+// Use is a point where the value of a binding is read.
+type Use struct {
+	// Index is the position of the use in the Uses of its Variables.
+	Index   int
+	Binding *resolver.Binding
+	Ident   *ast.Identifier
+	Node    ast.VisitableNode
+	Block   *cfg.Block
+}
+
+// Variables holds the definitions and uses of the bindings a graph tracks.
+//
+// A graph tracks the bindings declared in its function and used only there:
+// not from nested functions, not by direct eval or with statements, and not
+// aliased by the arguments object. Imports, the arguments object and the
+// names of function expressions are left out as well. Bindings of the
+// program scope of a script may also be assigned by other scripts, which is
+// not taken into account.
+type Variables struct {
+	Graph *cfg.Graph
+	// Bindings holds the tracked bindings, in order of first appearance in
+	// the blocks of the graph.
+	Bindings []*resolver.Binding
+	// Defs starts with the definitions on entry, in the order of Bindings.
+	Defs []*Def
+	Uses []*Use
+
+	index map[*resolver.Binding]int
+	// defs holds the indexes of the definitions of each binding.
+	defs []Set
+	// accesses holds the definitions and uses of each node, in evaluation
+	// order.
+	accesses map[ast.VisitableNode][]access
+}
+
+// access is a definition or a use.
+type access struct {
+	def *Def
+	use *Use
+}
+
+// Collect finds the definitions and uses in g of the bindings it tracks.
+func Collect(g *cfg.Graph, r *resolver.Resolver) *Variables {
+	v := &Variables{
+		Graph:    g,
+		index:    make(map[*resolver.Binding]int),
+		accesses: make(map[ast.VisitableNode][]access),
+	}
+	w := &walker{
+		resolver: r,
+		graph:    g,
+		vars:     v,
+		nodes:    make(map[ast.VisitableNode]bool),
+		heads:    make(map[*ast.VariableDeclaration]bool),
+		refs:     make(map[*ast.Identifier]*resolver.Reference),
+		tracked:  make(map[*resolver.Binding]bool),
+	}
+	w.V = w
+
+	h := &headFinder{heads: w.heads}
+	h.V = h
+	g.Node.VisitChildrenWith(h)
+
+	for _, blk := range g.Blocks {
+		for _, n := range blk.Nodes {
+			w.nodes[n] = true
+		}
+	}
+	for _, blk := range g.Blocks {
+		for _, n := range blk.Nodes {
+			w.block, w.node = blk, n
+			n.VisitWith(w)
+		}
+	}
+
+	// Number the definitions on entry first.
+	entry := make([]*Def, len(v.Bindings))
+	for i, b := range v.Bindings {
+		entry[i] = &Def{Binding: b, Ident: b.Decls[0], Block: g.Entry}
+	}
+	v.Defs = append(entry, v.Defs...)
+	v.defs = make([]Set, len(v.Bindings))
+	for i, d := range v.Defs {
+		d.Index = i
+		v.defs[v.index[d.Binding]].add(i)
+	}
+	return v
+}
+
+// Index returns the position of b in Bindings, or -1 if b is not tracked.
+func (v *Variables) Index(b *resolver.Binding) int {
+	if i, ok := v.index[b]; ok {
+		return i
+	}
+	return -1
+}
+
+type walker struct {
+	ast.NoopVisitor
+
+	resolver *resolver.Resolver
+	graph    *cfg.Graph
+	vars     *Variables
+
+	// nodes holds the nodes of the graph, whose accesses are their own,
+	// and heads the declarations in the heads of for-in and for-of loops.
+	nodes map[ast.VisitableNode]bool
+	heads map[*ast.VariableDeclaration]bool
+
+	refs    map[*ast.Identifier]*resolver.Reference
+	tracked map[*resolver.Binding]bool
+
+	block *cfg.Block
+	node  ast.VisitableNode
+	// may is set in operands that are not always evaluated, and undefined
+	// in the targets of var declarations without value.
+	may       int
+	undefined int
+}
+
+// track reports whether the graph tracks b, adding it to Bindings the first
+// time.
+func (w *walker) track(b *resolver.Binding) bool {
+	if t, ok := w.tracked[b]; ok {
+		return t
+	}
+	t := w.trackable(b)
+	w.tracked[b] = t
+	if t {
+		w.vars.index[b] = len(w.vars.Bindings)
+		w.vars.Bindings = append(w.vars.Bindings, b)
+		for _, ref := range b.References {
+			w.refs[ref.Ident] = ref
+		}
+	}
+	return t
+}
+
+func (w *walker) trackable(b *resolver.Binding) bool {
+	switch b.Kind {
+	case resolver.DeclKindArguments, resolver.DeclKindImport:
+		return false
+	}
+	if b.Scope.Kind == resolver.ScopeKindName || b.Scope.Eval || len(b.Decls) == 0 {
+		return false
+	}
+	fn := function(b.Scope)
+	if fn.Node != w.graph.Node || b.Kind == resolver.DeclKindParam && fn.MappedArguments {
+		return false
+	}
+	for _, ref := range b.References {
+		if ref.With || function(ref.Scope) != fn {
+			return false
+		}
+	}
+	return true
+}
+
+// function returns the scope of the function or program s is in.
+func function(s *resolver.Scope) *resolver.Scope {
+	for s.Kind != resolver.ScopeKindFunction && s.Kind != resolver.ScopeKindProgram {
+		s = s.Parent
+	}
+	return s
+}
+
+func (w *walker) def(b *resolver.Binding, id *ast.Identifier) {
+	d := &Def{Binding: b, Ident: id, Node: w.node, Block: w.block, May: w.may > 0}
+	w.vars.Defs = append(w.vars.Defs, d)
+	w.vars.accesses[w.node] = append(w.vars.accesses[w.node], access{def: d})
+}
+
+func (w *walker) use(b *resolver.Binding, id *ast.Identifier) {
+	u := &Use{Index: len(w.vars.Uses), Binding: b, Ident: id, Node: w.node, Block: w.block}
+	w.vars.Uses = append(w.vars.Uses, u)
+	w.vars.accesses[w.node] = append(w.vars.accesses[w.node], access{use: u})
+}
+
+// maybe visits n, which is not always evaluated.
+func (w *walker) maybe(n ast.VisitableNode) {
+	w.may++
+	n.VisitWith(w)
+	w.may--
+}
+
+func (w *walker) VisitIdentifier(n *ast.Identifier) {
+	b := w.resolver.Binding(n)
+	if b == nil || !w.track(b) {
+		return
+	}
+	if ref := w.refs[n]; ref != nil {
+		if ref.Kind != resolver.RefKindWrite {
+			w.use(b, n)
+		}
+		if ref.Kind != resolver.RefKindRead {
+			w.def(b, n)
+		}
+		return
+	}
+	if w.undefined == 0 && slices.Contains(b.Decls, n) {
+		w.def(b, n)
+	}
+}
+
+func (w *walker) VisitExpression(n *ast.Expression) {
+	// Parts of the node evaluated on their own are skipped.
+	if n.Expr != nil && !w.nodes[n.Expr] {
+		n.Expr.VisitWith(w)
+	}
+}
+
+func (w *walker) VisitVariableDeclaration(n *ast.VariableDeclaration) {
+	for i := range n.List {
+		d := &n.List[i]
+		if d.Initializer != nil {
+			d.Initializer.VisitWith(w)
+		}
+		if n.Token == token.Var && d.Initializer == nil && !w.heads[n] {
+			w.undefined++
+			d.Target.VisitWith(w)
+			w.undefined--
+		} else {
+			d.Target.VisitWith(w)
+		}
+	}
+}
+
+func (w *walker) VisitParameterList(n *ast.ParameterList) {
+	for i := range n.List {
+		d := &n.List[i]
+		if d.Initializer != nil {
+			w.maybe(d.Initializer)
+		}
+		d.Target.VisitWith(w)
+	}
+	if n.Rest != nil {
+		n.Rest.VisitWith(w)
+	}
+}
+
+func (w *walker) VisitAssignExpression(n *ast.AssignExpression) {
+	if id, ok := n.Left.Expr.(*ast.Identifier); ok && n.Operator != token.Assign {
+		// The value is read before the right operand is evaluated.
+		if b := w.resolver.Binding(id); b != nil && w.track(b) {
+			w.use(b, id)
+			n.Right.VisitWith(w)
+			w.def(b, id)
+			return
+		}
+	}
+	n.Right.VisitWith(w)
+	n.Left.VisitWith(w)
+}
+
+func (w *walker) VisitArrayPattern(n *ast.ArrayPattern) {
+	for i := range n.Elements {
+		w.target(&n.Elements[i])
+	}
+	if n.Rest != nil {
+		n.Rest.VisitWith(w)
+	}
+}
+
+func (w *walker) VisitObjectPattern(n *ast.ObjectPattern) {
+	for _, prop := range n.Properties {
+		switch prop := prop.Prop.(type) {
+		case *ast.PropertyShort:
+			if prop.Initializer != nil && prop.Initializer.Expr != nil {
+				w.maybe(prop.Initializer)
+			}
+			prop.Name.VisitWith(w)
+		case *ast.PropertyKeyed:
+			if prop.Computed {
+				prop.Key.VisitWith(w)
+			}
+			w.target(prop.Value)
+		}
+	}
+	if n.Rest != nil {
+		n.Rest.VisitWith(w)
+	}
+}
+
+// target visits a pattern element, whose default value is evaluated only if
+// the value is undefined.
+func (w *walker) target(n *ast.Expression) {
+	if def, ok := n.Expr.(*ast.AssignExpression); ok {
+		w.maybe(def.Right)
+		def.Left.VisitWith(w)
+		return
+	}
+	n.VisitWith(w)
+}
+
+func (w *walker) VisitBinaryExpression(n *ast.BinaryExpression) {
+	n.Left.VisitWith(w)
+	switch n.Operator {
+	case token.LogicalAnd, token.LogicalOr, token.Coalesce:
+		w.maybe(n.Right)
+	default:
+		n.Right.VisitWith(w)
+	}
+}
+
+func (w *walker) VisitConditionalExpression(n *ast.ConditionalExpression) {
+	n.Test.VisitWith(w)
+	w.maybe(n.Consequent)
+	w.maybe(n.Alternate)
+}
+
+func (w *walker) VisitOptionalChain(n *ast.OptionalChain) {
+	w.maybe(n.Base)
+}
+
+// Nested functions and class members other than computed keys run
+// separately.
+
+func (w *walker) VisitFunctionLiteral(n *ast.FunctionLiteral) {}
+
+func (w *walker) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {}
+
+func (w *walker) VisitClassStaticBlock(n *ast.ClassStaticBlock) {}
+
+func (w *walker) VisitFieldDefinition(n *ast.FieldDefinition) {
+	if n.Computed {
+		n.Key.VisitWith(w)
+	}
+}
+
+func (w *walker) VisitMethodDefinition(n *ast.MethodDefinition) {
+	if n.Computed {
+		n.Key.VisitWith(w)
+	}
+}
+
+// headFinder collects the declarations in the heads of for-in and for-of
+// loops, which assign a value on each iteration.
+type headFinder struct {
+	ast.NoopVisitor
+
+	heads map[*ast.VariableDeclaration]bool
+}
+
+func (h *headFinder) VisitForInStatement(n *ast.ForInStatement) {
+	if decl, ok := n.Into.Into.(*ast.VariableDeclaration); ok {
+		h.heads[decl] = true
+	}
+	n.VisitChildrenWith(h)
+}
+
+func (h *headFinder) VisitForOfStatement(n *ast.ForOfStatement) {
+	if decl, ok := n.Into.Into.(*ast.VariableDeclaration); ok {
+		h.heads[decl] = true
+	}
+	n.VisitChildrenWith(h)
+}
+
+func (h *headFinder) VisitFunctionLiteral(n *ast.FunctionLiteral) {}
+
+func (h *headFinder) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {}
@@ -0,0 +1,64 @@
+package cfg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/t14raptor/go-fast/generator"
+)
+
+// maxLabel is the length nodes are cut to in DOT labels.
+const maxLabel = 40
+
+// Dot returns the graph in the Graphviz DOT language, with a box per block
+// listing its nodes. Dead blocks are gray, and exception edges dashed.
+func (g *Graph) Dot() string {
+	var sb strings.Builder
+	sb.WriteString("digraph cfg {\n")
+	sb.WriteString("\tnode [shape=box fontname=monospace];\n")
+	for _, blk := range g.Blocks {
+		name := fmt.Sprintf("B%d", blk.Index)
+		switch blk {
+		case g.Entry:
+			name = "entry"
+		case g.Exit:
+			name = "exit"
+		}
+		label := name + `\l`
+		for _, n := range blk.Nodes {
+			label += escape(generator.Generate(n)) + `\l`
+		}
+		if blk.Cond != nil && blk.Nullish {
+			label += `?? nullish\l`
+		}
+		fmt.Fprintf(&sb, "\tb%d [label=\"%s\"", blk.Index, label)
+		if !blk.Live {
+			sb.WriteString(" color=gray fontcolor=gray")
+		}
+		sb.WriteString("];\n")
+	}
+	for _, blk := range g.Blocks {
+		for _, e := range blk.Succs {
+			fmt.Fprintf(&sb, "\tb%d -> b%d", e.From.Index, e.To.Index)
+			switch e.Kind {
+			case EdgeKindTrue, EdgeKindFalse:
+				fmt.Fprintf(&sb, " [label=%s]", e.Kind)
+			case EdgeKindException:
+				fmt.Fprintf(&sb, " [label=%s style=dashed]", e.Kind)
+			}
+			sb.WriteString(";\n")
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// escape makes the source of a node fit on one line of a DOT label.
+func escape(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxLabel {
+		s = string(r[:maxLabel-3]) + "..."
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
@@ -102,7 +102,9 @@ func (g *GenVisitor) VisitAssignExpression(n *ast.AssignExpression) {
 func (g *GenVisitor) VisitArrayPattern(n *ast.ArrayPattern) {
 	g.out.WriteString("[")
 	for i, elem := range n.Elements {
-		g.gen(elem.Expr)
+		if elem.Expr != nil {
+			g.gen(elem.Expr)
+		}
 		if i < len(n.Elements)-1 {
 			g.out.WriteString(", ")
 		}
@@ -132,7 +134,12 @@ func (g *GenVisitor) VisitBinaryExpression(n *ast.BinaryExpression) {
 	if pn, ok := g.p.(*ast.BinaryExpression); ok {
 		operatorPrecedence := n.Operator.Precedence(true)
 		parentOperatorPrecedence := pn.Operator.Precedence(true)
-		if operatorPrecedence < parentOperatorPrecedence || operatorPrecedence == parentOperatorPrecedence && pn.Right.Expr == n {
+		// ** groups to the right, the other operators to the left.
+		inner := pn.Right.Expr == n
+		if pn.Operator == token.Exponent {
+			inner = pn.Left.Expr == n
+		}
+		if operatorPrecedence < parentOperatorPrecedence || operatorPrecedence == parentOperatorPrecedence && inner {
 			g.out.WriteString("(")
 			defer g.out.WriteString(")")
 		}
@@ -286,6 +293,12 @@ func (g *GenVisitor) VisitMemberProperty(n *ast.MemberProperty) {
 	}
 }
 
+func (g *GenVisitor) VisitMetaProperty(n *ast.MetaProperty) {
+	g.gen(n.Meta)
+	g.out.WriteString(".")
+	g.gen(n.Property)
+}
+
 func (g *GenVisitor) VisitEmptyStatement(n *ast.EmptyStatement) {
 	g.out.WriteString(";")
 }
@@ -484,18 +497,28 @@ func (g *GenVisitor) VisitPropertyKeyed(n *ast.PropertyKeyed) {
 	if n.Kind == ast.PropertyKindGet || n.Kind == ast.PropertyKindSet {
 		g.out.WriteString(string(n.Kind))
 		g.out.WriteString(" ")
-		g.gen(n.Key.Expr)
+		g.propertyKey(n)
 		f := n.Value.Expr.(*ast.FunctionLiteral)
 		g.gen(&f.ParameterList)
 		g.out.WriteString(" ")
 		g.gen(f.Body)
 		return
 	}
-	g.gen(n.Key.Expr)
+	g.propertyKey(n)
 	g.out.WriteString(": ")
 	g.gen(n.Value.Expr)
 }
 
+func (g *GenVisitor) propertyKey(n *ast.PropertyKeyed) {
+	if n.Computed {
+		g.out.WriteString("[")
+		g.gen(n.Key.Expr)
+		g.out.WriteString("]")
+		return
+	}
+	g.gen(n.Key.Expr)
+}
+
 func (g *GenVisitor) VisitProgram(n *ast.Program) {
 	for _, b := range n.Body {
 		g.gen(b.Stmt)
@@ -587,6 +610,11 @@ func (g *GenVisitor) VisitTryStatement(n *ast.TryStatement) {
 }
 
 func (g *GenVisitor) VisitUnaryExpression(n *ast.UnaryExpression) {
+	// The base of ** cannot be a unary expression: -2 ** 2 is a syntax error.
+	if pn, ok := g.p.(*ast.BinaryExpression); ok && pn.Operator == token.Exponent && pn.Left.Expr == n {
+		g.out.WriteString("(")
+		defer g.out.WriteString(")")
+	}
 	g.out.WriteString(n.Operator.String())
 	if len(n.Operator.String()) > 2 {
 		g.out.WriteString(" ")
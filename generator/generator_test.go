@@ -0,0 +1,38 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/parser"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"function f() { return new.target; }", "return new.target;"},
+		{"var [, a] = b;", "var [, a] = b;"},
+		{"[a, , b] = c;", "[a, , b] = c"},
+		{"x = {[k]: 1, get [g]() {}};", "[k]: 1"},
+		{"x = {[k]: 1, get [g]() {}};", "get [g]()"},
+		{"x = a ** b ** c;", "x = a ** b ** c;"},
+		{"x = (a ** b) ** c;", "x = (a ** b) ** c;"},
+		{"x = (a - b) ** c;", "x = (a - b) ** c;"},
+		{"x = a - b ** c;", "x = a - b ** c;"},
+		{"x = (-8) ** y;", "x = (-8) ** y;"},
+		{"x = (typeof a) ** 2;", "x = (typeof a) ** 2;"},
+		{"x = -(a ** b);", "x = -(a ** b);"},
+	}
+	for _, tt := range tests {
+		p, err := parser.ParseFile(tt.src)
+		if err != nil {
+			t.Fatalf("ParseFile(%q): %v", tt.src, err)
+		}
+		if got := generator.Generate(p); !strings.Contains(got, tt.want) {
+			t.Errorf("Generate(%q) = %q, want it to contain %q", tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package jsvalue
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// FromExpr returns the value of e, if e is a literal, a negated number,
+// void 0, or an array or object literal of such values. The identifiers
+// undefined, NaN and Infinity are taken to be the globals unless the
+// resolver bound them. Regular expressions, spread elements, properties that
+// are not data properties or set __proto__, and strings escaping lone
+// surrogates have no value.
+//
+// Array and object literals evaluate to new objects, so each call returns
+// new *Array and *Object values.
+func FromExpr(e ast.Expr) (Value, bool) {
+	switch e := e.(type) {
+	case *ast.NullLiteral:
+		return Null{}, true
+	case *ast.BooleanLiteral:
+		return Boolean(e.Value), true
+	case *ast.NumberLiteral:
+		return Number(e.Value), true
+	case *ast.StringLiteral:
+		if s, ok := stringValue(e); ok {
+			return String(s), true
+		}
+	case *ast.Identifier:
+		if e.ScopeContext != 0 {
+			break
+		}
+		switch e.Name {
+		case "undefined":
+			return Undefined{}, true
+		case "NaN":
+			return Number(math.NaN()), true
+		case "Infinity":
+			return Number(math.Inf(1)), true
+		}
+	case *ast.UnaryExpression:
+		x, ok := FromExpr(e.Operand.Expr)
+		switch {
+		case !ok:
+		case e.Operator == token.Minus && x.Kind() == KindNumber:
+			return -x.(Number), true
+		case e.Operator == token.Void:
+			return Undefined{}, true
+		}
+	case *ast.ArrayLiteral:
+		a := &Array{Elements: make([]Value, len(e.Value))}
+		for i, elem := range e.Value {
+			if elem.Expr == nil {
+				continue
+			}
+			v, ok := FromExpr(elem.Expr)
+			if !ok {
+				return nil, false
+			}
+			a.Elements[i] = v
+		}
+		return a, true
+	case *ast.ObjectLiteral:
+		o := &Object{}
+		for _, prop := range e.Value {
+			p, ok := prop.Prop.(*ast.PropertyKeyed)
+			if !ok || p.Kind != ast.PropertyKindValue {
+				return nil, false
+			}
+			key, ok := propertyKey(p)
+			if !ok {
+				return nil, false
+			}
+			v, ok := FromExpr(p.Value.Expr)
+			if !ok {
+				return nil, false
+			}
+			o.set(key, v)
+		}
+		return o, true
+	}
+	return nil, false
+}
+
+// propertyKey returns the key of p, if known.
+func propertyKey(p *ast.PropertyKeyed) (string, bool) {
+	if !p.Computed {
+		switch key := p.Key.Expr.(type) {
+		case *ast.StringLiteral:
+			// A __proto__ key sets the prototype instead.
+			name, ok := stringValue(key)
+			return name, ok && name != "__proto__"
+		case *ast.NumberLiteral:
+			return FormatNumber(key.Value, 10), true
+		}
+		return "", false
+	}
+	v, ok := FromExpr(p.Key.Expr)
+	if !ok {
+		return "", false
+	}
+	key, err := ToString(v)
+	return key, err == nil
+}
+
+// stringValue returns the value of lit, unless it escapes a lone surrogate.
+func stringValue(lit *ast.StringLiteral) (string, bool) {
+	s := ast.StringValue(lit.Value)
+	if lit.Raw != nil && strings.ContainsRune(s, utf8.RuneError) && LoneSurrogate(*lit.Raw) {
+		return "", false
+	}
+	return s, true
+}
+
+// LoneSurrogate reports whether raw, the source text of a string literal or
+// template element, escapes a surrogate code unit that is not part of a
+// pair. The parser decodes those to U+FFFD, and a String cannot hold them.
+func LoneSurrogate(raw string) bool {
+	// high is set after an escaped high surrogate, which the next code
+	// unit must pair with.
+	high := false
+	for i := 0; i < len(raw); {
+		if raw[i] != '\\' {
+			if high {
+				return true
+			}
+			i++
+			continue
+		}
+		unit, n := escapedUnit(raw[i:])
+		i += n
+		switch {
+		case unit >= 0xdc00 && unit <= 0xdfff:
+			if !high {
+				return true
+			}
+			high = false
+		case high:
+			return true
+		case unit >= 0xd800 && unit <= 0xdbff:
+			high = true
+		}
+	}
+	return high
+}
+
+// escapedUnit returns the code point of the \u escape s starts with and its
+// length, or -1 and the length of another escape.
+func escapedUnit(s string) (rune, int) {
+	if len(s) < 2 || s[1] != 'u' {
+		return -1, 2
+	}
+	if len(s) > 2 && s[2] == '{' {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return -1, len(s)
+		}
+		v, err := strconv.ParseUint(s[3:end], 16, 32)
+		if err != nil {
+			return -1, end + 1
+		}
+		return rune(v), end + 1
+	}
+	if len(s) < 6 {
+		return -1, len(s)
+	}
+	v, err := strconv.ParseUint(s[2:6], 16, 16)
+	if err != nil {
+		return -1, 6
+	}
+	return rune(v), 6
+}
+
+// set defines the property key of o, which keeps its place if o has it.
+func (o *Object) set(key string, v Value) {
+	for i := range o.Properties {
+		if o.Properties[i].Key == key {
+			o.Properties[i].Value = v
+			return
+		}
+	}
+	o.Properties = append(o.Properties, Property{Key: key, Value: v})
+}
+
+// ToExpr returns an expression evaluating to v, built with the build
+// package. Bigints, which have no literal in the tree, and arrays and objects
+// containing them or themselves have none.
+func ToExpr(v Value) (ast.Expr, bool) {
+	return toExpr(v, nil)
+}
+
+// toExpr converts v, an element of the arrays and objects in outer.
+func toExpr(v Value, outer []Value) (ast.Expr, bool) {
+	for _, o := range outer {
+		if o == v {
+			return nil, false
+		}
+	}
+	switch v := v.(type) {
+	case Undefined:
+		return build.Undefined(), true
+	case Null:
+		return build.Null(), true
+	case Boolean:
+		return build.Bool(bool(v)), true
+	case Number:
+		return build.Num(float64(v)), true
+	case String:
+		return build.Str(string(v)), true
+	case *Array:
+		outer = append(outer, v)
+		elems := make([]ast.Expr, len(v.Elements))
+		for i, elem := range v.Elements {
+			if elem == nil {
+				continue
+			}
+			e, ok := toExpr(elem, outer)
+			if !ok {
+				return nil, false
+			}
+			elems[i] = e
+		}
+		return build.Array(elems...), true
+	case *Object:
+		outer = append(outer, v)
+		props := make([]ast.Prop, len(v.Properties))
+		for i, p := range v.Properties {
+			e, ok := toExpr(p.Value, outer)
+			if !ok {
+				return nil, false
+			}
+			if p.Key == "__proto__" {
+				props[i] = build.ComputedProp(build.Str(p.Key), e)
+			} else {
+				props[i] = build.Prop(p.Key, e)
+			}
+		}
+		return build.Object(props...), true
+	}
+	return nil, false
+}
@@ -0,0 +1,162 @@
+package jsvalue_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/jsvalue"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+// expr parses and resolves src, and returns the value assigned in its last
+// statement.
+func expr(t *testing.T, src string) ast.Expr {
+	t.Helper()
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile(%q): %v", src, err)
+	}
+	resolver.Resolve(p)
+	return p.Body[len(p.Body)-1].Stmt.(*ast.ExpressionStatement).Expression.Expr.(*ast.AssignExpression).Right.Expr
+}
+
+func TestFromExpr(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string // the value, or "" if it has none
+	}{
+		{`x = null`, "null"},
+		{`x = true`, "true"},
+		{`x = 0x1f`, "31"},
+		{`x = -1.5`, "-1.5"},
+		{`x = -0`, "-0"},
+		{`x = -"1"`, ""},
+		{`x = "a"`, `"a"`},
+		{`x = "é"`, `"é"`},
+		{`x = "日本語"`, `"日本語"`},
+		{`x = "\u00e9"`, `"é"`},
+		{`x = "😀"`, `"😀"`},
+		{`x = "\u{1F600}"`, `"😀"`},
+		{`x = "�"`, `"�"`},
+		{`x = "\\uD800"`, `"\\uD800"`},
+		{`x = "\uD800"`, ""},
+		{`x = "\u{DC00}"`, ""},
+		{`x = "\uD800a"`, ""},
+		{`x = "\uD800\uDC00"`, `"𐀀"`},
+		{`x = {"\uD800": 1}`, ""},
+		{`x = void 0`, "undefined"},
+		{`x = void f()`, ""},
+		{`x = undefined`, "undefined"},
+		{`x = NaN`, "NaN"},
+		{`x = -Infinity`, "-Infinity"},
+		{`let undefined; x = undefined`, ""},
+		{`x = a`, ""},
+		{`x = /a/`, ""},
+		{`x = [1, , "é"]`, `[1, <hole>, "é"]`},
+		{`x = [a]`, ""},
+		{`x = [...b]`, ""},
+		{`x = {a: 1, "b": [], 2: 3, 1.5: 4, [-1]: 5, ["c" + ""]: 6}`, ""},
+		{`x = {a: 1, "b": [], 2: 3, 1.5: 4, [-1]: 5, ["c"]: 6, a: 7}`, `{"a": 7, "b": [], "2": 3, "1.5": 4, "-1": 5, "c": 6}`},
+		{`x = {é: 1, "ü": 2, ["ö"]: 3}`, `{"é": 1, "ü": 2, "ö": 3}`},
+		{`x = {__proto__: null}`, ""},
+		{`x = {"__proto__": null}`, ""},
+		{`x = {["__proto__"]: null}`, `{"__proto__": null}`},
+		{`x = {a}`, ""},
+		{`x = {get a() {}}`, ""},
+		{`x = {...o}`, ""},
+	}
+	for _, tt := range tests {
+		v, ok := jsvalue.FromExpr(expr(t, tt.src))
+		got := ""
+		if ok {
+			got = show(v)
+		}
+		if got != tt.want {
+			t.Errorf("FromExpr(%s) = %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestLoneSurrogate(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want bool
+	}{
+		{`"a"`, false},
+		{`"\uD800"`, true},
+		{`"\uDC00"`, true},
+		{`"𐀀"`, false},
+		{`"\uD800\uDC00"`, false},
+		{`"\uDC00\uD800"`, true},
+		{`"\uD800𐀀"`, true},
+		{`"\uD800x"`, true},
+		{`"\uD800\n"`, true},
+		{`"\u{D800}\u{DC00}"`, false},
+		{`"\u{D800}"`, true},
+		{`"\u{1F600}"`, false},
+		{`"\\uD800"`, false},
+		{`\uD800`, true},
+	}
+	for _, tt := range tests {
+		if got := jsvalue.LoneSurrogate(tt.raw); got != tt.want {
+			t.Errorf("LoneSurrogate(%s) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestFromExprNew(t *testing.T) {
+	e := expr(t, `x = [{}]`)
+	a, _ := jsvalue.FromExpr(e)
+	b, _ := jsvalue.FromExpr(e)
+	if jsvalue.StrictEqual(a, b) || jsvalue.StrictEqual(a.(*jsvalue.Array).Elements[0], b.(*jsvalue.Array).Elements[0]) {
+		t.Errorf("FromExpr returned the same objects twice")
+	}
+}
+
+func TestToExpr(t *testing.T) {
+	self := arr(num(1))
+	self.Elements = append(self.Elements, self)
+	tests := []struct {
+		v    jsvalue.Value
+		want string // the generated expression, or "" if it has none
+	}{
+		{undefined, "void 0"},
+		{null, "null"},
+		{jsvalue.Boolean(false), "false"},
+		{num(1.5), "1.5"},
+		{num(-2), "-2"},
+		{num(math.Copysign(0, -1)), "-0"},
+		{num(math.NaN()), "NaN"},
+		{num(math.Inf(-1)), "-Infinity"},
+		{num(1e21), "1e+21"},
+		{str(`a"b` + "\n"), `"a\"b\n"`},
+		{str("é"), `"é"`},
+		{big(1), ""},
+		{arr(num(1), nil, str("a")), `[1, , "a"]`},
+		{arr(big(1)), ""},
+		{self, ""},
+		{obj("a", num(1), "b-c", arr(), "__proto__", null), `{ a: 1, "b-c": [], ["__proto__"]: null }`},
+	}
+	for _, tt := range tests {
+		e, ok := jsvalue.ToExpr(tt.v)
+		got := ""
+		if ok {
+			got = strings.Join(strings.Fields(generator.Generate(e)), " ")
+		}
+		if got != tt.want {
+			t.Errorf("ToExpr(%s) = %s, want %s", show(tt.v), got, tt.want)
+		}
+		if !ok {
+			continue
+		}
+		// The expression evaluates to the value again.
+		back, ok := jsvalue.FromExpr(e)
+		if !ok || show(back) != show(tt.v) {
+			t.Errorf("FromExpr(ToExpr(%s)) = %s, %v", show(tt.v), show(back), ok)
+		}
+	}
+}
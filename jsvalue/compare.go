@@ -0,0 +1,182 @@
+package jsvalue
+
+import (
+	"math"
+	"math/big"
+	"unicode/utf16"
+)
+
+// StrictEqual reports whether x === y.
+func StrictEqual(x, y Value) bool {
+	if x.Kind() != y.Kind() {
+		return false
+	}
+	switch x := x.(type) {
+	case Number:
+		return x == y.(Number)
+	case BigInt:
+		return x.Int.Cmp(y.(BigInt).Int) == 0
+	case *Array:
+		return x == y.(*Array)
+	case *Object:
+		return x == y.(*Object)
+	}
+	// Undefined, Null, Boolean and String compare as Go values.
+	return x == y
+}
+
+// LooseEqual reports whether x == y.
+func LooseEqual(x, y Value) (bool, error) {
+	// Arrays are objects, so an array and an object compare by identity.
+	if x.Kind() == y.Kind() || object(x) && object(y) {
+		return StrictEqual(x, y), nil
+	}
+	if nullish(x) || nullish(y) {
+		return nullish(x) && nullish(y), nil
+	}
+	if object(x) {
+		prim, err := ToPrimitive(x, HintDefault)
+		if err != nil {
+			return false, err
+		}
+		return LooseEqual(prim, y)
+	}
+	if object(y) {
+		prim, err := ToPrimitive(y, HintDefault)
+		if err != nil {
+			return false, err
+		}
+		return LooseEqual(x, prim)
+	}
+
+	// The operands are primitives of different types: booleans and strings
+	// compare as numbers, or as bigints against bigints.
+	switch x := x.(type) {
+	case Boolean, String:
+		if y, ok := y.(BigInt); ok {
+			if x, ok := x.(String); ok {
+				i, ok := ParseBigInt(string(x))
+				return ok && i.Cmp(y.Int) == 0, nil
+			}
+		}
+		f, _ := ToNumber(x)
+		return LooseEqual(Number(f), y)
+	case BigInt:
+		return LooseEqual(y, x)
+	}
+	switch y := y.(type) {
+	case Boolean, String:
+		f, _ := ToNumber(y)
+		return LooseEqual(x, Number(f))
+	case BigInt:
+		return compareNumberBigInt(float64(x.(Number)), y.Int) == 0, nil
+	}
+	return false, nil
+}
+
+func nullish(v Value) bool {
+	return v.Kind() == KindUndefined || v.Kind() == KindNull
+}
+
+func object(v Value) bool {
+	return v.Kind() == KindArray || v.Kind() == KindObject
+}
+
+// unordered is returned by compareNumberBigInt for NaN.
+const unordered = 2
+
+// compareNumberBigInt compares f and i exactly, returning -1, 0 or 1, or
+// unordered if f is NaN.
+func compareNumberBigInt(f float64, i *big.Int) int {
+	switch {
+	case math.IsNaN(f):
+		return unordered
+	case math.IsInf(f, 0):
+		if f > 0 {
+			return 1
+		}
+		return -1
+	}
+	return new(big.Float).SetFloat64(f).Cmp(new(big.Float).SetInt(i))
+}
+
+// LessThan compares x < y as the IsLessThan operation does, returning
+// Boolean, or Undefined if the operands are not ordered, as when one is NaN.
+// x is converted to a primitive first.
+func LessThan(x, y Value) (Value, error) {
+	x, err := ToPrimitive(x, HintNumber)
+	if err != nil {
+		return nil, err
+	}
+	if y, err = ToPrimitive(y, HintNumber); err != nil {
+		return nil, err
+	}
+
+	xs, xString := x.(String)
+	ys, yString := y.(String)
+	switch {
+	case xString && yString:
+		return Boolean(compareStrings(string(xs), string(ys)) < 0), nil
+	case xString:
+		if y, ok := y.(BigInt); ok {
+			i, ok := ParseBigInt(string(xs))
+			if !ok {
+				return Undefined{}, nil
+			}
+			return Boolean(i.Cmp(y.Int) < 0), nil
+		}
+	case yString:
+		if x, ok := x.(BigInt); ok {
+			i, ok := ParseBigInt(string(ys))
+			if !ok {
+				return Undefined{}, nil
+			}
+			return Boolean(x.Int.Cmp(i) < 0), nil
+		}
+	}
+
+	if x, err = ToNumeric(x); err != nil {
+		return nil, err
+	}
+	if y, err = ToNumeric(y); err != nil {
+		return nil, err
+	}
+	var c int
+	switch x := x.(type) {
+	case Number:
+		if y, ok := y.(Number); ok {
+			if math.IsNaN(float64(x)) || math.IsNaN(float64(y)) {
+				return Undefined{}, nil
+			}
+			return Boolean(x < y), nil
+		}
+		c = compareNumberBigInt(float64(x), y.(BigInt).Int)
+	case BigInt:
+		if y, ok := y.(BigInt); ok {
+			return Boolean(x.Int.Cmp(y.Int) < 0), nil
+		}
+		c = compareNumberBigInt(float64(y.(Number)), x.Int)
+		if c != unordered {
+			c = -c
+		}
+	}
+	if c == unordered {
+		return Undefined{}, nil
+	}
+	return Boolean(c < 0), nil
+}
+
+// compareStrings compares a and b by their UTF-16 code units, as JavaScript
+// does.
+func compareStrings(a, b string) int {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			if ua[i] < ub[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(ua) - len(ub)
+}
@@ -0,0 +1,105 @@
+package jsvalue_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/t14raptor/go-fast/jsvalue"
+)
+
+func TestEqual(t *testing.T) {
+	a, o := arr(), obj()
+	nan := num(math.NaN())
+	tests := []struct {
+		x, y          jsvalue.Value
+		strict, loose string
+	}{
+		{undefined, undefined, "true", "true"},
+		{undefined, null, "false", "true"},
+		{null, num(0), "false", "false"},
+		{null, str(""), "false", "false"},
+		{nan, nan, "false", "false"},
+		{num(0), num(math.Copysign(0, -1)), "true", "true"},
+		{num(1), str("1"), "false", "true"},
+		{num(0), str(""), "false", "true"},
+		{num(0), str(" \n"), "false", "true"},
+		{str("a"), str("a"), "true", "true"},
+		{jsvalue.Boolean(true), num(1), "false", "true"},
+		{jsvalue.Boolean(true), str("1"), "false", "true"},
+		{jsvalue.Boolean(false), str("false"), "false", "false"},
+		{big(1), big(1), "true", "true"},
+		{big(1), num(1), "false", "true"},
+		{num(1.5), big(1), "false", "false"},
+		{big(1), str("1"), "false", "true"},
+		{str("0x10"), big(16), "false", "true"},
+		{str("1.0"), big(1), "false", "false"},
+		{jsvalue.Boolean(true), big(1), "false", "true"},
+		{nan, big(0), "false", "false"},
+		{a, a, "true", "true"},
+		{arr(), arr(), "false", "false"},
+		{a, o, "false", "false"},
+		{o, o, "true", "true"},
+		{arr(num(1)), num(1), "false", "true"},
+		{arr(num(1), num(2)), str("1,2"), "false", "true"},
+		{arr(), jsvalue.Boolean(false), "false", "true"},
+		{obj(), str("[object Object]"), "false", "true"},
+		{arr(), null, "false", "false"},
+		{obj("toString", num(1)), num(1), "false", "TypeError"},
+	}
+	for _, tt := range tests {
+		for _, swap := range []bool{false, true} {
+			x, y := tt.x, tt.y
+			if swap {
+				x, y = y, x
+			}
+			if got := show(jsvalue.Boolean(jsvalue.StrictEqual(x, y))); got != tt.strict {
+				t.Errorf("%s === %s is %s, want %s", show(x), show(y), got, tt.strict)
+			}
+			eq, err := jsvalue.LooseEqual(x, y)
+			if got := result(jsvalue.Boolean(eq), err); got != tt.loose {
+				t.Errorf("%s == %s is %s, want %s", show(x), show(y), got, tt.loose)
+			}
+		}
+	}
+}
+
+func TestLessThan(t *testing.T) {
+	tests := []struct {
+		x, y jsvalue.Value
+		want string
+	}{
+		{num(1), num(2), "true"},
+		{num(2), num(1), "false"},
+		{num(1), num(1), "false"},
+		{num(1), num(math.NaN()), "undefined"},
+		{undefined, num(1), "undefined"},
+		{null, num(1), "true"},
+		{num(math.Inf(-1)), num(-1e308), "true"},
+		{str("10"), str("9"), "true"},
+		{str("10"), num(9), "false"},
+		{str("a"), str("b"), "true"},
+		{str("a"), str("ab"), "true"},
+		{str("B"), str("a"), "true"},
+		// Strings compare by UTF-16 code units.
+		{str("\uffff"), str("\U0001F600"), "false"},
+		{str("é"), str("z"), "false"},
+		{str("abc"), num(1), "undefined"},
+		{big(1), big(2), "true"},
+		{big(1), num(1.5), "true"},
+		{num(1.5), big(1), "false"},
+		{num(math.Inf(1)), big(1), "false"},
+		{big(1), num(math.NaN()), "undefined"},
+		{str("1"), big(2), "true"},
+		{big(2), str("1"), "false"},
+		{str("x"), big(2), "undefined"},
+		{arr(num(1)), arr(num(2)), "true"},
+		{arr(num(2)), arr(num(10)), "false"},
+		{obj(), num(1), "undefined"},
+		{obj("toString", num(1)), num(1), "TypeError"},
+	}
+	for _, tt := range tests {
+		if got := result(jsvalue.LessThan(tt.x, tt.y)); got != tt.want {
+			t.Errorf("%s < %s is %s, want %s", show(tt.x), show(tt.y), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package jsvalue
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Hint is the type ToPrimitive prefers to convert an object to.
+type Hint int
+
+const (
+	HintDefault Hint = iota
+	HintNumber
+	HintString
+)
+
+var hintNames = [...]string{
+	HintDefault: "default",
+	HintNumber:  "number",
+	HintString:  "string",
+}
+
+func (h Hint) String() string { return hintNames[h] }
+
+// ToPrimitive converts v to a primitive value.
+//
+// The valueOf method of arrays and objects returns the object itself, so
+// whatever the hint they convert through toString: arrays join their
+// elements and objects become "[object Object]". An own toString property
+// shadows that method and, since it is not a function, makes the conversion
+// throw. Arrays containing themselves join as empty, as engines do.
+func ToPrimitive(v Value, hint Hint) (Value, error) {
+	return toPrimitive(v, nil)
+}
+
+// toPrimitive converts v, an element of the arrays being joined.
+func toPrimitive(v Value, joining []*Array) (Value, error) {
+	switch v := v.(type) {
+	case *Array:
+		s, err := join(v, joining)
+		if err != nil {
+			return nil, err
+		}
+		return String(s), nil
+	case *Object:
+		if _, ok := v.Get("toString"); ok {
+			return nil, typeError("Cannot convert object to primitive value")
+		}
+		return String("[object Object]"), nil
+	}
+	return v, nil
+}
+
+func join(a *Array, joining []*Array) (string, error) {
+	for _, b := range joining {
+		if a == b {
+			return "", nil
+		}
+	}
+	joining = append(joining, a)
+	strs := make([]string, len(a.Elements))
+	for i, elem := range a.Elements {
+		switch elem.(type) {
+		case nil, Undefined, Null:
+			continue
+		}
+		prim, err := toPrimitive(elem, joining)
+		if err != nil {
+			return "", err
+		}
+		if strs[i], err = ToString(prim); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(strs, ","), nil
+}
+
+// ToBoolean converts v to a boolean.
+func ToBoolean(v Value) bool {
+	switch v := v.(type) {
+	case Boolean:
+		return bool(v)
+	case Number:
+		return v != 0 && !math.IsNaN(float64(v))
+	case BigInt:
+		return v.Int.Sign() != 0
+	case String:
+		return v != ""
+	case *Array, *Object:
+		return true
+	}
+	return false
+}
+
+// ToNumber converts v to a number. Bigints do not convert.
+func ToNumber(v Value) (float64, error) {
+	switch v := v.(type) {
+	case Undefined:
+		return math.NaN(), nil
+	case Null:
+		return 0, nil
+	case Boolean:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case Number:
+		return float64(v), nil
+	case BigInt:
+		return 0, typeError("Cannot convert a BigInt value to a number")
+	case String:
+		return ParseNumber(string(v)), nil
+	}
+	prim, err := ToPrimitive(v, HintNumber)
+	if err != nil {
+		return 0, err
+	}
+	return ToNumber(prim)
+}
+
+// ToNumeric converts v to a Number or BigInt.
+func ToNumeric(v Value) (Value, error) {
+	prim, err := ToPrimitive(v, HintNumber)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := prim.(BigInt); ok {
+		return b, nil
+	}
+	f, err := ToNumber(prim)
+	if err != nil {
+		return nil, err
+	}
+	return Number(f), nil
+}
+
+// ToString converts v to a string.
+func ToString(v Value) (string, error) {
+	switch v := v.(type) {
+	case Undefined:
+		return "undefined", nil
+	case Null:
+		return "null", nil
+	case Boolean:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	case Number:
+		return FormatNumber(float64(v), 10), nil
+	case BigInt:
+		return v.Int.String(), nil
+	case String:
+		return string(v), nil
+	}
+	prim, err := ToPrimitive(v, HintString)
+	if err != nil {
+		return "", err
+	}
+	return ToString(prim)
+}
+
+// ToInt32 converts v to a number, then to a signed 32-bit integer modulo
+// 2^32.
+func ToInt32(v Value) (int32, error) {
+	u, err := ToUint32(v)
+	return int32(u), err
+}
+
+// ToUint32 converts v to a number, then to an unsigned 32-bit integer modulo
+// 2^32.
+func ToUint32(v Value) (uint32, error) {
+	f, err := ToNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	return uint32Of(f), nil
+}
+
+// uint32Of converts f to an unsigned 32-bit integer modulo 2^32.
+func uint32Of(f float64) uint32 {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	f = math.Mod(math.Trunc(f), 1<<32)
+	if f < 0 {
+		f += 1 << 32
+	}
+	return uint32(f)
+}
+
+// TypeOf returns the result of the typeof operator on v.
+func TypeOf(v Value) string {
+	switch v.Kind() {
+	case KindArray, KindNull:
+		return "object"
+	}
+	return v.Kind().String()
+}
+
+// isSpace reports whether r is white space or a line terminator, which the
+// conversions from strings trim.
+func isSpace(r rune) bool {
+	switch r {
+	case '\t', '\n', '\v', '\f', '\r', ' ', '\u00a0', '\u1680', '\u2028',
+		'\u2029', '\u202f', '\u205f', '\u3000', '\ufeff':
+		return true
+	}
+	return r >= '\u2000' && r <= '\u200a'
+}
+
+// ParseNumber converts s to a number as the Number function does: surrounding
+// white space is ignored, the empty string is 0, and strings that are not a
+// decimal, Infinity or a 0x, 0o or 0b integer are NaN.
+func ParseNumber(s string) float64 {
+	s = strings.TrimFunc(s, isSpace)
+	if s == "" {
+		return 0
+	}
+	if i, ok := parseInteger(s); ok {
+		f, _ := new(big.Float).SetInt(i).Float64()
+		return f
+	}
+	sign, unsigned := 1, s
+	switch s[0] {
+	case '-':
+		sign = -1
+		fallthrough
+	case '+':
+		unsigned = s[1:]
+	}
+	if unsigned == "Infinity" {
+		return math.Inf(sign)
+	}
+	if !isDecimal(unsigned) {
+		return math.NaN()
+	}
+	// Values out of range round to infinity, as they do in JavaScript.
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// parseInteger parses s if it is a 0x, 0o or 0b integer.
+func parseInteger(s string) (*big.Int, bool) {
+	if len(s) < 3 || s[0] != '0' {
+		return nil, false
+	}
+	base := 0
+	switch s[1] {
+	case 'x', 'X':
+		base = 16
+	case 'o', 'O':
+		base = 8
+	case 'b', 'B':
+		base = 2
+	default:
+		return nil, false
+	}
+	if strings.ContainsAny(s[2:], "+-_") {
+		return nil, false
+	}
+	return new(big.Int).SetString(s[2:], base)
+}
+
+// isDecimal reports whether s is digits with an optional fraction and
+// exponent.
+func isDecimal(s string) bool {
+	digits := func() int {
+		n := 0
+		for len(s) > 0 && s[0] >= '0' && s[0] <= '9' {
+			s = s[1:]
+			n++
+		}
+		return n
+	}
+	n := digits()
+	if len(s) > 0 && s[0] == '.' {
+		s = s[1:]
+		n += digits()
+	}
+	if n == 0 {
+		return false
+	}
+	if len(s) > 0 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+			s = s[1:]
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return s == ""
+}
+
+// ParseBigInt converts s to a bigint as the BigInt function does, reporting
+// whether s is an integer: decimal with an optional sign, or 0x, 0o or 0b.
+// Surrounding white space is ignored and the empty string is 0.
+func ParseBigInt(s string) (*big.Int, bool) {
+	s = strings.TrimFunc(s, isSpace)
+	if s == "" {
+		return new(big.Int), true
+	}
+	if i, ok := parseInteger(s); ok {
+		return i, true
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 || digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return nil, false
+	}
+	return new(big.Int).SetString(s, 10)
+}
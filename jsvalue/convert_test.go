@@ -0,0 +1,237 @@
+package jsvalue_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/t14raptor/go-fast/jsvalue"
+)
+
+var (
+	undefined = jsvalue.Undefined{}
+	null      = jsvalue.Null{}
+)
+
+func num(f float64) jsvalue.Value { return jsvalue.Number(f) }
+func str(s string) jsvalue.Value  { return jsvalue.String(s) }
+func big(x int64) jsvalue.Value   { return jsvalue.NewBigInt(x) }
+
+func arr(elems ...jsvalue.Value) *jsvalue.Array { return &jsvalue.Array{Elements: elems} }
+
+func obj(kv ...any) *jsvalue.Object {
+	o := &jsvalue.Object{}
+	for i := 0; i < len(kv); i += 2 {
+		o.Properties = append(o.Properties, jsvalue.Property{Key: kv[i].(string), Value: kv[i+1].(jsvalue.Value)})
+	}
+	return o
+}
+
+func TestToNumber(t *testing.T) {
+	self := arr(num(1))
+	self.Elements = append(self.Elements, self)
+	tests := []struct {
+		v    jsvalue.Value
+		want string
+	}{
+		{undefined, "NaN"},
+		{null, "0"},
+		{jsvalue.Boolean(true), "1"},
+		{num(-0.5), "-0.5"},
+		{str(""), "0"},
+		{str(" \n\t12  "), "12"},
+		{str("\ufeff\u00a07"), "7"},
+		{str("0x1F"), "31"},
+		{str("0o17"), "15"},
+		{str("0b101"), "5"},
+		{str("-0x10"), "NaN"},
+		{str("1e3"), "1000"},
+		{str(".5"), "0.5"},
+		{str("5."), "5"},
+		{str("-0"), "-0"},
+		{str("+Infinity"), "Infinity"},
+		{str("-Infinity"), "-Infinity"},
+		{str("infinity"), "NaN"},
+		{str("1_000"), "NaN"},
+		{str("12px"), "NaN"},
+		{arr(), "0"},
+		{arr(num(5)), "5"},
+		{arr(num(1), num(2)), "NaN"},
+		{arr(null), "0"},
+		{obj(), "NaN"},
+		{big(1), "TypeError"},
+		{obj("toString", num(1)), "TypeError"},
+		{self, "NaN"},
+	}
+	for _, tt := range tests {
+		f, err := jsvalue.ToNumber(tt.v)
+		if got := result(jsvalue.Number(f), err); got != tt.want {
+			t.Errorf("ToNumber(%s) = %s, want %s", show(tt.v), got, tt.want)
+		}
+	}
+}
+
+func TestToString(t *testing.T) {
+	self := arr(num(1))
+	self.Elements = append(self.Elements, self)
+	tests := []struct {
+		v    jsvalue.Value
+		want string
+	}{
+		{undefined, `"undefined"`},
+		{null, `"null"`},
+		{jsvalue.Boolean(false), `"false"`},
+		{num(math.Copysign(0, -1)), `"0"`},
+		{num(1e21), `"1e+21"`},
+		{big(-12), `"-12"`},
+		{str("é"), `"é"`},
+		{arr(), `""`},
+		{arr(num(1), nil, undefined, null, str("a"), arr(num(2), num(3))), `"1,,,,a,2,3"`},
+		{obj("a", num(1)), `"[object Object]"`},
+		{arr(obj()), `"[object Object]"`},
+		{self, `"1,"`},
+		{obj("toString", str("x")), "TypeError"},
+		{arr(obj("toString", str("x"))), "TypeError"},
+	}
+	for _, tt := range tests {
+		s, err := jsvalue.ToString(tt.v)
+		if got := result(jsvalue.String(s), err); got != tt.want {
+			t.Errorf("ToString(%s) = %s, want %s", show(tt.v), got, tt.want)
+		}
+	}
+}
+
+func TestToPrimitive(t *testing.T) {
+	for _, hint := range []jsvalue.Hint{jsvalue.HintDefault, jsvalue.HintNumber, jsvalue.HintString} {
+		if got := result(jsvalue.ToPrimitive(arr(num(1), num(2)), hint)); got != `"1,2"` {
+			t.Errorf("ToPrimitive([1, 2], %s) = %s", hint, got)
+		}
+		if got := result(jsvalue.ToPrimitive(num(1), hint)); got != "1" {
+			t.Errorf("ToPrimitive(1, %s) = %s", hint, got)
+		}
+	}
+}
+
+func TestToNumeric(t *testing.T) {
+	tests := []struct {
+		v    jsvalue.Value
+		want string
+	}{
+		{big(5), "5n"},
+		{str("5"), "5"},
+		{arr(big(5)), "5"},
+		{obj("toString", null), "TypeError"},
+	}
+	for _, tt := range tests {
+		if got := result(jsvalue.ToNumeric(tt.v)); got != tt.want {
+			t.Errorf("ToNumeric(%s) = %s, want %s", show(tt.v), got, tt.want)
+		}
+	}
+}
+
+func TestToBoolean(t *testing.T) {
+	tests := []struct {
+		v    jsvalue.Value
+		want bool
+	}{
+		{undefined, false},
+		{null, false},
+		{jsvalue.Boolean(true), true},
+		{num(0), false},
+		{num(math.Copysign(0, -1)), false},
+		{num(math.NaN()), false},
+		{num(-1), true},
+		{big(0), false},
+		{big(2), true},
+		{str(""), false},
+		{str("0"), true},
+		{arr(), true},
+		{obj(), true},
+	}
+	for _, tt := range tests {
+		if got := jsvalue.ToBoolean(tt.v); got != tt.want {
+			t.Errorf("ToBoolean(%s) = %v, want %v", show(tt.v), got, tt.want)
+		}
+	}
+}
+
+func TestToInt32(t *testing.T) {
+	tests := []struct {
+		v      jsvalue.Value
+		int32  int32
+		uint32 uint32
+	}{
+		{num(1.9), 1, 1},
+		{num(-1.9), -1, 1<<32 - 1},
+		{num(1 << 31), -1 << 31, 1 << 31},
+		{num(1<<32 + 5), 5, 5},
+		{num(-(1 << 32) - 5), -5, 1<<32 - 5},
+		{num(1e21), -559939584, 3735027712},
+		{num(math.NaN()), 0, 0},
+		{num(math.Inf(-1)), 0, 0},
+		{str("0xffffffff"), -1, 1<<32 - 1},
+		{undefined, 0, 0},
+	}
+	for _, tt := range tests {
+		i, err := jsvalue.ToInt32(tt.v)
+		if err != nil || i != tt.int32 {
+			t.Errorf("ToInt32(%s) = %d, %v, want %d", show(tt.v), i, err, tt.int32)
+		}
+		u, err := jsvalue.ToUint32(tt.v)
+		if err != nil || u != tt.uint32 {
+			t.Errorf("ToUint32(%s) = %d, %v, want %d", show(tt.v), u, err, tt.uint32)
+		}
+	}
+	if _, err := jsvalue.ToInt32(big(1)); err == nil {
+		t.Errorf("ToInt32(1n) did not throw")
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	tests := []struct {
+		v    jsvalue.Value
+		want string
+	}{
+		{undefined, "undefined"},
+		{null, "object"},
+		{jsvalue.Boolean(true), "boolean"},
+		{num(1), "number"},
+		{big(1), "bigint"},
+		{str(""), "string"},
+		{arr(), "object"},
+		{obj(), "object"},
+	}
+	for _, tt := range tests {
+		if got := jsvalue.TypeOf(tt.v); got != tt.want {
+			t.Errorf("TypeOf(%s) = %s, want %s", show(tt.v), got, tt.want)
+		}
+	}
+}
+
+func TestParseBigInt(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string // the bigint, or "" if s is not one
+	}{
+		{"", "0"},
+		{"  42\n", "42"},
+		{"-42", "-42"},
+		{"0x10", "16"},
+		{"0b11", "3"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+		{"1.5", ""},
+		{"1e3", ""},
+		{"-0x10", ""},
+		{"12n", ""},
+	}
+	for _, tt := range tests {
+		i, ok := jsvalue.ParseBigInt(tt.s)
+		got := ""
+		if ok {
+			got = i.String()
+		}
+		if got != tt.want {
+			t.Errorf("ParseBigInt(%s) = %s, want %s", strconv.Quote(tt.s), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package jsvalue
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const digitChars = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// FormatNumber returns f in the given radix, between 2 and 36, as
+// Number.prototype.toString does.
+//
+// In radix 10 it is the shortest decimal that rounds to f, in exponent
+// notation from 1e21 and below 1e-6. Other radixes have as many fraction
+// digits as f has precision, as in V8 and SpiderMonkey.
+func FormatNumber(f float64, radix int) string {
+	if radix < 2 || radix > 36 {
+		panic(fmt.Sprintf("jsvalue: radix %d out of range", radix))
+	}
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case f == 0:
+		return "0"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	case f < 0:
+		return "-" + FormatNumber(-f, radix)
+	case radix == 10:
+		return formatDecimal(f)
+	}
+	return formatRadix(f, radix)
+}
+
+func formatDecimal(f float64) string {
+	// The shortest digits that round to f, and the position n of the
+	// decimal point relative to them.
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	n, _ := strconv.Atoi(exp)
+	n++
+	k := len(digits)
+	switch {
+	case k <= n && n <= 21:
+		return digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return "0." + strings.Repeat("0", -n) + digits
+	}
+	s := digits[:1]
+	if k > 1 {
+		s += "." + digits[1:]
+	}
+	if n > 0 {
+		return s + "e+" + strconv.Itoa(n-1)
+	}
+	return s + "e" + strconv.Itoa(n-1)
+}
+
+// formatRadix formats f, which is positive and finite, in a radix other than
+// 10.
+func formatRadix(f float64, radix int) string {
+	r := float64(radix)
+	integer := math.Floor(f)
+	fraction := f - integer
+	// Fraction digits are written until they are below the precision of f.
+	delta := math.Max(0.5*(math.Nextafter(f, math.Inf(1))-f), math.SmallestNonzeroFloat64)
+
+	var frac []byte
+digits:
+	for fraction >= delta {
+		fraction *= r
+		delta *= r
+		digit := int(fraction)
+		frac = append(frac, digitChars[digit])
+		fraction -= float64(digit)
+		// Round half to even, carrying into the digits written.
+		if (fraction > 0.5 || fraction == 0.5 && digit&1 != 0) && fraction+delta > 1 {
+			for {
+				if len(frac) == 0 {
+					integer++
+					break digits
+				}
+				last := strings.IndexByte(digitChars, frac[len(frac)-1])
+				frac = frac[:len(frac)-1]
+				if last+1 < radix {
+					frac = append(frac, digitChars[last+1])
+					break digits
+				}
+			}
+		}
+	}
+
+	// Integer digits beyond the precision of f are zeros.
+	var whole []byte
+	for exponent(integer/r) > 0 {
+		integer /= r
+		whole = append(whole, '0')
+	}
+	for {
+		rem := math.Mod(integer, r)
+		whole = append(whole, digitChars[int(rem)])
+		integer = (integer - rem) / r
+		if integer <= 0 {
+			break
+		}
+	}
+	for i, j := 0, len(whole)-1; i < j; i, j = i+1, j-1 {
+		whole[i], whole[j] = whole[j], whole[i]
+	}
+	if len(frac) == 0 {
+		return string(whole)
+	}
+	return string(whole) + "." + string(frac)
+}
+
+// exponent returns the exponent e of f as s * 2^e, s being its 53-bit
+// integer significand.
+func exponent(f float64) int {
+	biased := int(math.Float64bits(f) >> 52 & 0x7ff)
+	if biased == 0 {
+		return 1 - 1075
+	}
+	return biased - 1075
+}
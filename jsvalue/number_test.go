@@ -0,0 +1,56 @@
+package jsvalue_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/t14raptor/go-fast/jsvalue"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		f     float64
+		radix int
+		want  string
+	}{
+		{0, 10, "0"},
+		{math.Copysign(0, -1), 10, "0"},
+		{math.NaN(), 10, "NaN"},
+		{math.Inf(1), 10, "Infinity"},
+		{math.Inf(-1), 10, "-Infinity"},
+		{1, 10, "1"},
+		{-1.5, 10, "-1.5"},
+		{0.30000000000000004, 10, "0.30000000000000004"},
+		{123456789, 10, "123456789"},
+		{1e20, 10, "100000000000000000000"},
+		{1e21, 10, "1e+21"},
+		{1.5e21, 10, "1.5e+21"},
+		{0.000001, 10, "0.000001"},
+		{0.0000001, 10, "1e-7"},
+		{1.23e-7, 10, "1.23e-7"},
+		{123e-20, 10, "1.23e-18"},
+		{math.MaxFloat64, 10, "1.7976931348623157e+308"},
+		{5e-324, 10, "5e-324"},
+		{255, 16, "ff"},
+		{-255, 16, "-ff"},
+		{255, 2, "11111111"},
+		{0.5, 2, "0.1"},
+		{35, 36, "z"},
+		{0.5, 16, "0.8"},
+		{1e21, 16, "3635c9adc5dea00000"},
+	}
+	for _, tt := range tests {
+		if got := jsvalue.FormatNumber(tt.f, tt.radix); got != tt.want {
+			t.Errorf("FormatNumber(%v, %d) = %s, want %s", tt.f, tt.radix, got, tt.want)
+		}
+	}
+}
+
+func TestFormatNumberRadix(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("FormatNumber(1, 37) did not panic")
+		}
+	}()
+	jsvalue.FormatNumber(1, 37)
+}
@@ -0,0 +1,213 @@
+package jsvalue
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/t14raptor/go-fast/token"
+)
+
+// maxBigIntBits is the size bigints may have, as in V8.
+const maxBigIntBits = 1 << 30
+
+// Unary applies the operator op, which is -, +, !, ~, typeof or void, to x.
+func Unary(op token.Token, x Value) (Value, error) {
+	switch op {
+	case token.Not:
+		return Boolean(!ToBoolean(x)), nil
+	case token.Typeof:
+		return String(TypeOf(x)), nil
+	case token.Void:
+		return Undefined{}, nil
+	case token.Plus:
+		f, err := ToNumber(x)
+		if err != nil {
+			return nil, err
+		}
+		return Number(f), nil
+	case token.Minus, token.BitwiseNot:
+		n, err := ToNumeric(x)
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := n.(BigInt); ok {
+			if op == token.Minus {
+				return BigInt{Int: new(big.Int).Neg(b.Int)}, nil
+			}
+			return BigInt{Int: new(big.Int).Not(b.Int)}, nil
+		}
+		if op == token.Minus {
+			return -n.(Number), nil
+		}
+		return Number(^int32(uint32Of(float64(n.(Number))))), nil
+	}
+	panic(fmt.Sprintf("jsvalue: %s is not a unary operator", op))
+}
+
+// Binary applies the operator op to x and y. op is an arithmetic, bitwise,
+// equality or relational operator other than in and instanceof.
+func Binary(op token.Token, x, y Value) (Value, error) {
+	switch op {
+	case token.Equal, token.NotEqual:
+		eq, err := LooseEqual(x, y)
+		if err != nil {
+			return nil, err
+		}
+		return Boolean(eq == (op == token.Equal)), nil
+	case token.StrictEqual:
+		return Boolean(StrictEqual(x, y)), nil
+	case token.StrictNotEqual:
+		return Boolean(!StrictEqual(x, y)), nil
+	case token.Less, token.GreaterOrEqual:
+		r, err := LessThan(x, y)
+		if err != nil {
+			return nil, err
+		}
+		if op == token.Less {
+			return Boolean(r == Boolean(true)), nil
+		}
+		return Boolean(r == Boolean(false)), nil
+	case token.Greater, token.LessOrEqual:
+		r, err := LessThan(y, x)
+		if err != nil {
+			return nil, err
+		}
+		if op == token.Greater {
+			return Boolean(r == Boolean(true)), nil
+		}
+		return Boolean(r == Boolean(false)), nil
+	case token.Plus:
+		px, err := ToPrimitive(x, HintDefault)
+		if err != nil {
+			return nil, err
+		}
+		py, err := ToPrimitive(y, HintDefault)
+		if err != nil {
+			return nil, err
+		}
+		if px.Kind() == KindString || py.Kind() == KindString {
+			sx, err := ToString(px)
+			if err != nil {
+				return nil, err
+			}
+			sy, err := ToString(py)
+			if err != nil {
+				return nil, err
+			}
+			return String(sx + sy), nil
+		}
+		x, y = px, py
+	case token.Minus, token.Multiply, token.Slash, token.Remainder, token.Exponent,
+		token.And, token.Or, token.ExclusiveOr,
+		token.ShiftLeft, token.ShiftRight, token.UnsignedShiftRight:
+	default:
+		panic(fmt.Sprintf("jsvalue: %s is not a binary operator on values", op))
+	}
+
+	nx, err := ToNumeric(x)
+	if err != nil {
+		return nil, err
+	}
+	ny, err := ToNumeric(y)
+	if err != nil {
+		return nil, err
+	}
+	if nx.Kind() != ny.Kind() {
+		return nil, typeError("Cannot mix BigInt and other types, use explicit conversions")
+	}
+	if bx, ok := nx.(BigInt); ok {
+		return bigIntOp(op, bx.Int, ny.(BigInt).Int)
+	}
+	return Number(numberOp(op, float64(nx.(Number)), float64(ny.(Number)))), nil
+}
+
+func numberOp(op token.Token, x, y float64) float64 {
+	switch op {
+	case token.Plus:
+		return x + y
+	case token.Minus:
+		return x - y
+	case token.Multiply:
+		return x * y
+	case token.Slash:
+		return x / y
+	case token.Remainder:
+		return math.Mod(x, y)
+	case token.Exponent:
+		return pow(x, y)
+	}
+	a, b := uint32Of(x), uint32Of(y)
+	switch op {
+	case token.And:
+		return float64(int32(a & b))
+	case token.Or:
+		return float64(int32(a | b))
+	case token.ExclusiveOr:
+		return float64(int32(a ^ b))
+	case token.ShiftLeft:
+		return float64(int32(a << (b & 31)))
+	case token.ShiftRight:
+		return float64(int32(a) >> (b & 31))
+	}
+	return float64(a >> (b & 31))
+}
+
+func bigIntOp(op token.Token, x, y *big.Int) (Value, error) {
+	z := new(big.Int)
+	switch op {
+	case token.Plus:
+		z.Add(x, y)
+	case token.Minus:
+		z.Sub(x, y)
+	case token.Multiply:
+		z.Mul(x, y)
+	case token.Slash, token.Remainder:
+		if y.Sign() == 0 {
+			return nil, rangeError("Division by zero")
+		}
+		// Quotients are truncated and remainders have the sign of x.
+		if op == token.Slash {
+			z.Quo(x, y)
+		} else {
+			z.Rem(x, y)
+		}
+	case token.Exponent:
+		if y.Sign() < 0 {
+			return nil, rangeError("Exponent must be non-negative")
+		}
+		// Powers of numbers other than 0, 1 and -1 have at least y bits.
+		large := !y.IsInt64() || y.Int64() > maxBigIntBits || int64(x.BitLen()-1)*y.Int64() > maxBigIntBits
+		if x.CmpAbs(big.NewInt(1)) > 0 && large {
+			return nil, rangeError("Maximum BigInt size exceeded")
+		}
+		z.Exp(x, y, nil)
+	case token.And:
+		z.And(x, y)
+	case token.Or:
+		z.Or(x, y)
+	case token.ExclusiveOr:
+		z.Xor(x, y)
+	case token.ShiftLeft, token.ShiftRight:
+		left := (op == token.ShiftLeft) == (y.Sign() >= 0)
+		n := new(big.Int).Abs(y)
+		switch {
+		case x.Sign() == 0:
+		case left:
+			if !n.IsInt64() || int64(x.BitLen())+n.Int64() > maxBigIntBits {
+				return nil, rangeError("Maximum BigInt size exceeded")
+			}
+			z.Lsh(x, uint(n.Int64()))
+		case !n.IsInt64() || n.Int64() >= int64(x.BitLen()):
+			// Shifting right rounds towards negative infinity.
+			if x.Sign() < 0 {
+				z.SetInt64(-1)
+			}
+		default:
+			z.Rsh(x, uint(n.Int64()))
+		}
+	case token.UnsignedShiftRight:
+		return nil, typeError("BigInts have no unsigned right shift, use >> instead")
+	}
+	return BigInt{Int: z}, nil
+}
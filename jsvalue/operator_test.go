@@ -0,0 +1,122 @@
+package jsvalue_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/t14raptor/go-fast/jsvalue"
+	"github.com/t14raptor/go-fast/token"
+)
+
+func TestUnary(t *testing.T) {
+	tests := []struct {
+		op   token.Token
+		x    jsvalue.Value
+		want string
+	}{
+		{token.Not, str(""), "true"},
+		{token.Not, arr(), "false"},
+		{token.Typeof, null, `"object"`},
+		{token.Void, num(1), "undefined"},
+		{token.Plus, str(" 12 "), "12"},
+		{token.Plus, arr(), "0"},
+		{token.Plus, big(1), "TypeError"},
+		{token.Minus, num(0), "-0"},
+		{token.Minus, str("-0"), "0"},
+		{token.Minus, big(5), "-5n"},
+		{token.Minus, obj(), "NaN"},
+		{token.BitwiseNot, num(5), "-6"},
+		{token.BitwiseNot, num(-1), "0"},
+		{token.BitwiseNot, num(math.NaN()), "-1"},
+		{token.BitwiseNot, num(1 << 32), "-1"},
+		{token.BitwiseNot, big(5), "-6n"},
+		{token.BitwiseNot, obj("toString", num(1)), "TypeError"},
+	}
+	for _, tt := range tests {
+		if got := result(jsvalue.Unary(tt.op, tt.x)); got != tt.want {
+			t.Errorf("%s%s = %s, want %s", tt.op, show(tt.x), got, tt.want)
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	tests := []struct {
+		x    jsvalue.Value
+		op   token.Token
+		y    jsvalue.Value
+		want string
+	}{
+		{num(1), token.Plus, num(2), "3"},
+		{num(1), token.Plus, str("2"), `"12"`},
+		{str("a"), token.Plus, null, `"anull"`},
+		{arr(), token.Plus, obj(), `"[object Object]"`},
+		{arr(), token.Plus, arr(), `""`},
+		{jsvalue.Boolean(true), token.Plus, num(1), "2"},
+		{undefined, token.Plus, num(1), "NaN"},
+		{big(1), token.Plus, str("x"), `"1x"`},
+		{big(1), token.Plus, big(2), "3n"},
+		{big(1), token.Plus, num(2), "TypeError"},
+		{obj("toString", num(1)), token.Plus, str(""), "TypeError"},
+		{str("6"), token.Minus, str("2"), "4"},
+		{num(0.1), token.Multiply, num(3), "0.30000000000000004"},
+		{num(1), token.Slash, num(0), "Infinity"},
+		{num(-1), token.Slash, num(0), "-Infinity"},
+		{num(0), token.Slash, num(0), "NaN"},
+		{num(-5), token.Remainder, num(3), "-2"},
+		{num(5.5), token.Remainder, num(-2), "1.5"},
+		{num(-4), token.Remainder, num(2), "-0"},
+		{num(2), token.Exponent, num(10), "1024"},
+		{num(-8), token.Exponent, num(1.0 / 3), "NaN"},
+		{num(1), token.Exponent, num(math.Inf(1)), "NaN"},
+		{num(math.NaN()), token.Exponent, num(0), "1"},
+		{num(5), token.And, num(3), "1"},
+		{num(5), token.Or, num(3), "7"},
+		{num(5), token.ExclusiveOr, num(3), "6"},
+		{num(1), token.ShiftLeft, num(31), "-2147483648"},
+		{num(1), token.ShiftLeft, num(33), "2"},
+		{num(-16), token.ShiftRight, num(2), "-4"},
+		{num(-1), token.UnsignedShiftRight, num(0), "4294967295"},
+		{num(-1), token.UnsignedShiftRight, num(28), "15"},
+		{big(7), token.Slash, big(-2), "-3n"},
+		{big(-7), token.Remainder, big(2), "-1n"},
+		{big(1), token.Slash, big(0), "RangeError"},
+		{big(2), token.Exponent, big(64), "18446744073709551616n"},
+		{big(2), token.Exponent, big(-1), "RangeError"},
+		{big(2), token.Exponent, big(1 << 31), "RangeError"},
+		{big(1), token.Exponent, big(1 << 31), "1n"},
+		{big(-1), token.Exponent, big(3), "-1n"},
+		{big(6), token.And, big(-3), "4n"},
+		{big(1), token.ShiftLeft, big(70), "1180591620717411303424n"},
+		{big(1), token.ShiftLeft, big(1 << 31), "RangeError"},
+		{big(-5), token.ShiftRight, big(1), "-3n"},
+		{big(-5), token.ShiftRight, big(100), "-1n"},
+		{big(5), token.ShiftRight, big(-2), "20n"},
+		{big(1), token.UnsignedShiftRight, big(0), "TypeError"},
+		{num(1), token.StrictEqual, str("1"), "false"},
+		{num(1), token.Equal, str("1"), "true"},
+		{null, token.NotEqual, undefined, "false"},
+		{num(1), token.StrictNotEqual, num(1), "false"},
+		{num(1), token.Less, num(2), "true"},
+		{num(1), token.Greater, num(2), "false"},
+		{num(1), token.LessOrEqual, num(math.NaN()), "false"},
+		{num(1), token.GreaterOrEqual, num(math.NaN()), "false"},
+		{num(2), token.GreaterOrEqual, num(2), "true"},
+		{null, token.LessOrEqual, num(0), "true"},
+		{undefined, token.LessOrEqual, num(0), "false"},
+		{obj("toString", num(1)), token.Less, num(1), "TypeError"},
+	}
+	for _, tt := range tests {
+		if got := result(jsvalue.Binary(tt.op, tt.x, tt.y)); got != tt.want {
+			t.Errorf("%s %s %s = %s, want %s", show(tt.x), tt.op, show(tt.y), got, tt.want)
+		}
+	}
+}
+
+func TestBinaryPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Binary(in) did not panic")
+		}
+	}()
+	jsvalue.Binary(token.In, str("a"), obj())
+}
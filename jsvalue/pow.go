@@ -0,0 +1,301 @@
+package jsvalue
+
+import "math"
+
+// pow returns x ** y. The language leaves its precision to implementations,
+// which agree on exact results only: pow is that of fdlibm, within an ulp of
+// engines, where math.Pow may be several ulps off.
+func pow(x, y float64) float64 {
+	// Unlike in C, 1 ** NaN and 1 ** Infinity are NaN.
+	if math.IsNaN(y) || math.IsInf(y, 0) && math.Abs(x) == 1 {
+		return math.NaN()
+	}
+	return fdlibmPow(x, y)
+}
+
+const (
+	two53  = 9007199254740992.0
+	powL1  = 5.99999999999994648725e-01
+	powL2  = 4.28571428578550184252e-01
+	powL3  = 3.33333329818377432918e-01
+	powL4  = 2.72728123808534006489e-01
+	powL5  = 2.30660745775561754067e-01
+	powL6  = 2.06975017800338417784e-01
+	powP1  = 1.66666666666666019037e-01
+	powP2  = -2.77777777770155933842e-03
+	powP3  = 6.61375632143793436117e-05
+	powP4  = -1.65339022054652515390e-06
+	powP5  = 4.13813679705723846039e-08
+	lg2    = 6.93147180559945286227e-01
+	lg2H   = 6.93147182464599609375e-01
+	lg2L   = -1.90465429995776804525e-09
+	ovt    = 8.0085662595372944372e-17
+	cp     = 9.61796693925975554329e-01
+	cpH    = 9.61796700954437255859e-01
+	cpL    = -7.02846165095275826516e-09
+	ivln2  = 1.44269504088896338700e+00
+	ivln2H = 1.44269502162933349609e+00
+	ivln2L = 1.92596299112661746887e-08
+)
+
+var (
+	// huge*huge and tiny*tiny overflow and underflow at run time.
+	huge = 1.0e300
+	tiny = 1.0e-300
+
+	bp  = [2]float64{1.0, 1.5}
+	dpH = [2]float64{0.0, 5.84962487220764160156e-01}
+	dpL = [2]float64{0.0, 1.35003920212974897128e-08}
+)
+
+// high and low return the words of the representation of f, and withHigh
+// and withLow f with one of them replaced.
+
+func high(f float64) int32 { return int32(math.Float64bits(f) >> 32) }
+
+func low(f float64) uint32 { return uint32(math.Float64bits(f)) }
+
+func withHigh(f float64, hi int32) float64 {
+	return math.Float64frombits(uint64(uint32(hi))<<32 | math.Float64bits(f)&0xffffffff)
+}
+
+func withLow(f float64, lo uint32) float64 {
+	return math.Float64frombits(math.Float64bits(f)&^0xffffffff | uint64(lo))
+}
+
+// fdlibmPow is __ieee754_pow of fdlibm 5.3.
+func fdlibmPow(x, y float64) float64 {
+	hx, lx := high(x), low(x)
+	hy, ly := high(y), low(y)
+	ix, iy := hx&0x7fffffff, hy&0x7fffffff
+
+	// y is 0: x ** 0 is 1.
+	if uint32(iy)|ly == 0 {
+		return 1
+	}
+	// x or y is NaN.
+	if ix > 0x7ff00000 || ix == 0x7ff00000 && lx != 0 || iy > 0x7ff00000 || iy == 0x7ff00000 && ly != 0 {
+		return x + y
+	}
+
+	// When x is negative, yisint is 0 if y is not an integer, 1 if it is
+	// odd and 2 if it is even.
+	yisint := 0
+	if hx < 0 {
+		switch {
+		case iy >= 0x43400000:
+			yisint = 2
+		case iy >= 0x3ff00000:
+			k := uint(iy>>20) - 0x3ff
+			if k > 20 {
+				j := ly >> (52 - k)
+				if j<<(52-k) == ly {
+					yisint = 2 - int(j&1)
+				}
+			} else if ly == 0 {
+				j := iy >> (20 - k)
+				if j<<(20-k) == iy {
+					yisint = 2 - int(j&1)
+				}
+			}
+		}
+	}
+
+	// y is ±Infinity, ±1, 2 or 0.5.
+	if ly == 0 {
+		switch {
+		case iy == 0x7ff00000:
+			switch {
+			case (ix-0x3ff00000)|int32(lx) == 0:
+				return y - y
+			case ix >= 0x3ff00000:
+				if hy >= 0 {
+					return y
+				}
+				return 0
+			case hy < 0:
+				return -y
+			}
+			return 0
+		case iy == 0x3ff00000:
+			if hy < 0 {
+				return 1 / x
+			}
+			return x
+		case hy == 0x40000000:
+			return x * x
+		case hy == 0x3fe00000 && hx >= 0:
+			return math.Sqrt(x)
+		}
+	}
+
+	ax := math.Abs(x)
+	// x is ±0, ±Infinity or ±1.
+	if lx == 0 && (ix == 0x7ff00000 || ix == 0 || ix == 0x3ff00000) {
+		z := ax
+		if hy < 0 {
+			z = 1 / z
+		}
+		if hx < 0 {
+			if (ix-0x3ff00000)|int32(yisint) == 0 {
+				z = (z - z) / (z - z)
+			} else if yisint == 1 {
+				z = -z
+			}
+		}
+		return z
+	}
+
+	n := (hx >> 31) + 1
+	// A negative number to a power that is not an integer is NaN.
+	if n|int32(yisint) == 0 {
+		return (x - x) / (x - x)
+	}
+	// s is the sign of the result.
+	s := 1.0
+	if n|int32(yisint-1) == 0 {
+		s = -1
+	}
+
+	var t1, t2 float64
+	if iy > 0x41e00000 {
+		// |y| is above 2^31, and above 2^64 it must overflow or underflow.
+		if iy > 0x43f00000 {
+			if ix <= 0x3fefffff {
+				if hy < 0 {
+					return huge * huge
+				}
+				return tiny * tiny
+			}
+			if ix >= 0x3ff00000 {
+				if hy > 0 {
+					return huge * huge
+				}
+				return tiny * tiny
+			}
+		}
+		// It overflows or underflows if x is not close to 1.
+		if ix < 0x3fefffff {
+			if hy < 0 {
+				return s * huge * huge
+			}
+			return s * tiny * tiny
+		}
+		if ix > 0x3ff00000 {
+			if hy > 0 {
+				return s * huge * huge
+			}
+			return s * tiny * tiny
+		}
+		// |1-x| is at most 2^-20, so log(x) is x-x^2/2+x^3/3-x^4/4.
+		t := ax - 1
+		w := (t * t) * (0.5 - t*(0.3333333333333333333333-t*0.25))
+		u := ivln2H * t
+		v := t*ivln2L - w*ivln2
+		t1 = withLow(u+v, 0)
+		t2 = v - (t1 - u)
+	} else {
+		n = 0
+		// Scale subnormal numbers.
+		if ix < 0x00100000 {
+			ax *= two53
+			n -= 53
+			ix = high(ax)
+		}
+		n += (ix >> 20) - 0x3ff
+		j := ix & 0x000fffff
+		// Normalize ix and pick the interval of x.
+		ix = j | 0x3ff00000
+		k := 0
+		switch {
+		case j <= 0x3988e:
+			// |x| < sqrt(3/2)
+		case j < 0xbb67a:
+			// |x| < sqrt(3)
+			k = 1
+		default:
+			n++
+			ix -= 0x00100000
+		}
+		ax = withHigh(ax, ix)
+
+		// ss = s_h+s_l = (x-1)/(x+1) or (x-1.5)/(x+1.5)
+		u := ax - bp[k]
+		v := 1 / (ax + bp[k])
+		ss := u * v
+		sH := withLow(ss, 0)
+		// t_h = ax+bp[k] high
+		tH := withHigh(0, ((ix>>1)|0x20000000)+0x00080000+int32(k<<18))
+		tL := ax - (tH - bp[k])
+		sL := v * ((u - sH*tH) - sH*tL)
+		// log(ax)
+		s2 := ss * ss
+		r := s2 * s2 * (powL1 + s2*(powL2+s2*(powL3+s2*(powL4+s2*(powL5+s2*powL6)))))
+		r += sL * (sH + ss)
+		s2 = sH * sH
+		tH = withLow(3.0+s2+r, 0)
+		tL = r - ((tH - 3.0) - s2)
+		// u+v = ss*(1+...)
+		u = sH * tH
+		v = sL*tH + tL*ss
+		// 2/(3log2)*(ss+...)
+		pH := withLow(u+v, 0)
+		pL := v - (pH - u)
+		zH := cpH * pH
+		zL := cpL*pH + pL*cp + dpL[k]
+		// log2(ax) = (ss+...)*2/(3*log2) = n + dp_h + z_h + z_l
+		t := float64(n)
+		t1 = withLow(((zH+zL)+dpH[k])+t, 0)
+		t2 = zL - (((t1 - t) - dpH[k]) - zH)
+	}
+
+	// Split y into y1+y2 and compute (y1+y2)*(t1+t2).
+	y1 := withLow(y, 0)
+	pL := (y-y1)*t1 + y*t2
+	pH := y1 * t1
+	z := pL + pH
+	j := high(z)
+	i := int32(low(z))
+	if j >= 0x40900000 {
+		// z >= 1024
+		if (j-0x40900000)|i != 0 || pL+ovt > z-pH {
+			return s * huge * huge
+		}
+	} else if j&0x7fffffff >= 0x4090cc00 {
+		// z <= -1075
+		if uint32(j)-0xc090cc00|uint32(i) != 0 || pL <= z-pH {
+			return s * tiny * tiny
+		}
+	}
+
+	// 2^(p_h+p_l)
+	i = j & 0x7fffffff
+	k := (i >> 20) - 0x3ff
+	n = 0
+	if i > 0x3fe00000 {
+		// |z| > 0.5, so n = [z+0.5]
+		n = j + (0x00100000 >> (k + 1))
+		k = ((n & 0x7fffffff) >> 20) - 0x3ff
+		t := withHigh(0, n&^(0x000fffff>>k))
+		n = ((n & 0x000fffff) | 0x00100000) >> (20 - k)
+		if j < 0 {
+			n = -n
+		}
+		pH -= t
+	}
+	t := withLow(pL+pH, 0)
+	u := t * lg2H
+	v := (pL-(t-pH))*lg2 + t*lg2L
+	z = u + v
+	w := v - (z - u)
+	t = z * z
+	t1 = z - t*(powP1+t*(powP2+t*(powP3+t*(powP4+t*powP5))))
+	r := (z*t1)/(t1-2) - (w + z*w)
+	z = 1 - (r - z)
+	j = high(z) + n<<20
+	if j>>20 <= 0 {
+		// The result is subnormal.
+		return s * math.Ldexp(z, int(n))
+	}
+	return s * withHigh(z, j)
+}
@@ -0,0 +1,125 @@
+// Package jsvalue models JavaScript values and implements the abstract
+// operations of the language on them, such as ToNumber, ToString and the
+// equality and relational comparisons, as the specification defines them.
+//
+// Values are those a program can spell out without running code: primitives,
+// and arrays and objects of data properties inheriting from the built-in
+// prototypes, which are assumed to be unmodified. Operations that throw in
+// JavaScript return an *Error.
+package jsvalue
+
+import "math/big"
+
+// Kind is the type of a value.
+type Kind int
+
+const (
+	KindUndefined Kind = iota
+	KindNull
+	KindBoolean
+	KindNumber
+	KindBigInt
+	KindString
+	KindArray
+	KindObject
+)
+
+var kindNames = [...]string{
+	KindUndefined: "undefined",
+	KindNull:      "null",
+	KindBoolean:   "boolean",
+	KindNumber:    "number",
+	KindBigInt:    "bigint",
+	KindString:    "string",
+	KindArray:     "array",
+	KindObject:    "object",
+}
+
+func (k Kind) String() string { return kindNames[k] }
+
+// Value is a JavaScript value: Undefined, Null, Boolean, Number, BigInt,
+// String, *Array or *Object. Arrays and objects are compared by identity, so
+// two of them are the same object only if they are the same pointer.
+type Value interface {
+	Kind() Kind
+	_value()
+}
+
+type (
+	Undefined struct{}
+	Null      struct{}
+	Boolean   bool
+	Number    float64
+	String    string
+
+	// BigInt is a bigint. Int must not be modified.
+	BigInt struct {
+		Int *big.Int
+	}
+
+	// Array is an array. Nil elements are holes.
+	Array struct {
+		Elements []Value
+	}
+
+	// Object is an ordinary object with data properties only, whose
+	// prototype is Object.prototype.
+	Object struct {
+		Properties []Property
+	}
+
+	// Property is a data property.
+	Property struct {
+		Key   string
+		Value Value
+	}
+)
+
+func (Undefined) Kind() Kind { return KindUndefined }
+func (Null) Kind() Kind      { return KindNull }
+func (Boolean) Kind() Kind   { return KindBoolean }
+func (Number) Kind() Kind    { return KindNumber }
+func (BigInt) Kind() Kind    { return KindBigInt }
+func (String) Kind() Kind    { return KindString }
+func (*Array) Kind() Kind    { return KindArray }
+func (*Object) Kind() Kind   { return KindObject }
+
+func (Undefined) _value() {}
+func (Null) _value()      {}
+func (Boolean) _value()   {}
+func (Number) _value()    {}
+func (BigInt) _value()    {}
+func (String) _value()    {}
+func (*Array) _value()    {}
+func (*Object) _value()   {}
+
+// NewBigInt returns the bigint x.
+func NewBigInt(x int64) BigInt {
+	return BigInt{Int: big.NewInt(x)}
+}
+
+// Get returns the value of the own property key of o.
+func (o *Object) Get(key string) (Value, bool) {
+	for _, p := range o.Properties {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Error is an exception thrown by an operation, such as a TypeError.
+type Error struct {
+	Name    string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Name + ": " + e.Message }
+
+func typeError(msg string) *Error {
+	return &Error{Name: "TypeError", Message: msg}
+}
+
+func rangeError(msg string) *Error {
+	return &Error{Name: "RangeError", Message: msg}
+}
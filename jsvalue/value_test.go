@@ -0,0 +1,87 @@
+package jsvalue_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/jsvalue"
+)
+
+// show prints v as JavaScript source, with -0 distinct from 0 and bigints
+// suffixed with n.
+func show(v jsvalue.Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "<hole>"
+	case jsvalue.Undefined:
+		return "undefined"
+	case jsvalue.Null:
+		return "null"
+	case jsvalue.Boolean:
+		return fmt.Sprint(bool(v))
+	case jsvalue.Number:
+		if v == 0 && math.Signbit(float64(v)) {
+			return "-0"
+		}
+		return jsvalue.FormatNumber(float64(v), 10)
+	case jsvalue.BigInt:
+		return v.Int.String() + "n"
+	case jsvalue.String:
+		return fmt.Sprintf("%q", string(v))
+	case *jsvalue.Array:
+		elems := make([]string, len(v.Elements))
+		for i, e := range v.Elements {
+			elems[i] = show(e)
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case *jsvalue.Object:
+		props := make([]string, len(v.Properties))
+		for i, p := range v.Properties {
+			props[i] = fmt.Sprintf("%q: %s", p.Key, show(p.Value))
+		}
+		return "{" + strings.Join(props, ", ") + "}"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// result shows the value of an operation, or the name of the error it
+// throws.
+func result(v jsvalue.Value, err error) string {
+	if err != nil {
+		return err.(*jsvalue.Error).Name
+	}
+	return show(v)
+}
+
+func TestKind(t *testing.T) {
+	values := []jsvalue.Value{
+		jsvalue.Undefined{}, jsvalue.Null{}, jsvalue.Boolean(true), jsvalue.Number(1),
+		jsvalue.NewBigInt(1), jsvalue.String(""), &jsvalue.Array{}, &jsvalue.Object{},
+	}
+	var kinds []string
+	for _, v := range values {
+		kinds = append(kinds, v.Kind().String())
+	}
+	if got, want := strings.Join(kinds, " "), "undefined null boolean number bigint string array object"; got != want {
+		t.Errorf("kinds %s, want %s", got, want)
+	}
+}
+
+func TestObjectGet(t *testing.T) {
+	o := &jsvalue.Object{Properties: []jsvalue.Property{{Key: "a", Value: jsvalue.Number(1)}}}
+	if v, ok := o.Get("a"); !ok || v != jsvalue.Number(1) {
+		t.Errorf(`Get("a") = %v, %v`, v, ok)
+	}
+	if v, ok := o.Get("b"); ok {
+		t.Errorf(`Get("b") = %v, %v`, v, ok)
+	}
+}
+
+func TestError(t *testing.T) {
+	_, err := jsvalue.ToNumber(jsvalue.NewBigInt(1))
+	if got, want := fmt.Sprint(err), "TypeError: Cannot convert a BigInt value to a number"; got != want {
+		t.Errorf("error %s, want %s", got, want)
+	}
+}
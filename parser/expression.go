@@ -958,7 +958,7 @@ func (p *parser) parseRelationalExpression() ast.Expr {
 		if p.token == token.In {
 			p.next()
 			return &ast.BinaryExpression{
-				Operator: p.token,
+				Operator: token.In,
 				Left:     p.makeExpr(left),
 				Right:    p.makeExpr(p.parseShiftExpression()),
 			}
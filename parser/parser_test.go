@@ -0,0 +1,104 @@
+package parser_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/token"
+)
+
+type binaryCollector struct {
+	ast.NoopVisitor
+	ops []token.Token
+}
+
+func (c *binaryCollector) VisitBinaryExpression(n *ast.BinaryExpression) {
+	c.ops = append(c.ops, n.Operator)
+	n.VisitChildrenWith(c)
+}
+
+func TestBinaryOperator(t *testing.T) {
+	tests := []struct {
+		src  string
+		want token.Token
+	}{
+		{"a in b;", token.In},
+		{"class C { #x; static has(o) { return #x in o; } }", token.In},
+		{"a instanceof b;", token.InstanceOf},
+	}
+	for _, tt := range tests {
+		p, err := parser.ParseFile(tt.src)
+		if err != nil {
+			t.Fatalf("ParseFile(%q): %v", tt.src, err)
+		}
+		c := &binaryCollector{}
+		c.V = c
+		p.VisitWith(c)
+		if len(c.ops) != 1 || c.ops[0] != tt.want {
+			t.Errorf("%q has operators %v, want [%v]", tt.src, c.ops, tt.want)
+		}
+	}
+}
+
+func TestExportDeclaration(t *testing.T) {
+	tests := []struct {
+		src         string
+		declaration string // the type of the exported declaration
+		specifiers  [][2]string
+	}{
+		{"export function f() {}", "*ast.FunctionDeclaration", nil},
+		{"export async function f() {}", "*ast.FunctionDeclaration", nil},
+		{"export class C {}", "*ast.ClassDeclaration", nil},
+		{"export const a = 1, b = 2;", "*ast.VariableDeclaration", nil},
+		{"export let a;", "*ast.VariableDeclaration", nil},
+		{"export var a;", "*ast.VariableDeclaration", nil},
+		{"export {a, b as c};", "<nil>", [][2]string{{"a", "a"}, {"b", "c"}}},
+		{"export {a as default} from 'm';", "<nil>", [][2]string{{"a", "default"}}},
+	}
+	for _, tt := range tests {
+		p, err := parser.ParseFile(tt.src)
+		if err != nil {
+			t.Fatalf("ParseFile(%q): %v", tt.src, err)
+		}
+		n, ok := p.Body[0].Stmt.(*ast.ExportDeclaration)
+		if !ok {
+			t.Fatalf("%q parses as %T", tt.src, p.Body[0].Stmt)
+		}
+		var decl ast.Stmt
+		if n.Declaration != nil {
+			decl = n.Declaration.Stmt
+		}
+		if got := fmt.Sprintf("%T", decl); got != tt.declaration {
+			t.Errorf("%q exports a %s, want %s", tt.src, got, tt.declaration)
+		}
+		if len(n.Specifiers) != len(tt.specifiers) {
+			t.Fatalf("%q has %d specifiers, want %d", tt.src, len(n.Specifiers), len(tt.specifiers))
+		}
+		for i, s := range n.Specifiers {
+			if s.Local.Name != tt.specifiers[i][0] || s.Exported.Name != tt.specifiers[i][1] {
+				t.Errorf("%q: specifier %d exports %s as %s, want %s as %s", tt.src, i, s.Local.Name, s.Exported.Name, tt.specifiers[i][0], tt.specifiers[i][1])
+			}
+			for _, id := range []*ast.Identifier{s.Local, s.Exported} {
+				if at := tt.src[id.Idx-1:]; len(at) < len(id.Name) || at[:len(id.Name)] != id.Name {
+					t.Errorf("%q: %s is recorded at %q", tt.src, id.Name, at)
+				}
+			}
+		}
+		if len(p.Body) != 1 && n.Declaration != nil {
+			t.Errorf("%q parses as %d statements", tt.src, len(p.Body))
+		}
+	}
+}
+
+func TestExportDefault(t *testing.T) {
+	p, err := parser.ParseFile("export default function g() {}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := p.Body[0].Stmt.(*ast.ExportDeclaration)
+	if f, ok := n.Default.Expr.(*ast.FunctionLiteral); !ok || f.Name.Name != "g" || n.Declaration != nil {
+		t.Errorf("export default function g() {} has default %T and declaration %v", n.Default.Expr, n.Declaration)
+	}
+}
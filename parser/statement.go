@@ -957,8 +957,8 @@ func (p *parser) parseImportDeclaration() ast.Stmt {
 	importIdx := p.idx
 	p.next() // Move past 'import'
 
-	var defaultIdentifier *ast.Identifier
-	var specifiers []ast.ImportSpecifier
+	var defaultIdentifier, namespace *ast.Identifier
+	var specifiers ast.ImportSpecifiers
 
 	switch p.token {
 	case token.String:
@@ -974,7 +974,7 @@ func (p *parser) parseImportDeclaration() ast.Stmt {
 		p.next()
 		if p.token == token.Comma {
 			p.next() // Move past ','
-			if p.token != token.LeftBrace {
+			if p.token != token.LeftBrace && p.token != token.Multiply {
 				p.errorUnexpectedToken(p.token)
 				return &ast.BadStatement{From: importIdx, To: p.idx + 1}
 			}
@@ -1029,7 +1029,7 @@ func (p *parser) parseImportDeclaration() ast.Stmt {
 			p.errorUnexpectedToken(p.token)
 			return &ast.BadStatement{From: importIdx, To: p.idx + 1}
 		}
-		defaultIdentifier = &ast.Identifier{Idx: p.idx, Name: p.literal}
+		namespace = &ast.Identifier{Idx: p.idx, Name: p.literal}
 		p.next()
 	}
 
@@ -1050,6 +1050,7 @@ func (p *parser) parseImportDeclaration() ast.Stmt {
 		From:       fromIdx,
 		Source:     source,
 		Default:    defaultIdentifier,
+		Namespace:  namespace,
 		Specifiers: specifiers,
 	}
 }
@@ -1059,37 +1060,33 @@ func (p *parser) parseExportDeclaration() ast.Stmt {
 	p.next() // export
 
 	var defaultExpr *ast.Expression
-	if p.token == token.Default {
+	switch p.token {
+	case token.Default:
 		p.next() // default
 		defaultExpr = &ast.Expression{Expr: p.parseExpression()}
+	case token.Var, token.Let, token.Const, token.Function, token.Class, token.Async:
+		return &ast.ExportDeclaration{
+			Export:      exportIdx,
+			From:        exportIdx,
+			Declaration: p.makeStmt(p.parseStatement()),
+		}
 	}
 
-	var specifiers []ast.ExportSpecifier
+	var specifiers ast.ExportSpecifiers
 	if p.token == token.LeftBrace {
 		p.next() // {
 		for p.token != token.RightBrace {
-			imported := p.literal
+			local := &ast.Identifier{Idx: p.idx, Name: p.literal}
 			p.next() // identifier
 
-			var local string
+			exported := &ast.Identifier{Idx: local.Idx, Name: local.Name}
 			if p.token == token.As {
 				p.next() // as
-				local = p.literal
+				exported = &ast.Identifier{Idx: p.idx, Name: p.literal}
 				p.next() // identifier
-			} else {
-				local = imported
 			}
 
-			specifiers = append(specifiers, ast.ExportSpecifier{
-				Imported: &ast.Identifier{
-					Idx:  p.idx,
-					Name: imported,
-				},
-				Local: &ast.Identifier{
-					Idx:  p.idx,
-					Name: local,
-				},
-			})
+			specifiers = append(specifiers, ast.ExportSpecifier{Local: local, Exported: exported})
 
 			if p.token != token.RightBrace {
 				if p.token != token.Comma {
@@ -1104,7 +1101,7 @@ func (p *parser) parseExportDeclaration() ast.Stmt {
 	}
 
 	var source *ast.StringLiteral
-	if p.token == token.From {
+	if p.token == token.From || p.token == token.Identifier && p.literal == "from" {
 		p.next() // from
 		if p.token != token.String {
 			p.errorUnexpectedToken(p.token)
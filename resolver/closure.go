@@ -0,0 +1,291 @@
+package resolver
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Closure describes what a function uses from outside itself.
+type Closure struct {
+	// Node is the *ast.FunctionLiteral or *ast.ArrowFunctionLiteral, and
+	// Scope its function scope.
+	Node  ast.VisitableNode
+	Scope *Scope
+
+	// Reads and Writes hold the bindings declared outside the function that
+	// it or the functions nested in it read and write, in declaration order.
+	// The target of compound assignment, ++ and -- is in both.
+	Reads  []*Binding
+	Writes []*Binding
+	// Globals holds the free variables it refers to, sorted by name.
+	Globals []*Global
+
+	// This, Arguments and NewTarget are set if the function uses this, its
+	// arguments object or new.target. Those of an arrow function are the
+	// ones of the function around it.
+	This      bool
+	Arguments bool
+	NewTarget bool
+
+	// Pure is set if the function changes nothing outside itself: it writes
+	// no outer binding or global, assigns or deletes no property of this, of
+	// a parameter or outer binding or of an object it cannot trace to a
+	// binding of its own, and calls no direct eval and has no with statement.
+	// The functions it calls are not taken into account.
+	Pure bool
+}
+
+// Closures returns the closures of the functions of the program, in source
+// order.
+func (r *Resolver) Closures() []*Closure {
+	c := &closures{
+		resolver: r,
+		byScope:  make(map[*Scope]*Closure),
+		byNode:   make(map[ast.VisitableNode]*Closure),
+		reads:    make(map[*Closure]map[*Binding]struct{}),
+		writes:   make(map[*Closure]map[*Binding]struct{}),
+		globals:  make(map[*Closure]map[*Global]struct{}),
+	}
+	c.V = c
+
+	var list []*Closure
+	r.root.walk(func(s *Scope) {
+		if s.Kind != ScopeKindFunction {
+			return
+		}
+		switch s.Node.(type) {
+		case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral:
+			cl := &Closure{Node: s.Node, Scope: s, Pure: !s.Eval && !s.With}
+			list = append(list, cl)
+			c.byScope[s] = cl
+			c.byNode[s.Node] = cl
+			c.reads[cl] = make(map[*Binding]struct{})
+			c.writes[cl] = make(map[*Binding]struct{})
+			c.globals[cl] = make(map[*Global]struct{})
+		}
+	})
+
+	r.root.walk(func(s *Scope) {
+		for _, b := range s.Bindings {
+			if b.Kind == DeclKindArguments {
+				if cl := c.byScope[b.Scope]; cl != nil {
+					cl.Arguments = true
+				}
+			}
+			for _, ref := range b.References {
+				c.capture(ref.Scope, b.Scope, func(cl *Closure) {
+					if b.Kind == DeclKindArguments {
+						cl.Arguments = true
+					}
+					if ref.Kind != RefKindWrite {
+						c.reads[cl][b] = struct{}{}
+					}
+					if ref.Kind != RefKindRead {
+						c.writes[cl][b] = struct{}{}
+						cl.Pure = false
+					}
+				})
+			}
+		}
+	})
+	for _, g := range r.globals {
+		for _, ref := range g.References {
+			c.capture(ref.Scope, nil, func(cl *Closure) {
+				c.globals[cl][g] = struct{}{}
+				if ref.Kind != RefKindRead {
+					cl.Pure = false
+				}
+			})
+		}
+	}
+
+	r.root.Node.VisitWith(c)
+
+	for _, cl := range list {
+		cl.Reads = sortedBindings(c.reads[cl])
+		cl.Writes = sortedBindings(c.writes[cl])
+		for g := range c.globals[cl] {
+			cl.Globals = append(cl.Globals, g)
+		}
+		slices.SortFunc(cl.Globals, func(a, b *Global) int { return strings.Compare(a.Name, b.Name) })
+	}
+	return list
+}
+
+type closures struct {
+	ast.NoopVisitor
+
+	resolver *Resolver
+	byScope  map[*Scope]*Closure
+	byNode   map[ast.VisitableNode]*Closure
+
+	reads   map[*Closure]map[*Binding]struct{}
+	writes  map[*Closure]map[*Binding]struct{}
+	globals map[*Closure]map[*Global]struct{}
+
+	// stack holds the functions being visited, and nil for class field
+	// initializers and static blocks, which have a this of their own.
+	stack []*Closure
+}
+
+// capture calls fn for the closures a reference from the scope from to a
+// binding of the scope to crosses. A nil to stands for the global scope.
+func (c *closures) capture(from, to *Scope, fn func(*Closure)) {
+	for s := from; s != to; s = s.Parent {
+		// The name of a function expression is its own.
+		if cl := c.byScope[s]; cl != nil && (to == nil || to.Node != cl.Node) {
+			fn(cl)
+		}
+	}
+}
+
+// this calls fn for the closures sharing the this of the innermost function.
+func (c *closures) this(fn func(*Closure)) {
+	for i := len(c.stack) - 1; i >= 0; i-- {
+		cl := c.stack[i]
+		if cl == nil {
+			return
+		}
+		fn(cl)
+		if _, ok := cl.Node.(*ast.ArrowFunctionLiteral); !ok {
+			return
+		}
+	}
+}
+
+// write records an assignment to or deletion of a property of the object of
+// target, a member expression.
+func (c *closures) write(target ast.Expr) {
+	object := target
+	for {
+		switch t := object.(type) {
+		case *ast.MemberExpression:
+			object = t.Object.Expr
+			continue
+		case *ast.PrivateDotExpression:
+			object = t.Left.Expr
+			continue
+		}
+		break
+	}
+
+	switch object := object.(type) {
+	case *ast.ThisExpression:
+		c.this(func(cl *Closure) { cl.Pure = false })
+	case *ast.Identifier:
+		b := c.resolver.Binding(object)
+		for _, cl := range c.stack {
+			if cl != nil && (b == nil || b.Kind == DeclKindParam || !cl.Scope.contains(b.Scope)) {
+				cl.Pure = false
+			}
+		}
+	default:
+		for _, cl := range c.stack {
+			if cl != nil {
+				cl.Pure = false
+			}
+		}
+	}
+}
+
+// writeTargets records the properties assigned by a pattern.
+func (c *closures) writeTargets(target ast.Expr) {
+	switch t := target.(type) {
+	case *ast.MemberExpression, *ast.PrivateDotExpression:
+		c.write(t)
+	case *ast.ObjectPattern:
+		for _, prop := range t.Properties {
+			if prop, ok := prop.Prop.(*ast.PropertyKeyed); ok {
+				c.writeTargets(prop.Value.Expr)
+			}
+		}
+		c.writeTargets(t.Rest)
+	case *ast.ArrayPattern:
+		for _, elem := range t.Elements {
+			c.writeTargets(elem.Expr)
+		}
+		if t.Rest != nil {
+			c.writeTargets(t.Rest.Expr)
+		}
+	case *ast.AssignExpression:
+		c.writeTargets(t.Left.Expr)
+	}
+}
+
+func (c *closures) function(n ast.VisitableNode) {
+	c.stack = append(c.stack, c.byNode[n])
+	n.VisitChildrenWith(c)
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+func (c *closures) VisitFunctionLiteral(n *ast.FunctionLiteral) { c.function(n) }
+
+func (c *closures) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) { c.function(n) }
+
+func (c *closures) VisitFieldDefinition(n *ast.FieldDefinition) {
+	n.Key.VisitWith(c)
+	if n.Initializer != nil {
+		c.stack = append(c.stack, nil)
+		n.Initializer.VisitWith(c)
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+}
+
+func (c *closures) VisitClassStaticBlock(n *ast.ClassStaticBlock) {
+	c.stack = append(c.stack, nil)
+	n.VisitChildrenWith(c)
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+func (c *closures) VisitThisExpression(n *ast.ThisExpression) {
+	c.this(func(cl *Closure) { cl.This = true })
+}
+
+func (c *closures) VisitMetaProperty(n *ast.MetaProperty) {
+	if n.Meta.Name == "new" {
+		c.this(func(cl *Closure) { cl.NewTarget = true })
+	}
+}
+
+func (c *closures) VisitAssignExpression(n *ast.AssignExpression) {
+	c.writeTargets(n.Left.Expr)
+	n.VisitChildrenWith(c)
+}
+
+func (c *closures) VisitUpdateExpression(n *ast.UpdateExpression) {
+	c.writeTargets(n.Operand.Expr)
+	n.VisitChildrenWith(c)
+}
+
+func (c *closures) VisitUnaryExpression(n *ast.UnaryExpression) {
+	if n.Operator == token.Delete {
+		c.writeTargets(n.Operand.Expr)
+	}
+	n.VisitChildrenWith(c)
+}
+
+func (c *closures) VisitForInStatement(n *ast.ForInStatement) {
+	if into, ok := n.Into.Into.(*ast.Expression); ok {
+		c.writeTargets(into.Expr)
+	}
+	n.VisitChildrenWith(c)
+}
+
+func (c *closures) VisitForOfStatement(n *ast.ForOfStatement) {
+	if into, ok := n.Into.Into.(*ast.Expression); ok {
+		c.writeTargets(into.Expr)
+	}
+	n.VisitChildrenWith(c)
+}
+
+func sortedBindings(set map[*Binding]struct{}) []*Binding {
+	bindings := make([]*Binding, 0, len(set))
+	for b := range set {
+		bindings = append(bindings, b)
+	}
+	slices.SortFunc(bindings, compareBindings)
+	return bindings
+}
@@ -0,0 +1,109 @@
+package resolver_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+// describe summarizes a closure as reads, writes and globals followed by its
+// flags.
+func describe(cl *resolver.Closure) string {
+	names := func(bs []*resolver.Binding) string {
+		var s []string
+		for _, b := range bs {
+			s = append(s, b.Name)
+		}
+		return strings.Join(s, ",")
+	}
+	var globals []string
+	for _, g := range cl.Globals {
+		globals = append(globals, g.Name)
+	}
+	out := []string{"r=" + names(cl.Reads), "w=" + names(cl.Writes), "g=" + strings.Join(globals, ",")}
+	for _, f := range []struct {
+		set  bool
+		name string
+	}{{cl.This, "this"}, {cl.Arguments, "arguments"}, {cl.NewTarget, "new.target"}, {cl.Pure, "pure"}} {
+		if f.set {
+			out = append(out, f.name)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func TestClosures(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string // the closures in source order
+	}{
+		{`var a, b; function f(x) { var y = a + x; return y; }`,
+			[]string{"r=a w= g= pure"}},
+		{`var a, b; function f() { a = 1; b += 1; }`,
+			[]string{"r=b w=a,b g="}},
+		{`var a; function f() { return () => a++; }`,
+			[]string{"r=a w=a g=", "r=a w=a g="}},
+		{`function f() { var a; return () => a; }`,
+			[]string{"r= w= g= pure", "r=a w= g= pure"}},
+		{`function f() { return Math.max(1, 2) + g; }`,
+			[]string{"r= w= g=Math,g pure"}},
+		{`function f() { window.x = 1; }`,
+			[]string{"r= w= g=window"}},
+		{`function f() { this.x = 1; }`,
+			[]string{"r= w= g= this"}},
+		{`function f() { return this; }`,
+			[]string{"r= w= g= this pure"}},
+		{`function f() { return () => this; }`,
+			[]string{"r= w= g= this pure", "r= w= g= this pure"}},
+		{`function f() { return () => arguments[0]; }`,
+			[]string{"r= w= g= arguments pure", "r=arguments w= g= arguments pure"}},
+		{`function f() { return new.target; }`,
+			[]string{"r= w= g= new.target pure"}},
+		{`function f(o) { o.x = 1; }`,
+			[]string{"r= w= g="}},
+		{`function f() { var o = {}; o.x = 1; delete o.x; return o; }`,
+			[]string{"r= w= g= pure"}},
+		{`var o; function f() { delete o.x; }`,
+			[]string{"r=o w= g="}},
+		{`function f() { eval("x"); }`,
+			[]string{"r= w= g=eval"}},
+		{`var a; function f() { with ({}) { a; } }`,
+			[]string{"r=a w= g="}},
+		{`var a; var f = function g() { return g, a; };`,
+			[]string{"r=a w= g= pure"}},
+		{`function f() { class C { x = this; static { this.y = 1; } } }`,
+			[]string{"r= w= g= pure"}},
+		{`var a; function f() { for (a of []); }`,
+			[]string{"r= w=a g="}},
+	}
+	for _, tt := range tests {
+		r := resolver.Resolve(parse(t, tt.src))
+		var got []string
+		for _, cl := range r.Closures() {
+			got = append(got, describe(cl))
+		}
+		if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+			t.Errorf("Closures(%s):\ngot  %q\nwant %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestClosureNodes(t *testing.T) {
+	p := parse(t, `function f() { return () => 1; } class C { m() {} }`)
+	r := resolver.Resolve(p)
+	closures := r.Closures()
+	want := []string{"*ast.FunctionLiteral", "*ast.ArrowFunctionLiteral", "*ast.FunctionLiteral"}
+	if len(closures) != len(want) {
+		t.Fatalf("%d closures, want %d", len(closures), len(want))
+	}
+	for i, cl := range closures {
+		if got := fmt.Sprintf("%T", cl.Node); got != want[i] {
+			t.Errorf("closure %d is a %s, want %s", i, got, want[i])
+		}
+		if cl.Scope.Node != cl.Node || cl.Scope.Kind != resolver.ScopeKindFunction {
+			t.Errorf("closure %d has the scope of a %T", i, cl.Scope.Node)
+		}
+	}
+}
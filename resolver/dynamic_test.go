@@ -0,0 +1,84 @@
+package resolver_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+// flags returns a flag of the scopes of r in tree order.
+func flags(r *resolver.Resolver, flag func(*resolver.Scope) bool) []bool {
+	var out []bool
+	var walk func(s *resolver.Scope)
+	walk = func(s *resolver.Scope) {
+		out = append(out, flag(s))
+		for _, c := range s.Children {
+			walk(c)
+		}
+	}
+	walk(r.Root())
+	return out
+}
+
+func TestEval(t *testing.T) {
+	eval := func(s *resolver.Scope) bool { return s.Eval }
+	tests := []struct {
+		src  string
+		want []bool // the program, then its scopes in tree order
+	}{
+		{`eval("x");`, []bool{true}},
+		{`function f() { () => { eval("x"); }; } function g() {}`, []bool{true, true, true, false}},
+		{`function f(eval) { eval("x"); }`, []bool{false, false}},
+		{`let eval; { eval("x"); }`, []bool{false, false}},
+		{`(0, eval)("x"); window.eval("x");`, []bool{false}},
+		{`function f() { { eval; } }`, []bool{false, false, false}},
+		{`class C { x = eval("y"); }`, []bool{true, true, true}},
+	}
+	for _, tt := range tests {
+		r := resolver.Resolve(parse(t, tt.src))
+		if got := flags(r, eval); !slices.Equal(got, tt.want) {
+			t.Errorf("Eval in %s = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestWith(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []bool // With of the references to x
+	}{
+		{`var x; with (o) x;`, []bool{true}},
+		{`with (o) { x; } x;`, []bool{true, false}},
+		{`with (o) { let x; x; }`, []bool{false}},
+		{`with (o) { function f() { x; } }`, []bool{true}},
+		{`with (o) { function f(x) { x; } }`, []bool{false}},
+		{`var x; with (x) {}`, []bool{false}},
+		{`with (a) { with (b) { var x; } x; }`, []bool{true}},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		r := resolver.Resolve(p)
+		var got []bool
+		for _, id := range idents(p, "x") {
+			var refs []*resolver.Reference
+			if b := r.Binding(id); b != nil {
+				refs = b.References
+			} else {
+				for _, g := range r.Globals() {
+					if g.Name == "x" {
+						refs = g.References
+					}
+				}
+			}
+			for _, ref := range refs {
+				if ref.Ident == id {
+					got = append(got, ref.With)
+				}
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("With of the references to x in %s = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
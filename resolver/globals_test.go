@@ -0,0 +1,62 @@
+package resolver_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func TestGlobals(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string // name:references
+	}{
+		{`window.a; document.b(); navigator; window;`, "document:1 navigator:1 window:2"},
+		{`var window; window; let document; document;`, ""},
+		{`function f(navigator) { navigator; } navigator;`, "navigator:1"},
+		{`typeof x; x = 1; x++;`, "x:3"},
+		{`{ let a; } a; try {} catch (e) {} e;`, "a:1 e:1"},
+		{`o.window; ({window: 1}); label: for (;;) break label;`, "o:1"},
+		{`function f() { arguments; } arguments;`, "arguments:1"},
+		{`eval("x"); (class { m() { globalThis; } });`, "eval:1 globalThis:1"},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, g := range resolver.Globals(parse(t, tt.src)) {
+			got = append(got, fmt.Sprintf("%s:%d", g.Name, len(g.References)))
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("Globals(%s) = %q, want %q", tt.src, strings.Join(got, " "), tt.want)
+		}
+	}
+}
+
+func TestGlobalReferences(t *testing.T) {
+	p := parse(t, `window.a = 1; function f() { window = 2; }`)
+	r := resolver.Resolve(p)
+	globals := r.Globals()
+	if len(globals) != 1 || globals[0].Name != "window" {
+		t.Fatalf("Globals() = %v, want window", globals)
+	}
+	ids := idents(p, "window")
+	refs := globals[0].References
+	if len(refs) != 2 || refs[0].Ident != ids[0] || refs[1].Ident != ids[1] {
+		t.Fatalf("References = %v, want both identifiers in order", refs)
+	}
+	if refs[0].Kind != resolver.RefKindRead || refs[1].Kind != resolver.RefKindWrite {
+		t.Errorf("reference kinds = %s, %s, want read, write", refs[0].Kind, refs[1].Kind)
+	}
+	if refs[0].Scope != r.Root() || refs[1].Scope != r.Root().Children[0] {
+		t.Errorf("references are not in the scopes they are made from")
+	}
+	for _, id := range ids {
+		if id.ScopeContext != resolver.UnresolvedMark || r.Binding(id) != nil {
+			t.Errorf("global reference at %d resolves to context %d", id.Idx, id.ScopeContext)
+		}
+	}
+	if len(r.Root().Bindings) != 1 {
+		t.Errorf("the program declares %d names, want only f", len(r.Root().Bindings))
+	}
+}
@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"maps"
+	"slices"
 
 	"github.com/t14raptor/go-fast/ast"
 	"github.com/t14raptor/go-fast/token"
@@ -9,160 +10,213 @@ import (
 
 // Loosely inspired from https://rustdoc.swc.rs/swc_ecma_transforms_base/fn.resolver.html
 
+// Hoister declares the var declarations of a function or program body in
+// the current scope of its resolver, walking nested blocks but not nested
+// functions. In sloppy mode code it also hoists functions declared in blocks,
+// as Annex B.3.3 does, unless a var of the same name would be an error.
 type Hoister struct {
 	ast.NoopVisitor
 
 	resolver *Resolver
-	kind     DeclKind
-	inBlock  bool
 
-	inCatchBody bool
-
-	excludedFromCatch map[string]struct{}
-	catchParamDecls   map[string]struct{}
+	// lexical holds the names declared by let, const and class in the body
+	// and the enclosing blocks, and the parameter names.
+	lexical []map[string]struct{}
+	// catchParams holds the enclosing simple catch parameters, which a var
+	// of the same name redeclares (Annex B.3.4).
+	catchParams []string
 }
 
+// NewHoister returns a hoister declaring into the current scope of resolver.
+// Its visitor is already set, so setting V again, as callers used to, is
+// harmless.
 func NewHoister(resolver *Resolver) *Hoister {
-	return &Hoister{
-		resolver:          resolver,
-		kind:              DeclKindVar,
-		excludedFromCatch: make(map[string]struct{}),
-		catchParamDecls:   make(map[string]struct{}),
-	}
+	h := &Hoister{resolver: resolver}
+	h.V = h
+	return h
 }
 
-func (h *Hoister) addIdent(id *ast.Identifier) {
-	if h.inCatchBody {
-		if _, ok := h.catchParamDecls[id.Name]; ok {
-			if r, _ := h.resolver.lookupContext(id.Name); r != UnresolvedMark {
-				return
-			}
-		}
-
-		h.excludedFromCatch[id.Name] = struct{}{}
-	} else if _, ok := h.catchParamDecls[id.Name]; ok {
-		if _, excluded := h.excludedFromCatch[id.Name]; !excluded {
-			return
+func (h *Hoister) hoist(list ast.Statements, params *ast.ParameterList) {
+	names := lexicalNames(list)
+	if params != nil {
+		for _, param := range params.List {
+			BindingIdents(param.Target.Target, func(id *ast.Identifier) { names[id.Name] = struct{}{} })
 		}
+		BindingIdents(params.Rest, func(id *ast.Identifier) { names[id.Name] = struct{}{} })
 	}
-
-	h.resolver.modify(id, h.kind)
+	h.lexical = append(h.lexical, names)
+	list.VisitChildrenWith(h)
+	h.lexical = h.lexical[:len(h.lexical)-1]
 }
 
-func (h *Hoister) VisitBlockStatement(n *ast.BlockStatement) {
-	old := h.inBlock
-	h.inBlock = true
+func (h *Hoister) block(names map[string]struct{}, n ast.VisitableNode) {
+	h.lexical = append(h.lexical, names)
 	n.VisitChildrenWith(h)
-	h.inBlock = old
+	h.lexical = h.lexical[:len(h.lexical)-1]
 }
 
-func (h *Hoister) VisitCatchStatement(n *ast.CatchStatement) {
-	oldExclude := h.excludedFromCatch
-	h.excludedFromCatch = make(map[string]struct{})
-	oldInCatchBody := h.inCatchBody
-
-	if n.Parameter != nil {
-		if params := findIds(n.Parameter); len(params) == 1 {
-			h.catchParamDecls[params[0].Name] = struct{}{}
-		}
+func (h *Hoister) VisitVariableDeclaration(n *ast.VariableDeclaration) {
+	if n.Token != token.Var {
+		return
 	}
-
-	old := maps.Clone(h.catchParamDecls)
-
-	h.inCatchBody = true
-	n.Body.VisitWith(h)
-	h.inCatchBody = false
-	if n.Parameter != nil {
-		n.Parameter.VisitWith(h)
+	for _, decl := range n.List {
+		BindingIdents(decl.Target.Target, func(id *ast.Identifier) {
+			if slices.Contains(h.catchParams, id.Name) {
+				h.resolver.hoistCatchVar(id)
+			} else {
+				h.resolver.modify(id, DeclKindVar)
+			}
+		})
 	}
-
-	h.catchParamDecls = old
-	h.inCatchBody = oldInCatchBody
-	h.excludedFromCatch = oldExclude
 }
 
-func (h *Hoister) VisitStatements(n *ast.Statements) {
-	others := make(ast.Statements, 0, len(*n))
-	for i := range *n {
-		switch it := (*n)[i].Stmt.(type) {
-		case *ast.VariableDeclaration:
-			it.VisitWith(h)
-		case *ast.FunctionDeclaration:
-			it.VisitWith(h)
-		default:
-			others = append(others, (*n)[i])
+func (h *Hoister) VisitFunctionDeclaration(n *ast.FunctionDeclaration) {
+	if h.resolver.strict {
+		return
+	}
+	for _, names := range h.lexical {
+		if _, ok := names[n.Function.Name.Name]; ok {
+			return
 		}
 	}
+	h.resolver.modify(n.Function.Name, DeclKindFunction)
+}
 
-	for i := range others {
-		others[i].VisitWith(h)
-	}
+func (h *Hoister) VisitBlockStatement(n *ast.BlockStatement) {
+	h.block(lexicalNames(n.List), n)
 }
 
-func (h *Hoister) VisitVariableDeclaration(n *ast.VariableDeclaration) {
-	if h.inBlock && n.Token != token.Var {
-		return
+func (h *Hoister) VisitSwitchStatement(n *ast.SwitchStatement) {
+	names := make(map[string]struct{})
+	for _, c := range n.Body {
+		maps.Copy(names, lexicalNames(c.Consequent))
 	}
-
-	oldKind := h.kind
-	h.kind = DeclKindVar
-	n.VisitChildrenWith(h)
-	h.kind = oldKind
+	h.block(names, n)
 }
 
-func (h *Hoister) VisitBindingTarget(n *ast.BindingTarget) {
-	if ident, ok := n.Target.(*ast.Identifier); ok {
-		h.addIdent(ident)
+func (h *Hoister) VisitCatchStatement(n *ast.CatchStatement) {
+	if n.Parameter == nil {
+		n.Body.VisitWith(h)
 		return
 	}
-	n.VisitChildrenWith(h)
-}
-
-func (h *Hoister) VisitFunctionDeclaration(n *ast.FunctionDeclaration) {
-	if _, ok := h.catchParamDecls[n.Function.Name.Name]; ok {
+	if id, ok := n.Parameter.Target.(*ast.Identifier); ok {
+		h.catchParams = append(h.catchParams, id.Name)
+		n.Body.VisitWith(h)
+		h.catchParams = h.catchParams[:len(h.catchParams)-1]
 		return
 	}
+	names := make(map[string]struct{})
+	BindingIdents(n.Parameter.Target, func(id *ast.Identifier) { names[id.Name] = struct{}{} })
+	h.block(names, n)
+}
 
-	if h.inBlock {
-		if kind, declared := h.resolver.current.isDeclared(n.Function.Name.Name); declared {
-			if kind != DeclKindVar && kind != DeclKindFunction {
-				return
-			}
+func (h *Hoister) VisitForStatement(n *ast.ForStatement) {
+	names := make(map[string]struct{})
+	if n.Initializer != nil {
+		if decl, ok := n.Initializer.Initializer.(*ast.VariableDeclaration); ok {
+			names = lexicalNames(ast.Statements{{Stmt: decl}})
 		}
 	}
-
-	h.resolver.modify(n.Function.Name, DeclKindFunction)
+	h.block(names, n)
 }
 
-func (h *Hoister) VisitSwitchStatement(n *ast.SwitchStatement) {
-	n.Discriminant.VisitWith(h)
+func (h *Hoister) VisitForInStatement(n *ast.ForInStatement) {
+	h.block(forIntoNames(n.Into), n)
+}
 
-	old := h.inBlock
-	h.inBlock = true
-	n.Body.VisitWith(h)
-	h.inBlock = old
+func (h *Hoister) VisitForOfStatement(n *ast.ForOfStatement) {
+	h.block(forIntoNames(n.Into), n)
 }
 
 func (h *Hoister) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {}
+func (h *Hoister) VisitClassLiteral(n *ast.ClassLiteral)                 {}
 func (h *Hoister) VisitExpression(n *ast.Expression)                     {}
 func (h *Hoister) VisitFunctionLiteral(n *ast.FunctionLiteral)           {}
 
-type idsFinder struct {
-	ast.NoopVisitor
-
-	found []ast.Id
+func forIntoNames(n *ast.ForInto) map[string]struct{} {
+	if decl, ok := n.Into.(*ast.VariableDeclaration); ok {
+		return lexicalNames(ast.Statements{{Stmt: decl}})
+	}
+	return make(map[string]struct{})
 }
 
-func findIds(n ast.VisitableNode) []ast.Id {
-	v := &idsFinder{}
-	v.V = v
-	n.VisitWith(v)
-
-	return v.found
+// lexicalNames returns the names a statement list declares with let, const
+// and class.
+func lexicalNames(list ast.Statements) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, s := range list {
+		switch s := s.Stmt.(type) {
+		case *ast.VariableDeclaration:
+			if s.Token == token.Var {
+				continue
+			}
+			for _, decl := range s.List {
+				BindingIdents(decl.Target.Target, func(id *ast.Identifier) { names[id.Name] = struct{}{} })
+			}
+		case *ast.ClassDeclaration:
+			names[s.Class.Name.Name] = struct{}{}
+		}
+	}
+	return names
 }
 
-func (v *idsFinder) VisitExpression(n *ast.Expression) {}
+// BindingIdents calls fn for each identifier a binding pattern declares, in
+// source order.
+func BindingIdents(target ast.Expr, fn func(*ast.Identifier)) {
+	switch t := target.(type) {
+	case *ast.Identifier:
+		if t != nil {
+			fn(t)
+		}
+	case *ast.ObjectPattern:
+		for _, prop := range t.Properties {
+			switch prop := prop.Prop.(type) {
+			case *ast.PropertyShort:
+				fn(prop.Name)
+			case *ast.PropertyKeyed:
+				BindingIdents(prop.Value.Expr, fn)
+			}
+		}
+		BindingIdents(t.Rest, fn)
+	case *ast.ArrayPattern:
+		for _, elem := range t.Elements {
+			BindingIdents(elem.Expr, fn)
+		}
+		if t.Rest != nil {
+			BindingIdents(t.Rest.Expr, fn)
+		}
+	case *ast.AssignExpression:
+		BindingIdents(t.Left.Expr, fn)
+	}
+}
 
-func (v *idsFinder) VisitIdentifier(n *ast.Identifier) {
-	v.found = append(v.found, n.ToId())
+// hasExpression reports whether a binding pattern evaluates a default value
+// or a computed key.
+func hasExpression(target ast.Expr) bool {
+	switch t := target.(type) {
+	case *ast.ObjectPattern:
+		for _, prop := range t.Properties {
+			switch prop := prop.Prop.(type) {
+			case *ast.PropertyShort:
+				if prop.Initializer != nil && prop.Initializer.Expr != nil {
+					return true
+				}
+			case *ast.PropertyKeyed:
+				if prop.Computed || hasExpression(prop.Value.Expr) {
+					return true
+				}
+			}
+		}
+		return hasExpression(t.Rest)
+	case *ast.ArrayPattern:
+		for _, elem := range t.Elements {
+			if hasExpression(elem.Expr) {
+				return true
+			}
+		}
+		return t.Rest != nil && hasExpression(t.Rest.Expr)
+	case *ast.AssignExpression:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,213 @@
+package resolver
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Hygiene renames bindings of p so that every identifier refers, by its name
+// alone, to the binding its ScopeContext says it does. Transforms that inline
+// or move code leave identifiers of the same name but different contexts in
+// one scope, which the generator would print as the same variable.
+//
+// Unresolved references are taken to refer to globals, and unresolved
+// declarations to declare a binding where they are. When a name refers to
+// several bindings in one scope, the binding declared there is renamed, so
+// that outer bindings and globals keep their names. Hygiene resolves p again
+// and returns the resolver.
+func Hygiene(p *ast.Program) *Resolver {
+	h := &hygiene{
+		keys:  make(map[*ast.Identifier]ast.Id),
+		byKey: make(map[ast.Id][]*ast.Identifier),
+		used:  make(map[string]struct{}),
+	}
+	walkIdents(p, func(id *ast.Identifier) {
+		h.keys[id] = id.ToId()
+		h.byKey[id.ToId()] = append(h.byKey[id.ToId()], id)
+		h.used[id.Name] = struct{}{}
+	})
+
+	for {
+		Reset(p)
+		r := Resolve(p)
+
+		h.renamed = make(map[ast.Id]bool)
+		h.names = make(map[*ast.Identifier]string)
+		r.Root().walk(func(s *Scope) {
+			names := make([]string, 0, len(s.Bindings))
+			for name := range s.Bindings {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			for _, name := range names {
+				h.fix(s.Bindings[name])
+			}
+		})
+		if len(h.renamed) == 0 {
+			return r
+		}
+		h.rename(p)
+	}
+}
+
+type hygiene struct {
+	// keys holds the identity of each identifier before hygiene: its name
+	// and context.
+	keys  map[*ast.Identifier]ast.Id
+	byKey map[ast.Id][]*ast.Identifier
+	used  map[string]struct{}
+
+	// renamed holds the keys renamed since the program was last resolved,
+	// and names the new names of their identifiers.
+	renamed map[ast.Id]bool
+	names   map[*ast.Identifier]string
+}
+
+type hygieneGroup struct {
+	key      ast.Id
+	idents   []*ast.Identifier
+	declared bool
+}
+
+// fix picks new names for the identifiers of b that were meant to refer to
+// other bindings.
+func (h *hygiene) fix(b *Binding) {
+	var groups []*hygieneGroup
+	add := func(id *ast.Identifier, decl bool) bool {
+		key := h.keys[id]
+		if h.renamed[key] {
+			// Stale until the program is resolved again.
+			return false
+		}
+		i := slices.IndexFunc(groups, func(g *hygieneGroup) bool { return g.key == key })
+		if i < 0 {
+			i = len(groups)
+			groups = append(groups, &hygieneGroup{key: key})
+		}
+		groups[i].idents = append(groups[i].idents, id)
+		groups[i].declared = groups[i].declared || decl
+		return true
+	}
+	for _, id := range b.Decls {
+		if !add(id, true) {
+			return
+		}
+	}
+	for _, ref := range b.References {
+		if !add(ref.Ident, false) {
+			return
+		}
+	}
+	if len(groups) < 2 {
+		return
+	}
+
+	keep := groups[0]
+	for _, g := range groups {
+		if !g.declared {
+			keep = g
+			break
+		}
+	}
+	for _, g := range groups {
+		if g == keep {
+			continue
+		}
+		name := h.fresh(g.key.Name)
+		h.renamed[g.key] = true
+		idents := g.idents
+		if g.key.ScopeContext != UnresolvedMark {
+			// The binding may be referred to from other scopes too.
+			idents = h.byKey[g.key]
+		}
+		for _, id := range idents {
+			h.names[id] = name
+		}
+	}
+}
+
+// rename gives the identifiers fix picked their new names, expanding the
+// shorthand properties and specifiers naming them first so that property
+// keys and exported names stay the same.
+func (h *hygiene) rename(p *ast.Program) {
+	rn := &renamer{idents: make(map[*ast.Identifier]struct{}, len(h.names))}
+	rn.V = rn
+	for id := range h.names {
+		rn.idents[id] = struct{}{}
+	}
+	p.VisitWith(rn)
+
+	for id, name := range h.names {
+		id.Name = name
+	}
+}
+
+func (h *hygiene) fresh(base string) string {
+	for i := 1; ; i++ {
+		name := base + strconv.Itoa(i)
+		if _, ok := h.used[name]; !ok {
+			h.used[name] = struct{}{}
+			return name
+		}
+	}
+}
+
+// Fresh declares a binding in s for a new temporary, named after base so
+// that it clashes with no other binding or global: no binding in s, its
+// ancestors or descendants has the name. Identifiers declaring or referring
+// to the binding must be stamped with s.Context.
+func (s *Scope) Fresh(base string, kind DeclKind) *Binding {
+	if !build.IsIdentifierName(base) {
+		base = "_"
+	}
+	name := base
+	for i := 1; s.taken(name); i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	b := &Binding{Name: name, Kind: kind, Scope: s, initialized: true}
+	s.Bindings[name] = b
+	return b
+}
+
+func (s *Scope) taken(name string) bool {
+	if tok, _ := token.LiteralKeyword(name); tok != 0 {
+		return true
+	}
+	if s.Parent.Lookup(name) != nil || s.root().globals[name] != nil {
+		return true
+	}
+	taken := false
+	s.walk(func(s *Scope) {
+		taken = taken || s.Bindings[name] != nil
+	})
+	return taken
+}
+
+// walk calls fn for s and its descendants in order.
+func (s *Scope) walk(fn func(*Scope)) {
+	fn(s)
+	for _, c := range s.Children {
+		c.walk(fn)
+	}
+}
+
+type identWalker struct {
+	ast.NoopVisitor
+
+	fn func(*ast.Identifier)
+}
+
+func walkIdents(n ast.VisitableNode, fn func(*ast.Identifier)) {
+	w := &identWalker{fn: fn}
+	w.V = w
+	n.VisitWith(w)
+}
+
+func (w *identWalker) VisitIdentifier(n *ast.Identifier) {
+	w.fn(n)
+}
@@ -0,0 +1,117 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func TestHygiene(t *testing.T) {
+	tests := []struct {
+		src string
+		// from is renamed to to, keeping the contexts, as inlining code
+		// from elsewhere does.
+		from, to string
+		want     string
+	}{
+		{`var x = 1; function f() { var y = 2; return x + y; }`, "y", "x",
+			`var x = 1; function f() { var x1 = 2; return x + x1; }`},
+		{`function f() { var y; return y + g; }`, "y", "g",
+			`function f() { var g1; return g1 + g; }`},
+		{`function f(y) { return function () { return x + y; }; } var x;`, "y", "x",
+			`function f(x1) { return function () { return x + x1; }; } var x;`},
+		{`var x1; var x = 1; function f() { let y; { x; y; } }`, "y", "x",
+			`var x1; var x = 1; function f() { let x2; { x; x2; } }`},
+		{`var x = 1; function f() { var y = 2; return y; }`, "y", "x",
+			`var x = 1; function f() { var x = 2; return x; }`},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		resolver.Resolve(p)
+		for _, id := range idents(p, tt.from) {
+			id.Name = tt.to
+		}
+		r := resolver.Hygiene(p)
+		if got, want := generator.Generate(p), generator.Generate(parse(t, tt.want)); got != want {
+			t.Errorf("Hygiene(%s with %s renamed to %s) = %s, want %s", tt.src, tt.from, tt.to, got, want)
+		}
+		if got, want := len(r.Globals()), len(resolver.Globals(parse(t, tt.src))); got != want {
+			t.Errorf("Hygiene(%s) left %d globals, want %d", tt.src, got, want)
+		}
+	}
+}
+
+func TestHygieneSameScope(t *testing.T) {
+	// Two declarations of x from different contexts in one scope, as left by
+	// inlining the body of a function into the program.
+	p := parse(t, `var x = 1; var y = 2; x + y;`)
+	resolver.Resolve(p)
+	for _, id := range idents(p, "y") {
+		id.Name = "x"
+		id.ScopeContext = 42
+	}
+	resolver.Hygiene(p)
+	if got, want := generator.Generate(p), generator.Generate(parse(t, `var x = 1; var x1 = 2; x + x1;`)); got != want {
+		t.Errorf("Hygiene = %s, want %s", got, want)
+	}
+}
+
+func TestHygieneShorthand(t *testing.T) {
+	// The second x, from another context, is renamed without changing the
+	// key of {x} or the name export {x} exports.
+	p := parse(t, `var x = 1; var x = 2; var o = {x}; export {x};`)
+	resolver.Resolve(p)
+	spec := &p.Body[3].Stmt.(*ast.ExportDeclaration).Specifiers[0]
+	spec.Exported = spec.Local
+	for _, id := range idents(p, "x")[1:] {
+		id.ScopeContext = 42
+	}
+	resolver.Hygiene(p)
+	decls := p.Body[:3]
+	if got, want := generator.Generate(&decls), generator.Generate(&parse(t, `var x = 1; var x1 = 2; var o = {x: x1};`).Body); got != want {
+		t.Errorf("Hygiene = %s, want %s", got, want)
+	}
+	if spec.Local.Name != "x1" || spec.Exported.Name != "x" {
+		t.Errorf("export {x} became export {%s as %s}, want export {x1 as x}", spec.Local.Name, spec.Exported.Name)
+	}
+}
+
+func TestFresh(t *testing.T) {
+	p := parse(t, `var a, a1; function f(b) { { let c; } g; }`)
+	r := resolver.Resolve(p)
+	f := r.Root().Children[0]
+	tests := []struct {
+		scope *resolver.Scope
+		base  string
+		want  string
+	}{
+		{f, "a", "a2"}, // declared in the program
+		{f, "b", "b1"}, // declared in f
+		{f, "c", "c1"}, // declared in a block in f
+		{f, "g", "g1"}, // a global
+		{f, "d", "d"},
+		{f, "if", "if1"},
+		{f, "1d", "_"},
+		{r.Root(), "b", "b2"}, // b1 was declared in f above
+	}
+	for _, tt := range tests {
+		b := tt.scope.Fresh(tt.base, resolver.DeclKindLet)
+		if b.Name != tt.want || b.Scope != tt.scope || tt.scope.Bindings[b.Name] != b || b.Kind != resolver.DeclKindLet {
+			t.Errorf("Fresh(%q) in the %s scope = %+v, want %s declared there", tt.base, tt.scope.Kind, b, tt.want)
+		}
+	}
+	// Names handed out are taken.
+	if b := f.Fresh("d", resolver.DeclKindVar); b.Name != "d1" {
+		t.Errorf("second Fresh(d) = %s, want d1", b.Name)
+	}
+
+	// A temporary declared with Fresh resolves once its identifiers are
+	// stamped with the scope.
+	tmp := r.Root().Fresh("t", resolver.DeclKindVar)
+	id := &ast.Identifier{Name: tmp.Name, ScopeContext: r.Root().Context}
+	if r.Binding(id) != tmp {
+		t.Errorf("identifier stamped with the scope of %s does not resolve to it", tmp.Name)
+	}
+}
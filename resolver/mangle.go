@@ -0,0 +1,201 @@
+package resolver
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// MangleOptions configures Mangle.
+type MangleOptions struct {
+	// TopLevel also renames the bindings of the program scope, except
+	// exported ones. Scripts share these with other scripts through the
+	// global object, so they are kept by default.
+	TopLevel bool
+	// KeepFunctionNames and KeepClassNames keep the names of functions and
+	// classes, which are visible through their name property.
+	KeepFunctionNames bool
+	KeepClassNames    bool
+	// Reserved holds names that are never given to a binding.
+	Reserved []string
+}
+
+// Mangle renames the bindings of p to the shortest names available for
+// minified output. Globals keep their names, as do bindings visible to a
+// direct eval or referred to from inside a with statement, whose references
+// are resolved by name at run time.
+//
+// Each scope numbers its bindings after those of its enclosing scopes, so
+// sibling scopes reuse the same names. The numbers used most often across the
+// program get the shortest names, which also helps compression. Mangle
+// resolves p and returns the resolver, with the bindings renamed.
+func Mangle(p *ast.Program, opts MangleOptions) *Resolver {
+	Reset(p)
+	r := Resolve(p)
+
+	m := &mangler{
+		opts:     opts,
+		reserved: make(map[string]struct{}),
+		slots:    make(map[*Binding]int),
+		exported: make(map[*Binding]struct{}),
+	}
+	for _, export := range r.Module().Exports {
+		if export.Binding != nil {
+			m.exported[export.Binding] = struct{}{}
+		}
+	}
+	// Naming a binding arguments or eval would change what these mean.
+	m.reserved["arguments"] = struct{}{}
+	m.reserved["eval"] = struct{}{}
+	for _, name := range opts.Reserved {
+		m.reserved[name] = struct{}{}
+	}
+	for _, g := range r.Globals() {
+		m.reserved[g.Name] = struct{}{}
+	}
+	m.assign(r.Root(), 0)
+
+	// The most used slots get the shortest names.
+	order := make([]int, len(m.freq))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(m.freq[b], m.freq[a]) })
+	names := make([]string, len(m.freq))
+	next := 0
+	for _, slot := range order {
+		for {
+			name := minifiedName(next)
+			next++
+			if _, ok := m.reserved[name]; ok {
+				continue
+			}
+			if tok, _ := token.LiteralKeyword(name); tok != 0 {
+				continue
+			}
+			names[slot] = name
+			break
+		}
+	}
+
+	rn := &renamer{idents: make(map[*ast.Identifier]struct{})}
+	rn.V = rn
+	for b := range m.slots {
+		for _, id := range b.Decls {
+			rn.idents[id] = struct{}{}
+		}
+		for _, ref := range b.References {
+			rn.idents[ref.Ident] = struct{}{}
+		}
+	}
+	p.VisitWith(rn)
+
+	for b, slot := range m.slots {
+		name := names[slot]
+		for _, id := range b.Decls {
+			if _, ok := rn.idents[id]; ok {
+				id.Name = name
+			}
+		}
+		for _, ref := range b.References {
+			if _, ok := rn.idents[ref.Ident]; ok {
+				ref.Ident.Name = name
+			}
+		}
+	}
+	r.Root().walk(func(s *Scope) {
+		bindings := make(map[string]*Binding, len(s.Bindings))
+		for _, b := range s.Bindings {
+			if slot, ok := m.slots[b]; ok {
+				b.Name = names[slot]
+			}
+			bindings[b.Name] = b
+		}
+		s.Bindings = bindings
+	})
+	return r
+}
+
+type mangler struct {
+	opts MangleOptions
+
+	// reserved holds the names of globals and of the bindings kept.
+	reserved map[string]struct{}
+	exported map[*Binding]struct{}
+
+	slots map[*Binding]int
+	freq  []int
+}
+
+// assign numbers the renamed bindings of s and its descendants from next.
+func (m *mangler) assign(s *Scope, next int) {
+	bindings := make([]*Binding, 0, len(s.Bindings))
+	for _, b := range s.Bindings {
+		bindings = append(bindings, b)
+	}
+	slices.SortFunc(bindings, compareBindings)
+
+	for _, b := range bindings {
+		if !m.renames(b) {
+			m.reserved[b.Name] = struct{}{}
+			continue
+		}
+		m.slots[b] = next
+		if next == len(m.freq) {
+			m.freq = append(m.freq, 0)
+		}
+		m.freq[next] += len(b.Decls) + len(b.References)
+		next++
+	}
+	for _, c := range s.Children {
+		m.assign(c, next)
+	}
+}
+
+func (m *mangler) renames(b *Binding) bool {
+	if b.Scope.Parent == nil {
+		if !m.opts.TopLevel {
+			return false
+		}
+		if _, ok := m.exported[b]; ok {
+			return false
+		}
+	}
+	if m.opts.KeepFunctionNames && b.Kind == DeclKindFunction ||
+		m.opts.KeepClassNames && b.Kind == DeclKindClass {
+		return false
+	}
+	return b.Kind != DeclKindArguments && !dynamic(b) && !catchVar(b)
+}
+
+// compareBindings orders bindings by declaration, then by name.
+func compareBindings(a, b *Binding) int {
+	if c := cmp.Compare(declIdx(a), declIdx(b)); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Name, b.Name)
+}
+
+func declIdx(b *Binding) ast.Idx {
+	if len(b.Decls) == 0 {
+		return 0
+	}
+	return b.Decls[0].Idx
+}
+
+// minifiedName returns the i-th shortest identifier.
+func minifiedName(i int) string {
+	const head = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ_$"
+	const tail = head + "0123456789"
+
+	name := []byte{head[i%len(head)]}
+	i /= len(head)
+	for i > 0 {
+		i--
+		name = append(name, tail[i%len(tail)])
+		i /= len(tail)
+	}
+	return string(name)
+}
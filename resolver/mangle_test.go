@@ -0,0 +1,69 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func TestMangle(t *testing.T) {
+	tests := []struct {
+		src  string
+		opts resolver.MangleOptions
+		want string
+	}{
+		{`function f(x, y) { var z = x + y; return z; }`, resolver.MangleOptions{},
+			`function f(a, b) { var c = a + b; return c; }`},
+		// Sibling scopes reuse names.
+		{`function f(long) { return long; } function g(other) { return other; }`, resolver.MangleOptions{},
+			`function f(a) { return a; } function g(a) { return a; }`},
+		// The most used binding gets the shortest name.
+		{`function f(x, y) { y; y; y; return x; }`, resolver.MangleOptions{},
+			`function f(b, a) { a; a; a; return b; }`},
+		{`var top = 1; function f(x) { return x + top; }`, resolver.MangleOptions{TopLevel: true},
+			`var a = 1; function c(b) { return b + a; }`},
+		{`function f(x) { var y; eval(""); return x; } function g(x) { with (o) { x; } }`, resolver.MangleOptions{},
+			`function f(x) { var y; eval(""); return x; } function g(x) { with (o) { x; } }`},
+		{`function f() { function inner() {} class K {} var v; }`, resolver.MangleOptions{KeepFunctionNames: true, KeepClassNames: true},
+			`function f() { function inner() {} class K {} var a; }`},
+		{`function f() { function inner() {} class K {} }`, resolver.MangleOptions{},
+			`function f() { function a() {} class b {} }`},
+		// Globals and reserved names are never given out.
+		{`function f(x, y) { return a + x + y; }`, resolver.MangleOptions{Reserved: []string{"b"}},
+			`function f(c, d) { return a + c + d; }`},
+		{`function f() { var x = function g() { return g; }; }`, resolver.MangleOptions{},
+			`function f() { var b = function a() { return a; }; }`},
+		{`function f(x) { let y = x; { let z = y; } { let w = x; } }`, resolver.MangleOptions{},
+			`function f(a) { let b = a; { let c = b; } { let c = a; } }`},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		r := resolver.Mangle(p, tt.opts)
+		if got, want := generator.Generate(p), generator.Generate(parse(t, tt.want)); got != want {
+			t.Errorf("Mangle(%s, %+v) = %s, want %s", tt.src, tt.opts, got, want)
+		}
+
+		// The returned resolver describes the renamed program.
+		var check func(s *resolver.Scope)
+		check = func(s *resolver.Scope) {
+			for name, b := range s.Bindings {
+				if b.Name != name {
+					t.Errorf("Mangle(%s): binding %s is filed under %s", tt.src, b.Name, name)
+				}
+				for _, id := range b.Decls {
+					if id.Name != name {
+						t.Errorf("Mangle(%s): binding %s is declared as %s", tt.src, name, id.Name)
+					}
+				}
+			}
+			for _, c := range s.Children {
+				check(c)
+			}
+		}
+		check(r.Root())
+		if got, want := len(r.Globals()), len(resolver.Globals(parse(t, tt.src))); got != want {
+			t.Errorf("Mangle(%s) left %d globals, want %d", tt.src, got, want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package resolver
+
+import "github.com/t14raptor/go-fast/ast"
+
+// Module holds the imports and exports of a module, in source order. Those
+// of a script are empty.
+type Module struct {
+	Imports []*Import
+	Exports []*Export
+}
+
+// Import is a binding a module imports.
+type Import struct {
+	Decl *ast.ImportDeclaration
+	// Source is the module imported from and Name the name imported from
+	// it: "default" for a default import and "*" for a namespace import.
+	Source  string
+	Name    string
+	Binding *Binding
+}
+
+// Export is a name a module exports.
+type Export struct {
+	Decl *ast.ExportDeclaration
+	// Name is the name exported, "default" for export default.
+	Name string
+	// Binding is the local binding exported. It is nil for re-exports and
+	// for export default of an expression other than a named function or
+	// class, whose value is Decl.Default.
+	Binding *Binding
+	// Source and Imported are the module and name a re-export is from.
+	Source   string
+	Imported string
+}
+
+// Module returns the imports and exports of the program.
+func (r *Resolver) Module() *Module { return &r.module }
+
+// declareImport declares the bindings of an import, which are initialized
+// before the module runs.
+func (r *Resolver) declareImport(n *ast.ImportDeclaration) {
+	add := func(name string, local *ast.Identifier) {
+		r.modify(local, DeclKindImport)
+		r.module.Imports = append(r.module.Imports, &Import{
+			Decl:    n,
+			Source:  n.Source.Value,
+			Name:    name,
+			Binding: r.Binding(local),
+		})
+	}
+	if n.Default != nil {
+		add("default", n.Default)
+	}
+	if n.Namespace != nil {
+		add("*", n.Namespace)
+	}
+	for _, spec := range n.Specifiers {
+		add(spec.Imported.Name, spec.Local)
+	}
+}
+
+// declareExport declares the bindings of an exported declaration, and the
+// name of a function or class exported as default, which is declared in the
+// module scope like that of a declaration.
+func (r *Resolver) declareExport(n *ast.ExportDeclaration) {
+	if n.Declaration != nil {
+		r.declareLexical(ast.Statements{*n.Declaration})
+	}
+	if n.Default == nil {
+		return
+	}
+	switch d := n.Default.Expr.(type) {
+	case *ast.FunctionLiteral:
+		if named(d.Name) {
+			r.modify(d.Name, DeclKindFunction)
+		}
+	case *ast.ClassLiteral:
+		if named(d.Name) {
+			r.modify(d.Name, DeclKindClass)
+		}
+	}
+}
+
+// declIdents calls fn for each identifier a variable, function or class
+// declaration declares.
+func declIdents(s ast.Stmt, fn func(*ast.Identifier)) {
+	switch d := s.(type) {
+	case *ast.VariableDeclaration:
+		for _, decl := range d.List {
+			BindingIdents(decl.Target.Target, fn)
+		}
+	case *ast.FunctionDeclaration:
+		fn(d.Function.Name)
+	case *ast.ClassDeclaration:
+		fn(d.Class.Name)
+	}
+}
+
+func named(id *ast.Identifier) bool {
+	return id != nil && id.Name != ""
+}
+
+// link resolves the local bindings of the exports of the program, which may
+// be declared after them.
+func (r *Resolver) link() {
+	for _, export := range r.exports {
+		r.reference(export.ident, RefKindRead)
+		export.Binding = r.Binding(export.ident)
+	}
+	r.exports = nil
+}
+
+func (r *Resolver) VisitImportDeclaration(n *ast.ImportDeclaration) {}
+
+func (r *Resolver) VisitExportDeclaration(n *ast.ExportDeclaration) {
+	add := func(name string, b *Binding) {
+		r.module.Exports = append(r.module.Exports, &Export{Decl: n, Name: ast.StringValue(name), Binding: b})
+	}
+	if n.Declaration != nil {
+		n.Declaration.VisitWith(r)
+		declIdents(n.Declaration.Stmt, func(id *ast.Identifier) { add(id.Name, r.Binding(id)) })
+	}
+	if n.Default != nil {
+		// A named function or class declares its name, as in declareExport.
+		var b *Binding
+		switch d := n.Default.Expr.(type) {
+		case *ast.FunctionLiteral:
+			if named(d.Name) {
+				r.function(d)
+				b = r.Binding(d.Name)
+			}
+		case *ast.ClassLiteral:
+			if named(d.Name) {
+				r.class(d, false)
+				r.initialize(d.Name)
+				b = r.Binding(d.Name)
+			}
+		}
+		if b == nil {
+			r.read(n.Default)
+		}
+		add("default", b)
+	}
+	for _, spec := range n.Specifiers {
+		export := &Export{Decl: n, Name: ast.StringValue(spec.Exported.Name)}
+		if n.Source != nil {
+			export.Source = n.Source.Value
+			export.Imported = ast.StringValue(spec.Local.Name)
+		} else {
+			r.exports = append(r.exports, pendingExport{export, spec.Local})
+		}
+		r.module.Exports = append(r.module.Exports, export)
+	}
+}
+
+type pendingExport struct {
+	*Export
+	ident *ast.Identifier
+}
@@ -0,0 +1,136 @@
+package resolver_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func TestExports(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string // name=binding kind, or name<source.imported
+	}{
+		{`export function f() {}`, "f=function"},
+		{`export async function f() {}`, "f=function"},
+		{`export class C {}`, "C=class"},
+		{`export const a = 1, {b, c: [d]} = o;`, "a=const b=const d=const"},
+		{`export let a; export var b;`, "a=let b=var"},
+		{`var a; export {a, a as b};`, "a=var b=var"},
+		{`export {a}; function a() {}`, "a=function"},
+		{`export {a as b} from "m";`, "b<m.a"},
+		{`export default function g() {}`, "default=function"},
+		{`export default class G {}`, "default=class"},
+		{`export default function () {}`, "default"},
+		{`export default 1 + 2;`, "default"},
+	}
+	for _, tt := range tests {
+		r := resolver.Resolve(parse(t, tt.src))
+		var got []string
+		for _, e := range r.Module().Exports {
+			switch {
+			case e.Binding != nil:
+				got = append(got, e.Name+"="+e.Binding.Kind.String())
+			case e.Source != "":
+				got = append(got, e.Name+"<"+e.Source+"."+e.Imported)
+			default:
+				got = append(got, e.Name)
+			}
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("exports of %s = %q, want %q", tt.src, strings.Join(got, " "), tt.want)
+		}
+	}
+}
+
+func TestExportDefaultScope(t *testing.T) {
+	tests := []struct {
+		src, name string
+		kind      resolver.DeclKind
+	}{
+		{`g(); export default function g() { return g; }`, "g", resolver.DeclKindFunction},
+		{`export default class G { m() { return G; } } new G();`, "G", resolver.DeclKindClass},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		r := resolver.Resolve(p)
+		b := r.Root().Bindings[tt.name]
+		if b == nil || b.Kind != tt.kind {
+			t.Fatalf("%s: %s is declared in the module scope as %+v", tt.src, tt.name, b)
+		}
+		for _, id := range idents(p, tt.name) {
+			if r.Binding(id) != b {
+				t.Errorf("%s: %s at %d resolves to %+v", tt.src, tt.name, id.Idx, r.Binding(id))
+			}
+		}
+		if len(r.Globals()) != 0 {
+			t.Errorf("%s: globals %v", tt.src, r.Globals())
+		}
+		if e := r.Module().Exports[0]; e.Binding != b {
+			t.Errorf("%s: default export has binding %+v", tt.src, e.Binding)
+		}
+	}
+}
+
+func TestImports(t *testing.T) {
+	p := parse(t, `import d, {a, b as c} from "m"; import * as ns from "n"; d(a, c, ns);`)
+	r := resolver.Resolve(p)
+	var got []string
+	for _, imp := range r.Module().Imports {
+		got = append(got, fmt.Sprintf("%s.%s=%s", imp.Source, imp.Name, imp.Binding.Name))
+		if imp.Binding.Kind != resolver.DeclKindImport || len(imp.Binding.References) != 1 {
+			t.Errorf("import %s has binding %+v", imp.Name, imp.Binding)
+		}
+	}
+	if want := "m.default=d m.a=a m.b=c n.*=ns"; strings.Join(got, " ") != want {
+		t.Errorf("imports = %q, want %q", strings.Join(got, " "), want)
+	}
+	if !r.Root().Strict {
+		t.Errorf("a module is not strict")
+	}
+}
+
+func TestMangleExports(t *testing.T) {
+	p := parse(t, `export function f(x) { return x; } function g(y) { return y; } export {g as h}; const k = 1; export default k;`)
+	r := resolver.Mangle(p, resolver.MangleOptions{TopLevel: true})
+	for _, name := range []string{"f", "g"} {
+		if r.Root().Bindings[name] == nil {
+			t.Errorf("exported %s was renamed", name)
+		}
+	}
+	if r.Root().Bindings["k"] != nil {
+		t.Errorf("k, exported as the value of an expression, kept its name")
+	}
+	if f := r.Root().Bindings["f"]; f != nil && f.Decls[0].Name != "f" {
+		t.Errorf("f is declared as %s", f.Decls[0].Name)
+	}
+}
+
+func TestRenameExports(t *testing.T) {
+	p := parse(t, `var a; export {a, a as b};`)
+	r := resolver.Resolve(p)
+	if err := resolver.Rename(p, r.Root().Bindings["a"], "z"); err != nil {
+		t.Fatal(err)
+	}
+	export := p.Body[1].Stmt.(*ast.ExportDeclaration)
+	for i, want := range []string{"a", "b"} {
+		s := export.Specifiers[i]
+		if s.Local.Name != "z" || s.Exported.Name != want {
+			t.Errorf("specifier %d exports %s as %s, want z as %s", i, s.Local.Name, s.Exported.Name, want)
+		}
+	}
+
+	p = parse(t, `export function f() {} export const c = 1; export default function g() {}`)
+	r = resolver.Resolve(p)
+	for _, name := range []string{"f", "c"} {
+		if err := resolver.Rename(p, r.Root().Bindings[name], "z"); err == nil {
+			t.Errorf("renamed %s, exported by its declaration", name)
+		}
+	}
+	if err := resolver.Rename(p, r.Root().Bindings["g"], "z"); err != nil {
+		t.Errorf("Rename(g), exported as default: %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+package resolver
+
+import (
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Mutability tells whether a binding may change value.
+type Mutability int
+
+const (
+	// MutabilityConstant bindings are assigned at most once, by their
+	// declaration, before any read: every read sees the same value.
+	MutabilityConstant Mutability = iota
+	// MutabilityEffectivelyConstant bindings are assigned at most once, by
+	// their declaration, but some reads may happen before it and see
+	// undefined, as with a var read above its declaration.
+	MutabilityEffectivelyConstant
+	// MutabilityMutable bindings may be assigned several times.
+	MutabilityMutable
+)
+
+var mutabilityNames = [...]string{
+	MutabilityConstant:            "constant",
+	MutabilityEffectivelyConstant: "effectively constant",
+	MutabilityMutable:             "mutable",
+}
+
+func (m Mutability) String() string { return mutabilityNames[m] }
+
+// Constancy is the result of the mutability analysis of a binding.
+type Constancy struct {
+	Binding    *Binding
+	Mutability Mutability
+	// Init is the value a binding that is not mutable is declared with, if
+	// known: the initializer of a variable, or the function or class of a
+	// declaration. It is nil for parameters, imports, bindings declared by
+	// destructuring and variables declared without initializer.
+	Init ast.Expr
+}
+
+// Constancy classifies the bindings of the program by mutability.
+//
+// A binding written by an assignment, ++, --, a destructuring assignment or
+// the head of a for-in or for-of statement is mutable, as is one declared
+// several times with a value, a var declared with a value in a loop or in
+// the head of a for-in or for-of statement, and the arguments object. So are
+// the bindings eval or with may refer to by name. Bindings of the program
+// scope of a script may also be assigned by other scripts, which is not taken
+// into account.
+func (r *Resolver) Constancy() map[*Binding]*Constancy {
+	c := &constancy{
+		decls:    make(map[*ast.Identifier]*declInfo),
+		top:      make(map[ast.Stmt]bool),
+		declared: make(map[ast.VisitableNode]bool),
+	}
+	c.V = c
+	r.root.Node.VisitWith(c)
+
+	result := make(map[*Binding]*Constancy)
+	r.root.walk(func(s *Scope) {
+		for _, b := range s.Bindings {
+			result[b] = c.classify(b)
+		}
+	})
+	return result
+}
+
+// declInfo describes what a declaring identifier assigns.
+type declInfo struct {
+	// assigns is set if the declaration gives the binding a value, init
+	// being that value if known, and end the index after which it is set.
+	assigns bool
+	init    ast.Expr
+	end     ast.Idx
+	// top is set for declarations run once on entry to their function:
+	// those directly in its body. loop is set for declarations in a loop,
+	// and repeated for the var in the head of a for-in or for-of statement.
+	top      bool
+	loop     bool
+	repeated bool
+}
+
+type constancy struct {
+	ast.NoopVisitor
+
+	decls map[*ast.Identifier]*declInfo
+	// top holds the statements directly in the body of a function or the
+	// program, and declared the functions of function declarations.
+	top      map[ast.Stmt]bool
+	declared map[ast.VisitableNode]bool
+	// loop is the number of loops around the node being visited, in its
+	// function.
+	loop int
+}
+
+func (c *constancy) classify(b *Binding) *Constancy {
+	result := &Constancy{Binding: b, Mutability: MutabilityMutable}
+	if b.Kind == DeclKindArguments || dynamic(b) {
+		return result
+	}
+	for _, ref := range b.References {
+		if ref.Kind != RefKindRead {
+			return result
+		}
+	}
+
+	assigns := 0
+	dominates := true
+	var init ast.Expr
+	for _, id := range b.Decls {
+		d := c.decls[id]
+		if d == nil {
+			// A parameter, catch parameter, import or the name of a function
+			// or class expression, assigned on entry to its scope.
+			assigns++
+			continue
+		}
+		if d.repeated {
+			return result
+		}
+		if !d.assigns {
+			continue
+		}
+		assigns++
+		init = d.init
+		if b.Lexical() || !b.Scope.vars {
+			// Reads before the declaration throw, and functions declared
+			// in a block are set on entry to it.
+			continue
+		}
+		if d.loop {
+			return result
+		}
+		if !d.top {
+			dominates = false
+		}
+		if d.end == 0 {
+			// A function declaration, hoisted.
+			continue
+		}
+		for _, ref := range b.References {
+			if ref.Ident.Idx < d.end || c.hoisted(ref.Scope, b.Scope) {
+				dominates = false
+			}
+		}
+	}
+	if assigns > 1 {
+		return result
+	}
+	for _, ref := range b.References {
+		if ref.TDZ {
+			dominates = false
+		}
+	}
+
+	result.Mutability = MutabilityEffectivelyConstant
+	result.Init = init
+	if dominates {
+		result.Mutability = MutabilityConstant
+	}
+	return result
+}
+
+// hoisted reports whether a function declaration, which may be called before
+// the code around it runs, lies between the scopes from and to.
+func (c *constancy) hoisted(from, to *Scope) bool {
+	for s := from; s != to; s = s.Parent {
+		if c.declared[s.Node] {
+			return true
+		}
+	}
+	return false
+}
+
+// body visits the body of a function or the program.
+func (c *constancy) body(list ast.Statements) {
+	loop := c.loop
+	c.loop = 0
+	for _, s := range list {
+		c.top[s.Stmt] = true
+	}
+	list.VisitChildrenWith(c)
+	c.loop = loop
+}
+
+// declare records the identifiers a declarator declares.
+func (c *constancy) declare(decl *ast.VariableDeclaration, d *ast.VariableDeclarator, repeated bool) {
+	var init ast.Expr
+	var end ast.Idx
+	if d.Initializer != nil {
+		init = d.Initializer.Expr
+		end = init.Idx1()
+	}
+	if _, ok := d.Target.Target.(*ast.Identifier); !ok {
+		init = nil
+	}
+	BindingIdents(d.Target.Target, func(id *ast.Identifier) {
+		c.decls[id] = &declInfo{
+			assigns:  d.Initializer != nil || decl.Token != token.Var,
+			init:     init,
+			end:      end,
+			top:      c.top[decl],
+			loop:     c.loop > 0,
+			repeated: repeated,
+		}
+	})
+}
+
+func (c *constancy) forInto(into *ast.ForInto, source *ast.Expression, body *ast.Statement) {
+	switch into := into.Into.(type) {
+	case *ast.VariableDeclaration:
+		for i := range into.List {
+			c.declare(into, &into.List[i], into.Token == token.Var)
+		}
+		into.VisitChildrenWith(c)
+	case *ast.Expression:
+		into.VisitWith(c)
+	}
+	source.VisitWith(c)
+	c.loop++
+	body.VisitWith(c)
+	c.loop--
+}
+
+func (c *constancy) VisitProgram(n *ast.Program) {
+	c.body(n.Body)
+}
+
+func (c *constancy) VisitFunctionLiteral(n *ast.FunctionLiteral) {
+	n.ParameterList.VisitWith(c)
+	if n.Body != nil {
+		c.body(n.Body.List)
+	}
+}
+
+func (c *constancy) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {
+	n.ParameterList.VisitWith(c)
+	switch body := n.Body.Body.(type) {
+	case *ast.BlockStatement:
+		c.body(body.List)
+	case *ast.Expression:
+		body.VisitWith(c)
+	}
+}
+
+func (c *constancy) VisitClassStaticBlock(n *ast.ClassStaticBlock) {
+	c.body(n.Block.List)
+}
+
+func (c *constancy) VisitFunctionDeclaration(n *ast.FunctionDeclaration) {
+	c.decls[n.Function.Name] = &declInfo{
+		assigns: true,
+		init:    n.Function,
+		top:     c.top[n],
+		loop:    c.loop > 0,
+	}
+	c.declared[n.Function] = true
+	n.VisitChildrenWith(c)
+}
+
+func (c *constancy) VisitClassDeclaration(n *ast.ClassDeclaration) {
+	c.decls[n.Class.Name] = &declInfo{assigns: true, init: n.Class}
+	n.VisitChildrenWith(c)
+}
+
+func (c *constancy) VisitVariableDeclaration(n *ast.VariableDeclaration) {
+	for i := range n.List {
+		c.declare(n, &n.List[i], false)
+	}
+	n.VisitChildrenWith(c)
+}
+
+func (c *constancy) VisitForStatement(n *ast.ForStatement) {
+	if n.Initializer != nil {
+		n.Initializer.VisitWith(c)
+	}
+	c.loop++
+	if n.Test != nil {
+		n.Test.VisitWith(c)
+	}
+	if n.Update != nil {
+		n.Update.VisitWith(c)
+	}
+	n.Body.VisitWith(c)
+	c.loop--
+}
+
+func (c *constancy) VisitForInStatement(n *ast.ForInStatement) {
+	c.forInto(n.Into, n.Source, n.Body)
+}
+
+func (c *constancy) VisitForOfStatement(n *ast.ForOfStatement) {
+	c.forInto(n.Into, n.Source, n.Body)
+}
+
+func (c *constancy) VisitWhileStatement(n *ast.WhileStatement) {
+	c.loop++
+	n.VisitChildrenWith(c)
+	c.loop--
+}
+
+func (c *constancy) VisitDoWhileStatement(n *ast.DoWhileStatement) {
+	c.loop++
+	n.VisitChildrenWith(c)
+	c.loop--
+}
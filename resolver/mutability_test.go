@@ -0,0 +1,71 @@
+package resolver_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func TestConstancy(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string // the mutability of the first x and the type of its Init
+	}{
+		{`var x = "push"; a[x](1); var f = () => x;`, "constant *ast.StringLiteral"},
+		{`const x = 1; x;`, "constant *ast.NumberLiteral"},
+		{`let x = 1; x;`, "constant *ast.NumberLiteral"},
+		{`let x; x;`, "constant <nil>"},
+		{`function x() {} x();`, "constant *ast.FunctionLiteral"},
+		{`class x {} new x();`, "constant *ast.ClassLiteral"},
+		{`function f(x) { return x; }`, "constant <nil>"},
+		{`import x from "m"; x;`, "constant <nil>"},
+		{`var {x} = o; x;`, "constant <nil>"},
+
+		// Reads that may come first.
+		{`x; var x = 1;`, "effectively constant *ast.NumberLiteral"},
+		{`function f() { return x; } f(); var x = 1;`, "effectively constant *ast.NumberLiteral"},
+		{`if (a) { var x = 1; } x;`, "effectively constant *ast.NumberLiteral"},
+		// f may run before the var is set, but reading a let early throws.
+		{`var x = 1; function f() { return x; }`, "effectively constant *ast.NumberLiteral"},
+		{`f(); let x = 1; function f() { return x; }`, "constant *ast.NumberLiteral"},
+		{`var x; x;`, "constant <nil>"},
+
+		// Writes.
+		{`var x = 1; x = 2;`, "mutable <nil>"},
+		{`var x = 1; x++;`, "mutable <nil>"},
+		{`let x = 1; x += 1;`, "mutable <nil>"},
+		{`var x = 1; [x] = o;`, "mutable <nil>"},
+		{`var x = 1; ({k: x} = o);`, "mutable <nil>"},
+		{`var x; for (x in o);`, "mutable <nil>"},
+		{`for (var x of o);`, "mutable <nil>"},
+		{`var x = 1; var x = 2;`, "mutable <nil>"},
+		{`while (a) { var x = 1; }`, "mutable <nil>"},
+		{`for (;;) { let x = 1; }`, "constant *ast.NumberLiteral"},
+		{`for (const x of o) x;`, "constant <nil>"},
+		{`var x = 1; function f() { x = 2; }`, "mutable <nil>"},
+		{`function f() { var x = 1; eval("x = 2"); }`, "mutable <nil>"},
+		{`var x = 1; with (o) { x; }`, "mutable <nil>"},
+		{`try {} catch (x) { var x = 1; }`, "mutable <nil>"},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		r := resolver.Resolve(p)
+		c := r.Constancy()[r.Binding(idents(p, "x")[0])]
+		if c == nil {
+			t.Errorf("Constancy(%s) has no entry for x", tt.src)
+			continue
+		}
+		if got := fmt.Sprintf("%s %T", c.Mutability, c.Init); got != tt.want {
+			t.Errorf("Constancy(%s)[x] = %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestConstancyArguments(t *testing.T) {
+	r := resolver.Resolve(parse(t, `function f() { return arguments; }`))
+	b := r.Root().Children[0].Bindings["arguments"]
+	if c := r.Constancy()[b]; c == nil || c.Mutability != resolver.MutabilityMutable {
+		t.Errorf("the arguments object is %v, want mutable", c)
+	}
+}
@@ -0,0 +1,185 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Rename renames the binding b of p, a resolved program, to name, rewriting
+// its declarations and references. Shorthand properties and specifiers
+// naming the binding are expanded to keep the property or exported name, so
+// {a} becomes {a: name} and export {a} becomes export {name as a}.
+//
+// Rename returns an error and leaves p unchanged if name is not an
+// identifier, or if another binding named name is declared in the scope of b,
+// would shadow a reference to b, or would be shadowed by b. Bindings a direct
+// eval or a with statement may refer to by name cannot be renamed.
+func Rename(p *ast.Program, b *Binding, name string) error {
+	if name == b.Name {
+		return nil
+	}
+	if b.Kind == DeclKindArguments {
+		return fmt.Errorf("resolver: cannot rename the arguments object")
+	}
+	if dynamic(b) {
+		return fmt.Errorf("resolver: cannot rename %s: it may be referred to by name through eval or with", b.Name)
+	}
+	if exportedDecl(p, b) {
+		return fmt.Errorf("resolver: cannot rename %s: its declaration is exported under its name", b.Name)
+	}
+	if catchVar(b) {
+		return fmt.Errorf("resolver: cannot rename %s: a var in a catch clause redeclares it with the catch parameter", b.Name)
+	}
+	if !build.IsIdentifierName(name) {
+		return fmt.Errorf("resolver: cannot rename %s: %q is not an identifier", b.Name, name)
+	}
+	if tok, _ := token.LiteralKeyword(name); tok != 0 {
+		return fmt.Errorf("resolver: cannot rename %s: %s is a reserved word", b.Name, name)
+	}
+	if err := renameConflict(b, name); err != nil {
+		return err
+	}
+
+	rn := &renamer{idents: make(map[*ast.Identifier]struct{})}
+	rn.V = rn
+	for _, id := range b.Decls {
+		rn.idents[id] = struct{}{}
+	}
+	for _, ref := range b.References {
+		rn.idents[ref.Ident] = struct{}{}
+	}
+	p.VisitWith(rn)
+
+	for id := range rn.idents {
+		id.Name = name
+	}
+	delete(b.Scope.Bindings, b.Name)
+	b.Name = name
+	b.Scope.Bindings[name] = b
+	return nil
+}
+
+func renameConflict(b *Binding, name string) error {
+	if other := b.Scope.Bindings[name]; other != nil {
+		return fmt.Errorf("resolver: cannot rename %s: %s is declared in the same scope", b.Name, name)
+	}
+
+	// A binding named name between a reference and b would capture it.
+	for _, ref := range b.References {
+		for s := ref.Scope; s != b.Scope; s = s.Parent {
+			if s.Bindings[name] != nil {
+				return fmt.Errorf("resolver: cannot rename %s: a reference would resolve to the %s declared in a nested scope", b.Name, name)
+			}
+		}
+	}
+
+	// b would capture references to an outer binding or global named name
+	// from its scope.
+	var refs []*Reference
+	if outer := b.Scope.Parent.Lookup(name); outer != nil {
+		refs = outer.References
+	} else if g := b.Scope.root().globals[name]; g != nil {
+		refs = g.References
+	}
+	for _, ref := range refs {
+		for s := ref.Scope; s != nil; s = s.Parent {
+			if s == b.Scope {
+				return fmt.Errorf("resolver: cannot rename %s: it would shadow the outer %s", b.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// renamer expands the shorthand properties and specifiers naming the
+// identifiers being renamed.
+type renamer struct {
+	ast.NoopVisitor
+
+	idents map[*ast.Identifier]struct{}
+}
+
+func (rn *renamer) VisitProperty(n *ast.Property) {
+	n.VisitChildrenWith(rn)
+
+	short, ok := n.Prop.(*ast.PropertyShort)
+	if !ok {
+		return
+	}
+	if _, ok := rn.idents[short.Name]; !ok {
+		return
+	}
+	var value ast.Expr = short.Name
+	if short.Initializer != nil && short.Initializer.Expr != nil {
+		value = build.Assign(short.Name, short.Initializer.Expr)
+	}
+	prop := build.Prop(short.Name.Name, value)
+	prop.Key.Expr.(*ast.StringLiteral).Idx = short.Name.Idx
+	n.Prop = prop
+}
+
+func (rn *renamer) VisitImportSpecifier(n *ast.ImportSpecifier) {
+	// Without an alias, the imported name and the local binding are the
+	// same identifier.
+	if n.Imported == n.Local {
+		if _, ok := rn.idents[n.Local]; ok {
+			n.Imported = &ast.Identifier{Idx: n.Local.Idx, Name: n.Local.Name}
+		}
+	}
+	delete(rn.idents, n.Imported)
+}
+
+func (rn *renamer) VisitExportSpecifier(n *ast.ExportSpecifier) {
+	// The exported name stays the same, also where one identifier is both
+	// names.
+	if n.Exported == n.Local {
+		if _, ok := rn.idents[n.Local]; ok {
+			n.Exported = &ast.Identifier{Idx: n.Local.Idx, Name: n.Local.Name}
+		}
+	}
+}
+
+// dynamic reports whether b may be referred to by name at run time, by a
+// direct eval in its scope or from inside a with statement.
+func dynamic(b *Binding) bool {
+	if b.Scope.Eval {
+		return true
+	}
+	for _, ref := range b.References {
+		if ref.With {
+			return true
+		}
+	}
+	return false
+}
+
+// exportedDecl reports whether b is declared by an exported declaration, as
+// in export function f() {}, which exports it by its name.
+func exportedDecl(p *ast.Program, b *Binding) bool {
+	if b.Scope.Parent != nil || len(b.Decls) == 0 {
+		return false
+	}
+	for _, s := range p.Body {
+		export, ok := s.Stmt.(*ast.ExportDeclaration)
+		if !ok || export.Declaration == nil {
+			continue
+		}
+		found := false
+		declIdents(export.Declaration.Stmt, func(id *ast.Identifier) { found = found || slices.Contains(b.Decls, id) })
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// catchVar reports whether b is a catch parameter redeclared by a var, or a
+// var declared only that way. The identifier of such a var declares both, so
+// neither can be renamed on its own.
+func catchVar(b *Binding) bool {
+	return b.Kind == DeclKindCatch && len(b.Decls) > 1 || len(b.Decls) == 0 && b.Kind == DeclKindVar
+}
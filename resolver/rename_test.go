@@ -0,0 +1,101 @@
+package resolver_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func TestRename(t *testing.T) {
+	tests := []struct {
+		src        string
+		name       string
+		to         string
+		want       string // the renamed program, or the error
+		occurrence int
+	}{
+		{src: `var a = 1; function f() { return a + 1; } a++;`, name: "a", to: "b",
+			want: `var b = 1; function f() { return b + 1; } b++;`},
+		{src: `var a; function f(a) { return a; }`, name: "a", occurrence: 1, to: "b",
+			want: `var a; function f(b) { return b; }`},
+		{src: `let a = 1; x = {a, b: a};`, name: "a", to: "c",
+			want: `let c = 1; x = {a: c, b: c};`},
+		{src: `var a; ({a = 1} = o);`, name: "a", to: "c",
+			want: `var c; ({a: c = 1} = o);`},
+		{src: `var a; o.a; ({a: 1});`, name: "a", to: "c",
+			want: `var c; o.a; ({a: 1});`},
+		{src: `function f() {} f();`, name: "f", to: "f",
+			want: `function f() {} f();`},
+		{src: `var a; try {} catch (e) { e; }`, name: "e", to: "a",
+			want: `var a; try {} catch (a) { a; }`},
+
+		{src: `var a, b;`, name: "a", to: "b",
+			want: "resolver: cannot rename a: b is declared in the same scope"},
+		{src: `var a; function f() { var b; a; }`, name: "a", to: "b",
+			want: "resolver: cannot rename a: a reference would resolve to the b declared in a nested scope"},
+		{src: `b; function f() { var a; a; b; }`, name: "a", to: "b",
+			want: "resolver: cannot rename a: it would shadow the outer b"},
+		{src: `var b; function f() { var a; b; }`, name: "a", to: "b",
+			want: "resolver: cannot rename a: it would shadow the outer b"},
+		{src: `var a;`, name: "a", to: "1a",
+			want: `resolver: cannot rename a: "1a" is not an identifier`},
+		{src: `var a;`, name: "a", to: "if",
+			want: "resolver: cannot rename a: if is a reserved word"},
+		{src: `function f() { var a; eval("a"); }`, name: "a", to: "b",
+			want: "resolver: cannot rename a: it may be referred to by name through eval or with"},
+		{src: `var a; with (o) { a; }`, name: "a", to: "b",
+			want: "resolver: cannot rename a: it may be referred to by name through eval or with"},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		r := resolver.Resolve(p)
+		b := r.Binding(idents(p, tt.name)[tt.occurrence])
+		err := resolver.Rename(p, b, tt.to)
+		if strings.HasPrefix(tt.want, "resolver:") {
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Rename(%s, %s, %s) = %v, want %s", tt.src, tt.name, tt.to, err, tt.want)
+			}
+			if got, want := generator.Generate(p), generator.Generate(parse(t, tt.src)); got != want {
+				t.Errorf("Rename(%s, %s, %s) failed but changed the program to %s", tt.src, tt.name, tt.to, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Rename(%s, %s, %s): %v", tt.src, tt.name, tt.to, err)
+			continue
+		}
+		if got, want := generator.Generate(p), generator.Generate(parse(t, tt.want)); got != want {
+			t.Errorf("Rename(%s, %s, %s) = %s, want %s", tt.src, tt.name, tt.to, got, want)
+		}
+		if b.Name != tt.to || b.Scope.Bindings[tt.to] != b || (tt.to != tt.name && b.Scope.Bindings[tt.name] != nil) {
+			t.Errorf("Rename(%s, %s, %s) did not rebind %s in its scope", tt.src, tt.name, tt.to, tt.to)
+		}
+	}
+}
+
+func TestRenameArguments(t *testing.T) {
+	p := parse(t, `function f() { return arguments; }`)
+	r := resolver.Resolve(p)
+	b := r.Root().Children[0].Bindings["arguments"]
+	if err := resolver.Rename(p, b, "args"); err == nil {
+		t.Errorf("renamed the arguments object")
+	}
+}
+
+func TestRenameImport(t *testing.T) {
+	p := parse(t, `import {a} from "m"; a();`)
+	r := resolver.Resolve(p)
+	if err := resolver.Rename(p, r.Root().Bindings["a"], "b"); err != nil {
+		t.Fatal(err)
+	}
+	imp := r.Module().Imports[0]
+	spec := imp.Decl.Specifiers[0]
+	if spec.Imported.Name != "a" || spec.Local.Name != "b" || imp.Binding.Name != "b" {
+		t.Errorf("import {a} renamed to import {%s as %s}", spec.Imported.Name, spec.Local.Name)
+	}
+	if imp.Name != "a" {
+		t.Errorf("imported name = %s, want a", imp.Name)
+	}
+}
@@ -0,0 +1,149 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/t14raptor/go-fast/ast"
+)
+
+type resetter struct {
+	ast.NoopVisitor
+}
+
+// Reset clears the contexts of the identifiers and scopes in n, so that it
+// can be resolved again.
+func Reset(n ast.VisitableNode) {
+	v := &resetter{}
+	v.V = v
+	n.VisitWith(v)
+}
+
+func (v *resetter) VisitIdentifier(n *ast.Identifier) {
+	n.ScopeContext = UnresolvedMark
+}
+
+func (v *resetter) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {
+	n.ScopeContext = UnresolvedMark
+	n.VisitChildrenWith(v)
+}
+
+func (v *resetter) VisitBlockStatement(n *ast.BlockStatement) {
+	n.ScopeContext = UnresolvedMark
+	n.VisitChildrenWith(v)
+}
+
+func (v *resetter) VisitFunctionLiteral(n *ast.FunctionLiteral) {
+	n.ScopeContext = UnresolvedMark
+	n.VisitChildrenWith(v)
+}
+
+// Reresolve resolves n again after a transform changed it, against the
+// scopes around it, which is cheaper than resolving the whole program. n must
+// be a function, an arrow function or a block statement with a scope of its
+// own, as resolved by r.
+//
+// The scopes of n are replaced, and the bindings and globals around it drop
+// the references from n. Declarations in n of bindings around it, such as
+// vars hoisted out of a block, keep their binding; new ones are declared
+// there. Flags set on the enclosing scopes by eval, with or arguments in n
+// are kept, and references from n to let, const and class bindings around it
+// are taken to be past their dead zone.
+func (r *Resolver) Reresolve(n ast.VisitableNode) error {
+	s, err := r.scopeOf(n)
+	if err != nil {
+		return err
+	}
+	parent := s.Parent
+	i := slices.Index(parent.Children, s)
+
+	removed := make(map[*Scope]bool)
+	s.walk(func(s *Scope) {
+		removed[s] = true
+		delete(r.scopes, s.Context)
+	})
+	parent.Children = slices.Delete(parent.Children, i, i+1)
+
+	// Identifiers in n stamped with an outer scope declare or refer to a
+	// binding around it.
+	kept := make(map[*ast.Identifier]ast.ScopeContext)
+	walkIdents(n, func(id *ast.Identifier) {
+		if b := r.Binding(id); b != nil && slices.Contains(b.Decls, id) {
+			kept[id] = id.ScopeContext
+		}
+	})
+	for s := parent; s != nil; s = s.Parent {
+		for _, b := range s.Bindings {
+			b.References = slices.DeleteFunc(b.References, func(ref *Reference) bool { return removed[ref.Scope] })
+		}
+	}
+	for name, g := range r.globals {
+		g.References = slices.DeleteFunc(g.References, func(ref *Reference) bool { return removed[ref.Scope] })
+		if len(g.References) == 0 {
+			delete(r.globals, name)
+		}
+	}
+
+	Reset(n)
+	for id, ctx := range kept {
+		id.ScopeContext = ctx
+	}
+
+	r.current = parent
+	r.strict = parent.Strict
+	r.with = s.with
+	switch n := n.(type) {
+	case *ast.FunctionLiteral:
+		if s.Kind == ScopeKindName {
+			r.VisitFunctionLiteral(n)
+		} else {
+			r.function(n)
+		}
+	case *ast.ArrowFunctionLiteral:
+		r.VisitArrowFunctionLiteral(n)
+	case *ast.BlockStatement:
+		// New vars in the block are hoisted to the enclosing function.
+		vars := parent
+		for !vars.vars {
+			vars = vars.Parent
+		}
+		r.current = vars
+		n.VisitWith(NewHoister(r))
+		r.current = parent
+		r.VisitBlockStatement(n)
+	}
+	r.with = nil
+
+	// Put the new scope where the old one was.
+	last := len(parent.Children) - 1
+	ns := parent.Children[last]
+	parent.Children = slices.Insert(parent.Children[:last], i, ns)
+	return nil
+}
+
+// scopeOf returns the outermost scope owned by n.
+func (r *Resolver) scopeOf(n ast.VisitableNode) (*Scope, error) {
+	var ctx ast.ScopeContext
+	switch n := n.(type) {
+	case *ast.FunctionLiteral:
+		ctx = n.ScopeContext
+	case *ast.ArrowFunctionLiteral:
+		ctx = n.ScopeContext
+	case *ast.BlockStatement:
+		ctx = n.ScopeContext
+	default:
+		return nil, fmt.Errorf("resolver: cannot resolve a %T again", n)
+	}
+	s := r.scopes[ctx]
+	if s == nil || s.Node != n {
+		return nil, fmt.Errorf("resolver: %T has no scope of its own", n)
+	}
+	if s.vars && s.Kind == ScopeKindBlock {
+		// The body of a function with parameter expressions.
+		return nil, fmt.Errorf("resolver: a function body must be resolved again with its function")
+	}
+	if s.Parent.Node == n {
+		s = s.Parent
+	}
+	return s, nil
+}
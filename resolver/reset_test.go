@@ -0,0 +1,122 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+type contextCollector struct {
+	ast.NoopVisitor
+	contexts []ast.ScopeContext
+}
+
+func (c *contextCollector) VisitIdentifier(n *ast.Identifier) {
+	c.contexts = append(c.contexts, n.ScopeContext)
+}
+
+func (c *contextCollector) VisitBlockStatement(n *ast.BlockStatement) {
+	c.contexts = append(c.contexts, n.ScopeContext)
+	n.VisitChildrenWith(c)
+}
+
+func (c *contextCollector) VisitFunctionLiteral(n *ast.FunctionLiteral) {
+	c.contexts = append(c.contexts, n.ScopeContext)
+	n.VisitChildrenWith(c)
+}
+
+func (c *contextCollector) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {
+	c.contexts = append(c.contexts, n.ScopeContext)
+	n.VisitChildrenWith(c)
+}
+
+func TestReset(t *testing.T) {
+	p := parse(t, `var a; function f(b) { { let c = a + b; } return () => b; }`)
+	resolver.Resolve(p)
+	resolver.Reset(p)
+
+	c := &contextCollector{}
+	c.V = c
+	p.VisitWith(c)
+	for i, ctx := range c.contexts {
+		if ctx != resolver.UnresolvedMark {
+			t.Errorf("context %d is %d after Reset", i, ctx)
+		}
+	}
+
+	// The program can be resolved again.
+	r := resolver.Resolve(p)
+	if got, want := dump(r.Root()), dump(resolver.Resolve(parse(t, `var a; function f(b) { { let c = a + b; } return () => b; }`)).Root()); got != want {
+		t.Errorf("resolved after Reset:\ngot  %s\nwant %s", got, want)
+	}
+}
+
+// insert parses src and appends its statements to list, unresolved.
+func insert(t *testing.T, list *ast.Statements, src string) {
+	t.Helper()
+	*list = append(*list, parse(t, src).Body...)
+}
+
+func TestReresolve(t *testing.T) {
+	const src = `var x = 1; function f(a) { return a + x; } function g() { { let y; y; } }`
+	p := parse(t, src)
+	r := resolver.Resolve(p)
+	f := p.Body[1].Stmt.(*ast.FunctionDeclaration).Function
+	block := p.Body[2].Stmt.(*ast.FunctionDeclaration).Function.Body.List[0].Stmt.(*ast.BlockStatement)
+
+	insert(t, &f.Body.List, `var t = x; x = t + undefinedName;`)
+	if err := r.Reresolve(f); err != nil {
+		t.Fatal(err)
+	}
+	insert(t, &block.List, `var h = y;`)
+	if err := r.Reresolve(block); err != nil {
+		t.Fatal(err)
+	}
+
+	// The scopes are those of the changed program resolved anew, and in the
+	// same order.
+	want := `var x = 1; function f(a) { return a + x; var t = x; x = t + undefinedName; } function g() { { let y; y; var h = y; } }`
+	if got, want := dump(r.Root()), dump(resolver.Resolve(parse(t, want)).Root()); got != want {
+		t.Errorf("scopes after Reresolve:\ngot  %s\nwant %s", got, want)
+	}
+
+	x := r.Root().Bindings["x"]
+	if len(x.References) != 3 {
+		t.Errorf("x has %d references, want 3 from the new f and none from the old", len(x.References))
+	}
+	for _, ref := range x.References {
+		if r.Scope(ref.Scope.Context) != ref.Scope {
+			t.Errorf("reference to x from a dropped scope")
+		}
+	}
+	globals := r.Globals()
+	if len(globals) != 1 || globals[0].Name != "undefinedName" {
+		t.Errorf("Globals() = %v, want undefinedName", globals)
+	}
+	h := r.Binding(idents(p, "h")[0])
+	if h == nil || h.Scope.Node != p.Body[2].Stmt.(*ast.FunctionDeclaration).Function {
+		t.Errorf("var h is not hoisted to g")
+	}
+	if b := r.Binding(idents(p, "y")[2]); b == nil || b.Kind != resolver.DeclKindLet || len(b.References) != 2 {
+		t.Errorf("y in the new var resolves to %+v", b)
+	}
+}
+
+func TestReresolveErrors(t *testing.T) {
+	p := parse(t, `function f(a = 1) { } if (x) y;`)
+	r := resolver.Resolve(p)
+	f := p.Body[0].Stmt.(*ast.FunctionDeclaration).Function
+	for _, n := range []ast.VisitableNode{
+		f.Body, // shares the resolution of its parameters
+		p.Body[1].Stmt.(*ast.IfStatement),
+		&ast.BlockStatement{}, // never resolved
+	} {
+		if err := r.Reresolve(n); err == nil {
+			t.Errorf("Reresolve(%T) succeeded", n)
+		}
+	}
+	if err := r.Reresolve(f); err != nil {
+		t.Errorf("Reresolve(f): %v", err)
+	}
+}
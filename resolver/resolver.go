@@ -1,11 +1,17 @@
 package resolver
 
 import (
-	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/token"
 )
 
+// IdentType tells whether an identifier is referred to or declared.
+//
+// Deprecated: the resolver no longer uses it. Binding.Decls and
+// Binding.References tell declarations from references.
 type IdentType int
 
 const (
@@ -13,46 +19,24 @@ const (
 	IdentTypeBinding                  // Binding (declaration)
 )
 
-type DeclKind int
-
-const (
-	DeclKindVar DeclKind = iota
-	DeclKindFunction
-)
-
-type ScopeKind int
-
-const (
-	ScopeKindBlock ScopeKind = iota
-	ScopeKindFunction
-)
-
-type Scope struct {
-	parent *Scope
-
-	kind ScopeKind
-
-	ctx ast.ScopeContext
-
-	declaredSymbols map[string]DeclKind
-}
-
-func (s *Scope) isDeclared(id string) (DeclKind, bool) {
-	for scope := s; scope != nil; scope = scope.parent {
-		if declKind, exists := scope.declaredSymbols[id]; exists {
-			return declKind, true
-		}
-	}
-	return 0, false
-}
-
 type Resolver struct {
 	ast.NoopVisitor
 
+	root    *Scope
 	current *Scope
+	scopes  map[ast.ScopeContext]*Scope
+	globals map[string]*Global
 
-	identType IdentType
-	declKind  DeclKind
+	// strict is set in strict mode code, where functions declared in blocks
+	// are not hoisted.
+	strict bool
+	// with holds the scopes enclosing the with statements being visited.
+	with []*Scope
+
+	module Module
+	// exports holds the exports of local bindings, linked once the program
+	// has been visited.
+	exports []pendingExport
 
 	nextCtxt ast.ScopeContext
 }
@@ -62,10 +46,14 @@ const (
 	TopLevelMark   ast.ScopeContext = 1
 )
 
+// Resolve resolves the identifiers of p, which must not have been resolved
+// before. Identifiers declared or referred to are stamped with the context of
+// the scope declaring them, and free variables are left unresolved.
 func Resolve(p *ast.Program) *Resolver {
 	r := &Resolver{
-		identType: IdentTypeRef,
-		nextCtxt:  TopLevelMark,
+		nextCtxt: TopLevelMark,
+		scopes:   make(map[ast.ScopeContext]*Scope),
+		globals:  make(map[string]*Global),
 	}
 	r.V = r
 
@@ -73,21 +61,65 @@ func Resolve(p *ast.Program) *Resolver {
 	return r
 }
 
-func (r *Resolver) pushScope(kind ScopeKind) {
+// Globals resolves p and returns its free variables, the names it refers to
+// without declaring them, sorted by name.
+func Globals(p *ast.Program) []*Global {
+	return Resolve(p).Globals()
+}
+
+// Root returns the scope of the program.
+func (r *Resolver) Root() *Scope { return r.root }
+
+// Scope returns the scope with the context ctx, or nil.
+func (r *Resolver) Scope(ctx ast.ScopeContext) *Scope { return r.scopes[ctx] }
+
+// Globals returns the free variables of the program, sorted by name.
+func (r *Resolver) Globals() []*Global {
+	globals := make([]*Global, 0, len(r.globals))
+	for _, g := range r.globals {
+		globals = append(globals, g)
+	}
+	slices.SortFunc(globals, func(a, b *Global) int { return strings.Compare(a.Name, b.Name) })
+	return globals
+}
+
+// Binding returns the binding id declares or refers to, or nil if id is
+// unresolved.
+func (r *Resolver) Binding(id *ast.Identifier) *Binding {
+	if s := r.scopes[id.ScopeContext]; s != nil {
+		return s.Bindings[id.Name]
+	}
+	return nil
+}
+
+func (r *Resolver) pushScope(kind ScopeKind, node ast.VisitableNode) {
 	ctx := r.nextCtxt
 	r.nextCtxt++
 
-	r.current = &Scope{
-		parent:          r.current,
-		kind:            kind,
-		declaredSymbols: make(map[string]DeclKind),
-		ctx:             ctx,
+	s := &Scope{
+		Kind:     kind,
+		Parent:   r.current,
+		Node:     node,
+		Context:  ctx,
+		Strict:   r.strict,
+		Bindings: make(map[string]*Binding),
+	}
+	if len(r.with) > 0 {
+		s.with = slices.Clone(r.with)
+	}
+	if r.current != nil {
+		r.current.Children = append(r.current.Children, s)
+	} else {
+		r.root = s
+		s.globals = r.globals
 	}
+	r.scopes[ctx] = s
+	r.current = s
 }
 
 func (r *Resolver) popScope() {
-	if r.current.parent != nil {
-		r.current = r.current.parent
+	if r.current.Parent != nil {
+		r.current = r.current.Parent
 	}
 }
 
@@ -96,182 +128,620 @@ func (r *Resolver) modify(id *ast.Identifier, kind DeclKind) {
 		return
 	}
 
-	r.current.declaredSymbols[id.Name] = kind
+	b := r.current.Bindings[id.Name]
+	if b == nil {
+		b = &Binding{Name: id.Name, Kind: kind, Scope: r.current}
+		// Parameters and catch parameters are initialized once their
+		// pattern is evaluated, like lexical declarations.
+		b.initialized = !b.Lexical() && kind != DeclKindParam && kind != DeclKindCatch
+		r.current.Bindings[id.Name] = b
+	} else if kind == DeclKindFunction {
+		b.Kind = kind
+		b.initialized = true
+	}
+	b.Decls = append(b.Decls, id)
 
-	id.ScopeContext = r.current.ctx
+	id.ScopeContext = r.current.Context
 }
 
-func (r *Resolver) lookupContext(sym string) (ast.ScopeContext, *Scope) {
-	for scope := r.current; scope != nil; scope = scope.parent {
-		if _, exists := scope.declaredSymbols[sym]; exists {
-			return scope.ctx, scope
+// hoistCatchVar declares in the current scope the var id declares, which is
+// named after an enclosing catch parameter. The var is still hoisted, but id
+// redeclares the parameter and its initializer assigns to it, so id is left
+// to the parameter and the var has no declaring identifier of its own.
+func (r *Resolver) hoistCatchVar(id *ast.Identifier) {
+	if r.current.Bindings[id.Name] == nil {
+		r.current.Bindings[id.Name] = &Binding{Name: id.Name, Kind: DeclKindVar, Scope: r.current, initialized: true}
+	}
+}
+
+// declare declares id in the current scope, unless the hoister already has.
+func (r *Resolver) declare(id *ast.Identifier, kind DeclKind) {
+	if id.ScopeContext != UnresolvedMark {
+		return
+	}
+	if kind == DeclKindVar {
+		// A var named after an enclosing catch parameter redeclares it.
+		if b := r.current.Lookup(id.Name); b != nil && b.Kind == DeclKindCatch {
+			id.ScopeContext = b.Scope.Context
+			b.Decls = append(b.Decls, id)
+			return
 		}
 	}
-	return UnresolvedMark, nil
+	r.modify(id, kind)
 }
 
-func (r *Resolver) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {
-	r.pushScope(ScopeKindFunction)
+// declarePattern declares the identifiers of a binding pattern.
+func (r *Resolver) declarePattern(target ast.Expr, kind DeclKind) {
+	BindingIdents(target, func(id *ast.Identifier) { r.declare(id, kind) })
+}
 
-	n.ScopeContext = r.current.ctx
+// initialize ends the temporal dead zone of the bindings target declares.
+func (r *Resolver) initialize(target ast.Expr) {
+	BindingIdents(target, func(id *ast.Identifier) {
+		if b := r.Binding(id); b != nil {
+			b.initialized = true
+		}
+	})
+}
 
-	oldIdentType := r.identType
-	r.identType = IdentTypeBinding
-	n.ParameterList.VisitWith(r)
+// reference resolves id as a reference of the given kind. If no binding is
+// in scope, id is left unresolved and recorded as a global.
+func (r *Resolver) reference(id *ast.Identifier, kind RefKind) {
+	if id.ScopeContext != UnresolvedMark {
+		return
+	}
 
-	r.identType = IdentTypeRef
-	switch body := n.Body.Body.(type) {
-	case *ast.BlockStatement:
-		body.ScopeContext = r.current.ctx
-		// Prevent creating a new scope.
-		body.VisitChildrenWith(r)
-	case *ast.Expression:
-		body.VisitWith(r)
+	if id.Name == "arguments" {
+		r.arguments()
 	}
-	r.identType = oldIdentType
 
-	r.popScope()
+	b := r.current.Lookup(id.Name)
+	ref := &Reference{Ident: id, Kind: kind, Scope: r.current}
+	if len(r.with) > 0 {
+		// Unless declared in the body, the name may resolve to a property of
+		// the object.
+		w := r.with[len(r.with)-1]
+		ref.With = b == nil || b.Scope.contains(w)
+	}
+	if b != nil {
+		id.ScopeContext = b.Scope.Context
+		ref.TDZ = !b.initialized && !r.deferred(b.Scope)
+		b.References = append(b.References, ref)
+	} else {
+		g := r.globals[id.Name]
+		if g == nil {
+			g = &Global{Name: id.Name}
+			r.globals[id.Name] = g
+		}
+		g.References = append(g.References, ref)
+	}
 }
 
-func (r *Resolver) VisitBlockStatement(n *ast.BlockStatement) {
-	r.pushScope(ScopeKindBlock)
-	n.VisitChildrenWith(r)
-	r.popScope()
+// arguments declares the arguments object of the function the current
+// scope is in, unless a binding named arguments shadows it.
+func (r *Resolver) arguments() {
+	var fn *Scope
+	for s := r.current; s != nil && fn == nil; s = s.Parent {
+		if s.Bindings["arguments"] != nil {
+			return
+		}
+		if s.Kind != ScopeKindFunction {
+			continue
+		}
+		switch n := s.Node.(type) {
+		case *ast.FunctionLiteral:
+			fn = s
+			fn.MappedArguments = !fn.Strict && simpleParams(&n.ParameterList)
+		case *ast.ArrowFunctionLiteral:
+		default:
+			// Class fields and static blocks have no arguments.
+			return
+		}
+	}
+	if fn == nil {
+		return
+	}
+
+	fn.Arguments = true
+	fn.Bindings["arguments"] = &Binding{Name: "arguments", Kind: DeclKindArguments, Scope: fn, initialized: true}
 }
 
-func (r *Resolver) VisitForOfStatement(n *ast.ForOfStatement) {
-	r.pushScope(ScopeKindBlock) // Using Block scope for ForOfStatement
+// deferred reports whether the current scope is inside a function nested in
+// s, and so may run after s is fully evaluated.
+func (r *Resolver) deferred(s *Scope) bool {
+	for scope := r.current; scope != s; scope = scope.Parent {
+		if scope.Kind == ScopeKindFunction {
+			return true
+		}
+	}
+	return false
+}
 
-	oldIdentType := r.identType
-	r.identType = IdentTypeRef
+// read visits n, whose identifiers are all read.
+func (r *Resolver) read(n ast.VisitableNode) {
+	n.VisitWith(r)
+}
 
-	// Handle the 'Into' part (left-hand side of for...of)
-	n.Into.VisitWith(r)
+// pattern visits a binding or assignment target. If bind is set, its
+// identifiers have been declared and are skipped, and otherwise they are
+// references of the given kind. Default values, computed keys and the
+// objects of member expressions are read.
+func (r *Resolver) pattern(target ast.Expr, bind bool, kind RefKind) {
+	switch t := target.(type) {
+	case nil:
+	case *ast.Identifier:
+		if !bind {
+			r.reference(t, kind)
+		}
+	case *ast.ObjectPattern:
+		for _, prop := range t.Properties {
+			switch prop := prop.Prop.(type) {
+			case *ast.PropertyShort:
+				r.pattern(prop.Name, bind, kind)
+				if prop.Initializer != nil && prop.Initializer.Expr != nil {
+					r.read(prop.Initializer)
+				}
+			case *ast.PropertyKeyed:
+				if prop.Computed {
+					r.read(prop.Key)
+				}
+				r.pattern(prop.Value.Expr, bind, kind)
+			}
+		}
+		r.pattern(t.Rest, bind, kind)
+	case *ast.ArrayPattern:
+		for _, elem := range t.Elements {
+			r.pattern(elem.Expr, bind, kind)
+		}
+		if t.Rest != nil {
+			r.pattern(t.Rest.Expr, bind, kind)
+		}
+	case *ast.AssignExpression:
+		// A default value.
+		r.pattern(t.Left.Expr, bind, kind)
+		r.read(t.Right)
+	default:
+		r.read(t)
+	}
+}
 
-	// Handle the 'Source' part (right-hand side of for...of)
-	n.Source.VisitWith(r)
+// bind visits a declared binding pattern and its initializer, then ends the
+// dead zone of its bindings.
+func (r *Resolver) bind(target ast.Expr, init *ast.Expression) {
+	if init != nil {
+		r.read(init)
+	}
+	r.pattern(target, true, RefKindRead)
+	r.initialize(target)
+}
 
-	if blockStmt, ok := n.Body.Stmt.(*ast.BlockStatement); ok {
-		blockStmt.ScopeContext = r.current.ctx
+// params declares the parameters of a function, then visits their default
+// values and computed keys in order.
+func (r *Resolver) params(n *ast.ParameterList) {
+	for _, param := range n.List {
+		r.declarePattern(param.Target.Target, DeclKindParam)
 	}
-	n.Body.VisitWith(r)
+	r.declarePattern(n.Rest, DeclKindParam)
+
+	for _, param := range n.List {
+		r.bind(param.Target.Target, param.Initializer)
+	}
+	r.bind(n.Rest, nil)
+}
+
+// body resolves the body of a function. Its declarations share the scope of
+// the parameters, unless default values or computed keys in the parameters
+// could see them, in which case they get a scope of their own.
+func (r *Resolver) body(n *ast.BlockStatement, params *ast.ParameterList) {
+	separate := hasExpression(params.Rest)
+	for _, param := range params.List {
+		separate = separate || param.Initializer != nil || hasExpression(param.Target.Target)
+	}
+	if separate {
+		r.pushScope(ScopeKindBlock, n)
+	}
+	n.ScopeContext = r.current.Context
+	r.hoist(n.List, params)
+	n.List.VisitWith(r)
+	if separate {
+		r.popScope()
+	}
+}
+
+// hoist declares the bindings of a function or program body in the current
+// scope.
+func (r *Resolver) hoist(list ast.Statements, params *ast.ParameterList) {
+	r.current.vars = true
+	r.declareLexical(list)
+	NewHoister(r).hoist(list, params)
+}
+
+// declareLexical declares the let, const, class and function declarations
+// of a statement list in the current scope, except for functions already
+// hoisted.
+func (r *Resolver) declareLexical(list ast.Statements) {
+	for _, s := range list {
+		switch s := unlabel(s.Stmt).(type) {
+		case *ast.VariableDeclaration:
+			if s.Token == token.Var {
+				continue
+			}
+			for _, decl := range s.List {
+				r.declarePattern(decl.Target.Target, declKindOf(s.Token))
+			}
+		case *ast.ClassDeclaration:
+			r.modify(s.Class.Name, DeclKindClass)
+		case *ast.FunctionDeclaration:
+			r.modify(s.Function.Name, DeclKindFunction)
+		case *ast.ImportDeclaration:
+			r.declareImport(s)
+		case *ast.ExportDeclaration:
+			r.declareExport(s)
+		}
+	}
+}
+
+func (r *Resolver) function(n *ast.FunctionLiteral) {
+	oldStrict := r.strict
+	r.strict = r.strict || hasUseStrict(n.Body.List)
+	r.pushScope(ScopeKindFunction, n)
+
+	n.ScopeContext = r.current.Context
+
+	r.params(&n.ParameterList)
+	r.body(n.Body, &n.ParameterList)
 
-	r.identType = oldIdentType
 	r.popScope()
+	r.strict = oldStrict
 }
 
-func (r *Resolver) VisitForStatement(n *ast.ForStatement) {
-	r.pushScope(ScopeKindBlock) // Using Block scope as ForStatement is not defined
+// class resolves a class literal. Classes are strict mode code. The name of
+// a class expression is bound in the class scope, while references to the
+// name of a class declaration from its body resolve to the outer binding.
+func (r *Resolver) class(n *ast.ClassLiteral, expr bool) {
+	oldStrict := r.strict
+	r.strict = true
+	r.pushScope(ScopeKindClass, n)
+
+	named := expr && n.Name != nil && n.Name.Name != ""
+	if named {
+		r.modify(n.Name, DeclKindClass)
+	}
+	if n.SuperClass != nil {
+		r.read(n.SuperClass)
+	}
+	if named {
+		r.initialize(n.Name)
+	}
 
-	oldIdentType := r.identType
-	r.identType = IdentTypeBinding
+	for _, elem := range n.Body {
+		switch elem := elem.Element.(type) {
+		case *ast.MethodDefinition:
+			if elem.Computed {
+				r.read(elem.Key)
+			}
+			r.function(elem.Body)
+		case *ast.FieldDefinition:
+			if elem.Computed {
+				r.read(elem.Key)
+			}
+			if elem.Initializer != nil {
+				// Field initializers are evaluated like methods.
+				r.pushScope(ScopeKindFunction, elem)
+				r.read(elem.Initializer)
+				r.popScope()
+			}
+		case *ast.ClassStaticBlock:
+			r.pushScope(ScopeKindFunction, elem)
+			elem.Block.ScopeContext = r.current.Context
+			r.hoist(elem.Block.List, nil)
+			elem.Block.List.VisitWith(r)
+			r.popScope()
+		}
+	}
 
-	// Handle initializer
-	if n.Initializer != nil {
-		n.Initializer.VisitWith(r)
+	r.popScope()
+	r.strict = oldStrict
+}
+
+// forInOf resolves a for-in or for-of statement. A let or const in its head
+// is scoped to the statement, and in its dead zone while the source is
+// evaluated.
+func (r *Resolver) forInOf(n ast.VisitableNode, into *ast.ForInto, source *ast.Expression, body *ast.Statement) {
+	decl, _ := into.Into.(*ast.VariableDeclaration)
+	if decl == nil || decl.Token == token.Var {
+		r.read(source)
+		switch into := into.Into.(type) {
+		case *ast.VariableDeclaration:
+			into.VisitWith(r)
+		case *ast.Expression:
+			r.pattern(into.Expr, false, RefKindWrite)
+		}
+		body.VisitWith(r)
+		return
 	}
 
-	// Handle test expression
-	r.identType = IdentTypeRef
-	n.Test.VisitWith(r)
+	r.pushScope(ScopeKindBlock, n)
+	for _, d := range decl.List {
+		r.declarePattern(d.Target.Target, declKindOf(decl.Token))
+		r.perIteration(d.Target.Target)
+	}
+	r.read(source)
+	for _, d := range decl.List {
+		r.bind(d.Target.Target, d.Initializer)
+	}
+	body.VisitWith(r)
+	r.popScope()
+}
 
-	// Handle update expression
-	n.Update.VisitWith(r)
+func (r *Resolver) perIteration(target ast.Expr) {
+	BindingIdents(target, func(id *ast.Identifier) {
+		if b := r.Binding(id); b != nil {
+			b.PerIteration = true
+		}
+	})
+}
 
-	// Handle body
-	r.identType = oldIdentType
-	n.Body.VisitWith(r)
+func (r *Resolver) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {
+	oldStrict := r.strict
+	if body, ok := n.Body.Body.(*ast.BlockStatement); ok {
+		r.strict = r.strict || hasUseStrict(body.List)
+	}
+	r.pushScope(ScopeKindFunction, n)
+
+	n.ScopeContext = r.current.Context
+
+	r.params(&n.ParameterList)
+	switch body := n.Body.Body.(type) {
+	case *ast.BlockStatement:
+		r.body(body, &n.ParameterList)
+	case *ast.Expression:
+		r.read(body)
+	}
 
 	r.popScope()
+	r.strict = oldStrict
 }
 
-func (r *Resolver) VisitFunctionLiteral(n *ast.FunctionLiteral) {
-	if n.Name != nil {
-		r.modify(n.Name, DeclKindFunction)
+func (r *Resolver) VisitBlockStatement(n *ast.BlockStatement) {
+	r.pushScope(ScopeKindBlock, n)
+	n.ScopeContext = r.current.Context
+	r.declareLexical(n.List)
+	n.List.VisitWith(r)
+	r.popScope()
+}
+
+func (r *Resolver) VisitCatchStatement(n *ast.CatchStatement) {
+	if n.Parameter == nil {
+		n.Body.VisitWith(r)
+		return
 	}
 
-	r.pushScope(ScopeKindFunction)
+	r.pushScope(ScopeKindBlock, n)
+	r.declarePattern(n.Parameter.Target, DeclKindCatch)
+	r.bind(n.Parameter.Target, nil)
+	n.Body.VisitWith(r)
+	r.popScope()
+}
+
+func (r *Resolver) VisitClassDeclaration(n *ast.ClassDeclaration) {
+	r.modify(n.Class.Name, DeclKindClass)
+	r.class(n.Class, false)
+	r.initialize(n.Class.Name)
+}
 
-	n.ScopeContext = r.current.ctx
+func (r *Resolver) VisitClassLiteral(n *ast.ClassLiteral) {
+	r.class(n, true)
+}
+
+func (r *Resolver) VisitForInStatement(n *ast.ForInStatement) {
+	r.forInOf(n, n.Into, n.Source, n.Body)
+}
+
+func (r *Resolver) VisitForOfStatement(n *ast.ForOfStatement) {
+	r.forInOf(n, n.Into, n.Source, n.Body)
+}
 
-	oldIdentType := r.identType
-	r.identType = IdentTypeBinding
-	n.ParameterList.VisitWith(r)
+func (r *Resolver) VisitForStatement(n *ast.ForStatement) {
+	var decl *ast.VariableDeclaration
+	if n.Initializer != nil {
+		decl, _ = n.Initializer.Initializer.(*ast.VariableDeclaration)
+	}
+	lexical := decl != nil && decl.Token != token.Var
+	if lexical {
+		r.pushScope(ScopeKindBlock, n)
+		for _, d := range decl.List {
+			r.declarePattern(d.Target.Target, declKindOf(decl.Token))
+			if decl.Token == token.Let {
+				r.perIteration(d.Target.Target)
+			}
+		}
+	}
+
+	if n.Initializer != nil {
+		n.Initializer.VisitWith(r)
+	}
+	if n.Test != nil {
+		r.read(n.Test)
+	}
+	if n.Update != nil {
+		r.read(n.Update)
+	}
+	n.Body.VisitWith(r)
 
-	if rest, ok := n.ParameterList.Rest.(*ast.Identifier); ok {
-		rest.VisitWith(r)
-	} else if n.ParameterList.Rest != nil {
-		panic(fmt.Sprintf("Unexpected rest type: %T\n", n.ParameterList.Rest))
+	if lexical {
+		r.popScope()
 	}
+}
 
-	r.identType = IdentTypeRef
-	// Prevent creating new scope.
-	n.Body.ScopeContext = r.current.ctx
-	n.Body.VisitChildrenWith(r)
+func (r *Resolver) VisitFunctionDeclaration(n *ast.FunctionDeclaration) {
+	// The name is declared here if the declaration is not in a statement
+	// list, as in if (x) function f() {}.
+	r.modify(n.Function.Name, DeclKindFunction)
+	r.function(n.Function)
+}
 
-	r.identType = oldIdentType
+func (r *Resolver) VisitFunctionLiteral(n *ast.FunctionLiteral) {
+	// Declarations are visited by VisitFunctionDeclaration, so n is a
+	// function expression or a method.
+	if n.Name == nil || n.Name.Name == "" {
+		r.function(n)
+		return
+	}
 
+	r.pushScope(ScopeKindName, n)
+	r.modify(n.Name, DeclKindFunction)
+	r.function(n)
 	r.popScope()
 }
 
 func (r *Resolver) VisitProgram(n *ast.Program) {
-	r.pushScope(ScopeKindBlock)
-	n.VisitChildrenWith(r)
+	r.strict = hasUseStrict(n.Body) || isModule(n.Body)
+
+	r.pushScope(ScopeKindProgram, n)
+	r.hoist(n.Body, nil)
+	n.Body.VisitWith(r)
+	r.link()
 	r.popScope()
 }
 
-func (r *Resolver) VisitStatements(n *ast.Statements) {
-	// Handle hoisting
-	h := NewHoister(r)
-	h.V = h
-	n.VisitWith(h)
+func (r *Resolver) VisitSwitchStatement(n *ast.SwitchStatement) {
+	r.read(n.Discriminant)
 
-	// Resolve
-	n.VisitChildrenWith(r)
+	r.pushScope(ScopeKindBlock, n)
+	for _, c := range n.Body {
+		r.declareLexical(c.Consequent)
+	}
+	n.Body.VisitWith(r)
+	r.popScope()
 }
 
 func (r *Resolver) VisitVariableDeclaration(n *ast.VariableDeclaration) {
-	oldDeclKind := r.declKind
-	r.declKind = DeclKindVar
-
+	kind := declKindOf(n.Token)
 	for _, decl := range n.List {
-		oldIdentType := r.identType
-		r.identType = IdentTypeBinding
-		decl.Target.VisitWith(r)
-		r.identType = oldIdentType
+		r.declarePattern(decl.Target.Target, kind)
+		r.bind(decl.Target.Target, decl.Initializer)
+	}
+}
+
+func declKindOf(tok token.Token) DeclKind {
+	switch tok {
+	case token.Let:
+		return DeclKindLet
+	case token.Const:
+		return DeclKindConst
+	}
+	return DeclKindVar
+}
+
+func (r *Resolver) VisitCallExpression(n *ast.CallExpression) {
+	n.VisitChildrenWith(r)
 
-		if decl.Initializer != nil {
-			decl.Initializer.VisitChildrenWith(r)
+	// A direct call to the global eval.
+	if id, ok := n.Callee.Expr.(*ast.Identifier); ok && id.Name == "eval" && id.ScopeContext == UnresolvedMark {
+		for s := r.current; s != nil; s = s.Parent {
+			s.Eval = true
 		}
 	}
+}
+
+func (r *Resolver) VisitWithStatement(n *ast.WithStatement) {
+	r.read(n.Object)
+	for s := r.current; s != nil; s = s.Parent {
+		s.With = true
+	}
 
-	r.declKind = oldDeclKind
+	r.with = append(r.with, r.current)
+	n.Body.VisitWith(r)
+	r.with = r.with[:len(r.with)-1]
 }
 
-func (r *Resolver) VisitExpression(expr *ast.Expression) {
-	if expr == nil || expr.Expr == nil {
-		return
+func (r *Resolver) VisitAssignExpression(n *ast.AssignExpression) {
+	kind := RefKindWrite
+	if n.Operator != token.Assign {
+		kind = RefKindReadWrite
 	}
+	r.pattern(n.Left.Expr, false, kind)
+	r.read(n.Right)
+}
 
-	oldIdentType := r.identType
-	r.identType = IdentTypeRef
-	expr.VisitChildrenWith(r)
-	r.identType = oldIdentType
+func (r *Resolver) VisitUpdateExpression(n *ast.UpdateExpression) {
+	r.pattern(n.Operand.Expr, false, RefKindReadWrite)
 }
 
+// Property names, labels and private names are not references.
+
+func (r *Resolver) VisitMemberProperty(n *ast.MemberProperty) {
+	if computed, ok := n.Prop.(*ast.ComputedProperty); ok {
+		r.read(computed.Expr)
+	}
+}
+
+func (r *Resolver) VisitMetaProperty(n *ast.MetaProperty)           {}
+func (r *Resolver) VisitPrivateIdentifier(n *ast.PrivateIdentifier) {}
+
+func (r *Resolver) VisitLabelledStatement(n *ast.LabelledStatement) {
+	n.Statement.VisitWith(r)
+}
+
+func (r *Resolver) VisitBreakStatement(n *ast.BreakStatement)       {}
+func (r *Resolver) VisitContinueStatement(n *ast.ContinueStatement) {}
+
 func (r *Resolver) VisitIdentifier(n *ast.Identifier) {
-	if n == nil || n.ScopeContext != UnresolvedMark {
-		return
+	if n != nil {
+		r.reference(n, RefKindRead)
 	}
+}
+
+// simpleParams reports whether a parameter list has only identifiers, with
+// no default values, patterns or rest parameter.
+func simpleParams(n *ast.ParameterList) bool {
+	for _, param := range n.List {
+		if _, ok := param.Target.Target.(*ast.Identifier); !ok || param.Initializer != nil {
+			return false
+		}
+	}
+	return n.Rest == nil
+}
+
+func unlabel(s ast.Stmt) ast.Stmt {
+	for {
+		l, ok := s.(*ast.LabelledStatement)
+		if !ok {
+			return s
+		}
+		s = l.Statement.Stmt
+	}
+}
+
+// hasUseStrict reports whether the directive prologue of a statement list
+// contains "use strict".
+func hasUseStrict(list ast.Statements) bool {
+	for _, s := range list {
+		es, ok := s.Stmt.(*ast.ExpressionStatement)
+		if !ok || es.Expression == nil {
+			return false
+		}
+		lit, ok := es.Expression.Expr.(*ast.StringLiteral)
+		if !ok {
+			return false
+		}
+		// The directive must be written without escapes.
+		if lit.Raw != nil && len(*lit.Raw) == len("'use strict'") && (*lit.Raw)[1:len(*lit.Raw)-1] == "use strict" {
+			return true
+		}
+	}
+	return false
+}
 
-	switch r.identType {
-	case IdentTypeBinding:
-		r.modify(n, r.declKind)
-	case IdentTypeRef:
-		if mark, _ := r.lookupContext(n.Name); mark != UnresolvedMark {
-			n.ScopeContext = mark
-		} else {
-			r.modify(n, r.declKind)
+// isModule reports whether a program has imports or exports, making it a
+// module and so strict mode code.
+func isModule(list ast.Statements) bool {
+	for _, s := range list {
+		switch s.Stmt.(type) {
+		case *ast.ImportDeclaration, *ast.ExportDeclaration:
+			return true
 		}
 	}
+	return false
 }
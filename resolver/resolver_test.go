@@ -0,0 +1,221 @@
+package resolver_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+// resolution describes what the identifiers named name in p resolve to, in
+// source order: the same letter for the same binding, - for none, and ! after
+// a reference in the temporal dead zone.
+func resolution(r *resolver.Resolver, p *ast.Program, name string) string {
+	letters := make(map[*resolver.Binding]string)
+	tdz := make(map[*ast.Identifier]bool)
+	var out []string
+	for _, id := range idents(p, name) {
+		b := r.Binding(id)
+		if b == nil {
+			out = append(out, "-")
+			continue
+		}
+		if letters[b] == "" {
+			letters[b] = string(rune('a' + len(letters)))
+			for _, ref := range b.References {
+				tdz[ref.Ident] = ref.TDZ
+			}
+		}
+		s := letters[b]
+		if tdz[id] {
+			s += "!"
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		src, x   string
+		wantX    string
+		wantDecl resolver.DeclKind
+	}{
+		// Hoisting.
+		{"var", `x; var x = 1; x;`, "x", "a a a", resolver.DeclKindVar},
+		{"var in block", `function f() { { var x; } x; } x;`, "x", "a a -", resolver.DeclKindVar},
+		{"function", `x(); function x() {}`, "x", "a a", resolver.DeclKindFunction},
+		{"var and function", `var x; function x() {} x;`, "x", "a a a", resolver.DeclKindFunction},
+		{"param and var", `function f(x) { var x; x; }`, "x", "a a a", resolver.DeclKindParam},
+		{"annex b function", `{ function x() {} } x;`, "x", "a a", resolver.DeclKindFunction},
+		{"strict block function", `"use strict"; { function x() {} } x;`, "x", "a -", resolver.DeclKindFunction},
+		{"block function shadowed by let", `let x; { function x() {} } x;`, "x", "a b a", resolver.DeclKindLet},
+
+		// Temporal dead zone.
+		{"let", `x; let x = 1; x;`, "x", "a! a a", resolver.DeclKindLet},
+		{"let initializer", `let x = x;`, "x", "a a!", resolver.DeclKindLet},
+		{"let in function", `function f() { return x; } let x;`, "x", "a a", resolver.DeclKindLet},
+		{"let in arrow called early", `(() => x)(); const x = 1;`, "x", "a a", resolver.DeclKindConst},
+		{"class", `new x(); class x { m() { return x; } }`, "x", "a! a a", resolver.DeclKindClass},
+		{"class extends itself", `class x extends x {}`, "x", "a a!", resolver.DeclKindClass},
+		{"param default", `function f(x = y, y) {}`, "y", "a! a", resolver.DeclKindParam},
+		{"switch", `switch (0) { case 0: x; case 1: let x; }`, "x", "a! a", resolver.DeclKindLet},
+
+		// Block scopes.
+		{"shadowing let", `let x; { let x; x; } x;`, "x", "a b b a", resolver.DeclKindLet},
+		{"switch let", `switch (0) { case 0: let x; default: x; } x;`, "x", "a a -", resolver.DeclKindLet},
+		{"class expression name", `var x = class x { m() { x; } }; x;`, "x", "a b b a", resolver.DeclKindVar},
+		{"function expression name", `var x = function x() { x; }; x;`, "x", "a b b a", resolver.DeclKindVar},
+		{"param default and body var", `var x; function f(a = x) { var x; }`, "x", "a a b", resolver.DeclKindVar},
+
+		// Catch.
+		{"catch param", `try {} catch (x) { x; } x;`, "x", "a a -", resolver.DeclKindCatch},
+		{"catch pattern", `try {} catch ({x}) { x; } x;`, "x", "a a -", resolver.DeclKindCatch},
+		{"catch param and var", `try {} catch (x) { var x = 1; x; } x;`, "x", "a a a b", resolver.DeclKindCatch},
+		{"catch pattern and let", `try {} catch (x) { { let x; x; } }`, "x", "a b b", resolver.DeclKindCatch},
+
+		// Loops.
+		{"for let", `for (let x = 0; x < 1; x++) { x; } x;`, "x", "a a a a -", resolver.DeclKindLet},
+		{"for var", `for (var x = 0;;) {} x;`, "x", "a a", resolver.DeclKindVar},
+		{"for-of const", `for (const x of x) { x; }`, "x", "a a! a", resolver.DeclKindConst},
+		{"for-in let shadowing", `let x; for (let x in x) {} x;`, "x", "a b b! a", resolver.DeclKindLet},
+		{"for-in assignment", `let x; for (x in o) {}`, "x", "a a", resolver.DeclKindLet},
+
+		// Globals, labels and properties.
+		{"global", `x; x = 1; function f() { x; }`, "x", "- - -", 0},
+		{"label", `x: for (;;) { break x; }`, "x", "- -", 0},
+		{"property", `var x; o.x; ({x: 1}); x;`, "x", "a - a", resolver.DeclKindVar},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		r := resolver.Resolve(p)
+		if got := resolution(r, p, tt.x); got != tt.wantX {
+			t.Errorf("%s: %s resolves %s as %q, want %q", tt.name, tt.src, tt.x, got, tt.wantX)
+		}
+		if b := r.Binding(idents(p, tt.x)[0]); b != nil && b.Kind != tt.wantDecl {
+			t.Errorf("%s: first %s is a %s, want %s", tt.name, tt.x, b.Kind, tt.wantDecl)
+		}
+	}
+}
+
+func TestCatchVar(t *testing.T) {
+	// The var is hoisted out of the catch clause, but its identifier
+	// redeclares the parameter, so neither binding can be renamed alone.
+	p := parse(t, `try {} catch (x) { var x = 1; } x;`)
+	r := resolver.Resolve(p)
+	ids := idents(p, "x")
+	param, v := r.Binding(ids[0]), r.Binding(ids[2])
+	if v == nil || v.Kind != resolver.DeclKindVar || v.Scope != r.Root() || len(v.Decls) != 0 {
+		t.Fatalf("x after the catch clause resolves to %+v, want the hoisted var", v)
+	}
+	for _, b := range []*resolver.Binding{param, v} {
+		if err := resolver.Rename(p, b, "y"); err == nil {
+			t.Errorf("Rename(%s %s) succeeded", b.Kind, b.Name)
+		}
+	}
+}
+
+func TestPerIteration(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{`for (let x = 0;;) {}`, true},
+		{`for (const x = 0;;) {}`, false},
+		{`for (var x = 0;;) {}`, false},
+		{`for (let x of o) {}`, true},
+		{`for (const [x] in o) {}`, true},
+		{`let x; for (x of o) {}`, false},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		r := resolver.Resolve(p)
+		if got := r.Binding(idents(p, "x")[0]).PerIteration; got != tt.want {
+			t.Errorf("%s: PerIteration = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestDynamicScopes(t *testing.T) {
+	p := parse(t, `function f() { var x; { eval("x"); } } function g() { var eval; eval(); } function h() { with (o) { x; } }`)
+	r := resolver.Resolve(p)
+	funcs := r.Root().Children
+	if !r.Root().Eval || !funcs[0].Eval || !funcs[0].Children[0].Eval {
+		t.Errorf("direct eval in f does not mark its enclosing scopes")
+	}
+	if funcs[1].Eval {
+		t.Errorf("call to a local eval in g is taken for a direct eval")
+	}
+	if !funcs[2].With || !r.Root().With || funcs[0].With {
+		t.Errorf("With = %v, %v, %v for the program, h and f", r.Root().With, funcs[2].With, funcs[0].With)
+	}
+
+	p = parse(t, `var x; with (o) { let y; x; y; } x;`)
+	r = resolver.Resolve(p)
+	refs := r.Root().Bindings["x"].References
+	if !refs[0].With || refs[1].With {
+		t.Errorf("With of the references to x = %v, %v, want true, false", refs[0].With, refs[1].With)
+	}
+	if r.Binding(idents(p, "y")[1]).References[0].With {
+		t.Errorf("y declared in the with body may be a property of its object")
+	}
+}
+
+func TestStrict(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []bool // the root and its children
+	}{
+		{`function f() {}`, []bool{false, false}},
+		{`"use strict"; function f() {}`, []bool{true, true}},
+		{`'use\x20strict'; function f() {}`, []bool{false, false}},
+		{`function f() { "use strict"; } function g() {}`, []bool{false, true, false}},
+		{`class C { m() {} } function f() {}`, []bool{false, true, false}},
+		{`import x from "m"; function f() {}`, []bool{true, true}},
+	}
+	for _, tt := range tests {
+		r := resolver.Resolve(parse(t, tt.src))
+		got := []bool{r.Root().Strict}
+		for _, c := range r.Root().Children {
+			got = append(got, c.Strict)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %d scopes, want %d", tt.src, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: Strict = %v, want %v", tt.src, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		src                        string
+		arguments, mappedArguments bool
+	}{
+		{`function f(a) { arguments; }`, true, true},
+		{`function f(a) { "use strict"; arguments; }`, true, false},
+		{`function f(a = 1) { arguments; }`, true, false},
+		{`function f() { () => arguments; }`, true, true},
+		{`function f() { var arguments; arguments; }`, false, false},
+		{`function f() { arguments.length; }`, true, true},
+		{`function f() {}`, false, false},
+	}
+	for _, tt := range tests {
+		p := parse(t, tt.src)
+		r := resolver.Resolve(p)
+		f := r.Root().Children[0]
+		if f.Arguments != tt.arguments || f.MappedArguments != tt.mappedArguments {
+			t.Errorf("%s: Arguments, MappedArguments = %v, %v, want %v, %v", tt.src, f.Arguments, f.MappedArguments, tt.arguments, tt.mappedArguments)
+		}
+	}
+	if g := resolver.Globals(parse(t, `arguments; class C { x = arguments; }`)); len(g) != 1 || len(g[0].References) != 2 {
+		t.Errorf("arguments outside functions is not a global")
+	}
+}
@@ -0,0 +1,185 @@
+package resolver
+
+import "github.com/t14raptor/go-fast/ast"
+
+type DeclKind int
+
+const (
+	DeclKindVar DeclKind = iota
+	DeclKindFunction
+	DeclKindLet
+	DeclKindConst
+	DeclKindClass
+	DeclKindParam
+	DeclKindCatch
+	DeclKindImport
+	DeclKindArguments // the implicit arguments object of a function
+)
+
+var declKindNames = [...]string{
+	DeclKindVar:       "var",
+	DeclKindFunction:  "function",
+	DeclKindLet:       "let",
+	DeclKindConst:     "const",
+	DeclKindClass:     "class",
+	DeclKindParam:     "param",
+	DeclKindCatch:     "catch",
+	DeclKindImport:    "import",
+	DeclKindArguments: "arguments",
+}
+
+func (k DeclKind) String() string { return declKindNames[k] }
+
+type ScopeKind int
+
+const (
+	ScopeKindBlock ScopeKind = iota
+	ScopeKindFunction
+	ScopeKindProgram
+	ScopeKindClass // a class body, holding the name of a class expression
+	ScopeKindName  // the name of a named function expression
+)
+
+var scopeKindNames = [...]string{
+	ScopeKindBlock:    "block",
+	ScopeKindFunction: "function",
+	ScopeKindProgram:  "program",
+	ScopeKindClass:    "class",
+	ScopeKindName:     "name",
+}
+
+func (k ScopeKind) String() string { return scopeKindNames[k] }
+
+// RefKind tells how a reference uses its binding.
+type RefKind int
+
+const (
+	RefKindRead      RefKind = iota
+	RefKindWrite             // the target of =, or the head of for-in and for-of
+	RefKindReadWrite         // the target of compound assignment, ++ and --
+)
+
+var refKindNames = [...]string{
+	RefKindRead:      "read",
+	RefKindWrite:     "write",
+	RefKindReadWrite: "read-write",
+}
+
+func (k RefKind) String() string { return refKindNames[k] }
+
+// Scope is a node of the scope tree built by Resolve. Identifiers declared
+// in a scope are stamped with its Context.
+type Scope struct {
+	Kind     ScopeKind
+	Parent   *Scope
+	Children []*Scope
+
+	// Node is the node owning the scope, such as *ast.Program,
+	// *ast.FunctionLiteral or *ast.BlockStatement. The head of a for
+	// statement declaring let or const is owned by the statement and a catch
+	// parameter by the *ast.CatchStatement. Class fields and static blocks
+	// are function scopes.
+	Node    ast.VisitableNode
+	Context ast.ScopeContext
+
+	Bindings map[string]*Binding
+
+	// Strict is set for strict mode code.
+	Strict bool
+	// Eval is set if the scope or one nested in it calls eval directly. The
+	// evaluated code can refer to any binding in scope by name, and in sloppy
+	// mode code declare vars in the enclosing function.
+	Eval bool
+	// With is set if the scope or one nested in it has a with statement.
+	// References in its body may resolve to properties of its object.
+	With bool
+	// Arguments is set on the scope of a function using its arguments
+	// object. MappedArguments is also set if the function is sloppy mode
+	// code with simple parameters, whose values the arguments object aliases.
+	Arguments       bool
+	MappedArguments bool
+
+	// globals holds the free variables of the program in the root scope.
+	globals map[string]*Global
+	// vars is set on the scopes vars are hoisted to, and with holds the
+	// scopes enclosing the with statements the scope is in, for Reresolve.
+	vars bool
+	with []*Scope
+}
+
+// contains reports whether s is t or encloses it.
+func (s *Scope) contains(t *Scope) bool {
+	for ; t != nil; t = t.Parent {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scope) root() *Scope {
+	for s.Parent != nil {
+		s = s.Parent
+	}
+	return s
+}
+
+// Lookup returns the binding name refers to from s, or nil.
+func (s *Scope) Lookup(name string) *Binding {
+	for scope := s; scope != nil; scope = scope.Parent {
+		if b, ok := scope.Bindings[name]; ok {
+			return b
+		}
+	}
+	return nil
+}
+
+// Binding is a name declared in a scope.
+type Binding struct {
+	Name  string
+	Kind  DeclKind
+	Scope *Scope
+
+	// Decls holds the declaring identifiers. The first declares the binding,
+	// later ones redeclare a var or function, or a catch parameter with var.
+	// It is empty for a var declared only by such redeclarations.
+	Decls      []*ast.Identifier
+	References []*Reference
+
+	// PerIteration is set for a let in the head of a for statement and a let
+	// or const in the head of a for-in or for-of statement. Each iteration
+	// of the loop gets a fresh copy of the binding.
+	PerIteration bool
+
+	initialized bool
+}
+
+// Lexical reports whether b is declared by let, const or class, and so is
+// in its temporal dead zone until its declaration is evaluated.
+func (b *Binding) Lexical() bool {
+	return b.Kind == DeclKindLet || b.Kind == DeclKindConst || b.Kind == DeclKindClass
+}
+
+// Reference is an identifier referring to a binding.
+type Reference struct {
+	Ident *ast.Identifier
+	Kind  RefKind
+	// Scope is the scope the reference is in.
+	Scope *Scope
+
+	// TDZ is set if the reference is evaluated before its binding is
+	// initialized, as in let x = x, and so throws. References from nested
+	// functions are never in the dead zone, since they may run later.
+	TDZ bool
+	// With is set if the reference is in the body of a with statement and
+	// its binding is declared outside it, so that it may resolve to a
+	// property of the object instead.
+	With bool
+}
+
+// Global is a free variable: a name referred to but not declared, such as
+// window or document. Its references keep UnresolvedMark.
+type Global struct {
+	Name       string
+	References []*Reference
+}
@@ -0,0 +1,162 @@
+package resolver_test
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/resolver"
+)
+
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	p, err := parser.ParseFile(src)
+	if err != nil {
+		t.Fatalf("ParseFile(%q): %v", src, err)
+	}
+	return p
+}
+
+type identCollector struct {
+	ast.NoopVisitor
+	idents []*ast.Identifier
+}
+
+func (c *identCollector) VisitIdentifier(n *ast.Identifier) {
+	c.idents = append(c.idents, n)
+}
+
+// idents returns the identifiers of n named name, in source order.
+func idents(n ast.VisitableNode, name string) []*ast.Identifier {
+	c := &identCollector{}
+	c.V = c
+	n.VisitWith(c)
+	return slices.DeleteFunc(c.idents, func(id *ast.Identifier) bool { return id.Name != name })
+}
+
+// dump describes the scope tree under s, as kind{bindings}[children].
+func dump(s *resolver.Scope) string {
+	names := make([]string, 0, len(s.Bindings))
+	for name, b := range s.Bindings {
+		names = append(names, name+":"+b.Kind.String())
+	}
+	sort.Strings(names)
+	out := s.Kind.String() + "{" + strings.Join(names, " ") + "}"
+	if len(s.Children) > 0 {
+		children := make([]string, len(s.Children))
+		for i, c := range s.Children {
+			children[i] = dump(c)
+		}
+		out += "[" + strings.Join(children, " ") + "]"
+	}
+	return out
+}
+
+func TestScopeTree(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{`var a; let b; const c = 1; function d() {} class E {}`,
+			`program{E:class a:var b:let c:const d:function}[function{} class{}]`},
+		{`import x, {y as z} from "m"; import * as ns from "n";`,
+			`program{ns:import x:import z:import}`},
+		{`function f(a, [b], ...c) { var d; arguments; }`,
+			`program{f:function}[function{a:param arguments:arguments b:param c:param d:var}]`},
+		{`function f(a = 1) { var b; }`,
+			`program{f:function}[function{a:param}[block{b:var}]]`},
+		{`{ let a; } try {} catch (e) { let f; } finally {}`,
+			`program{}[block{a:let} block{} block{e:catch}[block{f:let}] block{}]`},
+		{`for (let i = 0;;) {} for (const k in o) {} for (var v of o) {}`,
+			`program{v:var}[block{i:let}[block{}] block{k:const}[block{}] block{}]`},
+		{`switch (x) { case 1: let a; default: class B {} }`,
+			`program{}[block{B:class a:let}[class{}]]`},
+		{`(function f() {}); (class C { x = 1; static { let y; } m() {} }); () => {};`,
+			`program{}[name{f:function}[function{}] class{C:class}[function{} function{y:let} function{}] function{}]`},
+	}
+	for _, tt := range tests {
+		r := resolver.Resolve(parse(t, tt.src))
+		if got := dump(r.Root()); got != tt.want {
+			t.Errorf("scopes of %s:\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestScopeNodes(t *testing.T) {
+	p := parse(t, `function f() {} try {} catch (e) {} for (let i;;) {}`)
+	r := resolver.Resolve(p)
+	root := r.Root()
+	if root.Node != p || root.Parent != nil || r.Scope(resolver.TopLevelMark) != root {
+		t.Fatalf("root scope %+v is not the scope of the program", root)
+	}
+	want := []string{"*ast.FunctionLiteral", "*ast.BlockStatement", "*ast.CatchStatement", "*ast.ForStatement"}
+	for i, c := range root.Children {
+		if got := fmt.Sprintf("%T", c.Node); i >= len(want) || got != want[i] {
+			t.Errorf("child %d is owned by a %s", i, got)
+		}
+		if c.Parent != root || r.Scope(c.Context) != c {
+			t.Errorf("child %d is not linked to the root or its context", i)
+		}
+	}
+	if len(root.Children) != len(want) {
+		t.Errorf("root has %d children, want %d", len(root.Children), len(want))
+	}
+}
+
+func TestBindings(t *testing.T) {
+	p := parse(t, `var x = 1; function f() { return x; } var x; x = 2;`)
+	r := resolver.Resolve(p)
+	ids := idents(p, "x")
+	b := r.Binding(ids[0])
+	if b == nil || b.Name != "x" || b.Kind != resolver.DeclKindVar || b.Scope != r.Root() {
+		t.Fatalf("Binding(x) = %+v", b)
+	}
+	if len(b.Decls) != 2 || b.Decls[0] != ids[0] || b.Decls[1] != ids[2] {
+		t.Errorf("Decls = %v, want the identifiers of both vars", b.Decls)
+	}
+	if len(b.References) != 2 || b.References[0].Ident != ids[1] || b.References[1].Ident != ids[3] {
+		t.Errorf("References = %v, want the return and the assignment", b.References)
+	}
+	for _, id := range ids {
+		if r.Binding(id) != b {
+			t.Errorf("identifier at %d resolves to %+v", id.Idx, r.Binding(id))
+		}
+	}
+	if got := b.References[0].Scope; got.Node != p.Body[1].Stmt.(*ast.FunctionDeclaration).Function {
+		t.Errorf("reference in f has scope %s owned by %T", got.Kind, got.Node)
+	}
+	if f := r.Root().Lookup("f"); f == nil || f.Kind != resolver.DeclKindFunction {
+		t.Errorf("Lookup(f) = %+v", f)
+	}
+	if g := r.Root().Lookup("g"); g != nil {
+		t.Errorf("Lookup(g) = %+v, want nil", g)
+	}
+}
+
+func TestRefKinds(t *testing.T) {
+	src := `let x; x; x = 1; x += 1; x++; --x; [x] = a; ({k: x} = a); for (x of a); for (x in a); x.y = 1; x[x] = 1;`
+	want := "read write read-write read-write read-write write write write write read read read"
+
+	p := parse(t, src)
+	r := resolver.Resolve(p)
+	b := r.Binding(idents(p, "x")[0])
+	var got []string
+	for _, ref := range b.References {
+		got = append(got, ref.Kind.String())
+	}
+	if strings.Join(got, " ") != want {
+		t.Errorf("reference kinds in %s:\ngot  %s\nwant %s", src, strings.Join(got, " "), want)
+	}
+}
+
+func TestLexical(t *testing.T) {
+	r := resolver.Resolve(parse(t, `var a; let b; const c = 1; class d {} function e(f) {}`))
+	for name, want := range map[string]bool{"a": false, "b": true, "c": true, "d": true, "e": false} {
+		if got := r.Root().Bindings[name].Lexical(); got != want {
+			t.Errorf("%s.Lexical() = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -51,6 +51,8 @@ func (t Token) Precedence(in bool) int {
 		return 9
 	case Multiply, Slash, Remainder, MultiplyAssign, QuotientAssign, RemainderAssign:
 		return 11
+	case Exponent, ExponentAssign:
+		return 12
 	}
 	return 0
 }
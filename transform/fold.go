@@ -0,0 +1,323 @@
+// Package transform rewrites programs into simpler equivalent ones.
+package transform
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/ast/build"
+	"github.com/t14raptor/go-fast/jsvalue"
+	"github.com/t14raptor/go-fast/resolver"
+	"github.com/t14raptor/go-fast/token"
+)
+
+// Fold replaces the expressions of p computed from literals alone by their
+// value, innermost first, so that `0x1f * -0x3 + 0x5d` becomes `0` and
+// `!![]` becomes `true`.
+//
+// Unary, binary and untagged template expressions fold when their operands
+// have a value by jsvalue.FromExpr: literals, negated numbers, void 0, the
+// globals undefined, NaN and Infinity, or array and object literals of such
+// values. Logical and conditional expressions whose left operand or test has
+// a value fold to the operand they evaluate to, whatever it is. Expressions
+// that throw, such as `({toString: 1}) + ""`, in and instanceof, and the
+// operands of delete are left alone.
+//
+// Fold resolves p first unless it is resolved already, so that bindings
+// named undefined, NaN or Infinity are not taken for the globals. The
+// contexts of a resolved program are kept, and so are the identifiers they
+// tell apart for resolver.Hygiene. Where a binding is named NaN or Infinity,
+// folded values write them as 0 / 0 and 1 / 0.
+func Fold(p *ast.Program) {
+	s := scanContexts(p)
+	if !s.resolved {
+		resolver.Resolve(p)
+		s = scanContexts(p)
+	}
+
+	f := &folder{shadowed: s.bound}
+	f.V = f
+	p.VisitWith(f)
+}
+
+type folder struct {
+	ast.NoopVisitor
+
+	// shadowed holds the globals NaN and Infinity if a binding has their
+	// name.
+	shadowed map[string]bool
+
+	// callee is set while visiting an operand whose value is used as a
+	// reference, such as a callee or the operand of typeof.
+	callee bool
+}
+
+func (f *folder) VisitExpression(n *ast.Expression) {
+	callee := f.callee
+	f.callee = false
+	n.VisitChildrenWith(f)
+	if n.Expr == nil {
+		return
+	}
+	if e := f.fold(n.Expr); e != nil {
+		if callee && isReference(e) {
+			// (0, a.b)() calls a.b without a this value, as
+			// (true && a.b)() does.
+			e = build.Seq(build.NumLit(0), e)
+		}
+		n.Expr = e
+	}
+}
+
+func (f *folder) VisitCallExpression(n *ast.CallExpression) {
+	f.callee = true
+	n.Callee.VisitWith(f)
+	n.ArgumentList.VisitWith(f)
+}
+
+func (f *folder) VisitTemplateLiteral(n *ast.TemplateLiteral) {
+	if n.Tag != nil {
+		f.callee = true
+		n.Tag.VisitWith(f)
+	}
+	n.Expressions.VisitWith(f)
+}
+
+func (f *folder) VisitUnaryExpression(n *ast.UnaryExpression) {
+	switch n.Operator {
+	case token.Delete:
+		return
+	case token.Typeof:
+		// typeof x does not throw if x is not declared, but typeof (0, x)
+		// does.
+		f.callee = true
+	}
+	n.Operand.VisitWith(f)
+}
+
+// isReference reports whether e evaluates to a reference whose base or
+// binding matters to the operator applied to it.
+func isReference(e ast.Expr) bool {
+	switch e.(type) {
+	case *ast.Identifier, *ast.MemberExpression, *ast.PrivateDotExpression, *ast.OptionalChain:
+		return true
+	}
+	return false
+}
+
+// fold returns the expression e folds to, or nil.
+func (f *folder) fold(e ast.Expr) ast.Expr {
+	switch e := e.(type) {
+	case *ast.UnaryExpression:
+		return f.foldUnary(e)
+	case *ast.BinaryExpression:
+		switch e.Operator {
+		case token.LogicalAnd, token.LogicalOr, token.Coalesce:
+			return foldLogical(e)
+		case token.In, token.InstanceOf:
+			return nil
+		}
+		x, ok := jsvalue.FromExpr(e.Left.Expr)
+		if !ok {
+			return nil
+		}
+		y, ok := jsvalue.FromExpr(e.Right.Expr)
+		if !ok {
+			return nil
+		}
+		v, err := jsvalue.Binary(e.Operator, x, y)
+		if e.Operator == token.Exponent && !exactPower(x, y) {
+			// Engines round other powers differently.
+			return nil
+		}
+		return f.result(v, err)
+	case *ast.ConditionalExpression:
+		if test, ok := jsvalue.FromExpr(e.Test.Expr); ok {
+			if jsvalue.ToBoolean(test) {
+				return e.Consequent.Expr
+			}
+			return e.Alternate.Expr
+		}
+	case *ast.TemplateLiteral:
+		if s, ok := foldTemplate(e); ok {
+			return build.Str(s)
+		}
+	}
+	return nil
+}
+
+// result returns the expression of the value of an operation, or nil if it
+// throws or its value has no expression.
+func (f *folder) result(v jsvalue.Value, err error) ast.Expr {
+	if err != nil {
+		return nil
+	}
+	e, ok := jsvalue.ToExpr(v)
+	if !ok || len(f.shadowed) == 0 {
+		return e
+	}
+	r := &globalReplacer{shadowed: f.shadowed}
+	r.V = r
+	x := &ast.Expression{Expr: e}
+	x.VisitWith(r)
+	return x.Expr
+}
+
+// exactPower reports whether x ** y is a safe integer, which all engines
+// compute exactly.
+func exactPower(x, y jsvalue.Value) bool {
+	nx, err := jsvalue.ToNumber(x)
+	if err != nil {
+		return false
+	}
+	ny, err := jsvalue.ToNumber(y)
+	if err != nil || nx != math.Trunc(nx) || ny != math.Trunc(ny) || ny < 0 {
+		return false
+	}
+	return math.Abs(math.Pow(nx, ny)) < 1<<53
+}
+
+func (f *folder) foldUnary(e *ast.UnaryExpression) ast.Expr {
+	switch e.Operator {
+	case token.Minus:
+		// Negated numbers are already as short as they get.
+		if numberLiteral(e.Operand.Expr) {
+			return nil
+		}
+	case token.Void:
+		// void 0 is how undefined is written.
+		if lit, ok := e.Operand.Expr.(*ast.NumberLiteral); ok && lit.Value == 0 {
+			return nil
+		}
+	case token.Plus, token.Not, token.BitwiseNot, token.Typeof:
+	default:
+		return nil
+	}
+	x, ok := jsvalue.FromExpr(e.Operand.Expr)
+	if !ok {
+		return nil
+	}
+	return f.result(jsvalue.Unary(e.Operator, x))
+}
+
+// numberLiteral reports whether e is a number literal or Infinity.
+func numberLiteral(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.NumberLiteral:
+		return true
+	case *ast.Identifier:
+		return e.Name == "Infinity" && e.ScopeContext == 0
+	}
+	return false
+}
+
+func foldLogical(e *ast.BinaryExpression) ast.Expr {
+	x, ok := jsvalue.FromExpr(e.Left.Expr)
+	if !ok {
+		return nil
+	}
+	var left bool
+	switch e.Operator {
+	case token.LogicalAnd:
+		left = !jsvalue.ToBoolean(x)
+	case token.LogicalOr:
+		left = jsvalue.ToBoolean(x)
+	case token.Coalesce:
+		left = x.Kind() != jsvalue.KindUndefined && x.Kind() != jsvalue.KindNull
+	}
+	if left {
+		return e.Left.Expr
+	}
+	return e.Right.Expr
+}
+
+func foldTemplate(e *ast.TemplateLiteral) (string, bool) {
+	if e.Tag != nil {
+		return "", false
+	}
+	var b strings.Builder
+	for i, elem := range e.Elements {
+		parsed := ast.StringValue(elem.Parsed)
+		if !elem.Valid || strings.ContainsRune(parsed, utf8.RuneError) && jsvalue.LoneSurrogate(elem.Literal) {
+			return "", false
+		}
+		b.WriteString(parsed)
+		if i < len(e.Expressions) {
+			v, ok := jsvalue.FromExpr(e.Expressions[i].Expr)
+			if !ok {
+				return "", false
+			}
+			s, err := jsvalue.ToString(v)
+			if err != nil {
+				return "", false
+			}
+			b.WriteString(s)
+		}
+	}
+	return b.String(), true
+}
+
+// contextScan finds whether a program is resolved, and which of the globals
+// NaN and Infinity its bindings are named after.
+type contextScan struct {
+	ast.NoopVisitor
+
+	resolved bool
+	bound    map[string]bool
+}
+
+func scanContexts(p *ast.Program) *contextScan {
+	s := &contextScan{bound: make(map[string]bool)}
+	s.V = s
+	p.VisitWith(s)
+	return s
+}
+
+func (s *contextScan) VisitIdentifier(n *ast.Identifier) {
+	// Free variables are left unresolved, so a resolved NaN or Infinity is a
+	// binding.
+	if n.ScopeContext != resolver.UnresolvedMark {
+		s.resolved = true
+		if n.Name == "NaN" || n.Name == "Infinity" {
+			s.bound[n.Name] = true
+		}
+	}
+}
+
+func (s *contextScan) VisitArrowFunctionLiteral(n *ast.ArrowFunctionLiteral) {
+	s.resolved = s.resolved || n.ScopeContext != resolver.UnresolvedMark
+	n.VisitChildrenWith(s)
+}
+
+func (s *contextScan) VisitBlockStatement(n *ast.BlockStatement) {
+	s.resolved = s.resolved || n.ScopeContext != resolver.UnresolvedMark
+	n.VisitChildrenWith(s)
+}
+
+func (s *contextScan) VisitFunctionLiteral(n *ast.FunctionLiteral) {
+	s.resolved = s.resolved || n.ScopeContext != resolver.UnresolvedMark
+	n.VisitChildrenWith(s)
+}
+
+// globalReplacer writes the globals NaN and Infinity in a folded value as
+// divisions where bindings have their name.
+type globalReplacer struct {
+	ast.NoopVisitor
+
+	shadowed map[string]bool
+}
+
+func (r *globalReplacer) VisitExpression(n *ast.Expression) {
+	n.VisitChildrenWith(r)
+	id, ok := n.Expr.(*ast.Identifier)
+	if !ok || !r.shadowed[id.Name] {
+		return
+	}
+	dividend := 0.0
+	if id.Name == "Infinity" {
+		dividend = 1
+	}
+	n.Expr = build.Binary(token.Slash, build.NumLit(dividend), build.NumLit(0))
+}
@@ -0,0 +1,176 @@
+package transform_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/t14raptor/go-fast/ast"
+	"github.com/t14raptor/go-fast/generator"
+	"github.com/t14raptor/go-fast/parser"
+	"github.com/t14raptor/go-fast/resolver"
+	"github.com/t14raptor/go-fast/transform"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`x = 0x1f * -0x3 + 0x5d;`, `x = 0;`},
+		{`x = !![];`, `x = true;`},
+		{`x = 1 + "a" + null;`, `x = "1anull";`},
+		{`x = -(1 + 2);`, `x = -3;`},
+		{`x = +"0x10";`, `x = 16;`},
+		{`x = ~5 | 0;`, `x = -6;`},
+		{`x = typeof void 0;`, `x = "undefined";`},
+		{`x = 0 / 0;`, `x = NaN;`},
+		{`x = -1 / 0;`, `x = -Infinity;`},
+		{`x = [1, 2] + "";`, `x = "1,2";`},
+		{`x = {} + "";`, `x = "[object Object]";`},
+		{"x = `a${1 + 1}b${null}`;", `x = "a2bnull";`},
+		{`x = 1 < 2 === true;`, `x = true;`},
+		{`x = "b" > "a";`, `x = true;`},
+		// Non-ASCII strings fold to the string itself.
+		{`x = "a" + "é";`, `x = "aé";`},
+		{`x = "日本" + 1;`, `x = "日本1";`},
+		{`x = "é" + "ü";`, `x = "éü";`},
+		{"x = `é${1}ü${\"ö\"}`;", `x = "é1üö";`},
+		{`x = ["é", "ü"] + "";`, `x = "é,ü";`},
+		{`x = "é" === "\u00e9";`, `x = true;`},
+		{`x = "\uffff" < "\u{1F600}";`, `x = false;`},
+		{`x = +"١";`, `x = NaN;`},
+		// Lone surrogates have no UTF-8 form, so strings escaping them are
+		// left alone.
+		{`x = "\uD800" + "";`, `x = "\uD800" + "";`},
+		{"x = `\\uD800${1}`;", "${1}`;"},
+		{"x = `${1}\\u{DC00}`;", "`${1}"},
+		{`x = "𐀀" + "";`, `x = "𐀀";`},
+		{"x = `\\uD800\\uDC00${1}`;", `x = "𐀀1";`},
+		{`x = "\\uD800" + "";`, `x = "\\uD800";`},
+		{`x = "�" + "";`, `x = "\ufffd";`},
+		{`x = undefined + 1;`, `x = NaN;`},
+		// Negated numbers and void 0 are already as short as they get.
+		{`x = -1;`, `x = -1;`},
+		{`x = void 0;`, `x = void 0;`},
+		{`x = void 1;`, `x = void 0;`},
+		// Logical and conditional expressions fold to the operand they
+		// evaluate to.
+		{`x = 0 || a;`, `x = a;`},
+		{`x = 1 && a;`, `x = a;`},
+		{`x = null ?? a;`, `x = a;`},
+		{`x = 0 ?? a;`, `x = 0;`},
+		{`x = "" ? a : b;`, `x = b;`},
+		// Callees keep no this value, and typeof its operand's.
+		{`(1 && a.b)();`, `(0, a.b)();`},
+		{`(1 && a)();`, `(0, a)();`},
+		{`x = typeof (1 && a);`, `x = typeof (0, a);`},
+		// Exact powers fold, others are rounded differently by engines.
+		{`x = 2 ** 10;`, `x = 1024;`},
+		{`x = 2 ** 0.5;`, `x = 2 ** 0.5;`},
+		{`x = 10 ** -1;`, `x = 10 ** -1;`},
+		// A negative base keeps its parentheses.
+		{`x = (0 - 8) ** y;`, `x = (-8) ** y;`},
+		{`x = (1 ? -2 : 3) ** y;`, `x = (-2) ** y;`},
+		{`x = (-8) ** 2;`, `x = 64;`},
+		// Expressions that throw and operators on objects are left alone.
+		{`x = ({toString: 1}) + "";`, `toString: 1`},
+		{`x = "a" in {};`, `x = "a" in {};`},
+		{`delete (1 + 1);`, `delete (1 + 1);`},
+		{`x = a + 1;`, `x = a + 1;`},
+		// Bindings named after the globals are not them.
+		{`function f() { var undefined = 5; return undefined + 1; }`, `return undefined + 1;`},
+		{`function f(NaN) { return NaN + 1; }`, `return NaN + 1;`},
+		{`let Infinity = 1; x = -Infinity;`, `x = -Infinity;`},
+		{`let Infinity = 1; x = Infinity || a;`, `x = Infinity || a;`},
+		{`function f() { return undefined + 1; }`, `return NaN;`},
+		// Folded values do not name globals that bindings shadow.
+		{`function f(NaN) { return 0 / 0; }`, `return 0 / 0;`},
+		{`function f(NaN) { return +"a"; }`, `return 0 / 0;`},
+		{`function f() { let Infinity; return 1 / 0; }`, `return 1 / 0;`},
+		{`function f() { let Infinity; return -1 / 0; }`, `return -(1 / 0);`},
+		{`function f(undefined) { return void 1; }`, `return void 0;`},
+		{`function f(undefined) { return typeof void 1; }`, `return "undefined";`},
+	}
+	for _, tt := range tests {
+		p, err := parser.ParseFile(tt.src)
+		if err != nil {
+			t.Fatalf("ParseFile(%q): %v", tt.src, err)
+		}
+		transform.Fold(p)
+		got := strings.Join(strings.Fields(generator.Generate(p)), " ")
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("Fold(%s) = %s, want it to contain %s", tt.src, got, tt.want)
+		}
+		if _, err := parser.ParseFile(got); err != nil {
+			t.Errorf("Fold(%s) = %s, which does not parse: %v", tt.src, got, err)
+		}
+	}
+}
+
+func TestFoldTag(t *testing.T) {
+	p, err := parser.ParseFile("(1 && a.b)`t`;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transform.Fold(p)
+	tag := p.Body[0].Stmt.(*ast.ExpressionStatement).Expression.Expr.(*ast.TemplateLiteral).Tag.Expr
+	seq, ok := tag.(*ast.SequenceExpression)
+	if !ok || len(seq.Sequence) != 2 {
+		t.Fatalf("the tag folds to %T, want (0, a.b)", tag)
+	}
+	if _, ok := seq.Sequence[1].Expr.(*ast.MemberExpression); !ok {
+		t.Errorf("the tag folds to (0, %T), want (0, a.b)", seq.Sequence[1].Expr)
+	}
+}
+
+func TestFoldResolved(t *testing.T) {
+	// A program resolved before keeps its contexts.
+	p, err := parser.ParseFile(`function f() { var undefined = 5; return undefined + 1; }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transform.Fold(p)
+	transform.Fold(p)
+	if got := generator.Generate(p); !strings.Contains(got, "undefined + 1") {
+		t.Errorf("folding twice gives %s", got)
+	}
+}
+
+func TestFoldHygiene(t *testing.T) {
+	// Folding keeps the contexts Hygiene tells the two x apart by.
+	p, err := parser.ParseFile(`var x = 1; function f() { var y = 2 + 3; return x + y; }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver.Resolve(p)
+	for _, id := range idents(p, "y") {
+		id.Name = "x"
+	}
+	transform.Fold(p)
+	resolver.Hygiene(p)
+	want, err := parser.ParseFile(`var x = 1; function f() { var x1 = 5; return x + x1; }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := generator.Generate(p), generator.Generate(want); got != want {
+		t.Errorf("Hygiene after Fold = %s, want %s", got, want)
+	}
+}
+
+type identCollector struct {
+	ast.NoopVisitor
+	idents []*ast.Identifier
+}
+
+func (c *identCollector) VisitIdentifier(n *ast.Identifier) {
+	c.idents = append(c.idents, n)
+}
+
+// idents returns the identifiers of n named name, in source order.
+func idents(n ast.VisitableNode, name string) []*ast.Identifier {
+	c := &identCollector{}
+	c.V = c
+	n.VisitWith(c)
+	return slices.DeleteFunc(c.idents, func(id *ast.Identifier) bool { return id.Name != name })
+}